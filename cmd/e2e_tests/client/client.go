@@ -1,8 +1,12 @@
 package client
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/shopspring/decimal"
@@ -28,8 +32,10 @@ func NewClient(serverUrl string) (*Client, error) {
 type Wallet struct {
 	Id            int64  `json:"id"`
 	UserAccountId int64  `json:"user_account_id"`
+	Name          string `json:"name"`
 	Currency      string `json:"currency"`
 	Balance       string `json:"balance"`
+	Status        string `json:"status"`
 }
 
 type WalletBalanceResponseData struct {
@@ -43,8 +49,39 @@ func (c *Client) Wallets(username string) (_responseBody WalletBalanceResponseBo
 	if username == "" {
 		return WalletBalanceResponseBody{}, 0, fmt.Errorf("malformedclient request. abort sending")
 	}
+	return c.WalletsAs(username, username)
+}
+
+// WalletsAs
+// Wallets of username requested by principal.
+func (c *Client) WalletsAs(principal string, username string) (WalletBalanceResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/user/" + username + "/wallets"
+	return httpGet[WalletBalanceResponseBody](c.httpClient, baseUrl, nil, []string{principal, ""})
+}
+
+// WalletsAt
+// Balances as of at, RFC3339.
+func (c *Client) WalletsAt(principal string, username string, at string) (WalletBalanceResponseBody, int, error) {
 	baseUrl := c.serverUrl + "/user/" + username + "/wallets"
-	return httpGet[WalletBalanceResponseBody](c.httpClient, baseUrl, nil)
+	return httpGet[WalletBalanceResponseBody](c.httpClient, baseUrl, map[string]interface{}{"at": at}, []string{principal, ""})
+}
+
+type BalanceResponseData struct {
+	Wallet Wallet    `json:"wallet"`
+	At     time.Time `json:"at"`
+}
+
+type BalanceResponseBody = ResponseBody[BalanceResponseData]
+
+// Balance
+// Balance of the wallet as of at, RFC3339. Now if at is empty.
+func (c *Client) Balance(principal string, walletId int64, at string) (BalanceResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/wallet/%d/balance", walletId)
+	var queryParams map[string]interface{}
+	if at != "" {
+		queryParams = map[string]interface{}{"at": at}
+	}
+	return httpGet[BalanceResponseBody](c.httpClient, baseUrl, queryParams, []string{principal, ""})
 }
 
 type CreatedUser struct {
@@ -69,8 +106,17 @@ func (c *Client) CreateUser(username string) (CreateUserResponseBody, int, error
 }
 
 type TransactionMetaData struct {
-	SourceWalletId *int64  `json:"source_wallet_id"`
-	Amount         *string `json:"amount"`
+	SourceWalletId      *int64     `json:"source_wallet_id"`
+	DestinationWalletId *int64     `json:"destination_wallet_id"`
+	Amount              *string    `json:"amount"`
+	Approvers           []string   `json:"approvers"`
+	ApprovalsRequired   *int       `json:"approvals_required"`
+	ExpiresAt           *time.Time `json:"expires_at"`
+	PaymentRequestId    *int64     `json:"payment_request_id"`
+	ReasonCode          *string    `json:"reason_code"`
+	CreatedBy           *string    `json:"created_by"`
+	ApprovedBy          *string    `json:"approved_by"`
+	RejectedBy          *string    `json:"rejected_by"`
 }
 
 type Transaction struct {
@@ -95,8 +141,21 @@ func (c *Client) Transactions(username string) (TransactionResponseBody, int, er
 	if username == "" {
 		return TransactionResponseBody{}, 0, fmt.Errorf("malformedclient request. abort sending")
 	}
+	return c.TransactionsAs(username, username)
+}
+
+// TransactionsAs
+// Transactions of username requested by principal.
+func (c *Client) TransactionsAs(principal string, username string) (TransactionResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/user/" + username + "/transactions"
+	return httpGet[TransactionResponseBody](c.httpClient, baseUrl, nil, []string{principal, ""})
+}
+
+// TransactionsExport
+// accept is text/csv or application/x-ndjson.
+func (c *Client) TransactionsExport(principal string, username string, accept string) ([]byte, string, int, error) {
 	baseUrl := c.serverUrl + "/user/" + username + "/transactions"
-	return httpGet[TransactionResponseBody](c.httpClient, baseUrl, nil)
+	return httpGetRaw(c.httpClient, baseUrl, accept, []string{principal, ""})
 }
 
 type User struct {
@@ -149,6 +208,7 @@ type CreateWalletResponseData struct {
 	Wallet *struct {
 		Id            int64  `json:"id"`
 		UserAccountId int64  `json:"user_account_id"`
+		Name          string `json:"name"`
 		Currency      string `json:"currency"`
 		Balance       string `json:"balance"`
 	} `json:"wallet"`
@@ -162,7 +222,74 @@ func (c *Client) CreateWallet(username string, currency string) (CreateWalletRes
 		"username": username,
 		"currency": currency,
 	}
-	return httpPost[CreateWalletResponseBody](c.httpClient, baseUrl, requestBody, nil)
+	return httpPost[CreateWalletResponseBody](c.httpClient, baseUrl, requestBody, []string{username, ""})
+}
+
+func (c *Client) CreateWalletAs(principal string, username string, currency string) (CreateWalletResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/wallet"
+	requestBody := map[string]interface{}{
+		"username": username,
+		"currency": currency,
+	}
+	return httpPost[CreateWalletResponseBody](c.httpClient, baseUrl, requestBody, []string{principal, ""})
+}
+
+func (c *Client) CreateNamedWallet(username string, currency string, name string) (CreateWalletResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/wallet"
+	requestBody := map[string]interface{}{
+		"username": username,
+		"currency": currency,
+		"name":     name,
+	}
+	return httpPost[CreateWalletResponseBody](c.httpClient, baseUrl, requestBody, []string{username, ""})
+}
+
+type Member struct {
+	WalletId   int64   `json:"wallet_id"`
+	Username   string  `json:"username"`
+	Role       string  `json:"role"`
+	SpendLimit *string `json:"spend_limit"`
+	UpdatedBy  string  `json:"updated_by"`
+}
+
+type MembersResponseData struct {
+	Members []Member `json:"members"`
+}
+
+type MembersResponseBody = ResponseBody[MembersResponseData]
+
+func (c *Client) Members(principal string, walletId int64) (MembersResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/wallet/%d/members", walletId)
+	return httpGet[MembersResponseBody](c.httpClient, baseUrl, nil, []string{principal, ""})
+}
+
+type MemberResponseData struct {
+	Member `json:"member"`
+}
+
+type MemberResponseBody = ResponseBody[MemberResponseData]
+
+func (c *Client) SetMember(principal string, walletId int64, username string, role string, spendLimit *decimal.Decimal) (MemberResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/wallet/%d/members/%s", walletId, username)
+	requestBody := map[string]interface{}{
+		"role": role,
+	}
+	if spendLimit != nil {
+		requestBody["spend_limit"] = spendLimit.String()
+	}
+	return httpPut[MemberResponseBody](c.httpClient, baseUrl, requestBody, []string{principal, ""})
+}
+
+type RemoveMemberResponseData struct {
+	WalletId int64  `json:"wallet_id"`
+	Username string `json:"username"`
+}
+
+type RemoveMemberResponseBody = ResponseBody[RemoveMemberResponseData]
+
+func (c *Client) RemoveMember(principal string, walletId int64, username string) (RemoveMemberResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/wallet/%d/members/%s", walletId, username)
+	return httpDelete[RemoveMemberResponseBody](c.httpClient, baseUrl, []string{principal, ""})
 }
 
 type TransferResponseData struct {
@@ -181,7 +308,561 @@ func (c *Client) Transfer(username string, fromWalletId int64, toWalletId int64,
 	return httpPost[TransferResponseBody](c.httpClient, baseUrl, requestBody, []string{username, ""})
 }
 
+type PaymentRequest struct {
+	Id            int64     `json:"id"`
+	Requester     string    `json:"requester"`
+	Payer         string    `json:"payer"`
+	WalletId      int64     `json:"wallet_id"`
+	Amount        string    `json:"amount"`
+	Currency      string    `json:"currency"`
+	Memo          string    `json:"memo"`
+	Status        string    `json:"status"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	TransactionId *int64    `json:"transaction_id"`
+}
+
+type PaymentRequestResponseData struct {
+	PaymentRequest `json:"payment_request"`
+}
+
+type PaymentRequestResponseBody = ResponseBody[PaymentRequestResponseData]
+
+// CreatePaymentRequest
+// Server default expiry if expiresAt is nil.
+func (c *Client) CreatePaymentRequest(principal string, payer string, walletId int64, amount decimal.Decimal, currency string, memo string, expiresAt *time.Time) (PaymentRequestResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/payment-requests"
+	requestBody := map[string]interface{}{
+		"payer":     payer,
+		"wallet_id": walletId,
+		"amount":    amount.String(),
+		"currency":  currency,
+		"memo":      memo,
+	}
+	if expiresAt != nil {
+		requestBody["expires_at"] = expiresAt
+	}
+	return httpPost[PaymentRequestResponseBody](c.httpClient, baseUrl, requestBody, []string{principal, ""})
+}
+
+type PaymentRequestsResponseData struct {
+	PaymentRequests []PaymentRequest `json:"payment_requests"`
+}
+
+type PaymentRequestsResponseBody = ResponseBody[PaymentRequestsResponseData]
+
+func (c *Client) PaymentRequests(principal string, username string, direction string, status string) (PaymentRequestsResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/user/%s/payment-requests", username)
+	query := map[string]interface{}{}
+	if direction != "" {
+		query["direction"] = direction
+	}
+	if status != "" {
+		query["status"] = status
+	}
+	return httpGet[PaymentRequestsResponseBody](c.httpClient, baseUrl, query, []string{principal, ""})
+}
+
+func (c *Client) PayPaymentRequest(principal string, id int64, walletId int64) (TransferResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/payment-requests/%d/pay", id)
+	requestBody := map[string]interface{}{
+		"wallet_id": walletId,
+		"nonce":     time.Now().UnixMilli(),
+	}
+	return httpPost[TransferResponseBody](c.httpClient, baseUrl, requestBody, []string{principal, ""})
+}
+
+func (c *Client) CancelPaymentRequest(principal string, id int64) (PaymentRequestResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/payment-requests/%d/cancel", id)
+	return httpPost[PaymentRequestResponseBody](c.httpClient, baseUrl, nil, []string{principal, ""})
+}
+
+type Approval struct {
+	Approver  string    `json:"approver"`
+	Decision  string    `json:"decision"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ApprovalResponseData struct {
+	Transaction `json:"transaction"`
+	Approvals   []Approval `json:"approvals"`
+}
+
+type ApprovalResponseBody = ResponseBody[ApprovalResponseData]
+
+func (c *Client) ApproveTransfer(username string, transactionId int64) (ApprovalResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/transaction/%d/approve", transactionId)
+	return httpPost[ApprovalResponseBody](c.httpClient, baseUrl, nil, []string{username, ""})
+}
+
+func (c *Client) RejectTransfer(username string, transactionId int64) (ApprovalResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/transaction/%d/reject", transactionId)
+	return httpPost[ApprovalResponseBody](c.httpClient, baseUrl, nil, []string{username, ""})
+}
+
+func (c *Client) PendingApprovals(principal string, username string) (TransactionResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/user/%s/approvals", username)
+	return httpGet[TransactionResponseBody](c.httpClient, baseUrl, nil, []string{principal, ""})
+}
+
+type ApprovalPolicy struct {
+	WalletId          int64    `json:"wallet_id"`
+	Threshold         string   `json:"threshold"`
+	RequiredApprovals int      `json:"required_approvals"`
+	TimeoutSeconds    int64    `json:"timeout_seconds"`
+	Approvers         []string `json:"approvers"`
+	UpdatedBy         string   `json:"updated_by"`
+}
+
+type ApprovalPolicyResponseData struct {
+	ApprovalPolicy `json:"approval_policy"`
+}
+
+type ApprovalPolicyResponseBody = ResponseBody[ApprovalPolicyResponseData]
+
+func (c *Client) ApprovalPolicy(principal string, walletId int64) (ApprovalPolicyResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/wallet/%d/approval-policy", walletId)
+	return httpGet[ApprovalPolicyResponseBody](c.httpClient, baseUrl, nil, []string{principal, ""})
+}
+
+func (c *Client) SetApprovalPolicy(adminUsername string, walletId int64, threshold decimal.Decimal, requiredApprovals int, timeoutSeconds int64, approvers []string) (ApprovalPolicyResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/admin/wallet/%d/approval-policy", walletId)
+	requestBody := map[string]interface{}{
+		"threshold":          threshold.String(),
+		"required_approvals": requiredApprovals,
+		"timeout_seconds":    timeoutSeconds,
+		"approvers":          approvers,
+	}
+	return httpPut[ApprovalPolicyResponseBody](c.httpClient, baseUrl, requestBody, []string{adminUsername, ""})
+}
+
+type AuditEvent struct {
+	Id          int64     `json:"id"`
+	Principal   *string   `json:"principal"`
+	Ip          string    `json:"ip"`
+	Method      string    `json:"method"`
+	Route       string    `json:"route"`
+	Path        string    `json:"path"`
+	WalletId    *int64    `json:"wallet_id"`
+	PayloadHash string    `json:"payload_hash"`
+	Outcome     string    `json:"outcome"`
+	HttpStatus  int       `json:"http_status"`
+	ErrorCode   *string   `json:"error_code"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type AuditEventsResponseData struct {
+	Events []AuditEvent `json:"events"`
+}
+
+type AuditEventsResponseBody = ResponseBody[AuditEventsResponseData]
+
+func (c *Client) AuditEvents(adminUsername string, queryParams map[string]interface{}) (AuditEventsResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/admin/audit"
+	return httpGet[AuditEventsResponseBody](c.httpClient, baseUrl, queryParams, []string{adminUsername, ""})
+}
+
+type UserAccount struct {
+	Id       int64  `json:"id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+	Frozen   bool   `json:"frozen"`
+}
+
+type AdminUserResponseData struct {
+	User    UserAccount `json:"user"`
+	Wallets []Wallet    `json:"wallets"`
+}
+
+type AdminUserResponseBody = ResponseBody[AdminUserResponseData]
+
+func (c *Client) AdminUser(adminUsername string, username string) (AdminUserResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/admin/user/" + username
+	return httpGet[AdminUserResponseBody](c.httpClient, baseUrl, nil, []string{adminUsername, ""})
+}
+
+type AdminUserAccountResponseData struct {
+	User UserAccount `json:"user"`
+}
+
+type AdminUserAccountResponseBody = ResponseBody[AdminUserAccountResponseData]
+
+func (c *Client) Freeze(adminUsername string, username string) (AdminUserAccountResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/admin/user/" + username + "/freeze"
+	return httpPost[AdminUserAccountResponseBody](c.httpClient, baseUrl, nil, []string{adminUsername, ""})
+}
+
+func (c *Client) Unfreeze(adminUsername string, username string) (AdminUserAccountResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/admin/user/" + username + "/unfreeze"
+	return httpPost[AdminUserAccountResponseBody](c.httpClient, baseUrl, nil, []string{adminUsername, ""})
+}
+
+func (c *Client) SetRole(adminUsername string, username string, role string) (AdminUserAccountResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/admin/user/" + username + "/role"
+	requestBody := map[string]interface{}{
+		"role": role,
+	}
+	return httpPut[AdminUserAccountResponseBody](c.httpClient, baseUrl, requestBody, []string{adminUsername, ""})
+}
+
+type AdjustmentResponseData struct {
+	Transaction `json:"transaction"`
+}
+
+type AdjustmentResponseBody = ResponseBody[AdjustmentResponseData]
+
+func (c *Client) Adjustment(adminUsername string, walletId int64, entryType string, amount decimal.Decimal, reasonCode string, reason string) (AdjustmentResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/admin/wallet/%d/adjustment", walletId)
+	requestBody := map[string]interface{}{
+		"entry_type":  entryType,
+		"amount":      amount.String(),
+		"reason_code": reasonCode,
+		"reason":      reason,
+		"nonce":       time.Now().UnixMilli(),
+	}
+	return httpPost[AdjustmentResponseBody](c.httpClient, baseUrl, requestBody, []string{adminUsername, ""})
+}
+
+func (c *Client) ApproveAdjustment(adminUsername string, transactionId int64) (AdjustmentResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/admin/adjustment/%d/approve", transactionId)
+	return httpPost[AdjustmentResponseBody](c.httpClient, baseUrl, nil, []string{adminUsername, ""})
+}
+
+func (c *Client) RejectAdjustment(adminUsername string, transactionId int64) (AdjustmentResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/admin/adjustment/%d/reject", transactionId)
+	return httpPost[AdjustmentResponseBody](c.httpClient, baseUrl, nil, []string{adminUsername, ""})
+}
+
+func (c *Client) PendingAdjustments(adminUsername string, queryParams map[string]interface{}) (PendingTransactionsResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/admin/adjustments/pending"
+	return httpGet[PendingTransactionsResponseBody](c.httpClient, baseUrl, queryParams, []string{adminUsername, ""})
+}
+
+type AdminWalletResponseData struct {
+	Wallet Wallet `json:"wallet"`
+}
+
+type AdminWalletResponseBody = ResponseBody[AdminWalletResponseData]
+
+func (c *Client) SetWalletStatus(adminUsername string, walletId int64, status string, reason string) (AdminWalletResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/admin/wallet/%d/status", walletId)
+	requestBody := map[string]interface{}{
+		"status": status,
+		"reason": reason,
+	}
+	return httpPut[AdminWalletResponseBody](c.httpClient, baseUrl, requestBody, []string{adminUsername, ""})
+}
+
+type WalletStatusChange struct {
+	Id         int64     `json:"id"`
+	WalletId   int64     `json:"wallet_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason"`
+	Actor      string    `json:"actor"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type WalletStatusHistoryResponseData struct {
+	Changes []WalletStatusChange `json:"changes"`
+}
+
+type WalletStatusHistoryResponseBody = ResponseBody[WalletStatusHistoryResponseData]
+
+func (c *Client) WalletStatusHistory(adminUsername string, walletId int64) (WalletStatusHistoryResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/admin/wallet/%d/status/history", walletId)
+	return httpGet[WalletStatusHistoryResponseBody](c.httpClient, baseUrl, nil, []string{adminUsername, ""})
+}
+
+type PendingTransactionsResponseData struct {
+	Transactions []Transaction `json:"transactions"`
+}
+
+type PendingTransactionsResponseBody = ResponseBody[PendingTransactionsResponseData]
+
+func (c *Client) PendingTransactions(adminUsername string, queryParams map[string]interface{}) (PendingTransactionsResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/admin/transactions/pending"
+	return httpGet[PendingTransactionsResponseBody](c.httpClient, baseUrl, queryParams, []string{adminUsername, ""})
+}
+
+type WalletDiscrepancy struct {
+	WalletId          int64   `json:"wallet_id"`
+	Balance           string  `json:"balance"`
+	LedgerSum         string  `json:"ledger_sum"`
+	LastLedgerBalance *string `json:"last_ledger_balance"`
+}
+
+type TransactionDiscrepancy struct {
+	Id        int64  `json:"id"`
+	Status    string `json:"status"`
+	Operation string `json:"operation"`
+}
+
+type ReconciliationResponseData struct {
+	Wallets                  int64                    `json:"wallets"`
+	Transactions             int64                    `json:"transactions"`
+	WalletDiscrepancies      []WalletDiscrepancy      `json:"wallet_discrepancies"`
+	TransactionDiscrepancies []TransactionDiscrepancy `json:"transaction_discrepancies"`
+}
+
+type ReconciliationResponseBody = ResponseBody[ReconciliationResponseData]
+
+func (c *Client) Reconciliation(adminUsername string) (ReconciliationResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/admin/reconciliation"
+	return httpGet[ReconciliationResponseBody](c.httpClient, baseUrl, nil, []string{adminUsername, ""})
+}
+
+type Profile struct {
+	Id          int64     `json:"id"`
+	Username    string    `json:"username"`
+	DisplayName *string   `json:"display_name"`
+	Email       *string   `json:"email"`
+	Status      string    `json:"status"`
+	KycTier     int       `json:"kyc_tier"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type ProfileResponseData struct {
+	User Profile `json:"user"`
+}
+
+type ProfileResponseBody = ResponseBody[ProfileResponseData]
+
+func (c *Client) Profile(principal string, username string) (ProfileResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/user/" + username
+	return httpGet[ProfileResponseBody](c.httpClient, baseUrl, nil, []string{principal, ""})
+}
+
+// UpdateProfile
+// requestBody keys are display_name, email, status and kyc_tier.
+func (c *Client) UpdateProfile(principal string, username string, requestBody map[string]interface{}) (ProfileResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/user/" + username
+	return httpPatch[ProfileResponseBody](c.httpClient, baseUrl, requestBody, []string{principal, ""})
+}
+
+type Statement struct {
+	WalletId       int64      `json:"wallet_id"`
+	Currency       string     `json:"currency"`
+	From           *time.Time `json:"from"`
+	To             time.Time  `json:"to"`
+	OpeningBalance string     `json:"opening_balance"`
+	Ledgers        []Ledger   `json:"ledgers"`
+	TotalCredits   string     `json:"total_credits"`
+	TotalDebits    string     `json:"total_debits"`
+	ClosingBalance string     `json:"closing_balance"`
+}
+
+type StatementResponseData struct {
+	Statement Statement `json:"statement"`
+}
+
+type StatementResponseBody = ResponseBody[StatementResponseData]
+
+// Statement
+// queryParams keys are from and to.
+func (c *Client) Statement(principal string, walletId int64, queryParams map[string]interface{}) (StatementResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/wallet/%d/statement", walletId)
+	return httpGet[StatementResponseBody](c.httpClient, baseUrl, queryParams, []string{principal, ""})
+}
+
+type Webhook struct {
+	Id         int64     `json:"id"`
+	Url        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+	Secret     string    `json:"secret"`
+}
+
+type CreateWebhookResponseData struct {
+	Webhook Webhook `json:"webhook"`
+}
+
+type CreateWebhookResponseBody = ResponseBody[CreateWebhookResponseData]
+
+func (c *Client) CreateWebhook(principal string, username string, url string, eventTypes []string) (CreateWebhookResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/user/" + username + "/webhooks"
+	return httpPost[CreateWebhookResponseBody](c.httpClient, baseUrl, map[string]interface{}{
+		"url":         url,
+		"event_types": eventTypes,
+	}, []string{principal, ""})
+}
+
+type WebhooksResponseData struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+type WebhooksResponseBody = ResponseBody[WebhooksResponseData]
+
+func (c *Client) Webhooks(principal string, username string) (WebhooksResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/user/" + username + "/webhooks"
+	return httpGet[WebhooksResponseBody](c.httpClient, baseUrl, nil, []string{principal, ""})
+}
+
+type DeleteWebhookResponseData struct {
+	WebhookId int64 `json:"webhook_id"`
+}
+
+type DeleteWebhookResponseBody = ResponseBody[DeleteWebhookResponseData]
+
+func (c *Client) DeleteWebhook(principal string, username string, webhookId int64) (DeleteWebhookResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/user/%s/webhooks/%d", username, webhookId)
+	return httpDelete[DeleteWebhookResponseBody](c.httpClient, baseUrl, []string{principal, ""})
+}
+
+type WebhookDelivery struct {
+	Id          int64           `json:"id"`
+	WebhookId   int64           `json:"webhook_id"`
+	EventType   string          `json:"event_type"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	LastError   *string         `json:"last_error"`
+	DeliveredAt *time.Time      `json:"delivered_at"`
+}
+
+type WebhookDeliveriesResponseData struct {
+	Deliveries []WebhookDelivery `json:"deliveries"`
+}
+
+type WebhookDeliveriesResponseBody = ResponseBody[WebhookDeliveriesResponseData]
+
+func (c *Client) WebhookDeliveries(principal string, username string, webhookId int64) (WebhookDeliveriesResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/user/%s/webhooks/%d/deliveries", username, webhookId)
+	return httpGet[WebhookDeliveriesResponseBody](c.httpClient, baseUrl, nil, []string{principal, ""})
+}
+
+type ReplayWebhookDeliveryResponseData struct {
+	Delivery WebhookDelivery `json:"delivery"`
+}
+
+type ReplayWebhookDeliveryResponseBody = ResponseBody[ReplayWebhookDeliveryResponseData]
+
+func (c *Client) ReplayWebhookDelivery(principal string, username string, deliveryId int64) (ReplayWebhookDeliveryResponseBody, int, error) {
+	baseUrl := c.serverUrl + fmt.Sprintf("/user/%s/webhooks/deliveries/%d/replay", username, deliveryId)
+	return httpPost[ReplayWebhookDeliveryResponseBody](c.httpClient, baseUrl, nil, []string{principal, ""})
+}
+
+// ServerSentEvent
+// Data is the raw json of the event.
+type ServerSentEvent struct {
+	Id    string
+	Event string
+	Data  string
+}
+
+// EventStream
+// Events of an activity stream, read in the background until closed.
+type EventStream struct {
+	events chan ServerSentEvent
+	cancel context.CancelFunc
+}
+
+// Events
+// Opens the activity stream of username. lastEventId is sent as Last-Event-ID unless empty. The stream is nil unless
+// the status is 200.
+func (c *Client) Events(principal string, username string, lastEventId string) (*EventStream, int, error) {
+	baseUrl := c.serverUrl + "/user/" + username + "/events"
+	headers := map[string]string{}
+	if lastEventId != "" {
+		headers["Last-Event-ID"] = lastEventId
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	resp, err := httpGetStream(ctx, baseUrl, "text/event-stream", headers, []string{principal, ""})
+	if err != nil {
+		cancel()
+		return nil, 0, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		cancel()
+		return nil, resp.StatusCode, nil
+	}
+
+	s := &EventStream{events: make(chan ServerSentEvent, 64), cancel: cancel}
+	go func() {
+		defer resp.Body.Close()
+		defer close(s.events)
+		scanner := bufio.NewScanner(resp.Body)
+		var e ServerSentEvent
+		for scanner.Scan() {
+			line := scanner.Text()
+			field, value, _ := strings.Cut(line, ": ")
+			switch {
+			case line == "":
+				if e.Event != "" {
+					s.events <- e
+				}
+				e = ServerSentEvent{}
+			case field == "id":
+				e.Id = value
+			case field == "event":
+				e.Event = value
+			case field == "data":
+				e.Data = value
+			}
+		}
+	}()
+	return s, resp.StatusCode, nil
+}
+
+// Next
+// Waits up to timeout for the next event. ok is false on timeout or end of stream.
+func (s *EventStream) Next(timeout time.Duration) (_event ServerSentEvent, _ok bool) {
+	select {
+	case e, ok := <-s.events:
+		return e, ok
+	case <-time.After(timeout):
+		return ServerSentEvent{}, false
+	}
+}
+
+func (s *EventStream) Close() {
+	s.cancel()
+}
+
+type AliveResponseData struct {
+	Status string `json:"status"`
+}
+
+type AliveResponseBody = ResponseBody[AliveResponseData]
+
+func (c *Client) Alive() (AliveResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/healthz"
+	return httpGet[AliveResponseBody](c.httpClient, baseUrl, nil, nil)
+}
+
+type ReadyChecks struct {
+	Database struct {
+		Status string `json:"status"`
+	} `json:"database"`
+	Migrations struct {
+		Status          string `json:"status"`
+		Version         int64  `json:"version"`
+		ExpectedVersion int64  `json:"expected_version"`
+	} `json:"migrations"`
+	Pool struct {
+		Status   string `json:"status"`
+		MaxConns int32  `json:"max_conns"`
+	} `json:"pool"`
+}
+
+type ReadyResponseData struct {
+	Status string       `json:"status"`
+	Checks *ReadyChecks `json:"checks"`
+}
+
+type ReadyResponseBody = ResponseBody[ReadyResponseData]
+
+func (c *Client) Ready() (ReadyResponseBody, int, error) {
+	baseUrl := c.serverUrl + "/readyz"
+	return httpGet[ReadyResponseBody](c.httpClient, baseUrl, nil, nil)
+}
+
+// ResponseBody
+// Error is set to Code of application/problem+json error responses.
 type ResponseBody[T any] struct {
 	Error *string `json:"error"`
 	Data  T       `json:"data"`
+
+	Code   *string `json:"code"`
+	Detail *string `json:"detail"`
 }
@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -13,6 +14,22 @@ import (
 var postLock sync.Mutex
 
 func httpPost[T ResponseBody[V], V any](httpClient *http.Client, baseUrl string, requestBody map[string]interface{}, basicAuthUsernamePassword []string) (_jsonResponseBody T, _statusCode int, _clientError error) {
+	return httpSend[T](httpClient, "POST", baseUrl, requestBody, basicAuthUsernamePassword)
+}
+
+func httpPut[T ResponseBody[V], V any](httpClient *http.Client, baseUrl string, requestBody map[string]interface{}, basicAuthUsernamePassword []string) (_jsonResponseBody T, _statusCode int, _clientError error) {
+	return httpSend[T](httpClient, "PUT", baseUrl, requestBody, basicAuthUsernamePassword)
+}
+
+func httpPatch[T ResponseBody[V], V any](httpClient *http.Client, baseUrl string, requestBody map[string]interface{}, basicAuthUsernamePassword []string) (_jsonResponseBody T, _statusCode int, _clientError error) {
+	return httpSend[T](httpClient, "PATCH", baseUrl, requestBody, basicAuthUsernamePassword)
+}
+
+func httpDelete[T ResponseBody[V], V any](httpClient *http.Client, baseUrl string, basicAuthUsernamePassword []string) (_jsonResponseBody T, _statusCode int, _clientError error) {
+	return httpSend[T](httpClient, "DELETE", baseUrl, nil, basicAuthUsernamePassword)
+}
+
+func httpSend[T ResponseBody[V], V any](httpClient *http.Client, method string, baseUrl string, requestBody map[string]interface{}, basicAuthUsernamePassword []string) (_jsonResponseBody T, _statusCode int, _clientError error) {
 	postLock.Lock()
 	time.Sleep(1 * time.Millisecond)
 	defer postLock.Unlock()
@@ -23,7 +40,7 @@ func httpPost[T ResponseBody[V], V any](httpClient *http.Client, baseUrl string,
 	}
 
 	fullURL := baseUrl
-	req, clientError := http.NewRequest("POST", fullURL, bytes.NewBuffer(bb))
+	req, clientError := http.NewRequest(method, fullURL, bytes.NewBuffer(bb))
 	if clientError != nil {
 		return t, 0, clientError
 	}
@@ -52,12 +69,12 @@ func httpPost[T ResponseBody[V], V any](httpClient *http.Client, baseUrl string,
 		return t, 0, fmt.Errorf("response body: %s %v", string(body), clientError)
 	}
 
-	return t, resp.StatusCode, clientError
+	return withProblemCodeAsError[T](t), resp.StatusCode, nil
 }
 
 var getLock sync.Mutex
 
-func httpGet[T ResponseBody[V], V any](httpClient *http.Client, fullURL string, queryParams map[string]interface{}) (jsonResponseBody T, statusCode int, _clientError error) {
+func httpGet[T ResponseBody[V], V any](httpClient *http.Client, fullURL string, queryParams map[string]interface{}, basicAuthUsernamePassword []string) (jsonResponseBody T, statusCode int, _clientError error) {
 	getLock.Lock()
 	time.Sleep(1 * time.Millisecond)
 	defer getLock.Unlock()
@@ -68,6 +85,18 @@ func httpGet[T ResponseBody[V], V any](httpClient *http.Client, fullURL string,
 		return t, 0, clientError
 	}
 
+	if len(queryParams) > 0 {
+		query := req.URL.Query()
+		for k, v := range queryParams {
+			query.Set(k, fmt.Sprint(v))
+		}
+		req.URL.RawQuery = query.Encode()
+	}
+
+	if len(basicAuthUsernamePassword) == 2 {
+		req.SetBasicAuth(basicAuthUsernamePassword[0], basicAuthUsernamePassword[1])
+	}
+
 	resp, clientError := httpClient.Do(req)
 	if clientError != nil {
 		return t, 0, clientError
@@ -88,5 +117,60 @@ func httpGet[T ResponseBody[V], V any](httpClient *http.Client, fullURL string,
 		return t, 0, fmt.Errorf("response body: %s %v", string(body), clientError)
 	}
 
-	return t, resp.StatusCode, nil
+	return withProblemCodeAsError[T](t), resp.StatusCode, nil
+}
+
+// httpGetRaw
+// For non-JSON responses. Returns the body and content type as is.
+func httpGetRaw(httpClient *http.Client, fullURL string, accept string, basicAuthUsernamePassword []string) (_body []byte, _contentType string, _statusCode int, _clientError error) {
+	getLock.Lock()
+	time.Sleep(1 * time.Millisecond)
+	defer getLock.Unlock()
+
+	req, clientError := http.NewRequest("GET", fullURL, nil)
+	if clientError != nil {
+		return nil, "", 0, clientError
+	}
+	req.Header.Set("Accept", accept)
+	if len(basicAuthUsernamePassword) == 2 {
+		req.SetBasicAuth(basicAuthUsernamePassword[0], basicAuthUsernamePassword[1])
+	}
+
+	resp, clientError := httpClient.Do(req)
+	if clientError != nil {
+		return nil, "", 0, clientError
+	}
+	defer resp.Body.Close()
+
+	body, clientError := io.ReadAll(resp.Body)
+	if clientError != nil {
+		return nil, "", 0, clientError
+	}
+	return body, resp.Header.Get("Content-Type"), resp.StatusCode, nil
+}
+
+func withProblemCodeAsError[T ResponseBody[V], V any](t T) T {
+	responseBody := ResponseBody[V](t)
+	if responseBody.Error == nil && responseBody.Code != nil {
+		responseBody.Error = responseBody.Code
+	}
+	return T(responseBody)
+}
+
+// httpGetStream
+// For streamed responses. The body is left open for the caller to read and close, the request ends with ctx.
+func httpGetStream(ctx context.Context, fullURL string, accept string, headers map[string]string, basicAuthUsernamePassword []string) (*http.Response, error) {
+	req, clientError := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
+	if clientError != nil {
+		return nil, clientError
+	}
+	req.Header.Set("Accept", accept)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if len(basicAuthUsernamePassword) == 2 {
+		req.SetBasicAuth(basicAuthUsernamePassword[0], basicAuthUsernamePassword[1])
+	}
+	// without client timeout, streams outlive it
+	return (&http.Client{}).Do(req)
 }
@@ -1,17 +1,24 @@
 package e2e_tests
 
 import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"slices"
 	"strconv"
+	"strings"
 	"sync"
 	"syscall"
 	"testing"
 	"time"
 
 	testclient "github.com/cryptonlx/crypto/cmd/e2e_tests/client"
+	webhookservice "github.com/cryptonlx/crypto/src/services/webhook"
 
 	"github.com/shopspring/decimal"
 )
@@ -59,6 +66,23 @@ func Exec(t *testing.T, client *testclient.Client) {
 	T_0009(t, client)
 	T_0010(t, client)
 	T_0011(t, client)
+	T_0012(t, client)
+	T_0013(t, client)
+	T_0014(t, client)
+	T_0015(t, client)
+	T_0016(t, client)
+	T_0017(t, client)
+	T_0018(t, client)
+	T_0019(t, client)
+	T_0020(t, client)
+	T_0021(t, client)
+	T_0022(t, client)
+	T_0023(t, client)
+	T_0024(t, client)
+	T_0025(t, client)
+	T_0026(t, client)
+	T_0027(t, client)
+	T_0028(t, client)
 }
 
 func Ping(t *testing.T, client *testclient.Client) {
@@ -71,11 +95,11 @@ func Ping(t *testing.T, client *testclient.Client) {
 func T_0001(t *testing.T, client *testclient.Client) {
 	futureUserName := NewRandomUserName("t00001", 12, 2*24*time.Hour)
 	responseBody, responseStatusCode, err := client.Wallets(futureUserName)
-	if responseStatusCode != http.StatusBadRequest {
-		t.Fatalf(`[T_0001_001] Wallets want status code 400. got code=%d, err=%#v`, responseStatusCode, err.Error())
+	if responseStatusCode != http.StatusUnauthorized {
+		t.Fatalf(`[T_0001_001] Wallets want status code 401. got code=%d, err=%#v`, responseStatusCode, err)
 	}
-	if responseBody.Error == nil || *responseBody.Error != "resource: user not found" {
-		t.Fatalf(`[T_0001_001] Wallets want Response.error="resource: user not found". got err=%v, Response.error=%v, user id=%d`, err, *responseBody.Error, 0)
+	if responseBody.Code == nil || *responseBody.Code != "unauthorized" {
+		t.Fatalf(`[T_0001_001] Wallets want Response.code="unauthorized". got err=%v, Response.code=%v, user id=%d`, err, responseBody.Code, 0)
 	}
 
 	username := NewRandomUserName("t00001", 12, 0)
@@ -99,12 +123,12 @@ func T_0001(t *testing.T, client *testclient.Client) {
 	}
 
 	createUserResponseBody, responseStatusCode, err = client.CreateUser(username)
-	if responseStatusCode != http.StatusBadRequest {
-		t.Fatalf("[T_0001_003] Duplicate CreateUser want 400. responseStatusCode=%d, err=%v", responseStatusCode, err)
+	if responseStatusCode != http.StatusConflict {
+		t.Fatalf("[T_0001_003] Duplicate CreateUser want 409. responseStatusCode=%d, err=%v", responseStatusCode, err)
 	}
 
-	if createUserResponseBody.Error == nil || *createUserResponseBody.Error != "unique_violation" {
-		t.Fatalf(`[T_0001_003] Duplicate CreateUser want "unique_violation". responseStatusCode=%s, err=%v`, *createUserResponseBody.Error, err)
+	if createUserResponseBody.Code == nil || *createUserResponseBody.Code != "already_exists" {
+		t.Fatalf(`[T_0001_003] Duplicate CreateUser want Response.code="already_exists". Response.code=%v, err=%v`, createUserResponseBody.Code, err)
 	}
 
 	responseBody, responseStatusCode, err = client.Wallets(username)
@@ -116,11 +140,11 @@ func T_0001(t *testing.T, client *testclient.Client) {
 func T_0002(t *testing.T, client *testclient.Client) {
 	futureUserName := NewRandomUserName("t00002", 12, 2*24*time.Hour)
 	responseBody, responseStatusCode, err := client.Transactions(futureUserName)
-	if responseStatusCode != http.StatusBadRequest {
-		t.Fatalf(`[T_0002_001] Transactions() want status code 400. Got responseStatusCode=%d, err=%#v`, responseStatusCode, err)
+	if responseStatusCode != http.StatusUnauthorized {
+		t.Fatalf(`[T_0002_001] Transactions() want status code 401. Got responseStatusCode=%d, err=%#v`, responseStatusCode, err)
 	}
-	if responseBody.Error != nil && *responseBody.Error != "resource: user not found" {
-		t.Fatalf(`[T_0002_001] Transactions() want Response.error "resource: user not found". got Response.error %s, user id = %d`, *responseBody.Error, 0)
+	if responseBody.Code == nil || *responseBody.Code != "unauthorized" {
+		t.Fatalf(`[T_0002_001] Transactions() want Response.code "unauthorized". got Response.code %v, user id = %d`, responseBody.Code, 0)
 	}
 
 	username := NewRandomUserName("t00002", 12, 0)
@@ -160,11 +184,11 @@ func T_0004(t *testing.T, client *testclient.Client) {
 	balanceBefore := wallet.Balance
 
 	dRespBody, dStatusCode, err := client.Deposit(username, wallet.Id, decimal.NewFromInt(-40))
-	if dStatusCode != http.StatusBadRequest {
-		t.Fatalf("[T_0004_001] Deposit want err 400. responseStatusCode=%d, err=%v", dStatusCode, err)
+	if dStatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0004_001] Deposit want err 422. responseStatusCode=%d, err=%v", dStatusCode, err)
 	}
-	if *(dRespBody.Error) != "invalid_amount" {
-		t.Fatalf(`[T_0004_001] Deposit() want Response.error "invalid_amount". got err=%v, Response.error=%s %#v`, err, *dRespBody.Error, dRespBody)
+	if dRespBody.Code == nil || *dRespBody.Code != "invalid_amount" {
+		t.Fatalf(`[T_0004_001] Deposit() want Response.code "invalid_amount". got err=%v, Response.code=%v %#v`, err, dRespBody.Code, dRespBody)
 	}
 
 	responseBody, responseStatusCode, err := client.Wallets(username)
@@ -255,15 +279,15 @@ func T_0006(t *testing.T, client *testclient.Client) {
 	if cErr != nil {
 		t.Fatalf(`[T_0006_001] Withdraw transaction want nil err, got error %v`, cErr)
 	}
-	if dStatusCode != http.StatusBadRequest {
-		t.Fatalf("[T_0006_001] Withdraw want 400. responseStatusCode=%d, err=%v", dStatusCode, cErr)
+	if dStatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0006_001] Withdraw want 422. responseStatusCode=%d, err=%v", dStatusCode, cErr)
 	}
-	if dRespBody.Error == nil || *dRespBody.Error != "insufficient_funds" {
+	if dRespBody.Code == nil || *dRespBody.Code != "insufficient_funds" {
 		var s string
-		if dRespBody.Error != nil {
-			s = *dRespBody.Error
+		if dRespBody.Code != nil {
+			s = *dRespBody.Code
 		}
-		t.Fatalf(`[T_0006_001] Withdraw want Response.err == "insufficient_funds". got Response.error=%v`, s)
+		t.Fatalf(`[T_0006_001] Withdraw want Response.code == "insufficient_funds". got Response.code=%v`, s)
 	}
 	if dRespBody.Data.Transaction.Id != 0 {
 		t.Fatalf(`[T_0006_001] Withdraw want zero transaction.Id.`)
@@ -367,15 +391,15 @@ func T_0008(t *testing.T, client *testclient.Client) {
 	if cErr != nil {
 		t.Fatalf(`[T_0008_002] Transfer transaction want nil err, got error %v`, cErr)
 	}
-	if wStatusCode != http.StatusBadRequest {
-		t.Fatalf("[T_0008_002] Transfer want 400. responseStatusCode=%d, err=%s", wStatusCode, cErr.Error())
+	if wStatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0008_002] Transfer want 422. responseStatusCode=%d, err=%v", wStatusCode, cErr)
 	}
 
-	if wRespBody.Error == nil {
-		t.Fatalf(`[T_0008_002] Transfer want non-nil responseBody.Error. got nil`)
+	if wRespBody.Code == nil {
+		t.Fatalf(`[T_0008_002] Transfer want non-nil responseBody.Code. got nil`)
 	}
-	if *wRespBody.Error != "currency_mismatch" {
-		t.Fatalf(`[T_0008_002] Transfer want responseBody.Error="currency_mismatch". got %s`, *wRespBody.Error)
+	if *wRespBody.Code != "currency_mismatch" {
+		t.Fatalf(`[T_0008_002] Transfer want responseBody.Code="currency_mismatch". got %s`, *wRespBody.Code)
 	}
 
 	tRespBody, tStatusCode, cErr := client.Transactions(username0)
@@ -420,15 +444,15 @@ func T_0009(t *testing.T, client *testclient.Client) {
 	if cErr != nil {
 		t.Fatalf(`[T_0009_002] Transfer transaction want nil cErr, got error %v`, cErr)
 	}
-	if wStatusCode != http.StatusBadRequest {
-		t.Fatalf("[T_0009_002] Transfer want 400. responseStatusCode=%d, err=%v", wStatusCode, cErr)
+	if wStatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0009_002] Transfer want 422. responseStatusCode=%d, err=%v", wStatusCode, cErr)
 	}
 
-	if wRespBody.Error == nil {
-		t.Fatalf(`[T_0009_002] Transfer want non-nil responseBody.Error. got nil`)
+	if wRespBody.Code == nil {
+		t.Fatalf(`[T_0009_002] Transfer want non-nil responseBody.Code. got nil`)
 	}
-	if *wRespBody.Error != "insufficient_funds" {
-		t.Fatalf(`[T_0009_002] Transfer want responseBody.Error="currency_mismatch". got %s`, *wRespBody.Error)
+	if *wRespBody.Code != "insufficient_funds" {
+		t.Fatalf(`[T_0009_002] Transfer want responseBody.Code="insufficient_funds". got %s`, *wRespBody.Code)
 	}
 
 	tRespBody, tStatusCode, cErr := client.Transactions(username0)
@@ -560,6 +584,1443 @@ func T_0011(t *testing.T, client *testclient.Client) {
 	SetupUserAndWalletCreation(t, client, "T_0011", []string{"SGD", "USD", "MYD"})
 }
 
+func T_0012(t *testing.T, client *testclient.Client) {
+	username0, user0Wallets := SetupUserAndWalletCreation(t, client, "T_0012", []string{"SGD"})
+	username1, _ := SetupUserAndWalletCreation(t, client, "T_0012", []string{"SGD"})
+
+	user0wallet0 := user0Wallets[0]
+	_, wStatusCode, cErr := client.Withdraw(username0, user0wallet0.Id, decimal.NewFromFloat(50.1))
+	if wStatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0012_001] Withdraw want 422. responseStatusCode=%d, err=%v", wStatusCode, cErr)
+	}
+
+	_, wStatusCode, cErr = client.Withdraw(username1, user0wallet0.Id, decimal.NewFromFloat(10))
+	if wStatusCode != http.StatusForbidden {
+		t.Fatalf("[T_0012_002] Withdraw by non-owner want 403. responseStatusCode=%d, err=%v", wStatusCode, cErr)
+	}
+
+	_, aStatusCode, cErr := client.AuditEvents(username0, nil)
+	if aStatusCode != http.StatusForbidden {
+		t.Fatalf("[T_0012_003] AuditEvents by non-admin want 403. responseStatusCode=%d, err=%v", aStatusCode, cErr)
+	}
+
+	pRespBody, pStatusCode, cErr := client.UpdateProfile(username0, username0, map[string]interface{}{
+		"display_name": strings.Repeat("a", 1<<20),
+	})
+	if pStatusCode != http.StatusRequestEntityTooLarge || pRespBody.Code == nil || *pRespBody.Code != "payload_too_large" {
+		t.Fatalf("[T_0012_005] UpdateProfile over 1 MiB want 413 payload_too_large. responseStatusCode=%d, err=%v", pStatusCode, cErr)
+	}
+
+	adminUsername := os.Getenv("ADMIN_USERNAME")
+	if adminUsername == "" {
+		t.Logf("[T_0012_004] ADMIN_USERNAME not set. skipping admin audit assertions")
+		return
+	}
+	aRespBody, aStatusCode, cErr := client.AuditEvents(adminUsername, map[string]interface{}{"wallet_id": user0wallet0.Id})
+	if aStatusCode != http.StatusOK {
+		t.Fatalf("[T_0012_004] AuditEvents want 200. responseStatusCode=%d, err=%v", aStatusCode, cErr)
+	}
+	events := aRespBody.Data.Events
+	if len(events) != 2 {
+		t.Fatalf("[T_0012_004] AuditEvents want events.len=2. got %d", len(events))
+	}
+	if events[0].Principal == nil || *events[0].Principal != username1 {
+		t.Fatalf("[T_0012_004] AuditEvents want events[0].principal=%s. got %v", username1, events[0].Principal)
+	}
+	if events[0].Outcome != "rejected" {
+		t.Fatalf("[T_0012_004] AuditEvents want events[0].outcome=rejected. got %s", events[0].Outcome)
+	}
+	if events[0].ErrorCode == nil || *events[0].ErrorCode != "forbidden" {
+		t.Fatalf("[T_0012_004] AuditEvents want events[0].error_code=forbidden. got %v", events[0].ErrorCode)
+	}
+	if events[1].Principal == nil || *events[1].Principal != username0 {
+		t.Fatalf("[T_0012_004] AuditEvents want events[1].principal=%s. got %v", username0, events[1].Principal)
+	}
+	if events[1].ErrorCode == nil || *events[1].ErrorCode != "insufficient_funds" {
+		t.Fatalf("[T_0012_004] AuditEvents want events[1].error_code=insufficient_funds. got %v", events[1].ErrorCode)
+	}
+	if events[1].Route != "POST /wallet/{wallet_id}/withdrawal" {
+		t.Fatalf("[T_0012_004] AuditEvents want events[1].route=%q. got %q", "POST /wallet/{wallet_id}/withdrawal", events[1].Route)
+	}
+}
+
+func T_0013(t *testing.T, client *testclient.Client) {
+	// T_0013_001
+	aliveResponseBody, responseStatusCode, err := client.Alive()
+	if responseStatusCode != http.StatusOK {
+		t.Fatalf("[T_0013_001] Alive want 200. responseStatusCode=%d, err=%v", responseStatusCode, err)
+	}
+	if aliveResponseBody.Data.Status != "alive" {
+		t.Fatalf("[T_0013_001] Alive want status=alive. got status=%s", aliveResponseBody.Data.Status)
+	}
+
+	// T_0013_002
+	readyResponseBody, responseStatusCode, err := client.Ready()
+	if responseStatusCode != http.StatusOK {
+		t.Fatalf("[T_0013_002] Ready want 200. responseStatusCode=%d, err=%v, body=%#v", responseStatusCode, err, readyResponseBody.Data)
+	}
+	if readyResponseBody.Data.Status != "ready" {
+		t.Fatalf("[T_0013_002] Ready want status=ready. got status=%s", readyResponseBody.Data.Status)
+	}
+	checks := readyResponseBody.Data.Checks
+	if checks == nil {
+		t.Fatalf("[T_0013_002] Ready want checks. got nil")
+	}
+	if checks.Database.Status != "up" {
+		t.Fatalf("[T_0013_002] Ready want checks.database.status=up. got %s", checks.Database.Status)
+	}
+	if checks.Migrations.Version != checks.Migrations.ExpectedVersion {
+		t.Fatalf("[T_0013_002] Ready want checks.migrations.version=%d. got %d", checks.Migrations.ExpectedVersion, checks.Migrations.Version)
+	}
+}
+
+func T_0014(t *testing.T, client *testclient.Client) {
+	username0, user0Wallets := SetupUserAndWalletCreation(t, client, "T_0014", []string{"SGD"})
+	username1, user1Wallets := SetupUserAndWalletCreation(t, client, "T_0014", []string{"SGD"})
+	user0wallet0, user1wallet0 := user0Wallets[0], user1Wallets[0]
+
+	// T_0014_001
+	_, statusCode, cErr := client.AdminUser(username0, username1)
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0014_001] AdminUser by customer want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Freeze(username0, username1)
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0014_001] Freeze by customer want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.SetRole(username0, username0, "admin")
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0014_001] SetRole by customer want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Adjustment(username0, user0wallet0.Id, "credit", decimal.NewFromInt(10), "goodwill", "T_0014")
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0014_001] Adjustment by customer want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0014_002
+	_, statusCode, cErr = client.AdminUser(NewRandomUserName("T_0014", 12, 0), username1)
+	if statusCode != http.StatusUnauthorized {
+		t.Fatalf("[T_0014_002] AdminUser by unknown principal want 401. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	adminUsername := os.Getenv("ADMIN_USERNAME")
+	if adminUsername == "" {
+		t.Logf("[T_0014_003] ADMIN_USERNAME not set. skipping admin assertions")
+		return
+	}
+
+	// T_0014_003
+	uRespBody, statusCode, cErr := client.AdminUser(adminUsername, username0)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0014_003] AdminUser want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if uRespBody.Data.User.Role != "customer" || uRespBody.Data.User.Frozen {
+		t.Fatalf("[T_0014_003] AdminUser want role=customer, frozen=false. got %+v", uRespBody.Data.User)
+	}
+
+	// T_0014_004
+	aRespBody, statusCode, cErr := client.Adjustment(adminUsername, user0wallet0.Id, "credit", decimal.NewFromInt(10), "goodwill", "T_0014 goodwill credit")
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0014_004] Adjustment want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if aRespBody.Data.Transaction.Operation != "adjustment" || aRespBody.Data.Transaction.Status != "pending_approval" {
+		t.Fatalf("[T_0014_004] Adjustment want operation=adjustment, status=pending_approval. got %+v", aRespBody.Data.Transaction)
+	}
+	wRespBody, statusCode, cErr := client.Wallets(username0)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0014_004] Wallets want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if wRespBody.Data.Wallets[0].Balance != "0" {
+		t.Fatalf("[T_0014_004] Wallets before approval want balance=0. got %s", wRespBody.Data.Wallets[0].Balance)
+	}
+	_, statusCode, cErr = client.ApproveAdjustment(adminUsername, aRespBody.Data.Transaction.Id)
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0014_004] ApproveAdjustment by maker want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	operatorUsername, _ := SetupUserAndWalletCreation(t, client, "T_0014", []string{})
+	_, statusCode, cErr = client.SetRole(adminUsername, operatorUsername, "operator")
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0014_004] SETUP SetRole operator want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	aRespBody, statusCode, cErr = client.ApproveAdjustment(operatorUsername, aRespBody.Data.Transaction.Id)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0014_004] ApproveAdjustment by operator want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	metaData := aRespBody.Data.Transaction.MetaData
+	if aRespBody.Data.Transaction.Status != "success" || len(aRespBody.Data.Transaction.Ledgers) != 1 ||
+		metaData.ApprovedBy == nil || *metaData.ApprovedBy != operatorUsername || metaData.CreatedBy == nil {
+		t.Fatalf("[T_0014_004] ApproveAdjustment want status=success, 1 ledger, approved_by=%s. got %+v", operatorUsername, aRespBody.Data.Transaction)
+	}
+	wRespBody, statusCode, cErr = client.Wallets(username0)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0014_004] Wallets want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if wRespBody.Data.Wallets[0].Balance != "10" {
+		t.Fatalf("[T_0014_004] Wallets want balance=10. got %s", wRespBody.Data.Wallets[0].Balance)
+	}
+
+	// T_0014_005
+	fRespBody, statusCode, cErr := client.Freeze(adminUsername, username0)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0014_005] Freeze want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if !fRespBody.Data.User.Frozen {
+		t.Fatalf("[T_0014_005] Freeze want frozen=true. got false")
+	}
+	dRespBody, statusCode, cErr := client.Deposit(username0, user0wallet0.Id, decimal.NewFromInt(1))
+	if statusCode != http.StatusForbidden || dRespBody.Code == nil || *dRespBody.Code != "account_frozen" {
+		t.Fatalf("[T_0014_005] Deposit by frozen user want 403 account_frozen. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	tRespBody, statusCode, cErr := client.Transfer(username1, user1wallet0.Id, user0wallet0.Id, decimal.NewFromInt(1))
+	if statusCode != http.StatusForbidden || tRespBody.Code == nil || *tRespBody.Code != "account_frozen" {
+		t.Fatalf("[T_0014_005] Transfer to frozen user want 403 account_frozen. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Wallets(username0)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0014_005] Wallets of frozen user want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0014_006
+	_, statusCode, cErr = client.Unfreeze(adminUsername, username0)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0014_006] Unfreeze want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Withdraw(username0, user0wallet0.Id, decimal.NewFromInt(1))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0014_006] Withdraw after Unfreeze want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0014_007
+	rRespBody, statusCode, cErr := client.SetRole(adminUsername, username1, "support_readonly")
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0014_007] SetRole want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if rRespBody.Data.User.Role != "support_readonly" {
+		t.Fatalf("[T_0014_007] SetRole want role=support_readonly. got %s", rRespBody.Data.User.Role)
+	}
+	_, statusCode, cErr = client.AuditEvents(username1, map[string]interface{}{"wallet_id": user0wallet0.Id})
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0014_007] AuditEvents by support_readonly want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Freeze(username1, username0)
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0014_007] Freeze by support_readonly want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+}
+
+func T_0015(t *testing.T, client *testclient.Client) {
+	username0, _ := SetupUserAndWalletCreation(t, client, "T_0015", []string{"SGD"})
+	username1, _ := SetupUserAndWalletCreation(t, client, "T_0015", []string{"SGD"})
+	futureUserName := NewRandomUserName("T_0015", 12, 2*24*time.Hour)
+
+	// T_0015_001
+	wRespBody, statusCode, cErr := client.WalletsAs(username0, username1)
+	if statusCode != http.StatusNotFound || wRespBody.Code == nil || *wRespBody.Code != "not_found" {
+		t.Fatalf("[T_0015_001] Wallets of other user want 404 not_found. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	tRespBody, statusCode, cErr := client.TransactionsAs(username0, username1)
+	if statusCode != http.StatusNotFound || tRespBody.Code == nil || *tRespBody.Code != "not_found" {
+		t.Fatalf("[T_0015_001] Transactions of other user want 404 not_found. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0015_002
+	wRespBody, statusCode, cErr = client.WalletsAs(username0, futureUserName)
+	if statusCode != http.StatusNotFound || wRespBody.Code == nil || *wRespBody.Code != "not_found" {
+		t.Fatalf("[T_0015_002] Wallets of user yet to be created want 404 not_found. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0015_003
+	_, statusCode, cErr = client.WalletsAs("", username1)
+	if statusCode != http.StatusUnauthorized {
+		t.Fatalf("[T_0015_003] Wallets without principal want 401. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	adminUsername := os.Getenv("ADMIN_USERNAME")
+	if adminUsername == "" {
+		t.Logf("[T_0015_004] ADMIN_USERNAME not set. skipping admin assertions")
+		return
+	}
+
+	// T_0015_004
+	_, statusCode, cErr = client.WalletsAs(adminUsername, username1)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0015_004] Wallets as admin want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.TransactionsAs(adminUsername, username1)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0015_004] Transactions as admin want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	wRespBody, statusCode, cErr = client.WalletsAs(adminUsername, futureUserName)
+	if statusCode != http.StatusNotFound || wRespBody.Code == nil || *wRespBody.Code != "not_found" {
+		t.Fatalf("[T_0015_004] Wallets of user yet to be created as admin want 404 not_found. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+}
+
+func T_0016(t *testing.T, client *testclient.Client) {
+	username0, user0Wallets := SetupUserAndWalletCreation(t, client, "T_0016", []string{"SGD"})
+	username1, user1Wallets := SetupUserAndWalletCreation(t, client, "T_0016", []string{"SGD"})
+	user0wallet0, user1wallet0 := user0Wallets[0], user1Wallets[0]
+
+	if user0wallet0.Status != "active" {
+		t.Fatalf("[T_0016_001] Wallets want status=active. got %s", user0wallet0.Status)
+	}
+
+	// T_0016_001
+	_, statusCode, cErr := client.SetWalletStatus(username0, user1wallet0.Id, "frozen", "T_0016")
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0016_001] SetWalletStatus by customer want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	adminUsername := os.Getenv("ADMIN_USERNAME")
+	if adminUsername == "" {
+		t.Logf("[T_0016_002] ADMIN_USERNAME not set. skipping admin assertions")
+		return
+	}
+
+	// T_0016_002
+	_, statusCode, cErr = client.Deposit(username0, user0wallet0.Id, decimal.NewFromInt(10))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0016_002] SETUP Deposit want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Deposit(username1, user1wallet0.Id, decimal.NewFromInt(10))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0016_002] SETUP Deposit want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	sRespBody, statusCode, cErr := client.SetWalletStatus(adminUsername, user0wallet0.Id, "debit_frozen", "T_0016 suspected takeover")
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0016_002] SetWalletStatus want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if sRespBody.Data.Wallet.Status != "debit_frozen" {
+		t.Fatalf("[T_0016_002] SetWalletStatus want status=debit_frozen. got %s", sRespBody.Data.Wallet.Status)
+	}
+	wRespBody, statusCode, cErr := client.Withdraw(username0, user0wallet0.Id, decimal.NewFromInt(1))
+	if statusCode != http.StatusForbidden || wRespBody.Code == nil || *wRespBody.Code != "wallet_frozen" {
+		t.Fatalf("[T_0016_002] Withdraw from debit_frozen wallet want 403 wallet_frozen. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Transfer(username1, user1wallet0.Id, user0wallet0.Id, decimal.NewFromInt(1))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0016_002] Transfer to debit_frozen wallet want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0016_003
+	_, statusCode, cErr = client.SetWalletStatus(adminUsername, user0wallet0.Id, "frozen", "T_0016 under investigation")
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0016_003] SetWalletStatus want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	dRespBody, statusCode, cErr := client.Deposit(username0, user0wallet0.Id, decimal.NewFromInt(1))
+	if statusCode != http.StatusForbidden || dRespBody.Code == nil || *dRespBody.Code != "wallet_frozen" {
+		t.Fatalf("[T_0016_003] Deposit to frozen wallet want 403 wallet_frozen. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0016_004
+	sRespBody, statusCode, cErr = client.SetWalletStatus(adminUsername, user0wallet0.Id, "closed", "T_0016 customer request")
+	if statusCode != http.StatusUnprocessableEntity || sRespBody.Code == nil || *sRespBody.Code != "wallet_not_empty" {
+		t.Fatalf("[T_0016_004] Close wallet with balance want 422 wallet_not_empty. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0016_005
+	_, statusCode, cErr = client.SetWalletStatus(adminUsername, user0wallet0.Id, "active", "T_0016 cleared")
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0016_005] SetWalletStatus want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Withdraw(username0, user0wallet0.Id, decimal.NewFromInt(11))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0016_005] Withdraw all want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.SetWalletStatus(adminUsername, user0wallet0.Id, "closed", "T_0016 customer request")
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0016_005] Close empty wallet want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	dRespBody, statusCode, cErr = client.Deposit(username0, user0wallet0.Id, decimal.NewFromInt(1))
+	if statusCode != http.StatusUnprocessableEntity || dRespBody.Code == nil || *dRespBody.Code != "wallet_closed" {
+		t.Fatalf("[T_0016_005] Deposit to closed wallet want 422 wallet_closed. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	sRespBody, statusCode, cErr = client.SetWalletStatus(adminUsername, user0wallet0.Id, "active", "T_0016 reopen")
+	if statusCode != http.StatusUnprocessableEntity || sRespBody.Code == nil || *sRespBody.Code != "wallet_closed" {
+		t.Fatalf("[T_0016_005] Reopen closed wallet want 422 wallet_closed. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0016_006
+	hRespBody, statusCode, cErr := client.WalletStatusHistory(adminUsername, user0wallet0.Id)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0016_006] WalletStatusHistory want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	changes := hRespBody.Data.Changes
+	wantToStatuses := []string{"closed", "active", "frozen", "debit_frozen"}
+	if len(changes) != len(wantToStatuses) {
+		t.Fatalf("[T_0016_006] WalletStatusHistory want changes.len=%d. got %d", len(wantToStatuses), len(changes))
+	}
+	for i, want := range wantToStatuses {
+		if changes[i].ToStatus != want || changes[i].Actor != adminUsername {
+			t.Fatalf("[T_0016_006] WalletStatusHistory want changes[%d].to_status=%s, actor=%s. got %+v", i, want, adminUsername, changes[i])
+		}
+	}
+	if changes[0].FromStatus != "active" || changes[0].Reason != "T_0016 customer request" {
+		t.Fatalf("[T_0016_006] WalletStatusHistory want changes[0].from_status=active with reason. got %+v", changes[0])
+	}
+}
+
+func T_0017(t *testing.T, client *testclient.Client) {
+	username0, user0Wallets := SetupUserAndWalletCreation(t, client, "T_0017", []string{"SGD"})
+	username1, _ := SetupUserAndWalletCreation(t, client, "T_0017", []string{"SGD"})
+	user0wallet0 := user0Wallets[0]
+
+	// T_0017_001
+	pRespBody, statusCode, cErr := client.Profile(username0, username0)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0017_001] Profile want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if pRespBody.Data.User.Status != "pending" || pRespBody.Data.User.KycTier != 0 || pRespBody.Data.User.CreatedAt.IsZero() {
+		t.Fatalf("[T_0017_001] Profile want status=pending, kyc_tier=0, created_at. got %+v", pRespBody.Data.User)
+	}
+
+	// T_0017_002
+	email := username0 + "@example.com"
+	pRespBody, statusCode, cErr = client.UpdateProfile(username0, username0, map[string]interface{}{"display_name": "T 0017", "email": email})
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0017_002] UpdateProfile want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if pRespBody.Data.User.Email == nil || *pRespBody.Data.User.Email != email {
+		t.Fatalf("[T_0017_002] UpdateProfile want email=%s. got %v", email, pRespBody.Data.User.Email)
+	}
+	_, statusCode, cErr = client.UpdateProfile(username1, username1, map[string]interface{}{"email": email})
+	if statusCode != http.StatusConflict {
+		t.Fatalf("[T_0017_002] UpdateProfile with email of other user want 409. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.UpdateProfile(username0, username0, map[string]interface{}{"email": "T 0017 <t0017@example.com>"})
+	if statusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0017_002] UpdateProfile with invalid email want 422. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	pRespBody, statusCode, cErr = client.UpdateProfile(username0, username0, map[string]interface{}{"display_name": strings.Repeat("é", 100)})
+	if statusCode != http.StatusOK || pRespBody.Data.User.DisplayName == nil || *pRespBody.Data.User.DisplayName != strings.Repeat("é", 100) {
+		t.Fatalf("[T_0017_002] UpdateProfile with 100 multibyte characters want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.UpdateProfile(username0, username0, map[string]interface{}{"display_name": strings.Repeat("é", 101)})
+	if statusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0017_002] UpdateProfile with 101 multibyte characters want 422. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0017_003
+	_, statusCode, cErr = client.UpdateProfile(username1, username0, map[string]interface{}{"display_name": "T 0017"})
+	if statusCode != http.StatusNotFound {
+		t.Fatalf("[T_0017_003] UpdateProfile of other user want 404. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.UpdateProfile(username0, username0, map[string]interface{}{"status": "verified"})
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0017_003] UpdateProfile own status want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0017_004
+	dRespBody, statusCode, cErr := client.Deposit(username0, user0wallet0.Id, decimal.NewFromFloat(1000.01))
+	if statusCode != http.StatusUnprocessableEntity || dRespBody.Code == nil || *dRespBody.Code != "kyc_limit_exceeded" {
+		t.Fatalf("[T_0017_004] Deposit over tier 0 limit want 422 kyc_limit_exceeded. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	adminUsername := os.Getenv("ADMIN_USERNAME")
+	if adminUsername == "" {
+		t.Logf("[T_0017_005] ADMIN_USERNAME not set. skipping admin assertions")
+		return
+	}
+
+	// T_0017_005
+	_, statusCode, cErr = client.UpdateProfile(adminUsername, username0, map[string]interface{}{"kyc_tier": 1})
+	if statusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0017_005] UpdateProfile kyc_tier of pending user want 422. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	pRespBody, statusCode, cErr = client.UpdateProfile(adminUsername, username0, map[string]interface{}{"status": "verified", "kyc_tier": 1})
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0017_005] UpdateProfile want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if pRespBody.Data.User.Status != "verified" || pRespBody.Data.User.KycTier != 1 {
+		t.Fatalf("[T_0017_005] UpdateProfile want status=verified, kyc_tier=1. got %+v", pRespBody.Data.User)
+	}
+	_, statusCode, cErr = client.Deposit(username0, user0wallet0.Id, decimal.NewFromInt(5000))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0017_005] Deposit within tier 1 limit want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0017_006
+	_, statusCode, cErr = client.UpdateProfile(adminUsername, username0, map[string]interface{}{"status": "suspended"})
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0017_006] UpdateProfile want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	wRespBody, statusCode, cErr := client.Withdraw(username0, user0wallet0.Id, decimal.NewFromInt(1))
+	if statusCode != http.StatusForbidden || wRespBody.Code == nil || *wRespBody.Code != "account_suspended" {
+		t.Fatalf("[T_0017_006] Withdraw by suspended user want 403 account_suspended. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Profile(username0, username0)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0017_006] Profile of suspended user want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+}
+
+func T_0018(t *testing.T, client *testclient.Client) {
+	username0, user0Wallets := SetupUserAndWalletCreation(t, client, "T_0018", []string{"SGD"})
+
+	// T_0018_001
+	for _, username := range []string{"T_0018 " + username0, "T_0018:" + username0, "ab", "admin", "System"} {
+		cRespBody, statusCode, cErr := client.CreateUser(username)
+		if statusCode != http.StatusUnprocessableEntity || cRespBody.Code == nil || *cRespBody.Code != "invalid_argument" {
+			t.Fatalf("[T_0018_001] CreateUser %q want 422 invalid_argument. responseStatusCode=%d, err=%v", username, statusCode, cErr)
+		}
+	}
+
+	// T_0018_002
+	for _, username := range []string{strings.ToUpper(username0), strings.ToLower(username0)} {
+		cRespBody, statusCode, cErr := client.CreateUser(username)
+		if statusCode != http.StatusConflict || cRespBody.Code == nil || *cRespBody.Code != "already_exists" {
+			t.Fatalf("[T_0018_002] CreateUser case variant %q want 409 already_exists. responseStatusCode=%d, err=%v", username, statusCode, cErr)
+		}
+	}
+
+	// T_0018_003
+	pRespBody, statusCode, cErr := client.Profile(strings.ToLower(username0), strings.ToUpper(username0))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0018_003] Profile by case variants want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if pRespBody.Data.User.Username != username0 {
+		t.Fatalf("[T_0018_003] Profile want username as registered %s. got %s", username0, pRespBody.Data.User.Username)
+	}
+	_, statusCode, cErr = client.Deposit(strings.ToUpper(username0), user0Wallets[0].Id, decimal.NewFromInt(1))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0018_003] Deposit by case variant of owner want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+}
+
+func T_0019(t *testing.T, client *testclient.Client) {
+	username0, user0Wallets := SetupUserAndWalletCreation(t, client, "T_0019", []string{"SGD"})
+	username1, _ := SetupUserAndWalletCreation(t, client, "T_0019", []string{"SGD"})
+	user0wallet0 := user0Wallets[0]
+
+	_, statusCode, cErr := client.Deposit(username0, user0wallet0.Id, decimal.NewFromInt(100))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0019] SETUP Deposit want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Withdraw(username0, user0wallet0.Id, decimal.RequireFromString("30.5"))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0019] SETUP Withdraw want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0019_001
+	sRespBody, statusCode, cErr := client.Statement(username0, user0wallet0.Id, nil)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0019_001] Statement want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	statement := sRespBody.Data.Statement
+	if statement.OpeningBalance != "0" || statement.TotalCredits != "100" || statement.TotalDebits != "30.5" || statement.ClosingBalance != "69.5" {
+		t.Fatalf("[T_0019_001] Statement want opening=0, credits=100, debits=30.5, closing=69.5. got %+v", statement)
+	}
+	if len(statement.Ledgers) != 2 || statement.Ledgers[0].Balance != "100" || statement.Ledgers[1].Balance != "69.5" {
+		t.Fatalf("[T_0019_001] Statement want ledgers oldest first with running balance [100, 69.5]. got %+v", statement.Ledgers)
+	}
+
+	// T_0019_002
+	sRespBody, statusCode, cErr = client.Statement(username0, user0wallet0.Id, map[string]interface{}{
+		"from": "2000-01-01T00:00:00Z",
+		"to":   "2000-02-01T00:00:00Z",
+	})
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0019_002] Statement of period before wallet creation want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	statement = sRespBody.Data.Statement
+	if len(statement.Ledgers) != 0 || statement.OpeningBalance != "0" || statement.ClosingBalance != "0" {
+		t.Fatalf("[T_0019_002] Statement of period before wallet creation want no ledgers, zero balances. got %+v", statement)
+	}
+	sRespBody, statusCode, cErr = client.Statement(username0, user0wallet0.Id, map[string]interface{}{
+		"from": time.Now().Add(time.Hour).Format(time.RFC3339),
+	})
+	if statusCode != http.StatusUnprocessableEntity || sRespBody.Code == nil || *sRespBody.Code != "invalid_argument" {
+		t.Fatalf("[T_0019_002] Statement from future want 422 invalid_argument. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0019_003
+	sRespBody, statusCode, cErr = client.Statement(username1, user0wallet0.Id, nil)
+	if statusCode != http.StatusNotFound || sRespBody.Code == nil || *sRespBody.Code != "not_found" {
+		t.Fatalf("[T_0019_003] Statement of other user's wallet want 404 not_found. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+}
+
+func T_0020(t *testing.T, client *testclient.Client) {
+	username0, user0Wallets := SetupUserAndWalletCreation(t, client, "T_0020", []string{"SGD"})
+	username1, _ := SetupUserAndWalletCreation(t, client, "T_0020", []string{"SGD"})
+	user0wallet0 := user0Wallets[0]
+
+	_, statusCode, cErr := client.Deposit(username0, user0wallet0.Id, decimal.NewFromInt(100))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0020] SETUP Deposit want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Withdraw(username0, user0wallet0.Id, decimal.RequireFromString("30.5"))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0020] SETUP Withdraw want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0020_001
+	tRespBody, statusCode, cErr := client.Transactions(username0)
+	if statusCode != http.StatusOK || len(tRespBody.Data.Transactions) != 2 {
+		t.Fatalf("[T_0020_001] Transactions want 200 with 2 transactions. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	for _, transaction := range tRespBody.Data.Transactions {
+		if len(transaction.Ledgers) != 1 || transaction.Ledgers[0].WalletId != user0wallet0.Id {
+			t.Fatalf("[T_0020_001] Transactions want ledger wallet_id=%d. got %+v", user0wallet0.Id, transaction.Ledgers)
+		}
+	}
+
+	// T_0020_002
+	body, contentType, statusCode, cErr := client.TransactionsExport(username0, username0, "text/csv")
+	if statusCode != http.StatusOK || !strings.HasPrefix(contentType, "text/csv") {
+		t.Fatalf("[T_0020_002] Transactions as csv want 200 text/csv. responseStatusCode=%d, contentType=%s, err=%v", statusCode, contentType, cErr)
+	}
+	records, err := csv.NewReader(bytes.NewReader(body)).ReadAll()
+	if err != nil || len(records) != 3 {
+		t.Fatalf("[T_0020_002] Transactions as csv want header and 2 rows. got %q, err=%v", body, err)
+	}
+	if strings.Join(records[0], ",") != "transaction_id,transaction_created_at,requestor_id,nonce,operation,status,ledger_id,wallet_id,entry_type,amount,balance,ledger_created_at" {
+		t.Fatalf("[T_0020_002] Transactions as csv unexpected header %v", records[0])
+	}
+	if records[1][4] != "withdraw" || records[1][9] != "30.500000" || records[1][10] != "69.500000" || records[2][9] != "100.000000" {
+		t.Fatalf("[T_0020_002] Transactions as csv want newest first with amounts 30.500000, 100.000000. got %v", records[1:])
+	}
+
+	// T_0020_003
+	body, contentType, statusCode, cErr = client.TransactionsExport(username0, username0, "application/x-ndjson")
+	if statusCode != http.StatusOK || !strings.HasPrefix(contentType, "application/x-ndjson") {
+		t.Fatalf("[T_0020_003] Transactions as ndjson want 200 application/x-ndjson. responseStatusCode=%d, contentType=%s, err=%v", statusCode, contentType, cErr)
+	}
+	lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("[T_0020_003] Transactions as ndjson want 2 lines. got %q", body)
+	}
+	for _, line := range lines {
+		var row map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &row); err != nil || row["wallet_id"] != float64(user0wallet0.Id) {
+			t.Fatalf("[T_0020_003] Transactions as ndjson want wallet_id=%d. got %s, err=%v", user0wallet0.Id, line, err)
+		}
+	}
+
+	// T_0020_004
+	_, contentType, statusCode, cErr = client.TransactionsExport(username1, username0, "text/csv")
+	if statusCode != http.StatusNotFound || contentType != "application/problem+json" {
+		t.Fatalf("[T_0020_004] Transactions of other user as csv want 404 problem. responseStatusCode=%d, contentType=%s, err=%v", statusCode, contentType, cErr)
+	}
+}
+
+func T_0021(t *testing.T, client *testclient.Client) {
+	username0, user0Wallets := SetupUserAndWalletCreation(t, client, "T_0021", []string{"SGD"})
+	username1, _ := SetupUserAndWalletCreation(t, client, "T_0021", []string{"SGD"})
+	user0wallet0 := user0Wallets[0]
+
+	dRespBody, statusCode, cErr := client.Deposit(username0, user0wallet0.Id, decimal.NewFromInt(100))
+	if statusCode != http.StatusOK || len(dRespBody.Data.Transaction.Ledgers) != 1 {
+		t.Fatalf("[T_0021] SETUP Deposit want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	depositedAt := dRespBody.Data.Transaction.Ledgers[0].CreatedAt
+	_, statusCode, cErr = client.Withdraw(username0, user0wallet0.Id, decimal.RequireFromString("30.5"))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0021] SETUP Withdraw want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0021_001
+	for at, want := range map[string]string{
+		"":                                   "69.5",
+		depositedAt.Format(time.RFC3339Nano): "100",
+		depositedAt.Add(-time.Millisecond).Format(time.RFC3339Nano): "0",
+	} {
+		bRespBody, statusCode, cErr := client.Balance(username0, user0wallet0.Id, at)
+		if statusCode != http.StatusOK || bRespBody.Data.Wallet.Balance != want {
+			t.Fatalf("[T_0021_001] Balance at %q want 200 balance=%s. got %+v, responseStatusCode=%d, err=%v", at, want, bRespBody.Data, statusCode, cErr)
+		}
+	}
+
+	// T_0021_002
+	wRespBody, statusCode, cErr := client.WalletsAt(username0, username0, depositedAt.Format(time.RFC3339Nano))
+	if statusCode != http.StatusOK || len(wRespBody.Data.Wallets) != 1 || wRespBody.Data.Wallets[0].Balance != "100" {
+		t.Fatalf("[T_0021_002] Wallets at deposit want 200 balance=100. got %+v, responseStatusCode=%d, err=%v", wRespBody.Data, statusCode, cErr)
+	}
+
+	// T_0021_003
+	bRespBody, statusCode, cErr := client.Balance(username0, user0wallet0.Id, time.Now().Add(time.Hour).Format(time.RFC3339))
+	if statusCode != http.StatusUnprocessableEntity || bRespBody.Code == nil || *bRespBody.Code != "invalid_argument" {
+		t.Fatalf("[T_0021_003] Balance in the future want 422 invalid_argument. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	bRespBody, statusCode, cErr = client.Balance(username0, user0wallet0.Id, "yesterday")
+	if statusCode != http.StatusBadRequest || bRespBody.Code == nil || *bRespBody.Code != "bad_request" {
+		t.Fatalf("[T_0021_003] Balance at malformed timestamp want 400 bad_request. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0021_004
+	bRespBody, statusCode, cErr = client.Balance(username1, user0wallet0.Id, "")
+	if statusCode != http.StatusNotFound || bRespBody.Code == nil || *bRespBody.Code != "not_found" {
+		t.Fatalf("[T_0021_004] Balance of other user's wallet want 404 not_found. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+}
+
+// webhookReceiver
+// Records signed deliveries. Responds status, 200 unless set.
+type webhookReceiver struct {
+	mu         sync.Mutex
+	secret     string
+	status     int
+	deliveries []webhookDelivery
+}
+
+type webhookDelivery struct {
+	Id    string
+	Event struct {
+		Type string          `json:"type"`
+		Data json.RawMessage `json:"data"`
+	}
+	SignatureErr error
+}
+
+func (wr *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+	d := webhookDelivery{Id: r.Header.Get(webhookservice.DeliveryHeader)}
+	d.SignatureErr = webhookservice.Verify(wr.secret, r.Header.Get(webhookservice.SignatureHeader), body, time.Now(), 5*time.Minute)
+	json.Unmarshal(body, &d.Event)
+	wr.deliveries = append(wr.deliveries, d)
+	if wr.status != 0 {
+		w.WriteHeader(wr.status)
+	}
+}
+
+// await
+// Deliveries of eventType received within 15s, at least n.
+func (wr *webhookReceiver) await(eventType string, n int) []webhookDelivery {
+	deadline := time.Now().Add(15 * time.Second)
+	for {
+		wr.mu.Lock()
+		var found []webhookDelivery
+		for _, d := range wr.deliveries {
+			if d.Event.Type == eventType {
+				found = append(found, d)
+			}
+		}
+		wr.mu.Unlock()
+		if len(found) >= n || time.Now().After(deadline) {
+			return found
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func T_0022(t *testing.T, client *testclient.Client) {
+	username0, user0Wallets := SetupUserAndWalletCreation(t, client, "T_0022", []string{"SGD"})
+	username1, _ := SetupUserAndWalletCreation(t, client, "T_0022", []string{"SGD"})
+	user0wallet0 := user0Wallets[0]
+
+	receiver := &webhookReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	// T_0022_001
+	cRespBody, statusCode, cErr := client.CreateWebhook(username0, username0, server.URL, []string{"transaction.succeeded", "transaction.failed", "balance.changed"})
+	if statusCode != http.StatusOK || !strings.HasPrefix(cRespBody.Data.Webhook.Secret, "whsec_") {
+		t.Fatalf("[T_0022_001] CreateWebhook want 200 with secret. got %+v, responseStatusCode=%d, err=%v", cRespBody.Data, statusCode, cErr)
+	}
+	webhook := cRespBody.Data.Webhook
+	receiver.mu.Lock()
+	receiver.secret = webhook.Secret
+	receiver.mu.Unlock()
+
+	// T_0022_002
+	_, statusCode, cErr = client.Deposit(username0, user0wallet0.Id, decimal.NewFromInt(100))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0022_002] Deposit want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	succeeded := receiver.await("transaction.succeeded", 1)
+	changed := receiver.await("balance.changed", 1)
+	if len(succeeded) != 1 || len(changed) != 1 {
+		t.Fatalf("[T_0022_002] want 1 transaction.succeeded and 1 balance.changed. got %d, %d", len(succeeded), len(changed))
+	}
+	var balance struct {
+		WalletId int64  `json:"wallet_id"`
+		Balance  string `json:"balance"`
+	}
+	json.Unmarshal(changed[0].Event.Data, &balance)
+	if changed[0].SignatureErr != nil || balance.WalletId != user0wallet0.Id || balance.Balance != "100" {
+		t.Fatalf("[T_0022_002] balance.changed want signed wallet_id=%d balance=100. got %+v, %s", user0wallet0.Id, changed[0], changed[0].Event.Data)
+	}
+
+	// T_0022_003
+	_, statusCode, cErr = client.Withdraw(username0, user0wallet0.Id, decimal.NewFromInt(1000))
+	if statusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0022_003] Withdraw over balance want 422. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	failed := receiver.await("transaction.failed", 1)
+	var transaction struct {
+		Status string `json:"status"`
+	}
+	if len(failed) == 1 {
+		json.Unmarshal(failed[0].Event.Data, &transaction)
+	}
+	if len(failed) != 1 || failed[0].SignatureErr != nil || transaction.Status != "error_insufficient_funds" {
+		t.Fatalf("[T_0022_003] want 1 signed transaction.failed status=error_insufficient_funds. got %+v", failed)
+	}
+
+	// T_0022_004
+	receiver.mu.Lock()
+	receiver.status = http.StatusServiceUnavailable
+	receiver.mu.Unlock()
+	_, statusCode, cErr = client.Deposit(username0, user0wallet0.Id, decimal.NewFromInt(1))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0022_004] Deposit want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if n := len(receiver.await("balance.changed", 2)); n != 2 {
+		t.Fatalf("[T_0022_004] want balance.changed attempted. got %d", n)
+	}
+	receiver.mu.Lock()
+	receiver.status = 0
+	receiver.mu.Unlock()
+	var retried testclient.WebhookDelivery
+	for deadline := time.Now().Add(5 * time.Second); retried.Id == 0 && time.Now().Before(deadline); time.Sleep(100 * time.Millisecond) {
+		dRespBody, _, _ := client.WebhookDeliveries(username0, username0, webhook.Id)
+		for _, d := range dRespBody.Data.Deliveries {
+			if d.EventType == "balance.changed" && d.LastError != nil && *d.LastError == "http status 503" {
+				retried = d
+				break
+			}
+		}
+	}
+	if retried.Id == 0 || retried.Status != "pending" {
+		t.Fatalf("[T_0022_004] Deliveries want failed balance.changed pending retry. got %+v", retried)
+	}
+	rRespBody, statusCode, cErr := client.ReplayWebhookDelivery(username0, username0, retried.Id)
+	if statusCode != http.StatusOK || rRespBody.Data.Delivery.Attempts != 0 || rRespBody.Data.Delivery.Status != "pending" {
+		t.Fatalf("[T_0022_004] ReplayWebhookDelivery want 200 pending with 0 attempts. got %+v, responseStatusCode=%d, err=%v", rRespBody.Data, statusCode, cErr)
+	}
+	if n := len(receiver.await("balance.changed", 3)); n < 3 {
+		t.Fatalf("[T_0022_004] want replayed balance.changed. got %d", n)
+	}
+
+	// T_0022_005
+	cRespBody, statusCode, cErr = client.CreateWebhook(username1, username0, server.URL, []string{"balance.changed"})
+	if statusCode != http.StatusNotFound || cRespBody.Code == nil || *cRespBody.Code != "not_found" {
+		t.Fatalf("[T_0022_005] CreateWebhook for other user want 404 not_found. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	cRespBody, statusCode, cErr = client.CreateWebhook(username0, username0, "ftp://example.com", []string{"balance.changed"})
+	if statusCode != http.StatusUnprocessableEntity || cRespBody.Code == nil || *cRespBody.Code != "invalid_argument" {
+		t.Fatalf("[T_0022_005] CreateWebhook with ftp url want 422 invalid_argument. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	cRespBody, statusCode, cErr = client.CreateWebhook(username0, username0, server.URL, []string{"wallet.created"})
+	if statusCode != http.StatusUnprocessableEntity || cRespBody.Code == nil || *cRespBody.Code != "invalid_argument" {
+		t.Fatalf("[T_0022_005] CreateWebhook with unknown event type want 422 invalid_argument. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0022_006
+	_, statusCode, cErr = client.DeleteWebhook(username0, username0, webhook.Id)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0022_006] DeleteWebhook want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	wRespBody, statusCode, cErr := client.Webhooks(username0, username0)
+	if statusCode != http.StatusOK || len(wRespBody.Data.Webhooks) != 0 {
+		t.Fatalf("[T_0022_006] Webhooks after delete want none. got %+v, responseStatusCode=%d, err=%v", wRespBody.Data, statusCode, cErr)
+	}
+}
+
+// awaitEvent
+// Next event named event of the stream, skipping others, or false after 5 seconds.
+func awaitEvent(stream *testclient.EventStream, event string) (testclient.ServerSentEvent, bool) {
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		e, ok := stream.Next(time.Until(deadline))
+		if !ok || e.Event == event {
+			return e, ok
+		}
+	}
+}
+
+func T_0023(t *testing.T, client *testclient.Client) {
+	username0, user0Wallets := SetupUserAndWalletCreation(t, client, "T_0023", []string{"SGD"})
+	username1, _ := SetupUserAndWalletCreation(t, client, "T_0023", []string{"SGD"})
+	user0wallet0 := user0Wallets[0]
+
+	type ledger struct {
+		Id       int64  `json:"id"`
+		WalletId int64  `json:"wallet_id"`
+		Balance  string `json:"balance"`
+	}
+	type transaction struct {
+		Id     int64  `json:"id"`
+		Status string `json:"status"`
+	}
+
+	// T_0023_001
+	stream, statusCode, cErr := client.Events(username0, username0, "")
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0023_001] Events want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Deposit(username0, user0wallet0.Id, decimal.NewFromInt(100))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0023_001] Deposit want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	e, ok := awaitEvent(stream, "ledger")
+	var l ledger
+	json.Unmarshal([]byte(e.Data), &l)
+	if !ok || e.Id != strconv.FormatInt(l.Id, 10) || l.WalletId != user0wallet0.Id || l.Balance != "100" {
+		t.Fatalf("[T_0023_001] want ledger event with id wallet_id=%d balance=100. got %+v", user0wallet0.Id, e)
+	}
+	firstLedgerId := e.Id
+	var succeeded bool
+	for !succeeded {
+		e, ok := awaitEvent(stream, "transaction")
+		if !ok {
+			t.Fatalf("[T_0023_001] want transaction event status=success")
+		}
+		var tx transaction
+		json.Unmarshal([]byte(e.Data), &tx)
+		succeeded = tx.Status == "success"
+	}
+	stream.Close()
+
+	// T_0023_002
+	_, statusCode, cErr = client.Deposit(username0, user0wallet0.Id, decimal.NewFromInt(50))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0023_002] Deposit want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	stream, statusCode, cErr = client.Events(username0, username0, firstLedgerId)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0023_002] Events with Last-Event-ID want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	e, ok = awaitEvent(stream, "ledger")
+	l = ledger{}
+	json.Unmarshal([]byte(e.Data), &l)
+	if !ok || e.Id == firstLedgerId || l.Balance != "150" {
+		t.Fatalf("[T_0023_002] want replayed ledger event balance=150. got %+v", e)
+	}
+	stream.Close()
+
+	// T_0023_003
+	_, statusCode, cErr = client.Events(username1, username0, "")
+	if statusCode != http.StatusNotFound {
+		t.Fatalf("[T_0023_003] Events of other user want 404. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Events(username0, username0, "abc")
+	if statusCode != http.StatusBadRequest {
+		t.Fatalf("[T_0023_003] Events with invalid Last-Event-ID want 400. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+}
+
+func T_0024(t *testing.T, client *testclient.Client) {
+	username0, user0Wallets := SetupUserAndWalletCreation(t, client, "T_0024", []string{"SGD"})
+	user0wallet0 := user0Wallets[0]
+
+	// T_0024_001
+	_, statusCode, cErr := client.Reconciliation(username0)
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0024_001] Reconciliation by customer want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.PendingTransactions(username0, nil)
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0024_001] PendingTransactions by customer want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	adminUsername := os.Getenv("ADMIN_USERNAME")
+	if adminUsername == "" {
+		t.Logf("[T_0024_002] ADMIN_USERNAME not set. skipping admin assertions")
+		return
+	}
+
+	// T_0024_002
+	_, statusCode, cErr = client.Deposit(username0, user0wallet0.Id, decimal.NewFromInt(100))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0024_002] SETUP Deposit want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Withdraw(username0, user0wallet0.Id, decimal.NewFromInt(1000))
+	if statusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0024_002] SETUP Withdraw want 422. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	rRespBody, statusCode, cErr := client.Reconciliation(adminUsername)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0024_002] Reconciliation want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if rRespBody.Data.Wallets < 1 || rRespBody.Data.Transactions < 2 {
+		t.Fatalf("[T_0024_002] Reconciliation want wallets>=1 transactions>=2. got %+v", rRespBody.Data)
+	}
+	if len(rRespBody.Data.WalletDiscrepancies) > 0 || len(rRespBody.Data.TransactionDiscrepancies) > 0 {
+		t.Fatalf("[T_0024_002] Reconciliation want no discrepancies. got %+v", rRespBody.Data)
+	}
+
+	// T_0024_003
+	pRespBody, statusCode, cErr := client.PendingTransactions(adminUsername, map[string]interface{}{"older_than": "1h"})
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0024_003] PendingTransactions want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	for _, transaction := range pRespBody.Data.Transactions {
+		if transaction.Status != "pending" || time.Since(transaction.CreatedAt) < time.Hour {
+			t.Fatalf("[T_0024_003] PendingTransactions want pending older than 1h. got %+v", transaction)
+		}
+	}
+	_, statusCode, cErr = client.PendingTransactions(adminUsername, map[string]interface{}{"older_than": "abc"})
+	if statusCode != http.StatusBadRequest {
+		t.Fatalf("[T_0024_003] PendingTransactions with invalid older_than want 400. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.PendingTransactions(adminUsername, map[string]interface{}{"limit": 5000})
+	if statusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0024_003] PendingTransactions with limit over max want 422. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+}
+
+func T_0025(t *testing.T, client *testclient.Client) {
+	username0, user0Wallets := SetupUserAndWalletCreation(t, client, "T_0025", []string{"SGD"})
+	user0wallet0 := user0Wallets[0]
+
+	// T_0025_001
+	_, statusCode, cErr := client.PendingAdjustments(username0, nil)
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0025_001] PendingAdjustments by customer want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	adminUsername := os.Getenv("ADMIN_USERNAME")
+	if adminUsername == "" {
+		t.Logf("[T_0025_002] ADMIN_USERNAME not set. skipping admin assertions")
+		return
+	}
+
+	// T_0025_002
+	aRespBody, statusCode, cErr := client.Adjustment(adminUsername, user0wallet0.Id, "credit", decimal.NewFromInt(5), "", "T_0025 missing reason code")
+	if statusCode != http.StatusUnprocessableEntity || aRespBody.Code == nil || *aRespBody.Code != "invalid_argument" {
+		t.Fatalf("[T_0025_002] Adjustment without reason_code want 422 invalid_argument. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Adjustment(adminUsername, user0wallet0.Id, "credit", decimal.NewFromInt(5), "bonus", "T_0025 unknown reason code")
+	if statusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0025_002] Adjustment with unknown reason_code want 422. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0025_003
+	aRespBody, statusCode, cErr = client.Adjustment(adminUsername, user0wallet0.Id, "debit", decimal.NewFromInt(5), "correction", "T_0025 duplicate credit")
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0025_003] Adjustment want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	adjustment := aRespBody.Data.Transaction
+	pRespBody, statusCode, cErr := client.PendingAdjustments(adminUsername, map[string]interface{}{"limit": 1000})
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0025_003] PendingAdjustments want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if !slices.ContainsFunc(pRespBody.Data.Transactions, func(tr testclient.Transaction) bool { return tr.Id == adjustment.Id }) {
+		t.Fatalf("[T_0025_003] PendingAdjustments want adjustment %d. got %+v", adjustment.Id, pRespBody.Data.Transactions)
+	}
+
+	// T_0025_004
+	aRespBody, statusCode, cErr = client.RejectAdjustment(adminUsername, adjustment.Id)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0025_004] RejectAdjustment by maker want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if aRespBody.Data.Transaction.Status != "rejected" || len(aRespBody.Data.Transaction.Ledgers) != 0 ||
+		aRespBody.Data.Transaction.MetaData.RejectedBy == nil {
+		t.Fatalf("[T_0025_004] RejectAdjustment want status=rejected, no ledgers, rejected_by. got %+v", aRespBody.Data.Transaction)
+	}
+	operatorUsername, _ := SetupUserAndWalletCreation(t, client, "T_0025", []string{})
+	_, statusCode, cErr = client.SetRole(adminUsername, operatorUsername, "operator")
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0025_004] SETUP SetRole operator want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.ApproveAdjustment(operatorUsername, adjustment.Id)
+	if statusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0025_004] ApproveAdjustment of rejected want 422. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0025_005
+	aRespBody, statusCode, cErr = client.Adjustment(adminUsername, user0wallet0.Id, "debit", decimal.NewFromInt(5), "correction", "T_0025 debit over balance")
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0025_005] Adjustment want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	aRespBody, statusCode, cErr = client.ApproveAdjustment(operatorUsername, aRespBody.Data.Transaction.Id)
+	if statusCode != http.StatusUnprocessableEntity || aRespBody.Code == nil || *aRespBody.Code != "insufficient_funds" {
+		t.Fatalf("[T_0025_005] ApproveAdjustment of debit over balance want 422 insufficient_funds. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.ApproveAdjustment(operatorUsername, 0)
+	if statusCode != http.StatusNotFound {
+		t.Fatalf("[T_0025_005] ApproveAdjustment of unknown transaction want 404. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+}
+
+func T_0026(t *testing.T, client *testclient.Client) {
+	username0, user0Wallets := SetupUserAndWalletCreation(t, client, "T_0026", []string{"SGD"})
+	user0wallet0 := user0Wallets[0]
+	_, user1Wallets := SetupUserAndWalletCreation(t, client, "T_0026", []string{"SGD"})
+	user1wallet0 := user1Wallets[0]
+
+	// T_0026_001
+	_, statusCode, cErr := client.ApprovalPolicy(username0, user0wallet0.Id)
+	if statusCode != http.StatusNotFound {
+		t.Fatalf("[T_0026_001] ApprovalPolicy without policy want 404. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.SetApprovalPolicy(username0, user0wallet0.Id, decimal.NewFromInt(50), 1, 3600, []string{username0})
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0026_001] SetApprovalPolicy by customer want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	adminUsername := os.Getenv("ADMIN_USERNAME")
+	if adminUsername == "" {
+		t.Logf("[T_0026_002] ADMIN_USERNAME not set. skipping admin assertions")
+		return
+	}
+
+	// T_0026_002
+	approver1, _ := SetupUserAndWalletCreation(t, client, "T_0026", []string{})
+	approver2, _ := SetupUserAndWalletCreation(t, client, "T_0026", []string{})
+	approver3, _ := SetupUserAndWalletCreation(t, client, "T_0026", []string{})
+	_, statusCode, cErr = client.SetApprovalPolicy(adminUsername, user0wallet0.Id, decimal.NewFromInt(50), 4, 3600, []string{approver1, approver2, approver3})
+	if statusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0026_002] SetApprovalPolicy with required_approvals over approvers want 422. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	pRespBody, statusCode, cErr := client.SetApprovalPolicy(adminUsername, user0wallet0.Id, decimal.NewFromInt(50), 2, 3600, []string{approver1, approver2, approver3, approver1})
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0026_002] SetApprovalPolicy want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if len(pRespBody.Data.Approvers) != 3 || pRespBody.Data.RequiredApprovals != 2 || pRespBody.Data.UpdatedBy == "" {
+		t.Fatalf("[T_0026_002] SetApprovalPolicy want 3 approvers, required_approvals=2, updated_by. got %+v", pRespBody.Data.ApprovalPolicy)
+	}
+	pRespBody, statusCode, cErr = client.ApprovalPolicy(username0, user0wallet0.Id)
+	if statusCode != http.StatusOK || pRespBody.Data.Threshold != "50" {
+		t.Fatalf("[T_0026_002] ApprovalPolicy by owner want 200 threshold=50. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0026_003
+	_, statusCode, cErr = client.Deposit(username0, user0wallet0.Id, decimal.NewFromInt(100))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0026_003] SETUP Deposit want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	tRespBody, statusCode, cErr := client.Transfer(username0, user0wallet0.Id, user1wallet0.Id, decimal.NewFromInt(10))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0026_003] Transfer under threshold want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if len(tRespBody.Data.Transaction.Ledgers) != 2 {
+		t.Fatalf("[T_0026_003] Transfer under threshold want 2 ledgers. got %+v", tRespBody.Data.Transaction)
+	}
+
+	// T_0026_004
+	tRespBody, statusCode, cErr = client.Transfer(username0, user0wallet0.Id, user1wallet0.Id, decimal.NewFromInt(60))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0026_004] Transfer over threshold want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	transfer := tRespBody.Data.Transaction
+	if transfer.Status != "pending_approval" || len(transfer.Ledgers) != 0 || len(transfer.MetaData.Approvers) != 3 ||
+		transfer.MetaData.ApprovalsRequired == nil || *transfer.MetaData.ApprovalsRequired != 2 || transfer.MetaData.ExpiresAt == nil {
+		t.Fatalf("[T_0026_004] Transfer over threshold want pending_approval without ledgers, approvers, approvals_required=2, expires_at. got %+v", transfer)
+	}
+	aRespBody, statusCode, cErr := client.PendingApprovals(approver1, approver1)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0026_004] PendingApprovals want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if !slices.ContainsFunc(aRespBody.Data.Transactions, func(tr testclient.Transaction) bool { return tr.Id == transfer.Id }) {
+		t.Fatalf("[T_0026_004] PendingApprovals want transfer %d. got %+v", transfer.Id, aRespBody.Data.Transactions)
+	}
+
+	// T_0026_005
+	_, statusCode, cErr = client.ApproveTransfer(username0, transfer.Id)
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0026_005] ApproveTransfer by requestor want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	dRespBody, statusCode, cErr := client.ApproveTransfer(approver1, transfer.Id)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0026_005] ApproveTransfer want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if dRespBody.Data.Transaction.Status != "pending_approval" || len(dRespBody.Data.Approvals) != 1 {
+		t.Fatalf("[T_0026_005] ApproveTransfer before quorum want pending_approval with 1 approval. got %+v", dRespBody.Data)
+	}
+	_, statusCode, cErr = client.ApproveTransfer(approver1, transfer.Id)
+	if statusCode != http.StatusConflict {
+		t.Fatalf("[T_0026_005] ApproveTransfer twice want 409. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0026_006
+	dRespBody, statusCode, cErr = client.ApproveTransfer(approver2, transfer.Id)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0026_006] ApproveTransfer reaching quorum want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if dRespBody.Data.Transaction.Status != "success" || len(dRespBody.Data.Transaction.Ledgers) != 2 || len(dRespBody.Data.Approvals) != 2 {
+		t.Fatalf("[T_0026_006] ApproveTransfer reaching quorum want success with 2 ledgers and 2 approvals. got %+v", dRespBody.Data)
+	}
+	_, statusCode, cErr = client.ApproveTransfer(approver3, transfer.Id)
+	if statusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0026_006] ApproveTransfer of executed transfer want 422. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0026_007
+	tRespBody, statusCode, cErr = client.Transfer(username0, user0wallet0.Id, user1wallet0.Id, decimal.NewFromInt(51))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0026_007] Transfer over threshold want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	dRespBody, statusCode, cErr = client.RejectTransfer(approver3, tRespBody.Data.Transaction.Id)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0026_007] RejectTransfer want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if dRespBody.Data.Transaction.Status != "rejected" || len(dRespBody.Data.Transaction.Ledgers) != 0 {
+		t.Fatalf("[T_0026_007] RejectTransfer want rejected without ledgers. got %+v", dRespBody.Data)
+	}
+	wRespBody, statusCode, cErr := client.Wallets(username0)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0026_007] Wallets want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if balance := wRespBody.Data.Wallets[0].Balance; balance != "30" {
+		t.Fatalf("[T_0026_007] Wallets want balance 30 after executed transfers only. got %s", balance)
+	}
+}
+
+func T_0027(t *testing.T, client *testclient.Client) {
+	username0, user0Wallets := SetupUserAndWalletCreation(t, client, "T_0027", []string{"SGD"})
+	user0wallet0 := user0Wallets[0]
+	spender, _ := SetupUserAndWalletCreation(t, client, "T_0027", []string{})
+	viewer, _ := SetupUserAndWalletCreation(t, client, "T_0027", []string{})
+	stranger, _ := SetupUserAndWalletCreation(t, client, "T_0027", []string{})
+
+	// T_0027_001
+	cRespBody, statusCode, cErr := client.CreateWallet(username0, "SGD")
+	if statusCode != http.StatusConflict || cRespBody.Code == nil || *cRespBody.Code != "already_exists" {
+		t.Fatalf("[T_0027_001] CreateWallet with existing name want 409 already_exists. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	cRespBody, statusCode, cErr = client.CreateNamedWallet(username0, "SGD", "savings")
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0027_001] CreateNamedWallet want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if cRespBody.Data.Wallet.Name != "savings" || cRespBody.Data.Wallet.Id == user0wallet0.Id {
+		t.Fatalf("[T_0027_001] CreateNamedWallet want a second SGD wallet named savings. got %+v", cRespBody.Data.Wallet)
+	}
+	_, statusCode, cErr = client.CreateWalletAs(stranger, username0, "USD")
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0027_001] CreateWallet of user0 by stranger want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0027_002
+	_, statusCode, cErr = client.SetMember(stranger, user0wallet0.Id, stranger, "owner", nil)
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0027_002] SetMember by non-member want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.SetMember(username0, user0wallet0.Id, spender, "spender", nil)
+	if statusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0027_002] SetMember spender without spend_limit want 422. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	spendLimit := decimal.NewFromInt(20)
+	mRespBody, statusCode, cErr := client.SetMember(username0, user0wallet0.Id, spender, "spender", &spendLimit)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0027_002] SetMember spender want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if mRespBody.Data.Role != "spender" || mRespBody.Data.SpendLimit == nil || *mRespBody.Data.SpendLimit != "20" {
+		t.Fatalf("[T_0027_002] SetMember want spender with spend_limit 20. got %+v", mRespBody.Data.Member)
+	}
+	_, statusCode, cErr = client.SetMember(username0, user0wallet0.Id, viewer, "viewer", nil)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0027_002] SetMember viewer want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	msRespBody, statusCode, cErr := client.Members(viewer, user0wallet0.Id)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0027_002] Members by viewer want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if len(msRespBody.Data.Members) != 3 {
+		t.Fatalf("[T_0027_002] Members want owner, spender and viewer. got %+v", msRespBody.Data.Members)
+	}
+	_, statusCode, cErr = client.Members(stranger, user0wallet0.Id)
+	if statusCode != http.StatusNotFound {
+		t.Fatalf("[T_0027_002] Members by non-member want 404. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0027_003
+	_, statusCode, cErr = client.Deposit(username0, user0wallet0.Id, decimal.NewFromInt(100))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0027_003] SETUP Deposit want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	wRespBody, statusCode, cErr := client.Withdraw(spender, user0wallet0.Id, decimal.NewFromInt(30))
+	if statusCode != http.StatusUnprocessableEntity || wRespBody.Code == nil || *wRespBody.Code != "spend_limit_exceeded" {
+		t.Fatalf("[T_0027_003] Withdraw by spender over spend_limit want 422 spend_limit_exceeded. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Withdraw(spender, user0wallet0.Id, decimal.NewFromInt(15))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0027_003] Withdraw by spender within spend_limit want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	wRespBody, statusCode, cErr = client.Withdraw(spender, user0wallet0.Id, decimal.NewFromInt(10))
+	if statusCode != http.StatusUnprocessableEntity || wRespBody.Code == nil || *wRespBody.Code != "spend_limit_exceeded" {
+		t.Fatalf("[T_0027_003] Withdraw by spender over spend_limit in total want 422 spend_limit_exceeded. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Withdraw(spender, user0wallet0.Id, decimal.NewFromInt(5))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0027_003] Withdraw by spender up to spend_limit in total want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Withdraw(viewer, user0wallet0.Id, decimal.NewFromInt(1))
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0027_003] Withdraw by viewer want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0027_004
+	bRespBody, statusCode, cErr := client.Wallets(spender)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0027_004] Wallets of spender want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if len(bRespBody.Data.Wallets) != 1 || bRespBody.Data.Wallets[0].Id != user0wallet0.Id || bRespBody.Data.Wallets[0].Balance != "80" {
+		t.Fatalf("[T_0027_004] Wallets of spender want the shared wallet with balance 80. got %+v", bRespBody.Data.Wallets)
+	}
+
+	// T_0027_005
+	_, statusCode, cErr = client.RemoveMember(username0, user0wallet0.Id, username0)
+	if statusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0027_005] RemoveMember of last owner want 422. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.RemoveMember(spender, user0wallet0.Id, spender)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0027_005] RemoveMember by member leaving want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Withdraw(spender, user0wallet0.Id, decimal.NewFromInt(1))
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0027_005] Withdraw by former member want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0027_006
+	tRespBody, statusCode, cErr := client.Transactions(viewer)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0027_006] Transactions of viewer want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	var ledgers []testclient.Ledger
+	for _, transaction := range tRespBody.Data.Transactions {
+		for _, l := range transaction.Ledgers {
+			if l.WalletId == user0wallet0.Id {
+				ledgers = append(ledgers, l)
+			}
+		}
+	}
+	if len(ledgers) != 2 || ledgers[0].EntryType != "debit" || ledgers[0].Balance != "80" || ledgers[1].Balance != "100" {
+		t.Fatalf("[T_0027_006] Transactions of viewer want deposit and withdrawal of the shared wallet. got %+v", ledgers)
+	}
+	export, _, statusCode, cErr := client.TransactionsExport(viewer, viewer, "text/csv")
+	if statusCode != http.StatusOK || !strings.Contains(string(export), ","+strconv.FormatInt(user0wallet0.Id, 10)+",debit,") {
+		t.Fatalf("[T_0027_006] TransactionsExport of viewer want the withdrawal of the shared wallet. responseStatusCode=%d, err=%v\n%s", statusCode, cErr, export)
+	}
+}
+
+func T_0028(t *testing.T, client *testclient.Client) {
+	requester, requesterWallets := SetupUserAndWalletCreation(t, client, "T_0028", []string{"SGD"})
+	requesterWallet := requesterWallets[0]
+	payer, payerWallets := SetupUserAndWalletCreation(t, client, "T_0028", []string{"SGD"})
+	payerWallet := payerWallets[0]
+	stranger, _ := SetupUserAndWalletCreation(t, client, "T_0028", []string{})
+
+	// T_0028_001
+	_, statusCode, cErr := client.CreatePaymentRequest(requester, requester, requesterWallet.Id, decimal.NewFromInt(10), "SGD", "", nil)
+	if statusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0028_001] CreatePaymentRequest from self want 422. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.CreatePaymentRequest(requester, payer, requesterWallet.Id, decimal.NewFromInt(10), "USD", "", nil)
+	if statusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0028_001] CreatePaymentRequest in another currency than the wallet want 422. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.CreatePaymentRequest(stranger, payer, requesterWallet.Id, decimal.NewFromInt(10), "SGD", "", nil)
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0028_001] CreatePaymentRequest into the wallet of another user want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	pRespBody, statusCode, cErr := client.CreatePaymentRequest(requester, payer, requesterWallet.Id, decimal.NewFromInt(10), "SGD", "dinner", nil)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0028_001] CreatePaymentRequest want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	request := pRespBody.Data.PaymentRequest
+	if request.Status != "pending" || request.Payer != payer || request.Amount != "10" || request.TransactionId != nil {
+		t.Fatalf("[T_0028_001] CreatePaymentRequest want pending request of 10. got %+v", request)
+	}
+
+	// T_0028_002
+	lRespBody, statusCode, cErr := client.PaymentRequests(payer, payer, "", "pending")
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0028_002] PaymentRequests incoming want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	if len(lRespBody.Data.PaymentRequests) != 1 || lRespBody.Data.PaymentRequests[0].Id != request.Id {
+		t.Fatalf("[T_0028_002] PaymentRequests incoming want the request. got %+v", lRespBody.Data.PaymentRequests)
+	}
+	lRespBody, statusCode, cErr = client.PaymentRequests(requester, requester, "outgoing", "")
+	if statusCode != http.StatusOK || len(lRespBody.Data.PaymentRequests) != 1 {
+		t.Fatalf("[T_0028_002] PaymentRequests outgoing want the request. responseStatusCode=%d, err=%v, got %+v", statusCode, cErr, lRespBody.Data.PaymentRequests)
+	}
+	_, statusCode, cErr = client.PaymentRequests(stranger, payer, "", "")
+	if statusCode != http.StatusNotFound {
+		t.Fatalf("[T_0028_002] PaymentRequests of another user want 404. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0028_003
+	_, statusCode, cErr = client.PayPaymentRequest(payer, request.Id, payerWallet.Id)
+	if statusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0028_003] PayPaymentRequest with insufficient balance want 422. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.Deposit(payer, payerWallet.Id, decimal.NewFromInt(25))
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0028_003] SETUP Deposit want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.PayPaymentRequest(stranger, request.Id, payerWallet.Id)
+	if statusCode != http.StatusNotFound {
+		t.Fatalf("[T_0028_003] PayPaymentRequest by another user want 404. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	tRespBody, statusCode, cErr := client.PayPaymentRequest(payer, request.Id, payerWallet.Id)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0028_003] PayPaymentRequest want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	transaction := tRespBody.Data.Transaction
+	if transaction.Status != "success" || len(transaction.Ledgers) != 2 || transaction.MetaData.PaymentRequestId == nil ||
+		*transaction.MetaData.PaymentRequestId != request.Id {
+		t.Fatalf("[T_0028_003] PayPaymentRequest want successful transfer of the request. got %+v", transaction)
+	}
+	lRespBody, statusCode, cErr = client.PaymentRequests(requester, requester, "outgoing", "paid")
+	if statusCode != http.StatusOK || len(lRespBody.Data.PaymentRequests) != 1 {
+		t.Fatalf("[T_0028_003] PaymentRequests paid want the request. responseStatusCode=%d, err=%v, got %+v", statusCode, cErr, lRespBody.Data.PaymentRequests)
+	}
+	if paid := lRespBody.Data.PaymentRequests[0]; paid.TransactionId == nil || *paid.TransactionId != transaction.Id {
+		t.Fatalf("[T_0028_003] PaymentRequests want paid by the transfer %d. got %+v", transaction.Id, paid)
+	}
+	bRespBody, statusCode, cErr := client.Wallets(requester)
+	if statusCode != http.StatusOK || len(bRespBody.Data.Wallets) != 1 || bRespBody.Data.Wallets[0].Balance != "10" {
+		t.Fatalf("[T_0028_003] Wallets of requester want balance 10. responseStatusCode=%d, err=%v, got %+v", statusCode, cErr, bRespBody.Data.Wallets)
+	}
+	_, statusCode, cErr = client.PayPaymentRequest(payer, request.Id, payerWallet.Id)
+	if statusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0028_003] PayPaymentRequest paid twice want 422. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0028_004
+	pRespBody, statusCode, cErr = client.CreatePaymentRequest(requester, payer, requesterWallet.Id, decimal.NewFromInt(5), "SGD", "", nil)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0028_004] SETUP CreatePaymentRequest want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	request = pRespBody.Data.PaymentRequest
+	_, statusCode, cErr = client.CancelPaymentRequest(payer, request.Id)
+	if statusCode != http.StatusForbidden {
+		t.Fatalf("[T_0028_004] CancelPaymentRequest by payer want 403. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	pRespBody, statusCode, cErr = client.CancelPaymentRequest(requester, request.Id)
+	if statusCode != http.StatusOK || pRespBody.Data.Status != "cancelled" {
+		t.Fatalf("[T_0028_004] CancelPaymentRequest want 200 cancelled. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	_, statusCode, cErr = client.PayPaymentRequest(payer, request.Id, payerWallet.Id)
+	if statusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("[T_0028_004] PayPaymentRequest cancelled want 422. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+
+	// T_0028_005
+	if requester == strings.ToLower(requester) || payer == strings.ToUpper(payer) {
+		t.Fatalf("[T_0028_005] SETUP want mixed case usernames. got %s, %s", requester, payer)
+	}
+	pRespBody, statusCode, cErr = client.CreatePaymentRequest(strings.ToLower(requester), strings.ToUpper(payer), requesterWallet.Id, decimal.NewFromInt(2), "SGD", "", nil)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0028_005] CreatePaymentRequest with other case usernames want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	request = pRespBody.Data.PaymentRequest
+	if request.Requester != requester || request.Payer != payer {
+		t.Fatalf("[T_0028_005] CreatePaymentRequest want usernames as registered. got %+v", request)
+	}
+	lRespBody, statusCode, cErr = client.PaymentRequests(strings.ToUpper(payer), strings.ToLower(payer), "", "pending")
+	if statusCode != http.StatusOK || len(lRespBody.Data.PaymentRequests) != 1 || lRespBody.Data.PaymentRequests[0].Id != request.Id {
+		t.Fatalf("[T_0028_005] PaymentRequests with other case usernames want the request. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	tRespBody, statusCode, cErr = client.PayPaymentRequest(strings.ToUpper(payer), request.Id, payerWallet.Id)
+	if statusCode != http.StatusOK || tRespBody.Data.Transaction.Status != "success" {
+		t.Fatalf("[T_0028_005] PayPaymentRequest as upper case payer want 200 success. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	pRespBody, statusCode, cErr = client.CreatePaymentRequest(requester, payer, requesterWallet.Id, decimal.NewFromInt(2), "SGD", "", nil)
+	if statusCode != http.StatusOK {
+		t.Fatalf("[T_0028_005] SETUP CreatePaymentRequest want 200. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+	pRespBody, statusCode, cErr = client.CancelPaymentRequest(strings.ToUpper(requester), pRespBody.Data.Id)
+	if statusCode != http.StatusOK || pRespBody.Data.Status != "cancelled" {
+		t.Fatalf("[T_0028_005] CancelPaymentRequest as upper case requester want 200 cancelled. responseStatusCode=%d, err=%v", statusCode, cErr)
+	}
+}
+
 func SetupUserAndWalletCreation(t *testing.T, client *testclient.Client, logPrefix string, currencies []string) (username string, wallets []testclient.Wallet) {
 	username = NewRandomUserName(logPrefix, 12, 0)
 	createUserResponseData, responseStatusCode, err := client.CreateUser(username)
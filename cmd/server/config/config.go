@@ -1,35 +1,623 @@
 package config
 
 import (
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"net"
+	"net/url"
 	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/controllers/tlsconfig"
+	outboxservice "github.com/cryptonlx/crypto/src/services/outbox"
+	webhookservice "github.com/cryptonlx/crypto/src/services/webhook"
 
 	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v2"
 )
 
 type Params struct {
-	DatabaseParams
-	ServerParams
+	DatabaseParams `yaml:"database"`
+	ServerParams   `yaml:"server"`
+	TLSParams      `yaml:"tls"`
+	WebhookParams  `yaml:"webhook"`
+	OutboxParams   `yaml:"outbox"`
 }
 
 type DatabaseParams struct {
-	ConnString string
+	ConnString string `yaml:"url"`
+	// AutoMigrate applies pending migrations on server start.
+	AutoMigrate     bool          `yaml:"auto_migrate"`
+	MaxConns        int32         `yaml:"max_conns"`
+	MinConns        int32         `yaml:"min_conns"`
+	MaxConnIdleTime time.Duration `yaml:"max_conn_idle_time"`
 }
 
 type ServerParams struct {
-	Port string
+	Port string `yaml:"listening_port"`
+	// GrpcPort is the listening address of the gRPC API, not served if empty. Rate limit and TLS are shared with http.
+	GrpcPort     string        `yaml:"grpc_listening_port"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	// RateLimit is the requests per second admitted across all clients, RateBurst the requests admitted at once.
+	RateLimit float64 `yaml:"rate_limit"`
+	RateBurst int     `yaml:"rate_burst"`
+	// ShutdownDelay is how long the server keeps serving with readiness failing before draining,
+	// for load balancers to observe readiness and stop routing new requests.
+	ShutdownDelay time.Duration `yaml:"shutdown_delay"`
+	// ShutdownTimeout is how long in-flight requests are drained before connections are closed.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+// TLSParams
+// HTTPS is served if CertFile is set.
+type TLSParams struct {
+	CertFile     string `yaml:"cert_file"`
+	KeyFile      string `yaml:"key_file"`
+	ClientCAFile string `yaml:"client_ca_file"`
+	// ClientAuth is one of none, optional or require.
+	ClientAuth   string   `yaml:"client_auth"`
+	MinVersion   string   `yaml:"min_version"`
+	CipherSuites []string `yaml:"cipher_suites"`
+	// ClientPrincipals maps verified client certificate subjects, distinguished name or common name, to principals.
+	ClientPrincipals map[string]string `yaml:"client_principals"`
+}
+
+func (t TLSParams) Enabled() bool {
+	return t.CertFile != ""
+}
+
+func (t TLSParams) Options() tlsconfig.Options {
+	return tlsconfig.Options{
+		CertFile:     t.CertFile,
+		KeyFile:      t.KeyFile,
+		ClientCAFile: t.ClientCAFile,
+		ClientAuth:   t.ClientAuth,
+		MinVersion:   t.MinVersion,
+		CipherSuites: t.CipherSuites,
+	}
+}
+
+// WebhookParams
+// Dispatcher of webhook deliveries, see webhookservice.DispatcherParams.
+type WebhookParams struct {
+	DispatchInterval time.Duration `yaml:"dispatch_interval"`
+	BatchSize        int           `yaml:"batch_size"`
+	Timeout          time.Duration `yaml:"timeout"`
+	MaxAttempts      int           `yaml:"max_attempts"`
+	RetryBackoff     time.Duration `yaml:"retry_backoff"`
+	MaxBackoff       time.Duration `yaml:"max_backoff"`
+	AllowPrivateUrls bool          `yaml:"allow_private_urls"`
+}
+
+func (w WebhookParams) DispatcherParams() webhookservice.DispatcherParams {
+	return webhookservice.DispatcherParams{
+		Interval:         w.DispatchInterval,
+		BatchSize:        w.BatchSize,
+		Timeout:          w.Timeout,
+		MaxAttempts:      w.MaxAttempts,
+		RetryBackoff:     w.RetryBackoff,
+		MaxBackoff:       w.MaxBackoff,
+		AllowPrivateUrls: w.AllowPrivateUrls,
+	}
+}
+
+// OutboxParams
+// Relay of domain events to a sink, see outboxservice.SinkParams and outboxservice.RelayParams.
+type OutboxParams struct {
+	Sink           string        `yaml:"sink"`
+	FilePath       string        `yaml:"file_path"`
+	NatsUrl        string        `yaml:"nats_url"`
+	NatsSubject    string        `yaml:"nats_subject"`
+	KafkaRestUrl   string        `yaml:"kafka_rest_url"`
+	KafkaTopic     string        `yaml:"kafka_topic"`
+	SinkTimeout    time.Duration `yaml:"sink_timeout"`
+	RelayInterval  time.Duration `yaml:"relay_interval"`
+	RelayBatchSize int           `yaml:"relay_batch_size"`
+}
+
+func (o OutboxParams) SinkParams() outboxservice.SinkParams {
+	return outboxservice.SinkParams{
+		Sink:         o.Sink,
+		FilePath:     o.FilePath,
+		NatsUrl:      o.NatsUrl,
+		NatsSubject:  o.NatsSubject,
+		KafkaRestUrl: o.KafkaRestUrl,
+		KafkaTopic:   o.KafkaTopic,
+		Timeout:      o.SinkTimeout,
+	}
+}
+
+func (o OutboxParams) RelayParams() outboxservice.RelayParams {
+	return outboxservice.RelayParams{
+		Interval:  o.RelayInterval,
+		BatchSize: o.RelayBatchSize,
+	}
+}
+
+// Flags
+// Command line options that are not Params.
+type Flags struct {
+	ConfigFile  string
+	PrintConfig bool
+	// Args are the arguments after the flags, i.e. `migrate up`.
+	Args []string
 }
 
-func LoadParams() (c Params, e error) {
+func Defaults() Params {
+	return Params{
+		DatabaseParams: DatabaseParams{
+			MaxConns:        10,
+			MinConns:        2,
+			MaxConnIdleTime: 5 * time.Minute,
+		},
+		ServerParams: ServerParams{
+			Port:            ":8080",
+			GrpcPort:        ":50051",
+			ReadTimeout:     5 * time.Second,
+			WriteTimeout:    5 * time.Second,
+			IdleTimeout:     5 * time.Second,
+			RateLimit:       1200,
+			RateBurst:       1200,
+			ShutdownTimeout: 15 * time.Second,
+		},
+		WebhookParams: WebhookParams{
+			DispatchInterval: time.Second,
+			BatchSize:        20,
+			Timeout:          10 * time.Second,
+			MaxAttempts:      8,
+			RetryBackoff:     10 * time.Second,
+			MaxBackoff:       time.Hour,
+		},
+		OutboxParams: OutboxParams{
+			Sink:           outboxservice.SinkLog,
+			NatsSubject:    "crypto.events",
+			KafkaTopic:     "crypto.events",
+			SinkTimeout:    10 * time.Second,
+			RelayInterval:  time.Second,
+			RelayBatchSize: 100,
+		},
+	}
+}
+
+// setting
+// A Params field settable by env var and by flag. The flag name is the lowercased env var with `-` separators,
+// i.e. DATABASE_URL and --database-url.
+type setting struct {
+	env   string
+	usage string
+	set   func(c *Params, v string) error
+}
+
+func (s setting) flagName() string {
+	return strings.ReplaceAll(strings.ToLower(s.env), "_", "-")
+}
+
+var settings = []setting{
+	{"DATABASE_URL", "postgres connection string", func(c *Params, v string) error {
+		c.ConnString = v
+		return nil
+	}},
+	{"AUTO_MIGRATE", "apply pending migrations on start (TRUE/FALSE)", func(c *Params, v string) (err error) {
+		c.AutoMigrate, err = parseBool(v)
+		return err
+	}},
+	{"DB_MAX_CONNS", "max connections of the db pool", func(c *Params, v string) (err error) {
+		c.MaxConns, err = parseInt32(v)
+		return err
+	}},
+	{"DB_MIN_CONNS", "connections the db pool keeps open", func(c *Params, v string) (err error) {
+		c.MinConns, err = parseInt32(v)
+		return err
+	}},
+	{"DB_MAX_CONN_IDLE_TIME", "idle time before a pooled connection is closed", func(c *Params, v string) (err error) {
+		c.MaxConnIdleTime, err = time.ParseDuration(v)
+		return err
+	}},
+	{"LISTENING_PORT", "listening address, i.e. :8080", func(c *Params, v string) error {
+		c.Port = v
+		return nil
+	}},
+	{"GRPC_LISTENING_PORT", "gRPC listening address, i.e. :50051, empty to not serve gRPC", func(c *Params, v string) error {
+		c.GrpcPort = v
+		return nil
+	}},
+	{"HTTP_READ_TIMEOUT", "http server read timeout", func(c *Params, v string) (err error) {
+		c.ReadTimeout, err = time.ParseDuration(v)
+		return err
+	}},
+	{"HTTP_WRITE_TIMEOUT", "http server write timeout", func(c *Params, v string) (err error) {
+		c.WriteTimeout, err = time.ParseDuration(v)
+		return err
+	}},
+	{"HTTP_IDLE_TIMEOUT", "http server keep-alive idle timeout", func(c *Params, v string) (err error) {
+		c.IdleTimeout, err = time.ParseDuration(v)
+		return err
+	}},
+	{"RATE_LIMIT", "requests per second admitted across all clients", func(c *Params, v string) (err error) {
+		c.RateLimit, err = strconv.ParseFloat(v, 64)
+		return err
+	}},
+	{"RATE_BURST", "requests admitted at once", func(c *Params, v string) (err error) {
+		c.RateBurst, err = strconv.Atoi(v)
+		return err
+	}},
+	{"SHUTDOWN_DELAY", "serve with readiness failing before draining", func(c *Params, v string) (err error) {
+		c.ShutdownDelay, err = time.ParseDuration(v)
+		return err
+	}},
+	{"SHUTDOWN_TIMEOUT", "drain in-flight requests before closing connections", func(c *Params, v string) (err error) {
+		c.ShutdownTimeout, err = time.ParseDuration(v)
+		return err
+	}},
+	{"WEBHOOK_DISPATCH_INTERVAL", "poll interval of outbox events and due webhook deliveries", func(c *Params, v string) (err error) {
+		c.DispatchInterval, err = time.ParseDuration(v)
+		return err
+	}},
+	{"WEBHOOK_BATCH_SIZE", "outbox events enqueued and webhook deliveries sent at once", func(c *Params, v string) (err error) {
+		c.BatchSize, err = strconv.Atoi(v)
+		return err
+	}},
+	{"WEBHOOK_TIMEOUT", "timeout of one webhook delivery attempt", func(c *Params, v string) (err error) {
+		c.Timeout, err = time.ParseDuration(v)
+		return err
+	}},
+	{"WEBHOOK_MAX_ATTEMPTS", "attempts before a webhook delivery is dead", func(c *Params, v string) (err error) {
+		c.MaxAttempts, err = strconv.Atoi(v)
+		return err
+	}},
+	{"WEBHOOK_RETRY_BACKOFF", "delay before the second webhook attempt, doubled per attempt", func(c *Params, v string) (err error) {
+		c.RetryBackoff, err = time.ParseDuration(v)
+		return err
+	}},
+	{"WEBHOOK_MAX_BACKOFF", "max delay between webhook attempts", func(c *Params, v string) (err error) {
+		c.MaxBackoff, err = time.ParseDuration(v)
+		return err
+	}},
+	{"WEBHOOK_ALLOW_PRIVATE_URLS", "admit http webhook urls and private addresses, for development only (TRUE/FALSE)", func(c *Params, v string) (err error) {
+		c.AllowPrivateUrls, err = parseBool(v)
+		return err
+	}},
+	{"OUTBOX_SINK", "sink of domain events: log, file, nats or kafka", func(c *Params, v string) error {
+		c.Sink = v
+		return nil
+	}},
+	{"OUTBOX_FILE_PATH", "file appended with domain events by the file sink", func(c *Params, v string) error {
+		c.FilePath = v
+		return nil
+	}},
+	{"OUTBOX_NATS_URL", "nats://[user:pass@]host:port of the nats sink", func(c *Params, v string) error {
+		c.NatsUrl = v
+		return nil
+	}},
+	{"OUTBOX_NATS_SUBJECT", "subject prefix of the nats sink, suffixed with the event type", func(c *Params, v string) error {
+		c.NatsSubject = v
+		return nil
+	}},
+	{"OUTBOX_KAFKA_REST_URL", "kafka rest proxy url of the kafka sink", func(c *Params, v string) error {
+		c.KafkaRestUrl = v
+		return nil
+	}},
+	{"OUTBOX_KAFKA_TOPIC", "topic of the kafka sink", func(c *Params, v string) error {
+		c.KafkaTopic = v
+		return nil
+	}},
+	{"OUTBOX_SINK_TIMEOUT", "timeout of one send to the sink", func(c *Params, v string) (err error) {
+		c.SinkTimeout, err = time.ParseDuration(v)
+		return err
+	}},
+	{"OUTBOX_RELAY_INTERVAL", "poll interval of unpublished domain events", func(c *Params, v string) (err error) {
+		c.RelayInterval, err = time.ParseDuration(v)
+		return err
+	}},
+	{"OUTBOX_RELAY_BATCH_SIZE", "domain events relayed per poll", func(c *Params, v string) (err error) {
+		c.RelayBatchSize, err = strconv.Atoi(v)
+		return err
+	}},
+	{"TLS_CERT_FILE", "PEM certificate (chain) file, serves HTTPS if set", func(c *Params, v string) error {
+		c.CertFile = v
+		return nil
+	}},
+	{"TLS_KEY_FILE", "PEM private key file of the certificate", func(c *Params, v string) error {
+		c.KeyFile = v
+		return nil
+	}},
+	{"TLS_CLIENT_CA_FILE", "PEM bundle client certificates are verified against", func(c *Params, v string) error {
+		c.ClientCAFile = v
+		return nil
+	}},
+	{"TLS_CLIENT_AUTH", "client certificate verification: none, optional or require", func(c *Params, v string) error {
+		c.ClientAuth = v
+		return nil
+	}},
+	{"TLS_MIN_VERSION", "minimum TLS version: 1.2 or 1.3", func(c *Params, v string) error {
+		c.MinVersion = v
+		return nil
+	}},
+	{"TLS_CIPHER_SUITES", "comma separated TLS 1.2 cipher suites, go defaults if empty", func(c *Params, v string) error {
+		c.CipherSuites = nil
+		for _, suite := range strings.Split(v, ",") {
+			if suite = strings.TrimSpace(suite); suite != "" {
+				c.CipherSuites = append(c.CipherSuites, suite)
+			}
+		}
+		return nil
+	}},
+	{"TLS_CLIENT_PRINCIPALS", "semicolon separated <certificate subject>=<principal>", func(c *Params, v string) error {
+		c.ClientPrincipals = map[string]string{}
+		for _, entry := range strings.Split(v, ";") {
+			if entry = strings.TrimSpace(entry); entry == "" {
+				continue
+			}
+			// subjects may contain `=`, principals may not
+			i := strings.LastIndex(entry, "=")
+			if i <= 0 || i == len(entry)-1 {
+				return fmt.Errorf("want <certificate subject>=<principal>. got %q", entry)
+			}
+			c.ClientPrincipals[strings.TrimSpace(entry[:i])] = strings.TrimSpace(entry[i+1:])
+		}
+		return nil
+	}},
+}
+
+// LoadParams
+// Resolves Params from, in increasing precedence, defaults, the yaml config file (--config or CONFIG_FILE),
+// env vars (and .env) and flags. Params are validated.
+func LoadParams(args []string) (Params, Flags, error) {
 	err := godotenv.Load()
 	if !(os.Getenv("OPTIONAL_LOAD_ENV_FILE") == "TRUE") && err != nil {
-		return Params{}, fmt.Errorf("error. loading .env file is compulsory. OPTIONAL_LOAD_ENV_FILE=%s.\n", os.Getenv("OPTIONAL_LOAD_ENV_FILE"))
+		return Params{}, Flags{}, fmt.Errorf("error. loading .env file is compulsory. OPTIONAL_LOAD_ENV_FILE=%s.\n", os.Getenv("OPTIONAL_LOAD_ENV_FILE"))
+	}
+	return load(args, os.Getenv, os.ReadFile)
+}
+
+func load(args []string, getenv func(string) string, readFile func(string) ([]byte, error)) (Params, Flags, error) {
+	var flags Flags
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&flags.ConfigFile, "config", getenv("CONFIG_FILE"), "yaml config file")
+	fs.BoolVar(&flags.PrintConfig, "print-config", false, "print the resolved config with secrets redacted and exit")
+
+	// flag values are applied after env vars
+	var flagValues []func(c *Params) error
+	for _, s := range settings {
+		fs.Func(s.flagName(), s.usage, func(v string) error {
+			flagValues = append(flagValues, func(c *Params) error {
+				if err := s.set(c, v); err != nil {
+					return fmt.Errorf("--%s=%s: %w", s.flagName(), v, err)
+				}
+				return nil
+			})
+			return nil
+		})
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return Params{}, Flags{}, err
+		}
+		return Params{}, Flags{}, fmt.Errorf("error. %w\n%s", err, Usage())
 	}
-	c.ServerParams.Port = os.Getenv("LISTENING_PORT")
+	flags.Args = fs.Args()
 
-	// database
-	dbUrl := os.Getenv("DATABASE_URL")
-	c.DatabaseParams.ConnString = dbUrl
+	c := Defaults()
+	if flags.ConfigFile != "" {
+		b, err := readFile(flags.ConfigFile)
+		if err != nil {
+			return Params{}, Flags{}, fmt.Errorf("error. reading config file: %w", err)
+		}
+		if err := yaml.UnmarshalStrict(b, &c); err != nil {
+			return Params{}, Flags{}, fmt.Errorf("error. parsing config file %s: %w", flags.ConfigFile, err)
+		}
+	}
+
+	for _, s := range settings {
+		v := getenv(s.env)
+		if v == "" {
+			continue
+		}
+		if err := s.set(&c, v); err != nil {
+			return Params{}, Flags{}, fmt.Errorf("error. invalid %s=%s: %w", s.env, v, err)
+		}
+	}
+
+	for _, set := range flagValues {
+		if err := set(&c); err != nil {
+			return Params{}, Flags{}, fmt.Errorf("error. invalid flag %w", err)
+		}
+	}
+
+	if err := c.Validate(); err != nil {
+		return Params{}, Flags{}, err
+	}
+	return c, flags, nil
+}
+
+// Validate
+// Reports every invalid param at once.
+func (c Params) Validate() error {
+	var errs []error
+	invalid := func(format string, a ...any) {
+		errs = append(errs, fmt.Errorf("invalid config. "+format, a...))
+	}
+
+	if c.ConnString == "" {
+		invalid("database url (DATABASE_URL) is required")
+	}
+	if c.MaxConns < 1 {
+		invalid("database max_conns (DB_MAX_CONNS) must be at least 1. got %d", c.MaxConns)
+	}
+	if c.MinConns < 0 || c.MinConns > c.MaxConns {
+		invalid("database min_conns (DB_MIN_CONNS) must be between 0 and max_conns %d. got %d", c.MaxConns, c.MinConns)
+	}
+	if c.MaxConnIdleTime <= 0 {
+		invalid("database max_conn_idle_time (DB_MAX_CONN_IDLE_TIME) must be positive. got %s", c.MaxConnIdleTime)
+	}
+
+	if _, _, err := net.SplitHostPort(c.Port); err != nil {
+		invalid("server listening_port (LISTENING_PORT) must be host:port, i.e. :8080. got %q", c.Port)
+	}
+	if c.GrpcPort != "" {
+		if _, _, err := net.SplitHostPort(c.GrpcPort); err != nil {
+			invalid("server grpc_listening_port (GRPC_LISTENING_PORT) must be host:port, i.e. :50051. got %q", c.GrpcPort)
+		} else if c.GrpcPort == c.Port {
+			invalid("server grpc_listening_port (GRPC_LISTENING_PORT) must differ from listening_port. got %q", c.GrpcPort)
+		}
+	}
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"read_timeout (HTTP_READ_TIMEOUT)", c.ReadTimeout},
+		{"write_timeout (HTTP_WRITE_TIMEOUT)", c.WriteTimeout},
+		{"idle_timeout (HTTP_IDLE_TIMEOUT)", c.IdleTimeout},
+		{"shutdown_timeout (SHUTDOWN_TIMEOUT)", c.ShutdownTimeout},
+	} {
+		if d.value <= 0 {
+			invalid("server %s must be positive. got %s", d.name, d.value)
+		}
+	}
+	if c.ShutdownDelay < 0 {
+		invalid("server shutdown_delay (SHUTDOWN_DELAY) must not be negative. got %s", c.ShutdownDelay)
+	}
+	if c.RateLimit <= 0 {
+		invalid("server rate_limit (RATE_LIMIT) must be positive. got %v", c.RateLimit)
+	}
+	if c.RateBurst < 1 {
+		invalid("server rate_burst (RATE_BURST) must be at least 1. got %d", c.RateBurst)
+	}
+
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"dispatch_interval (WEBHOOK_DISPATCH_INTERVAL)", c.DispatchInterval},
+		{"timeout (WEBHOOK_TIMEOUT)", c.Timeout},
+		{"retry_backoff (WEBHOOK_RETRY_BACKOFF)", c.RetryBackoff},
+	} {
+		if d.value <= 0 {
+			invalid("webhook %s must be positive. got %s", d.name, d.value)
+		}
+	}
+	if c.MaxBackoff < c.RetryBackoff {
+		invalid("webhook max_backoff (WEBHOOK_MAX_BACKOFF) must be at least retry_backoff %s. got %s", c.RetryBackoff, c.MaxBackoff)
+	}
+	if c.BatchSize < 1 {
+		invalid("webhook batch_size (WEBHOOK_BATCH_SIZE) must be at least 1. got %d", c.BatchSize)
+	}
+	if c.MaxAttempts < 1 {
+		invalid("webhook max_attempts (WEBHOOK_MAX_ATTEMPTS) must be at least 1. got %d", c.MaxAttempts)
+	}
+
+	switch c.Sink {
+	case outboxservice.SinkLog:
+	case outboxservice.SinkFile:
+		if c.FilePath == "" {
+			invalid("outbox file_path (OUTBOX_FILE_PATH) is required by the file sink")
+		}
+	case outboxservice.SinkNats:
+		if _, err := outboxservice.NewNatsSink(c.NatsUrl, c.NatsSubject, c.SinkTimeout); err != nil {
+			invalid("outbox nats_url (OUTBOX_NATS_URL), nats_subject (OUTBOX_NATS_SUBJECT) %s", err)
+		}
+	case outboxservice.SinkKafka:
+		if u, err := url.Parse(c.KafkaRestUrl); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid("outbox kafka_rest_url (OUTBOX_KAFKA_REST_URL) must be an http(s) url. got %q", c.KafkaRestUrl)
+		}
+		if c.KafkaTopic == "" {
+			invalid("outbox kafka_topic (OUTBOX_KAFKA_TOPIC) is required by the kafka sink")
+		}
+	default:
+		invalid("outbox sink (OUTBOX_SINK) must be one of %s. got %q", strings.Join(outboxservice.Sinks, ", "), c.Sink)
+	}
+	if c.SinkTimeout <= 0 {
+		invalid("outbox sink_timeout (OUTBOX_SINK_TIMEOUT) must be positive. got %s", c.SinkTimeout)
+	}
+	if c.RelayInterval <= 0 {
+		invalid("outbox relay_interval (OUTBOX_RELAY_INTERVAL) must be positive. got %s", c.RelayInterval)
+	}
+	if c.RelayBatchSize < 1 {
+		invalid("outbox relay_batch_size (OUTBOX_RELAY_BATCH_SIZE) must be at least 1. got %d", c.RelayBatchSize)
+	}
+
+	if err := c.TLSParams.Options().Validate(); err != nil {
+		invalid("tls (TLS_*) %s", strings.ReplaceAll(err.Error(), "\n", "; "))
+	}
+	if len(c.ClientPrincipals) > 0 && c.ClientCAFile == "" {
+		invalid("tls client_principals (TLS_CLIENT_PRINCIPALS) requires client certificate verification")
+	}
+	return errors.Join(errs...)
+}
+
+var dsnPasswordPattern = regexp.MustCompile(`(password\s*=\s*)('(?:[^'\\]|\\.)*'|\S+)`)
+
+// Redacted
+// Copy of c safe to print. Masks the passwords of the database and nats urls.
+func (c Params) Redacted() Params {
+	if u, err := url.Parse(c.NatsUrl); err == nil {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), "REDACTED")
+			c.NatsUrl = u.String()
+		}
+	}
+	u, err := url.Parse(c.ConnString)
+	if err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql") {
+		if _, ok := u.User.Password(); ok {
+			u.User = url.UserPassword(u.User.Username(), "REDACTED")
+		}
+		if query := u.Query(); query.Has("password") {
+			query.Set("password", "REDACTED")
+			u.RawQuery = query.Encode()
+		}
+		c.ConnString = u.String()
+		return c
+	}
+	// keyword/value connection string, i.e. `host=localhost password=secret`
+	c.ConnString = dsnPasswordPattern.ReplaceAllString(c.ConnString, "${1}REDACTED")
+	return c
+}
+
+// Print
+// Writes the redacted params as yaml, usable as a config file.
+func (c Params) Print(w io.Writer) error {
+	b, err := yaml.Marshal(c.Redacted())
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(b)
+	return err
+}
+
+func Usage() string {
+	var b strings.Builder
+	b.WriteString(`usage: server [flags] [migrate <command>]
+
+flags take precedence over env vars, env vars over the config file.
+
+  --config <file>                 yaml config file, env CONFIG_FILE
+  --print-config                  print the resolved config with secrets redacted and exit
+`)
+	for _, s := range settings {
+		fmt.Fprintf(&b, "  --%-29s %s, env %s\n", s.flagName()+" <v>", s.usage, s.env)
+	}
+	return b.String()
+}
+
+func parseBool(v string) (bool, error) {
+	switch strings.ToUpper(v) {
+	case "TRUE":
+		return true, nil
+	case "FALSE":
+		return false, nil
+	}
+	return false, fmt.Errorf("want TRUE or FALSE. got %q", v)
+}
 
-	return c, nil
+func parseInt32(v string) (int32, error) {
+	i, err := strconv.ParseInt(v, 10, 32)
+	return int32(i), err
 }
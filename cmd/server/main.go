@@ -3,7 +3,10 @@ package main
 import (
 	"context"
 	"errors"
+	"flag"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -11,24 +14,86 @@ import (
 	"time"
 
 	serverconfig "github.com/cryptonlx/crypto/cmd/server/config"
+	"github.com/cryptonlx/crypto/schemas"
 	"github.com/cryptonlx/crypto/src/controllers/httplog"
+	"github.com/cryptonlx/crypto/src/controllers/middlewares"
+	"github.com/cryptonlx/crypto/src/controllers/response_types"
+	"github.com/cryptonlx/crypto/src/controllers/rpc"
+	"github.com/cryptonlx/crypto/src/controllers/rpc/walletpb"
+	"github.com/cryptonlx/crypto/src/controllers/tlsconfig"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
 
+	activitymux "github.com/cryptonlx/crypto/src/controllers/mux/activity"
+	adminmux "github.com/cryptonlx/crypto/src/controllers/mux/admin"
+	auditmux "github.com/cryptonlx/crypto/src/controllers/mux/audit"
+	healthmux "github.com/cryptonlx/crypto/src/controllers/mux/health"
 	usermux "github.com/cryptonlx/crypto/src/controllers/mux/user"
+	webhookmux "github.com/cryptonlx/crypto/src/controllers/mux/webhook"
+	activityrepo "github.com/cryptonlx/crypto/src/repositories/activity"
+	auditrepo "github.com/cryptonlx/crypto/src/repositories/audit"
+	"github.com/cryptonlx/crypto/src/repositories/migrations"
+	outboxrepo "github.com/cryptonlx/crypto/src/repositories/outbox"
 	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+	webhookrepo "github.com/cryptonlx/crypto/src/repositories/webhook"
+	activityservice "github.com/cryptonlx/crypto/src/services/activity"
+	auditservice "github.com/cryptonlx/crypto/src/services/audit"
+	healthservice "github.com/cryptonlx/crypto/src/services/health"
+	outboxservice "github.com/cryptonlx/crypto/src/services/outbox"
 	userservice "github.com/cryptonlx/crypto/src/services/user"
+	webhookservice "github.com/cryptonlx/crypto/src/services/webhook"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 
 	_ "github.com/cryptonlx/crypto/docs"
 	"github.com/swaggo/http-swagger"
 )
 
 func main() {
-	configParams, dbConnPool, err := Init()
+	configParams, flags, err := serverconfig.LoadParams(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		fmt.Print(serverconfig.Usage())
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if flags.PrintConfig {
+		if err := configParams.Print(os.Stdout); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if len(flags.Args) > 0 {
+		if flags.Args[0] != "migrate" {
+			log.Fatal(serverconfig.Usage())
+		}
+		if err := Migrate(configParams, flags.Args[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	dbConnPool, err := Init(configParams)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	migrator, err := migrations.New(dbConnPool, schemas.FS)
 	if err != nil {
 		log.Fatal(err)
 	}
+	if configParams.AutoMigrate {
+		applied, err := migrator.Up(context.Background())
+		for _, m := range applied {
+			log.Printf("applied migration %03d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
 
 	interruptSignal := make(chan os.Signal, 1)
 	signal.Notify(interruptSignal, syscall.SIGINT /*keyboard input*/, syscall.SIGTERM /*process kill*/)
@@ -36,77 +101,251 @@ func main() {
 
 	mux.Handle("/swagger/", httpSwagger.WrapHandler)
 
-	userRepo := userrepo.New(dbConnPool)
+	outboxRepo := outboxrepo.New(dbConnPool)
+	userRepo := userrepo.New(dbConnPool, outboxservice.NewPublisher(outboxrepo.Outbox{}))
 	userService := userservice.New(userRepo)
 	userHandlers := usermux.NewHandlers(userService)
 
+	auditRepo := auditrepo.New(dbConnPool)
+	auditService := auditservice.New(auditRepo, userService)
+	auditHandlers := auditmux.NewHandlers(auditService)
+	audited := middlewares.MiddewareStack{}.Wrap(auditHandlers.Middleware)
+
 	mux.HandleFunc("GET /user/{username}/wallets", userHandlers.Wallets)
 	mux.HandleFunc("GET /user/{username}/transactions", userHandlers.Transactions)
-	mux.HandleFunc("POST /user", userHandlers.CreateUser)
-	mux.HandleFunc("POST /wallet", userHandlers.CreateWallet)
-	mux.HandleFunc("POST /wallet/{wallet_id}/deposit", userHandlers.Deposit)
-	mux.HandleFunc("POST /wallet/{wallet_id}/withdrawal", userHandlers.Withdraw)
-	mux.HandleFunc("POST /wallet/{wallet_id}/transfer", userHandlers.Transfer)
+	mux.HandleFunc("GET /user/{username}", userHandlers.Profile)
+	mux.Handle("PATCH /user/{username}", audited.Finalize(userHandlers.UpdateProfile))
+	mux.Handle("POST /user", audited.Finalize(userHandlers.CreateUser))
+	mux.Handle("POST /wallet", audited.Finalize(userHandlers.CreateWallet))
+	mux.Handle("POST /wallet/{wallet_id}/deposit", audited.Finalize(userHandlers.Deposit))
+	mux.Handle("POST /wallet/{wallet_id}/withdrawal", audited.Finalize(userHandlers.Withdraw))
+	mux.Handle("POST /wallet/{wallet_id}/transfer", audited.Finalize(userHandlers.Transfer))
+	mux.HandleFunc("GET /wallet/{wallet_id}/statement", userHandlers.Statement)
+	mux.HandleFunc("GET /wallet/{wallet_id}/balance", userHandlers.Balance)
+	mux.HandleFunc("GET /wallet/{wallet_id}/approval-policy", userHandlers.ApprovalPolicy)
+	mux.HandleFunc("GET /wallet/{wallet_id}/members", userHandlers.Members)
+	mux.Handle("PUT /wallet/{wallet_id}/members/{username}", audited.Finalize(userHandlers.SetMember))
+	mux.Handle("DELETE /wallet/{wallet_id}/members/{username}", audited.Finalize(userHandlers.RemoveMember))
+	mux.HandleFunc("GET /user/{username}/approvals", userHandlers.PendingApprovals)
+	mux.Handle("POST /transaction/{transaction_id}/approve", audited.Finalize(userHandlers.ApproveTransfer))
+	mux.Handle("POST /transaction/{transaction_id}/reject", audited.Finalize(userHandlers.RejectTransfer))
+	mux.Handle("POST /payment-requests", audited.Finalize(userHandlers.CreatePaymentRequest))
+	mux.HandleFunc("GET /user/{username}/payment-requests", userHandlers.PaymentRequests)
+	mux.Handle("POST /payment-requests/{payment_request_id}/pay", audited.Finalize(userHandlers.PayPaymentRequest))
+	mux.Handle("POST /payment-requests/{payment_request_id}/cancel", audited.Finalize(userHandlers.CancelPaymentRequest))
+
+	webhookRepo := webhookrepo.New(dbConnPool)
+	webhookService := webhookservice.New(webhookRepo, userService, configParams.AllowPrivateUrls)
+	webhookHandlers := webhookmux.NewHandlers(webhookService)
+	mux.HandleFunc("GET /user/{username}/webhooks", webhookHandlers.Webhooks)
+	mux.Handle("POST /user/{username}/webhooks", audited.Finalize(webhookHandlers.CreateWebhook))
+	mux.Handle("DELETE /user/{username}/webhooks/{webhook_id}", audited.Finalize(webhookHandlers.DeleteWebhook))
+	mux.HandleFunc("GET /user/{username}/webhooks/{webhook_id}/deliveries", webhookHandlers.Deliveries)
+	mux.Handle("POST /user/{username}/webhooks/deliveries/{delivery_id}/replay", audited.Finalize(webhookHandlers.Replay))
+
+	activityHub := activityservice.New(activityrepo.New(dbConnPool), userService)
+	activityHandlers := activitymux.NewHandlers(activityHub)
+	mux.HandleFunc("GET /user/{username}/events", activityHandlers.Events)
+
+	adminHandlers := adminmux.NewHandlers(userService)
+	mux.HandleFunc("GET /admin/audit", auditHandlers.Events)
+	mux.HandleFunc("GET /admin/user/{username}", adminHandlers.User)
+	mux.Handle("POST /admin/user/{username}/freeze", audited.Finalize(adminHandlers.Freeze))
+	mux.Handle("POST /admin/user/{username}/unfreeze", audited.Finalize(adminHandlers.Unfreeze))
+	mux.Handle("PUT /admin/user/{username}/role", audited.Finalize(adminHandlers.SetRole))
+	mux.Handle("POST /admin/wallet/{wallet_id}/adjustment", audited.Finalize(adminHandlers.Adjustment))
+	mux.HandleFunc("GET /admin/adjustments/pending", adminHandlers.PendingAdjustments)
+	mux.Handle("POST /admin/adjustment/{transaction_id}/approve", audited.Finalize(adminHandlers.ApproveAdjustment))
+	mux.Handle("POST /admin/adjustment/{transaction_id}/reject", audited.Finalize(adminHandlers.RejectAdjustment))
+	mux.Handle("PUT /admin/wallet/{wallet_id}/status", audited.Finalize(adminHandlers.SetWalletStatus))
+	mux.HandleFunc("GET /admin/wallet/{wallet_id}/status/history", adminHandlers.WalletStatusHistory)
+	mux.Handle("PUT /admin/wallet/{wallet_id}/approval-policy", audited.Finalize(adminHandlers.SetApprovalPolicy))
+	mux.Handle("DELETE /admin/wallet/{wallet_id}/approval-policy", audited.Finalize(adminHandlers.DeleteApprovalPolicy))
+	mux.HandleFunc("GET /admin/transactions/pending", adminHandlers.PendingTransactions)
+	mux.HandleFunc("GET /admin/reconciliation", adminHandlers.Reconciliation)
+
+	healthService := healthservice.New(dbConnPool, migrator)
+	healthHandlers := healthmux.NewHandlers(healthService)
+	// probes are served outside the rate limit and the request log, so that load does not fail them
+	probes := http.NewServeMux()
+	probes.HandleFunc("GET /healthz", healthHandlers.Alive)
+	probes.HandleFunc("GET /readyz", healthHandlers.Ready)
+
+	limiter := rate.NewLimiter(rate.Limit(configParams.RateLimit), configParams.RateBurst)
+	server := &http.Server{
+		Addr: configParams.ServerParams.Port,
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if probe, pattern := probes.Handler(r); pattern != "" {
+				probe.ServeHTTP(w, r)
+				return
+			}
+			if !limiter.Allow() {
+				response_types.WriteProblem(w, r, utils.TooManyRequestsError)
+				return
+			}
+			r = httplog.ContextualizeHttpRequest(r)
+			r = middlewares.ContextualizeClientCertPrincipal(r, configParams.ClientPrincipals)
+			log.Printf("%s [request received]\n", httplog.SPrintHttpRequestPrefix(r))
+			mux.ServeHTTP(w, r)
+		}),
+		ReadTimeout:  configParams.ReadTimeout,
+		WriteTimeout: configParams.WriteTimeout,
+		IdleTimeout:  configParams.IdleTimeout,
+	}
+	// event streams never end by themselves, they are ended for Shutdown to drain
+	server.RegisterOnShutdown(activityHub.Close)
+
+	// same services, rate limit and audit log as http
+	grpcOptions := rpc.NewInterceptors(limiter, configParams.ClientPrincipals, auditService).ServerOptions()
+
+	if configParams.TLSParams.Enabled() {
+		tlsReloader, err := tlsconfig.New(configParams.TLSParams.Options())
+		if err != nil {
+			log.Fatal(err)
+		}
+		server.TLSConfig = tlsReloader.Config()
+		grpcOptions = append(grpcOptions, grpc.Creds(credentials.NewTLS(tlsReloader.Config())))
+
+		reloadSignal := make(chan os.Signal, 1)
+		signal.Notify(reloadSignal, syscall.SIGHUP)
+		go func() {
+			for range reloadSignal {
+				if err := tlsReloader.Reload(); err != nil {
+					log.Printf("TLS reload err %v ; serving previous certificate\n", err)
+					continue
+				}
+				log.Println("TLS certificate reloaded")
+			}
+		}()
+	}
+
+	dispatcherCtx, stopDispatcher := context.WithCancel(context.Background())
+	dispatcherDone := make(chan struct{})
+	go func() {
+		defer close(dispatcherDone)
+		webhookservice.NewDispatcher(webhookRepo, outboxRepo, configParams.DispatcherParams()).Run(dispatcherCtx)
+	}()
+
+	outboxSink, err := outboxservice.NewSink(configParams.SinkParams())
+	if err != nil {
+		log.Fatal(err)
+	}
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	relayDone := make(chan struct{})
+	go func() {
+		defer close(relayDone)
+		outboxservice.NewRelay(outboxRepo, outboxSink, configParams.RelayParams()).Run(relayCtx)
+	}()
+
+	activityCtx, stopActivity := context.WithCancel(context.Background())
+	activityDone := make(chan struct{})
+	go func() {
+		defer close(activityDone)
+		activityHub.Run(activityCtx)
+	}()
+
+	expiryCtx, stopExpiry := context.WithCancel(context.Background())
+	expiryDone := make(chan struct{})
+	go func() {
+		defer close(expiryDone)
+		userService.RunApprovalExpiry(expiryCtx, userservice.ApprovalExpiryInterval)
+	}()
 
 	go func() {
 		log.Println("Listening on " + configParams.ServerParams.Port)
-		limiter := rate.NewLimiter(1200, 1200)
-
-		server := &http.Server{
-			Addr: configParams.ServerParams.Port,
-			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-				if !limiter.Allow() {
-					http.Error(w, "Too Many Requests", http.StatusTooManyRequests)
-					return
-				}
-				r = httplog.ContextualizeHttpRequest(r)
-				log.Printf("%s [request received]\n", httplog.SPrintHttpRequestPrefix(r))
-				mux.ServeHTTP(w, r)
-			}),
-			ReadTimeout:  5 * time.Second,
-			WriteTimeout: 5 * time.Second,
-			IdleTimeout:  5 * time.Second,
+		var err error
+		if server.TLSConfig != nil {
+			// certificate is served by TLSConfig
+			err = server.ListenAndServeTLS("", "")
+		} else {
+			err = server.ListenAndServe()
 		}
-		if err := server.ListenAndServe(); err != nil {
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			log.Printf("ListenAndServe err %v\n", err)
 
 			os.Exit(1)
 		}
 	}()
 
+	grpcServer := grpc.NewServer(grpcOptions...)
+	walletpb.RegisterWalletServiceServer(grpcServer, rpc.NewServer(userService))
+	if configParams.GrpcPort != "" {
+		grpcListener, err := net.Listen("tcp", configParams.GrpcPort)
+		if err != nil {
+			log.Fatal(err)
+		}
+		go func() {
+			log.Println("gRPC listening on " + configParams.GrpcPort)
+			if err := grpcServer.Serve(grpcListener); err != nil {
+				log.Printf("gRPC Serve err %v\n", err)
+
+				os.Exit(1)
+			}
+		}()
+	}
+
 	recvSig := <-interruptSignal
 	log.Println("Received signal: " + recvSig.String() + " ; tearing down...")
-	log.Println("Terminating hepmilserver::main()...")
-}
+	signal.Stop(interruptSignal)
 
-func Init() (serverconfig.Params, *pgxpool.Pool, error) {
-	params, err := serverconfig.LoadParams()
-	if err != nil {
-		return serverconfig.Params{}, nil, err
+	healthHandlers.SetDraining()
+	time.Sleep(configParams.ShutdownDelay)
+
+	log.Printf("Draining in-flight requests, timeout %s...\n", configParams.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), configParams.ShutdownTimeout)
+	defer cancel()
+	grpcStopped := make(chan struct{})
+	go func() {
+		defer close(grpcStopped)
+		grpcServer.GracefulStop()
+	}()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Shutdown err %v ; closing remaining connections\n", err)
+		server.Close()
 	}
+	select {
+	case <-grpcStopped:
+	case <-shutdownCtx.Done():
+		log.Println("gRPC GracefulStop timed out ; closing remaining connections")
+		grpcServer.Stop()
+	}
+
+	// attempts in flight are cancelled, recorded and retried after restart
+	stopDispatcher()
+	<-dispatcherDone
+	stopActivity()
+	<-activityDone
+	stopExpiry()
+	<-expiryDone
+	// a message in flight is relayed again after restart
+	stopRelay()
+	<-relayDone
+	outboxSink.Close()
 
+	dbConnPool.Close()
+	log.Println("Terminating hepmilserver::main()...")
+}
+
+func Init(params serverconfig.Params) (*pgxpool.Pool, error) {
 	// Db Connection Pool
-	if params.ConnString == "" {
-		return serverconfig.Params{}, nil, errors.New("no db connection string provided")
-	}
 	pgConfig, err := pgxpool.ParseConfig(params.ConnString)
 	if err != nil {
-		return serverconfig.Params{}, nil, err
+		return nil, err
 	}
 
-	pgConfig.MaxConns = 10
-	pgConfig.MinConns = 2
-	pgConfig.MaxConnIdleTime = 5 * time.Minute
+	pgConfig.MaxConns = params.MaxConns
+	pgConfig.MinConns = params.MinConns
+	pgConfig.MaxConnIdleTime = params.MaxConnIdleTime
 	ctx := context.Background()
 
 	dbConnPool, err := pgxpool.NewWithConfig(ctx, pgConfig)
 	if err != nil {
-		return serverconfig.Params{}, nil, err
+		return nil, err
 	}
 
-	err = dbConnPool.Ping(ctx)
-	if err != nil {
-		panic(err)
+	// The pool connects lazily. An unreachable database is reported by GET /readyz instead of failing startup.
+	if err := dbConnPool.Ping(ctx); err != nil {
+		log.Printf("Db ping err %v\n", err)
 	}
-	return params, dbConnPool, nil
+	return dbConnPool, nil
 }
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	serverconfig "github.com/cryptonlx/crypto/cmd/server/config"
+	"github.com/cryptonlx/crypto/schemas"
+	"github.com/cryptonlx/crypto/src/repositories/migrations"
+)
+
+const migrateUsage = `usage: server [flags] migrate <command>
+
+commands:
+  up                 apply all pending migrations
+  down [steps]       revert the latest applied migrations, default 1 step
+  status             list migrations and when they were applied
+  baseline <version> mark migrations up to version as applied without executing them,
+                     for databases set up manually from ./schemas`
+
+// Migrate
+// Entry point of `server migrate <command>`.
+func Migrate(params serverconfig.Params, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	dbConnPool, err := Init(params)
+	if err != nil {
+		return err
+	}
+	defer dbConnPool.Close()
+
+	migrator, err := migrations.New(dbConnPool, schemas.FS)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		applied, err := migrator.Up(ctx)
+		for _, m := range applied {
+			log.Printf("applied %03d_%s\n", m.Version, m.Name)
+		}
+		if err != nil {
+			return err
+		}
+		log.Printf("schema at version %d\n", migrator.LatestVersion())
+		return nil
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid steps %q\n%s", args[1], migrateUsage)
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		for _, m := range reverted {
+			log.Printf("reverted %03d_%s\n", m.Version, m.Name)
+		}
+		return err
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, s := range statuses {
+			appliedAt := "pending"
+			if s.AppliedAt != nil {
+				appliedAt = s.AppliedAt.String()
+			}
+			fmt.Fprintf(w, "%03d\t%s\t%s\n", s.Version, s.Name, appliedAt)
+		}
+		return w.Flush()
+	case "baseline":
+		if len(args) < 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q\n%s", args[1], migrateUsage)
+		}
+		marked, err := migrator.Baseline(ctx, version)
+		for _, m := range marked {
+			log.Printf("marked %03d_%s as applied\n", m.Version, m.Name)
+		}
+		return err
+	}
+	return errors.New(migrateUsage)
+}
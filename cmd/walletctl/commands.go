@@ -0,0 +1,279 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/cryptonlx/crypto/pkg/client"
+
+	"github.com/shopspring/decimal"
+)
+
+func command(ctx context.Context, c *client.Client, p printer, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	switch args[0] {
+	case "user":
+		return userCommand(ctx, c, p, args[1:])
+	case "wallet":
+		return walletCommand(ctx, c, p, args[1:])
+	case "adjustment":
+		return adjustmentCommand(ctx, c, p, args[1:])
+	case "transactions":
+		return transactionsCommand(ctx, c, p, args[1:])
+	case "reconcile":
+		return reconcile(ctx, c, p, args[1:])
+	}
+	return fmt.Errorf("unknown command %q\n\n%w", args[0], errUsage)
+}
+
+func userCommand(ctx context.Context, c *client.Client, p printer, args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	username := args[1]
+	switch args[0] {
+	case "create":
+		user, err := c.CreateUser(ctx, username)
+		if err != nil {
+			return err
+		}
+		return p.user(user)
+	case "show":
+		user, err := c.AdminUser(ctx, username)
+		if err != nil {
+			return err
+		}
+		return p.adminUser(user)
+	case "transactions":
+		transactions, err := c.Transactions(ctx, username)
+		if err != nil {
+			return err
+		}
+		return p.transactions(transactions)
+	}
+	return fmt.Errorf("unknown command user %q\n\n%w", args[0], errUsage)
+}
+
+func walletCommand(ctx context.Context, c *client.Client, p printer, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	fs := flag.NewFlagSet("wallet "+args[0], flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	at := fs.String("at", "", "")
+	from := fs.String("from", "", "")
+	to := fs.String("to", "", "")
+	reason := fs.String("reason", "", "")
+	reasonCode := fs.String("reason-code", "", "")
+	nonce := fs.Int64("nonce", 0, "")
+	debitOnly := fs.Bool("debit-only", false, "")
+	name := fs.String("name", "", "")
+	positional, err := parse(fs, args[1:])
+	if err != nil {
+		return err
+	}
+
+	if args[0] == "create" {
+		if len(positional) != 2 {
+			return errUsage
+		}
+		wallet, err := c.CreateWallet(ctx, positional[0], positional[1], *name)
+		if err != nil {
+			return err
+		}
+		return p.wallet(wallet)
+	}
+
+	if len(positional) == 0 {
+		return errUsage
+	}
+	walletId, err := strconv.ParseInt(positional[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid wallet_id %q\n\n%w", positional[0], errUsage)
+	}
+	positional = positional[1:]
+
+	switch args[0] {
+	case "balance":
+		atTime, err := parseTime("at", *at)
+		if err != nil {
+			return err
+		}
+		balance, err := c.Balance(ctx, walletId, atTime)
+		if err != nil {
+			return err
+		}
+		return p.balance(balance)
+	case "history":
+		fromTime, err := parseTime("from", *from)
+		if err != nil {
+			return err
+		}
+		toTime, err := parseTime("to", *to)
+		if err != nil {
+			return err
+		}
+		statement, err := c.Statement(ctx, walletId, fromTime, toTime)
+		if err != nil {
+			return err
+		}
+		return p.statement(statement)
+	case "adjust":
+		if len(positional) != 2 || *reasonCode == "" || *reason == "" {
+			return errUsage
+		}
+		amount, err := decimal.NewFromString(positional[1])
+		if err != nil {
+			return fmt.Errorf("invalid amount %q\n\n%w", positional[1], errUsage)
+		}
+		transaction, err := c.Adjustment(ctx, client.AdjustmentRequest{
+			WalletId:   walletId,
+			EntryType:  positional[0],
+			Amount:     amount,
+			ReasonCode: *reasonCode,
+			Reason:     *reason,
+			Nonce:      *nonce,
+		})
+		if err != nil {
+			return err
+		}
+		return p.transaction(transaction)
+	case "freeze", "unfreeze":
+		if *reason == "" {
+			return errUsage
+		}
+		status := "active"
+		if args[0] == "freeze" {
+			status = "frozen"
+			if *debitOnly {
+				status = "debit_frozen"
+			}
+		}
+		wallet, err := c.SetWalletStatus(ctx, walletId, status, *reason)
+		if err != nil {
+			return err
+		}
+		return p.wallet(wallet)
+	case "status-history":
+		changes, err := c.WalletStatusHistory(ctx, walletId)
+		if err != nil {
+			return err
+		}
+		return p.statusChanges(changes)
+	}
+	return fmt.Errorf("unknown command wallet %q\n\n%w", args[0], errUsage)
+}
+
+func adjustmentCommand(ctx context.Context, c *client.Client, p printer, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	fs := flag.NewFlagSet("adjustment "+args[0], flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	limit := fs.Int("limit", 0, "")
+	positional, err := parse(fs, args[1:])
+	if err != nil {
+		return err
+	}
+
+	if args[0] == "pending" {
+		if len(positional) > 0 {
+			return errUsage
+		}
+		transactions, err := c.PendingAdjustments(ctx, *limit)
+		if err != nil {
+			return err
+		}
+		return p.transactions(transactions)
+	}
+
+	if len(positional) != 1 {
+		return errUsage
+	}
+	transactionId, err := strconv.ParseInt(positional[0], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid transaction_id %q\n\n%w", positional[0], errUsage)
+	}
+	var transaction client.Transaction
+	switch args[0] {
+	case "approve":
+		transaction, err = c.ApproveAdjustment(ctx, transactionId)
+	case "reject":
+		transaction, err = c.RejectAdjustment(ctx, transactionId)
+	default:
+		return fmt.Errorf("unknown command adjustment %q\n\n%w", args[0], errUsage)
+	}
+	if err != nil {
+		return err
+	}
+	return p.transaction(transaction)
+}
+
+func transactionsCommand(ctx context.Context, c *client.Client, p printer, args []string) error {
+	if len(args) == 0 || args[0] != "pending" {
+		return errUsage
+	}
+	fs := flag.NewFlagSet("transactions pending", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	olderThan := fs.Duration("older-than", 0, "")
+	limit := fs.Int("limit", 0, "")
+	if positional, err := parse(fs, args[1:]); err != nil || len(positional) > 0 {
+		return errUsage
+	}
+
+	transactions, err := c.PendingTransactions(ctx, *olderThan, *limit)
+	if err != nil {
+		return err
+	}
+	return p.transactions(transactions)
+}
+
+func reconcile(ctx context.Context, c *client.Client, p printer, args []string) error {
+	if len(args) > 0 {
+		return errUsage
+	}
+	reconciliation, err := c.Reconcile(ctx)
+	if err != nil {
+		return err
+	}
+	if err := p.reconciliation(reconciliation); err != nil {
+		return err
+	}
+	if len(reconciliation.WalletDiscrepancies) > 0 || len(reconciliation.TransactionDiscrepancies) > 0 {
+		return errDiscrepancies
+	}
+	return nil
+}
+
+// parse
+// Parses flags of fs anywhere among args, i.e. after positional arguments. Returns the positional arguments.
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, fmt.Errorf("%w\n\n%w", err, errUsage)
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+func parseTime(name string, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("--%s must be an RFC 3339 time\n\n%w", name, errUsage)
+	}
+	return t, nil
+}
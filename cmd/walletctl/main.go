@@ -0,0 +1,160 @@
+// Command walletctl is the operations CLI of the wallet service. It calls the http API as the principal of --user, so
+// commands are authorized by its role and recorded to the audit log like any other request.
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/cryptonlx/crypto/pkg/client"
+)
+
+const usage = `usage: walletctl [flags] <command> [args] [command flags]
+
+commands:
+  user create <username>                            create a user
+  user show <username>                              account, role and wallets with balances
+  user transactions <username>                      transactions with ledgers, sorted by newest
+  wallet create <username> <currency> [--name name] create a wallet, named main without --name
+  wallet balance <wallet_id> [--at time]            balance, at a past RFC 3339 time with --at
+  wallet history <wallet_id> [--from time] [--to time]
+                                                    statement of ledgers with running balances
+  wallet adjust <wallet_id> <credit|debit> <amount> --reason-code <code> --reason <reason> [--nonce n]
+                                                    create a manual adjustment pending approval,
+                                                    code is correction, chargeback, goodwill,
+                                                    fee_refund or fraud_recovery
+  wallet freeze <wallet_id> --reason <reason> [--debit-only]
+                                                    freeze a wallet, or only its debits
+  wallet unfreeze <wallet_id> --reason <reason>     set a frozen wallet active
+  wallet status-history <wallet_id>                 status changes with reason and actor
+  adjustment pending [--limit n]                    adjustments pending approval, sorted by oldest
+  adjustment approve <transaction_id>               post the ledger of an adjustment created by
+                                                    another operator
+  adjustment reject <transaction_id>                reject an adjustment pending approval
+  transactions pending [--older-than 1m] [--limit n]
+                                                    transactions left pending by a crashed server
+  reconcile                                         check ledgers against wallet balances and
+                                                    transactions, exit status 3 on discrepancies
+
+flags:
+  --url       base url of the server, env WALLETCTL_URL (default http://localhost:8080)
+  --user      principal sent by basic auth, env WALLETCTL_USER
+  --cacert    PEM CA certificate verifying the server, env WALLETCTL_CACERT
+  --cert      PEM client certificate, env WALLETCTL_CERT
+  --key       PEM key of the client certificate, env WALLETCTL_KEY
+  --output    table or json, env WALLETCTL_OUTPUT (default table)
+  --timeout   timeout of each request (default 30s)`
+
+var (
+	// errUsage
+	// Invalid command line, exit status 2.
+	errUsage = errors.New(usage)
+	// errDiscrepancies
+	// Reconciliation found discrepancies, exit status 3.
+	errDiscrepancies = errors.New("reconciliation found discrepancies")
+)
+
+func main() {
+	err := run(context.Background(), os.Args[1:], os.Getenv, os.Stdout)
+	switch {
+	case err == nil:
+	case errors.Is(err, flag.ErrHelp):
+		fmt.Println(usage)
+	case errors.Is(err, errUsage):
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	case errors.Is(err, errDiscrepancies):
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(3)
+	default:
+		fmt.Fprintf(os.Stderr, "error. %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// Flags
+// Global flags, before the command.
+type Flags struct {
+	Url     string
+	User    string
+	CaCert  string
+	Cert    string
+	Key     string
+	Output  string
+	Timeout time.Duration
+}
+
+func run(ctx context.Context, args []string, getenv func(string) string, stdout io.Writer) error {
+	var flags Flags
+	fs := flag.NewFlagSet("walletctl", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.StringVar(&flags.Url, "url", envOr(getenv, "WALLETCTL_URL", "http://localhost:8080"), "")
+	fs.StringVar(&flags.User, "user", getenv("WALLETCTL_USER"), "")
+	fs.StringVar(&flags.CaCert, "cacert", getenv("WALLETCTL_CACERT"), "")
+	fs.StringVar(&flags.Cert, "cert", getenv("WALLETCTL_CERT"), "")
+	fs.StringVar(&flags.Key, "key", getenv("WALLETCTL_KEY"), "")
+	fs.StringVar(&flags.Output, "output", envOr(getenv, "WALLETCTL_OUTPUT", "table"), "")
+	fs.DurationVar(&flags.Timeout, "timeout", 30*time.Second, "")
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return fmt.Errorf("%w\n\n%w", err, errUsage)
+	}
+	if flags.Output != "table" && flags.Output != "json" {
+		return fmt.Errorf("--output must be table or json\n\n%w", errUsage)
+	}
+
+	c, err := newClient(flags)
+	if err != nil {
+		return err
+	}
+	return command(ctx, c, printer{w: stdout, json: flags.Output == "json"}, fs.Args())
+}
+
+func newClient(flags Flags) (*client.Client, error) {
+	options := []client.Option{client.WithTimeout(flags.Timeout)}
+	if flags.User != "" {
+		options = append(options, client.WithAuth(client.BasicAuth(flags.User)))
+	}
+	if flags.CaCert != "" || flags.Cert != "" {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if flags.CaCert != "" {
+			pem, err := os.ReadFile(flags.CaCert)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no certificate in %s", flags.CaCert)
+			}
+		}
+		if flags.Cert != "" {
+			cert, err := tls.LoadX509KeyPair(flags.Cert, flags.Key)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = tlsConfig
+		options = append(options, client.WithHTTPClient(&http.Client{Transport: transport}))
+	}
+	return client.New(strings.TrimSuffix(flags.Url, "/"), options...)
+}
+
+func envOr(getenv func(string) string, key string, fallback string) string {
+	if v := getenv(key); v != "" {
+		return v
+	}
+	return fallback
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const (
+	walletBody      = `{"data":{"wallet":{"id":4,"user_account_id":1,"currency":"USD","balance":"10","status":"debit_frozen"}},"error":null}`
+	adjustmentBody  = `{"data":{"transaction":{"id":5,"nonce":7,"status":"pending_approval","operation":"adjustment","metadata":{"amount":"2.5","reason_code":"chargeback","reason":"chargeback 1","created_by":"ada"},"ledgers":[]}},"error":null}`
+	approvalBody    = `{"data":{"transaction":{"id":5,"nonce":7,"status":"success","operation":"adjustment","metadata":{"amount":"2.5","reason_code":"chargeback","reason":"chargeback 1","created_by":"ada","approved_by":"grace"},"ledgers":[{"id":3,"wallet_id":4,"transaction_id":5,"entry_type":"credit","amount":"2.5","balance":"12.5"}]}},"error":null}`
+	reconcileBody   = `{"data":{"at":"2025-06-09T02:02:31Z","wallets":2,"transactions":3,"wallet_discrepancies":[],"transaction_discrepancies":[]},"error":null}`
+	discrepancyBody = `{"data":{"at":"2025-06-09T02:02:31Z","wallets":2,"transactions":3,"wallet_discrepancies":[{"wallet_id":4,"currency":"USD","balance":"10","ledger_sum":"9","last_ledger_balance":null,"ledgers":0}],"transaction_discrepancies":[]},"error":null}`
+)
+
+func TestRun(t *testing.T) {
+	tests := []struct {
+		name          string
+		args          []string
+		response      string
+		wantErr       error
+		wantRequest   string
+		wantBody      map[string]any
+		wantOutput    []string
+		wantJsonField string
+	}{
+		{
+			name:        "adjust with flags after arguments",
+			args:        []string{"--user", "ada", "wallet", "adjust", "4", "credit", "2.5", "--reason-code", "chargeback", "--reason", "chargeback 1", "--nonce", "7"},
+			response:    adjustmentBody,
+			wantRequest: "POST /admin/wallet/4/adjustment",
+			wantBody:    map[string]any{"entry_type": "credit", "amount": "2.5", "reason_code": "chargeback", "reason": "chargeback 1", "nonce": float64(7)},
+			wantOutput:  []string{"TRANSACTION", "pending_approval", "2.5", "chargeback 1"},
+		},
+		{
+			name:        "approve adjustment",
+			args:        []string{"--user", "ada", "adjustment", "approve", "5"},
+			response:    approvalBody,
+			wantRequest: "POST /admin/adjustment/5/approve",
+			wantOutput:  []string{"success", "12.5"},
+		},
+		{
+			name:        "pending adjustments",
+			args:        []string{"--user", "ada", "adjustment", "pending", "--limit", "10"},
+			response:    `{"data":{"transactions":[]},"error":null}`,
+			wantRequest: "GET /admin/adjustments/pending?limit=10",
+			wantOutput:  []string{"TRANSACTION"},
+		},
+		{
+			name:        "freeze debits",
+			args:        []string{"--user", "ada", "wallet", "freeze", "4", "--debit-only", "--reason", "dispute"},
+			response:    walletBody,
+			wantRequest: "PUT /admin/wallet/4/status",
+			wantBody:    map[string]any{"status": "debit_frozen", "reason": "dispute"},
+			wantOutput:  []string{"WALLET", "debit_frozen"},
+		},
+		{
+			name:        "pending transactions",
+			args:        []string{"--user", "ada", "transactions", "pending", "--older-than", "5m", "--limit", "10"},
+			response:    `{"data":{"transactions":[]},"error":null}`,
+			wantRequest: "GET /admin/transactions/pending?limit=10&older_than=5m0s",
+			wantOutput:  []string{"TRANSACTION"},
+		},
+		{
+			name:          "reconcile as json",
+			args:          []string{"--user", "ada", "--output", "json", "reconcile"},
+			response:      reconcileBody,
+			wantRequest:   "GET /admin/reconciliation",
+			wantJsonField: "wallet_discrepancies",
+		},
+		{
+			name:        "reconcile with discrepancies",
+			args:        []string{"--user", "ada", "reconcile"},
+			response:    discrepancyBody,
+			wantRequest: "GET /admin/reconciliation",
+			wantErr:     errDiscrepancies,
+			wantOutput:  []string{"LEDGER SUM", "9"},
+		},
+		{
+			name:    "adjust without reason",
+			args:    []string{"wallet", "adjust", "4", "credit", "2.5"},
+			wantErr: errUsage,
+		},
+		{
+			name:    "adjust without reason code",
+			args:    []string{"wallet", "adjust", "4", "credit", "2.5", "--reason", "chargeback 1"},
+			wantErr: errUsage,
+		},
+		{
+			name:    "unknown command",
+			args:    []string{"wallets"},
+			wantErr: errUsage,
+		},
+		{
+			name:    "invalid output",
+			args:    []string{"--output", "xml", "reconcile"},
+			wantErr: errUsage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotRequest, gotAuthorization string
+			var gotBody map[string]any
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotRequest = r.Method + " " + r.URL.RequestURI()
+				gotAuthorization = r.Header.Get("Authorization")
+				json.NewDecoder(r.Body).Decode(&gotBody)
+				io.WriteString(w, tt.response)
+			}))
+			defer ts.Close()
+
+			var stdout bytes.Buffer
+			getenv := func(key string) string {
+				if key == "WALLETCTL_URL" {
+					return ts.URL
+				}
+				return ""
+			}
+			err := run(context.Background(), tt.args, getenv, &stdout)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("run want nil err. got %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("run want err %v. got %v", tt.wantErr, err)
+			}
+			if gotRequest != tt.wantRequest {
+				t.Fatalf("want request %q. got %q", tt.wantRequest, gotRequest)
+			}
+			if tt.wantRequest != "" && gotAuthorization != "Basic YWRhOg==" {
+				t.Fatalf("want basic auth of ada. got %q", gotAuthorization)
+			}
+			for k, v := range tt.wantBody {
+				if gotBody[k] != v {
+					t.Fatalf("want body %s=%v. got %v", k, v, gotBody)
+				}
+			}
+			for _, s := range tt.wantOutput {
+				if !strings.Contains(stdout.String(), s) {
+					t.Fatalf("want output containing %q. got\n%s", s, stdout.String())
+				}
+			}
+			if tt.wantJsonField != "" {
+				var out map[string]any
+				if err := json.Unmarshal(stdout.Bytes(), &out); err != nil {
+					t.Fatalf("want json output. got %v\n%s", err, stdout.String())
+				}
+				if _, ok := out[tt.wantJsonField]; !ok {
+					t.Fatalf("want json field %s. got\n%s", tt.wantJsonField, stdout.String())
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cryptonlx/crypto/pkg/client"
+
+	"github.com/shopspring/decimal"
+)
+
+// printer
+// Writes results as aligned tables, or as the indented json of the client types.
+type printer struct {
+	w    io.Writer
+	json bool
+}
+
+func (p printer) print(v any, table func(w *tabwriter.Writer)) error {
+	if p.json {
+		e := json.NewEncoder(p.w)
+		e.SetIndent("", "  ")
+		return e.Encode(v)
+	}
+	w := tabwriter.NewWriter(p.w, 0, 0, 2, ' ', 0)
+	table(w)
+	return w.Flush()
+}
+
+func (p printer) user(user client.User) error {
+	return p.print(user, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "ID\tUSERNAME")
+		fmt.Fprintf(w, "%d\t%s\n", user.Id, user.Username)
+	})
+}
+
+func (p printer) adminUser(user client.AdminUser) error {
+	return p.print(user, func(w *tabwriter.Writer) {
+		u := user.User
+		fmt.Fprintln(w, "ID\tUSERNAME\tROLE\tFROZEN\tSTATUS\tKYC TIER")
+		fmt.Fprintf(w, "%d\t%s\t%s\t%t\t%s\t%d\n", u.Id, u.Username, u.Role, u.Frozen, u.Status, u.KycTier)
+		fmt.Fprintln(w)
+		walletRows(w, user.Wallets)
+	})
+}
+
+func (p printer) wallet(wallet client.Wallet) error {
+	return p.print(wallet, func(w *tabwriter.Writer) {
+		walletRows(w, []client.Wallet{wallet})
+	})
+}
+
+func walletRows(w io.Writer, wallets []client.Wallet) {
+	fmt.Fprintln(w, "WALLET\tNAME\tCURRENCY\tBALANCE\tSTATUS")
+	for _, wallet := range wallets {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", wallet.Id, wallet.Name, wallet.Currency, wallet.Balance, wallet.Status)
+	}
+}
+
+func (p printer) balance(balance client.WalletBalance) error {
+	return p.print(balance, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "WALLET\tCURRENCY\tBALANCE\tSTATUS\tAT")
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", balance.Wallet.Id, balance.Wallet.Currency, balance.Wallet.Balance,
+			balance.Wallet.Status, formatTime(balance.At))
+	})
+}
+
+func (p printer) statement(s client.Statement) error {
+	return p.print(s, func(w *tabwriter.Writer) {
+		from := "-"
+		if s.From != nil {
+			from = formatTime(*s.From)
+		}
+		fmt.Fprintln(w, "WALLET\tCURRENCY\tFROM\tTO\tOPENING\tCREDITS\tDEBITS\tCLOSING")
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", s.WalletId, s.Currency, from, formatTime(s.To),
+			s.OpeningBalance, s.TotalCredits, s.TotalDebits, s.ClosingBalance)
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "LEDGER\tTRANSACTION\tCREATED AT\tENTRY\tAMOUNT\tBALANCE")
+		for _, l := range s.Ledgers {
+			fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\n", l.Id, l.TransactionId, formatTime(l.CreatedAt), l.EntryType,
+				l.Amount, l.Balance)
+		}
+	})
+}
+
+func (p printer) transaction(transaction client.Transaction) error {
+	return p.print(transaction, func(w *tabwriter.Writer) {
+		transactionRows(w, []client.Transaction{transaction})
+	})
+}
+
+func (p printer) transactions(transactions []client.Transaction) error {
+	return p.print(transactions, func(w *tabwriter.Writer) {
+		transactionRows(w, transactions)
+	})
+}
+
+// transactionRows
+// One row per ledger, one row without ledger columns for transactions without ledgers.
+func transactionRows(w io.Writer, transactions []client.Transaction) {
+	fmt.Fprintln(w, "TRANSACTION\tCREATED AT\tOPERATION\tSTATUS\tREQUESTOR\tNONCE\tWALLET\tENTRY\tAMOUNT\tBALANCE\tREASON")
+	for _, t := range transactions {
+		reason := "-"
+		if t.MetaData.Reason != nil {
+			reason = *t.MetaData.Reason
+		}
+		row := fmt.Sprintf("%d\t%s\t%s\t%s\t%d\t%d", t.Id, formatTime(t.CreatedAt), t.Operation, t.Status,
+			t.RequestorId, t.Nonce)
+		if len(t.Ledgers) == 0 {
+			amount := "-"
+			if t.MetaData.Amount != nil {
+				amount = t.MetaData.Amount.String()
+			}
+			fmt.Fprintf(w, "%s\t-\t-\t%s\t-\t%s\n", row, amount, reason)
+		}
+		for _, l := range t.Ledgers {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\n", row, l.WalletId, l.EntryType, l.Amount, l.Balance, reason)
+		}
+	}
+}
+
+func (p printer) statusChanges(changes []client.WalletStatusChange) error {
+	return p.print(changes, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "ID\tWALLET\tCREATED AT\tFROM\tTO\tACTOR\tREASON")
+		for _, c := range changes {
+			fmt.Fprintf(w, "%d\t%d\t%s\t%s\t%s\t%s\t%s\n", c.Id, c.WalletId, formatTime(c.CreatedAt), c.FromStatus,
+				c.ToStatus, c.Actor, c.Reason)
+		}
+	})
+}
+
+func (p printer) reconciliation(r client.Reconciliation) error {
+	return p.print(r, func(w *tabwriter.Writer) {
+		fmt.Fprintln(w, "AT\tWALLETS\tTRANSACTIONS\tWALLET DISCREPANCIES\tTRANSACTION DISCREPANCIES")
+		fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%d\n", formatTime(r.At), r.Wallets, r.Transactions,
+			len(r.WalletDiscrepancies), len(r.TransactionDiscrepancies))
+		if len(r.WalletDiscrepancies) > 0 {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "WALLET\tCURRENCY\tBALANCE\tLEDGER SUM\tLAST LEDGER BALANCE\tLEDGERS")
+			for _, d := range r.WalletDiscrepancies {
+				fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%d\n", d.WalletId, d.Currency, d.Balance, d.LedgerSum,
+					optional(d.LastLedgerBalance), d.Ledgers)
+			}
+		}
+		if len(r.TransactionDiscrepancies) > 0 {
+			fmt.Fprintln(w)
+			fmt.Fprintln(w, "TRANSACTION\tSTATUS\tOPERATION\tLEDGERS\tCREDITS\tDEBITS")
+			for _, d := range r.TransactionDiscrepancies {
+				fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%s\t%s\n", d.Id, d.Status, d.Operation, d.Ledgers, d.Credits, d.Debits)
+			}
+		}
+	})
+}
+
+func optional(d *decimal.Decimal) string {
+	if d == nil {
+		return "-"
+	}
+	return d.String()
+}
+
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
@@ -15,70 +15,112 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/user": {
+        "/admin/adjustment/{transaction_id}/approve": {
             "post": {
-                "description": "Create a new user.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
                 ],
+                "description": "Posts the ledger of the adjustment and records the approver. Fails the adjustment if the wallet was closed, or a debit exceeds the balance. Roles operator and admin only, not by the maker of the adjustment nor on own wallets.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/problem+json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "Create a new user.",
+                "summary": "Approve an adjustment pending approval.",
                 "parameters": [
                     {
-                        "description": "Create User Request Body",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/user.CreateUserRequestBody"
-                        }
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction Id of the adjustment",
+                        "name": "transaction_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.CreateUserResponseBody"
+                            "$ref": "#/definitions/admin.AdjustmentResponseBody"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponseBody400"
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponseBody500"
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
                         }
                     }
                 }
             }
         },
-        "/user/{username}/transactions": {
-            "get": {
-                "description": "Get transactions of user's wallets sorted by newest.",
-                "consumes": [
-                    "application/json"
+        "/admin/adjustment/{transaction_id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
                 ],
+                "description": "Sets the adjustment to rejected without ledgers and records the actor. The maker may reject own adjustments. Roles operator and admin only, not on own wallets.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/problem+json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "Get transactions of user's wallets sorted by newest.",
+                "summary": "Reject an adjustment pending approval.",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "username",
-                        "name": "user_id",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction Id of the adjustment",
+                        "name": "transaction_id",
                         "in": "path",
                         "required": true
                     }
@@ -87,120 +129,233 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.TransactionsResponseBody"
+                            "$ref": "#/definitions/admin.AdjustmentResponseBody"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponseBody400"
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
                         }
                     }
                 }
             }
         },
-        "/user/{username}/wallets": {
+        "/admin/adjustments/pending": {
             "get": {
-                "description": "Get balances of user's wallets.",
-                "consumes": [
-                    "application/json"
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
                 ],
+                "description": "Get adjustments created and neither approved nor rejected yet. Roles support_readonly, operator and admin only.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/problem+json"
                 ],
                 "tags": [
-                    "user"
+                    "admin"
                 ],
-                "summary": "Get balances of user's wallets.",
+                "summary": "Get adjustments pending approval, sorted by oldest.",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "username",
-                        "name": "user_id",
-                        "in": "path",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max number of adjustments, default 100, max 1000",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.GetWalletsResponseBody"
+                            "$ref": "#/definitions/admin.PendingTransactionsResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponseBody500"
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
                         }
                     }
                 }
             }
         },
-        "/wallet": {
-            "post": {
-                "description": "Create a new wallet for user.",
+        "/admin/audit": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get audit events of wallet operation attempts sorted by newest. Roles support_readonly, operator and admin only.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/problem+json"
                 ],
                 "tags": [
-                    "wallet"
+                    "admin"
                 ],
-                "summary": "Create a new wallet for user.",
+                "summary": "Get audit events of wallet operation attempts sorted by newest.",
                 "parameters": [
                     {
-                        "description": "Create Wallet Request Body",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/user.CreateWalletRequestBody"
-                        }
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "principal of request",
+                        "name": "username",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "wallet id in request path",
+                        "name": "wallet_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 timestamp, inclusive",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 timestamp, exclusive",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max number of events, default 100, max 1000",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.CreateWalletResponseBody"
+                            "$ref": "#/definitions/audit.EventsResponseBody"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponseBody400"
+                            "$ref": "#/definitions/audit.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/audit.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/audit.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/audit.ProblemResponseBody"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponseBody500"
+                            "$ref": "#/definitions/audit.ProblemResponseBody"
                         }
                     }
                 }
             }
         },
-        "/wallet/{wallet_id}/deposit": {
-            "post": {
+        "/admin/reconciliation": {
+            "get": {
                 "security": [
                     {
                         "BasicAuth": []
                     }
                 ],
-                "description": "Deposit to wallet",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Checks in one snapshot that each wallet balance equals the sum of its ledgers and the balance after its newest ledger, that successful transactions have one ledger, two for transfers with equal credit and debit, and that other transactions have none. Reads all ledgers. Roles support_readonly, operator and admin only.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/problem+json"
                 ],
                 "tags": [
-                    "wallet"
+                    "admin"
                 ],
-                "summary": "Deposit to wallet",
+                "summary": "Reconcile ledgers with wallet balances and transactions.",
                 "parameters": [
                     {
                         "type": "string",
@@ -208,64 +363,52 @@ const docTemplate = `{
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "Wallet Id",
-                        "name": "wallet_id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Create Deposit Request Body",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/user.DepositRequestBody"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.DepositResponseBody"
+                            "$ref": "#/definitions/admin.ReconciliationResponseBody"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponseBody400"
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponseBody500"
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
                         }
                     }
                 }
             }
         },
-        "/wallet/{wallet_id}/transfer": {
-            "post": {
+        "/admin/transactions/pending": {
+            "get": {
                 "security": [
                     {
                         "BasicAuth": []
                     }
                 ],
-                "description": "Transfer to another wallet.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get transactions pending for longer than older_than. Wallet operations complete within their request, pending ones were left by a crashed process and have no ledgers. Roles support_readonly, operator and admin only.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/problem+json"
                 ],
                 "tags": [
-                    "wallet"
+                    "admin"
                 ],
-                "summary": "Transfer to another wallet.",
+                "summary": "Get transactions left pending, sorted by oldest.",
                 "parameters": [
                     {
                         "type": "string",
@@ -276,61 +419,73 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Wallet Id",
-                        "name": "wallet_id",
-                        "in": "path",
-                        "required": true
+                        "description": "min age as a Go duration, default 1m",
+                        "name": "older_than",
+                        "in": "query"
                     },
                     {
-                        "description": "Create Transfer Request Body",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/user.TransferRequestBody"
-                        }
+                        "type": "integer",
+                        "description": "max number of transactions, default 100, max 1000",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.TransferResponseBody"
+                            "$ref": "#/definitions/admin.PendingTransactionsResponseBody"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponseBody400"
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponseBody500"
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
                         }
                     }
                 }
             }
         },
-        "/wallet/{wallet_id}/withdrawal": {
-            "post": {
+        "/admin/user/{username}": {
+            "get": {
                 "security": [
                     {
                         "BasicAuth": []
                     }
                 ],
-                "description": "Withdraw from wallet",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Get account, role, frozen flag and wallets of any user. Roles support_readonly, operator and admin only.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "application/problem+json"
                 ],
                 "tags": [
-                    "wallet"
+                    "admin"
                 ],
-                "summary": "Withdraw from wallet",
+                "summary": "Get account and wallets of any user.",
                 "parameters": [
                     {
                         "type": "string",
@@ -341,46 +496,3893 @@ const docTemplate = `{
                     },
                     {
                         "type": "string",
-                        "description": "Wallet Id",
-                        "name": "wallet_id",
+                        "description": "username",
+                        "name": "username",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Create Withdraw Request Body",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/user.WithdrawRequestBody"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/user.WithdrawResponseBody"
+                            "$ref": "#/definitions/admin.UserResponseBody"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponseBody400"
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/user.ErrorResponseBody500"
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_cryptonlx_crypto_src_controllers_mux_user.Ledger": {
+        },
+        "/admin/user/{username}/freeze": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Frozen accounts cannot deposit, withdraw, transfer nor receive transfers. Operations in flight complete first.\nRoles operator and admin only, not on own account.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Freeze a user account.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin.UserAccountResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/user/{username}/role": {
+            "put": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Set role of a user. Role admin only, not on own account.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set role of a user.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Set Role Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.SetRoleRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin.UserAccountResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/user/{username}/unfreeze": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Unfreeze a user account. Roles operator and admin only, not on own account.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Unfreeze a user account.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin.UserAccountResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/wallet/{wallet_id}/adjustment": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Credit or debit any wallet, frozen accounts included, with a reason code and reason. The adjustment is pending_approval without ledgers until approved by another operator. Roles operator and admin only, not on own wallets.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Create a manual adjustment of any wallet, pending approval.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet Id",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Adjustment Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.AdjustmentRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin.AdjustmentResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/wallet/{wallet_id}/approval-policy": {
+            "put": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Transfers from the wallet over threshold are then pending_approval until approved by required_approvals of the approvers, and expire after timeout_seconds, between 60 and 2592000. Transfers already pending keep the approvers and expiry they were created with. Role admin only, not on own wallets.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set the approval policy of transfers from a wallet.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet Id",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Set Approval Policy Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.SetApprovalPolicyRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ApprovalPolicyResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Transfers from the wallet then need no approval. Transfers already pending approval are unaffected. Role admin only, not on own wallets.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Delete the approval policy of transfers from a wallet.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet Id",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin.DeleteApprovalPolicyResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/wallet/{wallet_id}/status": {
+            "put": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Transition a wallet to active, frozen (no credit nor debit), debit_frozen (credit only) or closed. Closing requires zero balance and is final. Roles operator and admin only, not on own wallets.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Freeze, unfreeze or close a wallet.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet Id",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Set Wallet Status Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/admin.SetWalletStatusRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin.WalletResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/wallet/{wallet_id}/status/history": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get status transitions of a wallet with reason and actor. Roles support_readonly, operator and admin only.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get status history of a wallet sorted by newest.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet Id",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/admin.WalletStatusHistoryResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/admin.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Liveness of the process. Does not check dependencies, a failing database does not warrant a restart.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness of the process.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.AliveResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment-requests": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Requests payer to pay amount into wallet_id, whose currency must be currency. The payer lists it in the incoming payment requests and pays it by transfer before expires_at. Owners and spenders of the wallet only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Request a payment from another user.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Create Payment Request Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.CreatePaymentRequestRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.PaymentRequestResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment-requests/{payment_request_id}/cancel": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Cancels the pending payment request. Requester only, otherwise 403, or 404 for other users.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Cancel a payment request.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payment Request Id",
+                        "name": "payment_request_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.PaymentRequestResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/payment-requests/{payment_request_id}/pay": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Transfers the amount of the pending payment request from wallet_id to the wallet of the request, and sets the request paid in the same database transaction. The transaction metadata has the payment_request_id. Amounts over the threshold of the approval policy of wallet_id cannot be paid by request. Payer only, otherwise 403, or 404 for other users.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Pay a payment request.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Payment Request Id",
+                        "name": "payment_request_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Pay Payment Request Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.PayPaymentRequestRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.TransferResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Readiness to serve traffic. Pings the database and checks the schema is migrated to at least the version the server expects. A newer schema is reported as migrations ahead, as during a rolling deploy, and does not fail readiness.\nPool saturation is reported but does not fail readiness. 503 while server is draining for shutdown.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness to serve traffic.",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/health.ReadyResponseBody"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/health.ReadyResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/{transaction_id}/approve": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Records the approval of one of the approvers of the transfer. Once approved by the required number of approvers, the transfer executes and succeeds or fails like any transfer. Not by the requestor of the transfer. Transfers past their expiry are expired instead, with error approval_expired.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Approve a transfer pending approval.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction Id of the transfer",
+                        "name": "transaction_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.ApprovalResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/transaction/{transaction_id}/reject": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Sets the transfer to rejected without ledgers. By one of the approvers of the transfer, or by its requestor to cancel it.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "transaction"
+                ],
+                "summary": "Reject a transfer pending approval.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Transaction Id of the transfer",
+                        "name": "transaction_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.ApprovalResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/user": {
+            "post": {
+                "description": "Create a new user. Usernames are 3 to 64 letters and digits separated by single ., _ or -, unique regardless of case.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Create a new user.",
+                "parameters": [
+                    {
+                        "description": "Create User Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.CreateUserRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.CreateUserResponseBody"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{username}": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get profile, account status and KYC tier of user. Owner or roles support_readonly, operator and admin only, otherwise 404.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get profile of user.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProfileResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "display_name and email are updated by the user. status and kyc_tier are updated by roles operator and admin, not on own account.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Update profile of user.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Profile Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.UpdateProfileRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProfileResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{username}/approvals": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get transfers pending approval, not expired, where user is an approver without a decision yet. Owner or roles support_readonly, operator and admin only, otherwise 404.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get transfers awaiting the decision of user, sorted by oldest.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.TransactionsResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{username}/events": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Streams postings to wallets of user as ` + "`" + `ledger` + "`" + ` events with the ledger id as event id, and status changes of transactions requested by user as ` + "`" + `transaction` + "`" + ` events without id, until the client disconnects. Reconnect with the Last-Event-ID header to receive the ledgers after that id first; transaction events are not replayed. Owner or roles support_readonly, operator and admin only, otherwise 404.",
+                "produces": [
+                    "text/event-stream",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Stream activity of user as server-sent events.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "id of the last ledger event received",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "data of ledger events, transaction events carry a Transaction",
+                        "schema": {
+                            "$ref": "#/definitions/activity.Ledger"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/activity.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/activity.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/activity.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/activity.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{username}/payment-requests": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Incoming payment requests are to be paid by user, outgoing ones are requested by user. Owner or roles support_readonly, operator and admin only, otherwise 404.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "payment"
+                ],
+                "summary": "Get payment requests of user sorted by newest.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "incoming (default) or outgoing",
+                        "name": "direction",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, paid, cancelled or expired, all if omitted",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.PaymentRequestsResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{username}/transactions": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get transactions of user's wallets sorted by newest. Owner or roles support_readonly, operator and admin only, otherwise 404.\nWith Accept text/csv or application/x-ndjson, streams one row per ledger of the user's wallets with columns transaction_id, transaction_created_at, requestor_id, nonce, operation, status, ledger_id, wallet_id, entry_type, amount, balance, ledger_created_at.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get transactions of user's wallets sorted by newest.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "application/json (default), text/csv or application/x-ndjson",
+                        "name": "Accept",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.TransactionsResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{username}/wallets": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get balances of user's wallets. Owner or roles support_readonly, operator and admin only, otherwise 404.\nWith at, balances as of that instant from ledger balance snapshots. Statuses are current.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Get balances of user's wallets.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "user_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 timestamp, inclusive, not in the future",
+                        "name": "at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.GetWalletsResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{username}/webhooks": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get webhooks of user sorted by oldest, without secrets. Owner or roles support_readonly, operator and admin only, otherwise 404.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Get webhooks of user.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.WebhooksResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "The url must be https and resolve to public addresses, checked again on every delivery. Events are POSTed as json with headers X-Webhook-Delivery (id, deliveries are sent at least once), X-Webhook-Event and X-Webhook-Signature t=\u003cunix seconds\u003e,v1=\u003chex hmac-sha256 of \"\u003ct\u003e.\u003cbody\u003e\" keyed by secret\u003e. Non 2xx responses are retried with exponential backoff until dead. The secret is only returned here. Owner only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Subscribe a url to events of user.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create Webhook Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/webhook.CreateWebhookRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.CreateWebhookResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{username}/webhooks/deliveries/{delivery_id}/replay": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Schedules a delivery, typically a dead one, for now with a fresh retry budget. The payload is unchanged and signed at send time. Owner only.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Send a delivery again.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "delivery id",
+                        "name": "delivery_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ReplayResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{username}/webhooks/{webhook_id}": {
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Stops deliveries to the webhook, pending deliveries are dead. Owner only.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Delete webhook of user.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "webhook id",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.DeleteWebhookResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/{username}/webhooks/{webhook_id}/deliveries": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get deliveries of webhook with their status, attempts and last error. Owner or roles support_readonly, operator and admin only, otherwise 404.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Get deliveries of webhook sorted by newest.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "username",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "webhook id",
+                        "name": "webhook_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "max number of deliveries, default 100, max 1000",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.DeliveriesResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/webhook.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Create a new wallet for user. User only, otherwise 403.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Create a new wallet for user.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Create Wallet Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.CreateWalletRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.CreateWalletResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/{wallet_id}/approval-policy": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Transfers over threshold are pending approval until approved by required_approvals of the approvers, and expire after timeout_seconds. 404 if transfers need no approval. Owner or roles support_readonly, operator and admin only, otherwise 404.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Get the approval policy of transfers from the wallet.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet Id",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.ApprovalPolicyResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/{wallet_id}/balance": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get balance of wallet as of an instant from ledger balance snapshots, now if at is omitted. Status is current. Owner or roles support_readonly, operator and admin only, otherwise 404.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Get balance of wallet as of an instant.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet Id",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 timestamp, inclusive, not in the future",
+                        "name": "at",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.WalletBalanceResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/{wallet_id}/deposit": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Deposit to wallet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Deposit to wallet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet Id",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create Deposit Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.DepositRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.DepositResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/{wallet_id}/members": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Owners operate the wallet and manage its members, spenders operate it with their debits up to spend_limit in total per UTC day, viewers read it. Members or roles support_readonly, operator and admin only, otherwise 404.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Get the members of the wallet.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet Id",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.MembersResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/{wallet_id}/members/{username}": {
+            "put": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Spenders require spend_limit, the maximum total of their debits of the wallet per UTC day (00:00 to 24:00 UTC), successful withdrawals and transfers out summed. The wallet keeps at least one owner. Owners of the wallet only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Add a member to the wallet or change its role.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet Id",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "username of the member",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Set Member Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.SetMemberRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.MemberResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Owners of the wallet, or the member leaving it. The last owner cannot be removed.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Remove a member from the wallet.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet Id",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "username of the member",
+                        "name": "username",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.RemoveMemberResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/{wallet_id}/statement": {
+            "get": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Get opening balance, ledgers sorted by oldest with running balance, totals and closing balance of wallet in a period. Owner or roles support_readonly, operator and admin only, otherwise 404.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Get statement of wallet.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Wallet Id",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 timestamp, inclusive. Defaults to the first ledger",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 timestamp, exclusive. Defaults to now",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.StatementResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/{wallet_id}/transfer": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Transfer to another wallet. Transfers over the threshold of the approval policy of the wallet are pending_approval without ledgers, and execute once approved by the required number of its approvers.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Transfer to another wallet.",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet Id",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create Transfer Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.TransferRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.TransferResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        },
+        "/wallet/{wallet_id}/withdrawal": {
+            "post": {
+                "security": [
+                    {
+                        "BasicAuth": []
+                    }
+                ],
+                "description": "Withdraw from wallet",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Withdraw from wallet",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basic Authorization",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Wallet Id",
+                        "name": "wallet_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create Withdraw Request Body",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/user.WithdrawRequestBody"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/user.WithdrawResponseBody"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/user.ProblemResponseBody"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "activity.Ledger": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "40.1122"
+                },
+                "balance": {
+                    "type": "string",
+                    "example": "2.2324"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "entry_type": {
+                    "type": "string",
+                    "example": "credit"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 12222214214
+                },
+                "transaction_id": {
+                    "type": "integer",
+                    "example": 1749286345000
+                },
+                "wallet_id": {
+                    "type": "integer",
+                    "example": 1021
+                }
+            }
+        },
+        "activity.ProblemResponseBody": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "insufficient_funds"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "insufficient_funds"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/wallet/1/withdrawal"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 422
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Unprocessable Entity"
+                },
+                "trace_id": {
+                    "type": "string",
+                    "example": "5d2e3a4c-7f6b-4a51-9d0e-2f1c8b7a6e5d"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "admin.AdjustmentRequestBody": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "10.23"
+                },
+                "entry_type": {
+                    "type": "string",
+                    "enum": [
+                        "credit",
+                        "debit"
+                    ],
+                    "example": "credit"
+                },
+                "nonce": {
+                    "type": "integer",
+                    "example": 1749286345000
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "chargeback 1234"
+                },
+                "reason_code": {
+                    "type": "string",
+                    "enum": [
+                        "correction",
+                        "chargeback",
+                        "goodwill",
+                        "fee_refund",
+                        "fraud_recovery"
+                    ],
+                    "example": "chargeback"
+                }
+            }
+        },
+        "admin.AdjustmentResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/admin.AdjustmentResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "admin.AdjustmentResponseData": {
+            "type": "object",
+            "properties": {
+                "transaction": {
+                    "$ref": "#/definitions/admin.Transaction"
+                }
+            }
+        },
+        "admin.ApprovalPolicy": {
+            "type": "object",
+            "properties": {
+                "approvers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "grace",
+                        "linus",
+                        "margaret"
+                    ]
+                },
+                "required_approvals": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "threshold": {
+                    "type": "string",
+                    "example": "10000"
+                },
+                "timeout_seconds": {
+                    "type": "integer",
+                    "example": 86400
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "updated_by": {
+                    "type": "string",
+                    "example": "ada"
+                },
+                "wallet_id": {
+                    "type": "integer",
+                    "example": 1021
+                }
+            }
+        },
+        "admin.ApprovalPolicyResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/admin.ApprovalPolicyResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "admin.ApprovalPolicyResponseData": {
+            "type": "object",
+            "properties": {
+                "approval_policy": {
+                    "$ref": "#/definitions/admin.ApprovalPolicy"
+                }
+            }
+        },
+        "admin.DeleteApprovalPolicyResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/admin.DeleteApprovalPolicyResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "admin.DeleteApprovalPolicyResponseData": {
+            "type": "object",
+            "properties": {
+                "wallet_id": {
+                    "type": "integer",
+                    "example": 1021
+                }
+            }
+        },
+        "admin.Ledger": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "40.1122"
+                },
+                "balance": {
+                    "type": "string",
+                    "example": "2.2324"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "entry_type": {
+                    "type": "string",
+                    "example": "credit"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 12222214214
+                },
+                "transaction_id": {
+                    "type": "integer",
+                    "example": 1749286345000
+                },
+                "wallet_id": {
+                    "type": "integer",
+                    "example": 1021
+                }
+            }
+        },
+        "admin.PendingTransactionsResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/admin.PendingTransactionsResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "admin.PendingTransactionsResponseData": {
+            "type": "object",
+            "properties": {
+                "transactions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.Transaction"
+                    }
+                }
+            }
+        },
+        "admin.ProblemResponseBody": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "insufficient_funds"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "insufficient_funds"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/wallet/1/withdrawal"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 422
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Unprocessable Entity"
+                },
+                "trace_id": {
+                    "type": "string",
+                    "example": "5d2e3a4c-7f6b-4a51-9d0e-2f1c8b7a6e5d"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "admin.ReconciliationResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/admin.ReconciliationResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "admin.ReconciliationResponseData": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string",
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "transaction_discrepancies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.TransactionDiscrepancy"
+                    }
+                },
+                "transactions": {
+                    "type": "integer",
+                    "example": 5400
+                },
+                "wallet_discrepancies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.WalletDiscrepancy"
+                    }
+                },
+                "wallets": {
+                    "type": "integer",
+                    "example": 120
+                }
+            }
+        },
+        "admin.SetApprovalPolicyRequestBody": {
+            "type": "object",
+            "properties": {
+                "approvers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "grace",
+                        "linus",
+                        "margaret"
+                    ]
+                },
+                "required_approvals": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "threshold": {
+                    "type": "string",
+                    "example": "10000"
+                },
+                "timeout_seconds": {
+                    "type": "integer",
+                    "example": 86400
+                }
+            }
+        },
+        "admin.SetRoleRequestBody": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "customer",
+                        "support_readonly",
+                        "operator",
+                        "admin"
+                    ],
+                    "example": "support_readonly"
+                }
+            }
+        },
+        "admin.SetWalletStatusRequestBody": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "example": "suspected account takeover"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "frozen",
+                        "debit_frozen",
+                        "closed"
+                    ],
+                    "example": "frozen"
+                }
+            }
+        },
+        "admin.Transaction": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "ledgers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.Ledger"
+                    }
+                },
+                "metadata": {
+                    "$ref": "#/definitions/admin.TransactionMetaData"
+                },
+                "nonce": {
+                    "type": "integer",
+                    "example": 1749460653395
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "adjustment"
+                },
+                "requestor_id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "pending_approval",
+                        "success",
+                        "rejected"
+                    ],
+                    "example": "success"
+                }
+            }
+        },
+        "admin.TransactionDiscrepancy": {
+            "type": "object",
+            "properties": {
+                "credits": {
+                    "type": "string",
+                    "example": "0"
+                },
+                "debits": {
+                    "type": "string",
+                    "example": "10"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "ledgers": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "operation": {
+                    "type": "string",
+                    "example": "transfer"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "admin.TransactionMetaData": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "40.1122"
+                },
+                "approved_by": {
+                    "type": "string",
+                    "example": "grace"
+                },
+                "created_by": {
+                    "type": "string",
+                    "example": "ada"
+                },
+                "entry_type": {
+                    "type": "string",
+                    "example": "credit"
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "chargeback 1234"
+                },
+                "reason_code": {
+                    "type": "string",
+                    "example": "chargeback"
+                },
+                "rejected_by": {
+                    "type": "string",
+                    "example": "grace"
+                },
+                "source_wallet_id": {
+                    "type": "integer",
+                    "example": 1021
+                }
+            }
+        },
+        "admin.UserAccount": {
+            "type": "object",
+            "properties": {
+                "frozen": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "kyc_tier": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "customer",
+                        "support_readonly",
+                        "operator",
+                        "admin"
+                    ],
+                    "example": "customer"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "verified",
+                        "suspended"
+                    ],
+                    "example": "verified"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "user1"
+                }
+            }
+        },
+        "admin.UserAccountResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/admin.UserAccountResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "admin.UserAccountResponseData": {
+            "type": "object",
+            "properties": {
+                "user": {
+                    "$ref": "#/definitions/admin.UserAccount"
+                }
+            }
+        },
+        "admin.UserResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/admin.UserResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "admin.UserResponseData": {
+            "type": "object",
+            "properties": {
+                "user": {
+                    "$ref": "#/definitions/admin.UserAccount"
+                },
+                "wallets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.Wallet"
+                    }
+                }
+            }
+        },
+        "admin.Wallet": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "string",
+                    "example": "10.000123"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "main"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "frozen",
+                        "debit_frozen",
+                        "closed"
+                    ],
+                    "example": "active"
+                },
+                "user_account_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "admin.WalletDiscrepancy": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "string",
+                    "example": "10.5"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "last_ledger_balance": {
+                    "description": "LastLedgerBalance is null for wallets without ledgers.",
+                    "type": "string",
+                    "example": "10"
+                },
+                "ledger_sum": {
+                    "type": "string",
+                    "example": "10"
+                },
+                "ledgers": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "wallet_id": {
+                    "type": "integer",
+                    "example": 1021
+                }
+            }
+        },
+        "admin.WalletResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/admin.WalletResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "admin.WalletResponseData": {
+            "type": "object",
+            "properties": {
+                "wallet": {
+                    "$ref": "#/definitions/admin.Wallet"
+                }
+            }
+        },
+        "admin.WalletStatusChange": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string",
+                    "example": "operator1"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "from_status": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "reason": {
+                    "type": "string",
+                    "example": "suspected account takeover"
+                },
+                "to_status": {
+                    "type": "string",
+                    "example": "frozen"
+                },
+                "wallet_id": {
+                    "type": "integer",
+                    "example": 1021
+                }
+            }
+        },
+        "admin.WalletStatusHistoryResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/admin.WalletStatusHistoryResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "admin.WalletStatusHistoryResponseData": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/admin.WalletStatusChange"
+                    }
+                }
+            }
+        },
+        "audit.AuditEvent": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "error_code": {
+                    "type": "string",
+                    "example": "insufficient_funds"
+                },
+                "http_status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "ip": {
+                    "type": "string",
+                    "example": "127.0.0.1"
+                },
+                "method": {
+                    "type": "string",
+                    "example": "POST"
+                },
+                "outcome": {
+                    "type": "string",
+                    "example": "rejected"
+                },
+                "path": {
+                    "type": "string",
+                    "example": "/wallet/1/withdrawal"
+                },
+                "payload_hash": {
+                    "type": "string",
+                    "example": "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"
+                },
+                "principal": {
+                    "type": "string",
+                    "example": "user1"
+                },
+                "route": {
+                    "type": "string",
+                    "example": "POST /wallet/{wallet_id}/withdrawal"
+                },
+                "trace_id": {
+                    "type": "string",
+                    "example": "5d2e3a4c-7f6b-4a51-9d0e-2f1c8b7a6e5d"
+                },
+                "wallet_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "audit.EventsResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/audit.EventsResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "audit.EventsResponseData": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/audit.AuditEvent"
+                    }
+                }
+            }
+        },
+        "audit.ProblemResponseBody": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "insufficient_funds"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "insufficient_funds"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/wallet/1/withdrawal"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 422
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Unprocessable Entity"
+                },
+                "trace_id": {
+                    "type": "string",
+                    "example": "5d2e3a4c-7f6b-4a51-9d0e-2f1c8b7a6e5d"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "github_com_cryptonlx_crypto_src_controllers_mux_user.Approval": {
+            "type": "object",
+            "properties": {
+                "approver": {
+                    "type": "string",
+                    "example": "grace"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "decision": {
+                    "type": "string",
+                    "enum": [
+                        "approved",
+                        "rejected"
+                    ],
+                    "example": "approved"
+                }
+            }
+        },
+        "github_com_cryptonlx_crypto_src_controllers_mux_user.ApprovalPolicy": {
+            "type": "object",
+            "properties": {
+                "approvers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "grace",
+                        "linus",
+                        "margaret"
+                    ]
+                },
+                "required_approvals": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "threshold": {
+                    "type": "string",
+                    "example": "10000"
+                },
+                "timeout_seconds": {
+                    "type": "integer",
+                    "example": 86400
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "updated_by": {
+                    "type": "string",
+                    "example": "ada"
+                },
+                "wallet_id": {
+                    "type": "integer",
+                    "example": 1021
+                }
+            }
+        },
+        "github_com_cryptonlx_crypto_src_controllers_mux_user.Ledger": {
             "type": "object",
             "properties": {
                 "amount": {
@@ -413,6 +4415,111 @@ const docTemplate = `{
                 }
             }
         },
+        "github_com_cryptonlx_crypto_src_controllers_mux_user.PaymentRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "12.5"
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-06-16T02:02:31.213543+08:00"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 3
+                },
+                "memo": {
+                    "type": "string",
+                    "example": "dinner"
+                },
+                "payer": {
+                    "type": "string",
+                    "example": "grace"
+                },
+                "requester": {
+                    "type": "string",
+                    "example": "ada"
+                },
+                "status": {
+                    "description": "Status is expired for pending requests past expires_at.",
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "paid",
+                        "cancelled",
+                        "expired"
+                    ],
+                    "example": "pending"
+                },
+                "transaction_id": {
+                    "type": "integer",
+                    "x-nullable": true,
+                    "example": 21
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "wallet_id": {
+                    "type": "integer",
+                    "example": 1021
+                }
+            }
+        },
+        "github_com_cryptonlx_crypto_src_controllers_mux_user.Statement": {
+            "type": "object",
+            "properties": {
+                "closing_balance": {
+                    "type": "string",
+                    "example": "70.25"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "from": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": "2025-06-01T00:00:00Z"
+                },
+                "ledgers": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cryptonlx_crypto_src_controllers_mux_user.Ledger"
+                    }
+                },
+                "opening_balance": {
+                    "type": "string",
+                    "example": "10.5"
+                },
+                "to": {
+                    "type": "string",
+                    "example": "2025-07-01T00:00:00Z"
+                },
+                "total_credits": {
+                    "type": "string",
+                    "example": "100"
+                },
+                "total_debits": {
+                    "type": "string",
+                    "example": "40.25"
+                },
+                "wallet_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
         "github_com_cryptonlx_crypto_src_controllers_mux_user.Transaction": {
             "type": "object",
             "properties": {
@@ -445,56 +4552,307 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1
                 },
-                "status": {
+                "status": {
+                    "type": "string",
+                    "example": "success"
+                }
+            }
+        },
+        "github_com_cryptonlx_crypto_src_controllers_mux_user.TransactionMetaData": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string",
+                    "example": "40.1122"
+                },
+                "approvals_required": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "approvers": {
+                    "description": "Approvers, ApprovalsRequired and ExpiresAt are set for transfers pending approval.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "grace"
+                    ]
+                },
+                "destination_wallet_id": {
+                    "type": "integer",
+                    "example": 1022
+                },
+                "expires_at": {
+                    "type": "string",
+                    "example": "2025-06-10T02:02:31.213543+08:00"
+                },
+                "payment_request_id": {
+                    "description": "PaymentRequestId is set for transfers paying a payment request.",
+                    "type": "integer",
+                    "example": 3
+                },
+                "source_wallet_id": {
+                    "type": "integer",
+                    "example": 1021
+                }
+            }
+        },
+        "github_com_cryptonlx_crypto_src_controllers_mux_user.User": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "username": {
+                    "type": "string",
+                    "example": "user1"
+                }
+            }
+        },
+        "github_com_cryptonlx_crypto_src_controllers_mux_user.Wallet": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "type": "string",
+                    "example": "10.000123"
+                },
+                "currency": {
+                    "type": "string",
+                    "example": "USD"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "name": {
+                    "type": "string",
+                    "example": "main"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "active",
+                        "frozen",
+                        "debit_frozen",
+                        "closed"
+                    ],
+                    "example": "active"
+                },
+                "user_account_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "health.AliveResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/health.AliveResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "health.AliveResponseData": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "alive"
+                }
+            }
+        },
+        "health.Checks": {
+            "type": "object",
+            "properties": {
+                "database": {
+                    "$ref": "#/definitions/health.DatabaseCheck"
+                },
+                "migrations": {
+                    "$ref": "#/definitions/health.MigrationsCheck"
+                },
+                "pool": {
+                    "$ref": "#/definitions/health.PoolCheck"
+                }
+            }
+        },
+        "health.DatabaseCheck": {
+            "type": "object",
+            "properties": {
+                "latency_ms": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "example": "up"
+                }
+            }
+        },
+        "health.MigrationsCheck": {
+            "type": "object",
+            "properties": {
+                "expected_version": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "status": {
+                    "description": "Status is ahead when the schema is newer than the server expects, as during a rolling deploy. Ready still.",
+                    "type": "string",
+                    "enum": [
+                        "up",
+                        "ahead",
+                        "down"
+                    ],
+                    "example": "up"
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "health.PoolCheck": {
+            "type": "object",
+            "properties": {
+                "acquired_conns": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "idle_conns": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "max_conns": {
+                    "type": "integer",
+                    "example": 10
+                },
+                "saturation": {
+                    "type": "number",
+                    "example": 0.1
+                },
+                "status": {
+                    "type": "string",
+                    "example": "up"
+                },
+                "total_conns": {
+                    "type": "integer",
+                    "example": 2
+                }
+            }
+        },
+        "health.ReadyResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/health.ReadyResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "health.ReadyResponseData": {
+            "type": "object",
+            "properties": {
+                "checks": {
+                    "$ref": "#/definitions/health.Checks"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "ready",
+                        "not_ready",
+                        "draining"
+                    ],
+                    "example": "ready"
+                }
+            }
+        },
+        "user.ApprovalPolicyResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/user.ApprovalPolicyResponseData"
+                },
+                "error": {
                     "type": "string",
-                    "example": "success"
+                    "x-nullable": true,
+                    "example": ""
                 }
             }
         },
-        "github_com_cryptonlx_crypto_src_controllers_mux_user.TransactionMetaData": {
+        "user.ApprovalPolicyResponseData": {
             "type": "object",
             "properties": {
-                "amount": {
-                    "type": "string",
-                    "example": "40.1122"
-                },
-                "source_wallet_id": {
-                    "type": "integer",
-                    "example": 1021
+                "approval_policy": {
+                    "$ref": "#/definitions/github_com_cryptonlx_crypto_src_controllers_mux_user.ApprovalPolicy"
                 }
             }
         },
-        "github_com_cryptonlx_crypto_src_controllers_mux_user.User": {
+        "user.ApprovalResponseBody": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer",
-                    "example": 1
+                "data": {
+                    "$ref": "#/definitions/user.ApprovalResponseData"
                 },
-                "username": {
+                "error": {
                     "type": "string",
-                    "example": "user1"
+                    "x-nullable": true,
+                    "example": ""
                 }
             }
         },
-        "github_com_cryptonlx_crypto_src_controllers_mux_user.Wallet": {
+        "user.ApprovalResponseData": {
             "type": "object",
             "properties": {
-                "balance": {
+                "approvals": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cryptonlx_crypto_src_controllers_mux_user.Approval"
+                    }
+                },
+                "transaction": {
+                    "$ref": "#/definitions/github_com_cryptonlx_crypto_src_controllers_mux_user.Transaction"
+                }
+            }
+        },
+        "user.CreatePaymentRequestRequestBody": {
+            "type": "object",
+            "properties": {
+                "amount": {
                     "type": "string",
-                    "example": "10.000123"
+                    "example": "12.5"
                 },
                 "currency": {
                     "type": "string",
                     "example": "USD"
                 },
-                "id": {
-                    "type": "integer",
-                    "example": 1
+                "expires_at": {
+                    "description": "ExpiresAt defaults to 7 days from now, at most 90 days from now.",
+                    "type": "string",
+                    "example": "2025-06-16T02:02:31.213543+08:00"
                 },
-                "user_account_id": {
+                "memo": {
+                    "type": "string",
+                    "example": "dinner"
+                },
+                "payer": {
+                    "type": "string",
+                    "example": "grace"
+                },
+                "wallet_id": {
+                    "description": "WalletId is the wallet of the requestor to be paid into.",
                     "type": "integer",
-                    "example": 1
+                    "example": 1021
                 }
             }
         },
@@ -535,6 +4893,11 @@ const docTemplate = `{
                     "type": "string",
                     "example": "USD"
                 },
+                "name": {
+                    "description": "Name is unique per user and currency, main if empty.",
+                    "type": "string",
+                    "example": "savings"
+                },
                 "username": {
                     "type": "string",
                     "example": "username1"
@@ -590,6 +4953,14 @@ const docTemplate = `{
                     "type": "integer",
                     "example": 1
                 },
+                "name": {
+                    "type": "string",
+                    "example": "main"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "active"
+                },
                 "user_account_id": {
                     "type": "integer",
                     "example": 1
@@ -609,11 +4980,276 @@ const docTemplate = `{
                 }
             }
         },
-        "user.DepositResponseBody": {
+        "user.DepositResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/user.DepositResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "user.DepositResponseData": {
+            "type": "object",
+            "properties": {
+                "transaction": {
+                    "$ref": "#/definitions/github_com_cryptonlx_crypto_src_controllers_mux_user.Transaction"
+                }
+            }
+        },
+        "user.GetWalletsResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/user.GetWalletsResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "user.GetWalletsResponseData": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "description": "At is set for balances as of a past instant.",
+                    "type": "string",
+                    "example": "2025-06-30T23:59:59Z"
+                },
+                "user": {
+                    "$ref": "#/definitions/github_com_cryptonlx_crypto_src_controllers_mux_user.User"
+                },
+                "wallets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cryptonlx_crypto_src_controllers_mux_user.Wallet"
+                    }
+                }
+            }
+        },
+        "user.Member": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "owner",
+                        "spender",
+                        "viewer"
+                    ],
+                    "example": "spender"
+                },
+                "spend_limit": {
+                    "description": "SpendLimit is the maximum total of debits per UTC day, spenders only.",
+                    "type": "string",
+                    "example": "100"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "updated_by": {
+                    "type": "string",
+                    "example": "ada"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "grace"
+                },
+                "wallet_id": {
+                    "type": "integer",
+                    "example": 1021
+                }
+            }
+        },
+        "user.MemberResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/user.MemberResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "user.MemberResponseData": {
+            "type": "object",
+            "properties": {
+                "member": {
+                    "$ref": "#/definitions/user.Member"
+                }
+            }
+        },
+        "user.MembersResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/user.MembersResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "user.MembersResponseData": {
+            "type": "object",
+            "properties": {
+                "members": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/user.Member"
+                    }
+                }
+            }
+        },
+        "user.PayPaymentRequestRequestBody": {
+            "type": "object",
+            "properties": {
+                "nonce": {
+                    "type": "integer",
+                    "example": 1749286345000
+                },
+                "wallet_id": {
+                    "description": "WalletId is the wallet of the payer to transfer from.",
+                    "type": "integer",
+                    "example": 1022
+                }
+            }
+        },
+        "user.PaymentRequestResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/user.PaymentRequestResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "user.PaymentRequestResponseData": {
+            "type": "object",
+            "properties": {
+                "payment_request": {
+                    "$ref": "#/definitions/github_com_cryptonlx_crypto_src_controllers_mux_user.PaymentRequest"
+                }
+            }
+        },
+        "user.PaymentRequestsResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/user.PaymentRequestsResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "user.PaymentRequestsResponseData": {
+            "type": "object",
+            "properties": {
+                "payment_requests": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_cryptonlx_crypto_src_controllers_mux_user.PaymentRequest"
+                    }
+                }
+            }
+        },
+        "user.ProblemResponseBody": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "insufficient_funds"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "insufficient_funds"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/wallet/1/withdrawal"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 422
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Unprocessable Entity"
+                },
+                "trace_id": {
+                    "type": "string",
+                    "example": "5d2e3a4c-7f6b-4a51-9d0e-2f1c8b7a6e5d"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "user.Profile": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "display_name": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": "User One"
+                },
+                "email": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": "user1@example.com"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "kyc_tier": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "verified",
+                        "suspended"
+                    ],
+                    "example": "verified"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "user1"
+                }
+            }
+        },
+        "user.ProfileResponseBody": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/user.DepositResponseData"
+                    "$ref": "#/definitions/user.ProfileResponseData"
                 },
                 "error": {
                     "type": "string",
@@ -622,45 +5258,64 @@ const docTemplate = `{
                 }
             }
         },
-        "user.DepositResponseData": {
+        "user.ProfileResponseData": {
             "type": "object",
             "properties": {
-                "transaction": {
-                    "$ref": "#/definitions/github_com_cryptonlx_crypto_src_controllers_mux_user.Transaction"
+                "user": {
+                    "$ref": "#/definitions/user.Profile"
                 }
             }
         },
-        "user.ErrorResponseBody400": {
+        "user.RemoveMemberResponseBody": {
             "type": "object",
             "properties": {
                 "data": {
-                    "type": "integer",
-                    "example": 0
+                    "$ref": "#/definitions/user.RemoveMemberResponseData"
                 },
                 "error": {
                     "type": "string",
-                    "example": "error_bad_request"
+                    "x-nullable": true,
+                    "example": ""
                 }
             }
         },
-        "user.ErrorResponseBody500": {
+        "user.RemoveMemberResponseData": {
             "type": "object",
             "properties": {
-                "data": {
+                "username": {
+                    "type": "string",
+                    "example": "grace"
+                },
+                "wallet_id": {
                     "type": "integer",
-                    "example": 0
+                    "example": 1021
+                }
+            }
+        },
+        "user.SetMemberRequestBody": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "type": "string",
+                    "enum": [
+                        "owner",
+                        "spender",
+                        "viewer"
+                    ],
+                    "example": "spender"
                 },
-                "error": {
+                "spend_limit": {
+                    "description": "SpendLimit is required for spenders, not allowed otherwise.",
                     "type": "string",
-                    "example": "internal_server_error"
+                    "example": "100"
                 }
             }
         },
-        "user.GetWalletsResponseBody": {
+        "user.StatementResponseBody": {
             "type": "object",
             "properties": {
                 "data": {
-                    "$ref": "#/definitions/user.GetWalletsResponseData"
+                    "$ref": "#/definitions/user.StatementResponseData"
                 },
                 "error": {
                     "type": "string",
@@ -669,17 +5324,11 @@ const docTemplate = `{
                 }
             }
         },
-        "user.GetWalletsResponseData": {
+        "user.StatementResponseData": {
             "type": "object",
             "properties": {
-                "user": {
-                    "$ref": "#/definitions/github_com_cryptonlx_crypto_src_controllers_mux_user.User"
-                },
-                "wallets": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/github_com_cryptonlx_crypto_src_controllers_mux_user.Wallet"
-                    }
+                "statement": {
+                    "$ref": "#/definitions/github_com_cryptonlx_crypto_src_controllers_mux_user.Statement"
                 }
             }
         },
@@ -745,6 +5394,57 @@ const docTemplate = `{
                 }
             }
         },
+        "user.UpdateProfileRequestBody": {
+            "type": "object",
+            "properties": {
+                "display_name": {
+                    "type": "string",
+                    "example": "User One"
+                },
+                "email": {
+                    "type": "string",
+                    "example": "user1@example.com"
+                },
+                "kyc_tier": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "verified",
+                        "suspended"
+                    ],
+                    "example": "verified"
+                }
+            }
+        },
+        "user.WalletBalanceResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/user.WalletBalanceResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "user.WalletBalanceResponseData": {
+            "type": "object",
+            "properties": {
+                "at": {
+                    "type": "string",
+                    "example": "2025-06-30T23:59:59Z"
+                },
+                "wallet": {
+                    "$ref": "#/definitions/github_com_cryptonlx_crypto_src_controllers_mux_user.Wallet"
+                }
+            }
+        },
         "user.WithdrawLedger": {
             "type": "object",
             "properties": {
@@ -849,6 +5549,281 @@ const docTemplate = `{
                     "example": "success"
                 }
             }
+        },
+        "webhook.CreateWebhookRequestBody": {
+            "type": "object",
+            "properties": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "transaction.succeeded",
+                        "transaction.failed",
+                        "balance.changed"
+                    ]
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/webhooks/crypto"
+                }
+            }
+        },
+        "webhook.CreateWebhookResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/webhook.CreateWebhookResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "webhook.CreateWebhookResponseData": {
+            "type": "object",
+            "properties": {
+                "webhook": {
+                    "$ref": "#/definitions/webhook.CreatedWebhook"
+                }
+            }
+        },
+        "webhook.CreatedWebhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "transaction.succeeded",
+                        "balance.changed"
+                    ]
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "secret": {
+                    "type": "string",
+                    "example": "whsec_5d2e3a4c7f6b4a519d0e2f1c8b7a6e5d5d2e3a4c7f6b4a519d0e2f1c8b7a6e5d"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/webhooks/crypto"
+                }
+            }
+        },
+        "webhook.DeleteWebhookResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/webhook.DeleteWebhookResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "webhook.DeleteWebhookResponseData": {
+            "type": "object",
+            "properties": {
+                "webhook_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "webhook.DeliveriesResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/webhook.DeliveriesResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "webhook.DeliveriesResponseData": {
+            "type": "object",
+            "properties": {
+                "deliveries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/webhook.Delivery"
+                    }
+                }
+            }
+        },
+        "webhook.Delivery": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer",
+                    "example": 8
+                },
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "delivered_at": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "event_type": {
+                    "type": "string",
+                    "example": "balance.changed"
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "last_error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": "http status 503"
+                },
+                "next_attempt_at": {
+                    "description": "NextAttemptAt is the due time of pending deliveries.",
+                    "type": "string",
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "status": {
+                    "type": "string",
+                    "enum": [
+                        "pending",
+                        "delivered",
+                        "dead"
+                    ],
+                    "example": "dead"
+                },
+                "webhook_id": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "webhook.ProblemResponseBody": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string",
+                    "example": "insufficient_funds"
+                },
+                "detail": {
+                    "type": "string",
+                    "example": "insufficient_funds"
+                },
+                "instance": {
+                    "type": "string",
+                    "example": "/wallet/1/withdrawal"
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 422
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Unprocessable Entity"
+                },
+                "trace_id": {
+                    "type": "string",
+                    "example": "5d2e3a4c-7f6b-4a51-9d0e-2f1c8b7a6e5d"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "webhook.ReplayResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/webhook.ReplayResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "webhook.ReplayResponseData": {
+            "type": "object",
+            "properties": {
+                "delivery": {
+                    "$ref": "#/definitions/webhook.Delivery"
+                }
+            }
+        },
+        "webhook.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string",
+                    "example": "2025-06-09T02:02:31.213543+08:00"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "transaction.succeeded",
+                        "balance.changed"
+                    ]
+                },
+                "id": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://example.com/webhooks/crypto"
+                }
+            }
+        },
+        "webhook.WebhooksResponseBody": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/webhook.WebhooksResponseData"
+                },
+                "error": {
+                    "type": "string",
+                    "x-nullable": true,
+                    "example": ""
+                }
+            }
+        },
+        "webhook.WebhooksResponseData": {
+            "type": "object",
+            "properties": {
+                "webhooks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/webhook.Webhook"
+                    }
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,553 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// CreateUser
+// Unauthenticated. Usernames are unique in any case.
+func (c *Client) CreateUser(ctx context.Context, username string) (User, error) {
+	var data struct {
+		User User `json:"user"`
+	}
+	err := c.call(ctx, http.MethodPost, "/user", nil, map[string]any{"username": username}, &data)
+	return data.User, err
+}
+
+// Profile
+// Profile of username.
+func (c *Client) Profile(ctx context.Context, username string) (Profile, error) {
+	var data struct {
+		User Profile `json:"user"`
+	}
+	err := c.call(ctx, http.MethodGet, pathf("/user/%s", username), nil, nil, &data)
+	return data.User, err
+}
+
+// UpdateProfile
+// Updates the non-nil fields of the profile of username.
+func (c *Client) UpdateProfile(ctx context.Context, username string, update ProfileUpdate) (Profile, error) {
+	var data struct {
+		User Profile `json:"user"`
+	}
+	err := c.call(ctx, http.MethodPatch, pathf("/user/%s", username), nil, update, &data)
+	return data.User, err
+}
+
+// CreateWallet
+// By username only. name is unique per user and currency, main if empty. username is the owner member of the wallet.
+func (c *Client) CreateWallet(ctx context.Context, username string, currency string, name string) (Wallet, error) {
+	var data struct {
+		Wallet Wallet `json:"wallet"`
+	}
+	err := c.call(ctx, http.MethodPost, "/wallet", nil, map[string]any{"username": username, "currency": currency, "name": name}, &data)
+	return data.Wallet, err
+}
+
+// Wallets
+// Current balances of the wallets of username.
+func (c *Client) Wallets(ctx context.Context, username string) (UserWallets, error) {
+	return c.WalletsAt(ctx, username, time.Time{})
+}
+
+// WalletsAt
+// Balances of the wallets of username as of at, current if zero.
+func (c *Client) WalletsAt(ctx context.Context, username string, at time.Time) (UserWallets, error) {
+	query := url.Values{}
+	if !at.IsZero() {
+		query.Set("at", formatTime(at))
+	}
+	var data UserWallets
+	err := c.call(ctx, http.MethodGet, pathf("/user/%s/wallets", username), query, nil, &data)
+	return data, err
+}
+
+// Balance
+// Balance of the wallet as of at, current if zero.
+func (c *Client) Balance(ctx context.Context, walletId int64, at time.Time) (WalletBalance, error) {
+	query := url.Values{}
+	if !at.IsZero() {
+		query.Set("at", formatTime(at))
+	}
+	var data WalletBalance
+	err := c.call(ctx, http.MethodGet, pathf("/wallet/%d/balance", walletId), query, nil, &data)
+	return data, err
+}
+
+// Statement
+// Statement of the wallet for [from, to). Zero from is the first ledger, zero to is now.
+func (c *Client) Statement(ctx context.Context, walletId int64, from, to time.Time) (Statement, error) {
+	query := url.Values{}
+	if !from.IsZero() {
+		query.Set("from", formatTime(from))
+	}
+	if !to.IsZero() {
+		query.Set("to", formatTime(to))
+	}
+	var data struct {
+		Statement Statement `json:"statement"`
+	}
+	err := c.call(ctx, http.MethodGet, pathf("/wallet/%d/statement", walletId), query, nil, &data)
+	return data.Statement, err
+}
+
+// Transactions
+// Transactions of the wallets of username sorted by newest.
+func (c *Client) Transactions(ctx context.Context, username string) ([]Transaction, error) {
+	var data struct {
+		Transactions []Transaction `json:"transactions"`
+	}
+	err := c.call(ctx, http.MethodGet, pathf("/user/%s/transactions", username), nil, nil, &data)
+	return data.Transactions, err
+}
+
+type ExportFormat string
+
+const (
+	ExportCsv    ExportFormat = "text/csv"
+	ExportNdjson ExportFormat = "application/x-ndjson"
+)
+
+// ExportTransactions
+// Streams one row per ledger of the wallets of username. The caller closes the reader, it ends with ctx.
+func (c *Client) ExportTransactions(ctx context.Context, username string, format ExportFormat) (io.ReadCloser, error) {
+	res, err := c.stream(ctx, pathf("/user/%s/transactions", username), nil, string(format), nil)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+// Deposit
+// Credits the wallet. Returns the transaction with its ledger.
+func (c *Client) Deposit(ctx context.Context, req DepositRequest) (Transaction, error) {
+	return c.walletOperation(ctx, pathf("/wallet/%d/deposit", req.WalletId), map[string]any{
+		"amount": req.Amount.String(),
+		"nonce":  c.nonce(req.Nonce),
+	})
+}
+
+// Withdraw
+// Debits the wallet. Returns the transaction with its ledger.
+func (c *Client) Withdraw(ctx context.Context, req WithdrawRequest) (Transaction, error) {
+	return c.walletOperation(ctx, pathf("/wallet/%d/withdrawal", req.WalletId), map[string]any{
+		"amount": req.Amount.String(),
+		"nonce":  c.nonce(req.Nonce),
+	})
+}
+
+// Transfer
+// Moves the amount between wallets of the same currency. Returns the transaction with both ledgers, or without ledgers
+// and status pending_approval if over the threshold of the approval policy of the source wallet.
+func (c *Client) Transfer(ctx context.Context, req TransferRequest) (Transaction, error) {
+	return c.walletOperation(ctx, pathf("/wallet/%d/transfer", req.SourceWalletId), map[string]any{
+		"destination_wallet_id": req.DestinationWalletId,
+		"amount":                req.Amount.String(),
+		"nonce":                 c.nonce(req.Nonce),
+	})
+}
+
+func (c *Client) walletOperation(ctx context.Context, path string, body map[string]any) (Transaction, error) {
+	var data struct {
+		Transaction Transaction `json:"transaction"`
+	}
+	err := c.call(ctx, http.MethodPost, path, nil, body, &data)
+	return data.Transaction, err
+}
+
+func (c *Client) nonce(nonce int64) int64 {
+	if nonce != 0 {
+		return nonce
+	}
+	return c.nonces.Nonce()
+}
+
+// Webhooks
+// Webhooks of username.
+func (c *Client) Webhooks(ctx context.Context, username string) ([]Webhook, error) {
+	var data struct {
+		Webhooks []Webhook `json:"webhooks"`
+	}
+	err := c.call(ctx, http.MethodGet, pathf("/user/%s/webhooks", username), nil, nil, &data)
+	return data.Webhooks, err
+}
+
+// CreateWebhook
+// The returned Secret signs deliveries, it is not returned again.
+func (c *Client) CreateWebhook(ctx context.Context, username string, webhookUrl string, eventTypes []string) (Webhook, error) {
+	var data struct {
+		Webhook Webhook `json:"webhook"`
+	}
+	err := c.call(ctx, http.MethodPost, pathf("/user/%s/webhooks", username), nil, map[string]any{
+		"url":         webhookUrl,
+		"event_types": eventTypes,
+	}, &data)
+	return data.Webhook, err
+}
+
+// DeleteWebhook
+// Pending deliveries of the webhook are dead.
+func (c *Client) DeleteWebhook(ctx context.Context, username string, webhookId int64) error {
+	return c.call(ctx, http.MethodDelete, pathf("/user/%s/webhooks/%d", username, webhookId), nil, nil, nil)
+}
+
+// WebhookDeliveries
+// Deliveries of the webhook sorted by newest, at most limit, server default if 0.
+func (c *Client) WebhookDeliveries(ctx context.Context, username string, webhookId int64, limit int) ([]WebhookDelivery, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	var data struct {
+		Deliveries []WebhookDelivery `json:"deliveries"`
+	}
+	err := c.call(ctx, http.MethodGet, pathf("/user/%s/webhooks/%d/deliveries", username, webhookId), query, nil, &data)
+	return data.Deliveries, err
+}
+
+// ReplayWebhookDelivery
+// Schedules the delivery for now with a fresh retry budget.
+func (c *Client) ReplayWebhookDelivery(ctx context.Context, username string, deliveryId int64) (WebhookDelivery, error) {
+	var data struct {
+		Delivery WebhookDelivery `json:"delivery"`
+	}
+	err := c.call(ctx, http.MethodPost, pathf("/user/%s/webhooks/deliveries/%d/replay", username, deliveryId), nil, nil, &data)
+	return data.Delivery, err
+}
+
+// AuditEvents
+// Roles support_readonly, operator and admin only.
+func (c *Client) AuditEvents(ctx context.Context, filter AuditFilter) ([]AuditEvent, error) {
+	query := url.Values{}
+	if filter.Username != "" {
+		query.Set("username", filter.Username)
+	}
+	if filter.WalletId != 0 {
+		query.Set("wallet_id", strconv.FormatInt(filter.WalletId, 10))
+	}
+	if !filter.From.IsZero() {
+		query.Set("from", formatTime(filter.From))
+	}
+	if !filter.To.IsZero() {
+		query.Set("to", formatTime(filter.To))
+	}
+	if filter.Limit > 0 {
+		query.Set("limit", strconv.Itoa(filter.Limit))
+	}
+	var data struct {
+		Events []AuditEvent `json:"events"`
+	}
+	err := c.call(ctx, http.MethodGet, "/admin/audit", query, nil, &data)
+	return data.Events, err
+}
+
+// AdminUser
+// User account with wallets, roles support_readonly, operator and admin only.
+func (c *Client) AdminUser(ctx context.Context, username string) (AdminUser, error) {
+	var data AdminUser
+	err := c.call(ctx, http.MethodGet, pathf("/admin/user/%s", username), nil, nil, &data)
+	return data, err
+}
+
+// Freeze
+// Blocks wallet operations of username, operators and admins only.
+func (c *Client) Freeze(ctx context.Context, username string) (UserAccount, error) {
+	return c.userAccount(ctx, http.MethodPost, pathf("/admin/user/%s/freeze", username), nil)
+}
+
+// Unfreeze
+// Operators and admins only.
+func (c *Client) Unfreeze(ctx context.Context, username string) (UserAccount, error) {
+	return c.userAccount(ctx, http.MethodPost, pathf("/admin/user/%s/unfreeze", username), nil)
+}
+
+// SetRole
+// Admin only.
+func (c *Client) SetRole(ctx context.Context, username string, role string) (UserAccount, error) {
+	return c.userAccount(ctx, http.MethodPut, pathf("/admin/user/%s/role", username), map[string]any{"role": role})
+}
+
+func (c *Client) userAccount(ctx context.Context, method string, path string, body any) (UserAccount, error) {
+	var data struct {
+		User UserAccount `json:"user"`
+	}
+	err := c.call(ctx, method, path, nil, body, &data)
+	return data.User, err
+}
+
+// Adjustment
+// Creates a credit or debit of the wallet for a reason, pending approval by another operator. Operators and admins
+// only.
+func (c *Client) Adjustment(ctx context.Context, req AdjustmentRequest) (Transaction, error) {
+	return c.walletOperation(ctx, pathf("/admin/wallet/%d/adjustment", req.WalletId), map[string]any{
+		"entry_type":  req.EntryType,
+		"amount":      req.Amount.String(),
+		"reason_code": req.ReasonCode,
+		"reason":      req.Reason,
+		"nonce":       c.nonce(req.Nonce),
+	})
+}
+
+// ApproveAdjustment
+// Posts the ledger of the adjustment pending approval, operators and admins other than its maker only.
+func (c *Client) ApproveAdjustment(ctx context.Context, transactionId int64) (Transaction, error) {
+	return c.adjustmentDecision(ctx, pathf("/admin/adjustment/%d/approve", transactionId))
+}
+
+// RejectAdjustment
+// Rejects the adjustment pending approval, operators and admins only.
+func (c *Client) RejectAdjustment(ctx context.Context, transactionId int64) (Transaction, error) {
+	return c.adjustmentDecision(ctx, pathf("/admin/adjustment/%d/reject", transactionId))
+}
+
+func (c *Client) adjustmentDecision(ctx context.Context, path string) (Transaction, error) {
+	var data struct {
+		Transaction Transaction `json:"transaction"`
+	}
+	err := c.call(ctx, http.MethodPost, path, nil, nil, &data)
+	return data.Transaction, err
+}
+
+// PendingAdjustments
+// Adjustments pending approval sorted by oldest, at most limit, server default if 0. Roles support_readonly, operator
+// and admin only.
+func (c *Client) PendingAdjustments(ctx context.Context, limit int) ([]Transaction, error) {
+	query := url.Values{}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	var data struct {
+		Transactions []Transaction `json:"transactions"`
+	}
+	err := c.call(ctx, http.MethodGet, "/admin/adjustments/pending", query, nil, &data)
+	return data.Transactions, err
+}
+
+// SetWalletStatus
+// status is active, frozen, debit_frozen or closed.
+func (c *Client) SetWalletStatus(ctx context.Context, walletId int64, status string, reason string) (Wallet, error) {
+	var data struct {
+		Wallet Wallet `json:"wallet"`
+	}
+	err := c.call(ctx, http.MethodPut, pathf("/admin/wallet/%d/status", walletId), nil, map[string]any{
+		"status": status,
+		"reason": reason,
+	}, &data)
+	return data.Wallet, err
+}
+
+// WalletStatusHistory
+// Status changes of the wallet, roles support_readonly, operator and admin only.
+func (c *Client) WalletStatusHistory(ctx context.Context, walletId int64) ([]WalletStatusChange, error) {
+	var data struct {
+		Changes []WalletStatusChange `json:"changes"`
+	}
+	err := c.call(ctx, http.MethodGet, pathf("/admin/wallet/%d/status/history", walletId), nil, nil, &data)
+	return data.Changes, err
+}
+
+// ApprovalPolicy
+// Approval policy of transfers from the wallet, not_found if transfers need no approval.
+func (c *Client) ApprovalPolicy(ctx context.Context, walletId int64) (ApprovalPolicy, error) {
+	var data struct {
+		ApprovalPolicy approvalPolicy `json:"approval_policy"`
+	}
+	err := c.call(ctx, http.MethodGet, pathf("/wallet/%d/approval-policy", walletId), nil, nil, &data)
+	return data.ApprovalPolicy.approvalPolicy(), err
+}
+
+// SetApprovalPolicy
+// Sets the approval policy of transfers from policy.WalletId, admins only. UpdatedBy and UpdatedAt are ignored.
+func (c *Client) SetApprovalPolicy(ctx context.Context, policy ApprovalPolicy) (ApprovalPolicy, error) {
+	var data struct {
+		ApprovalPolicy approvalPolicy `json:"approval_policy"`
+	}
+	err := c.call(ctx, http.MethodPut, pathf("/admin/wallet/%d/approval-policy", policy.WalletId), nil, map[string]any{
+		"threshold":          policy.Threshold.String(),
+		"required_approvals": policy.RequiredApprovals,
+		"timeout_seconds":    int64(policy.Timeout / time.Second),
+		"approvers":          policy.Approvers,
+	}, &data)
+	return data.ApprovalPolicy.approvalPolicy(), err
+}
+
+// DeleteApprovalPolicy
+// Transfers from the wallet then need no approval, admins only.
+func (c *Client) DeleteApprovalPolicy(ctx context.Context, walletId int64) error {
+	return c.call(ctx, http.MethodDelete, pathf("/admin/wallet/%d/approval-policy", walletId), nil, nil, nil)
+}
+
+// ApproveTransfer
+// Approves the transfer pending approval as one of its approvers. The transfer executes once approved by the required
+// number of approvers.
+func (c *Client) ApproveTransfer(ctx context.Context, transactionId int64) (TransferApproval, error) {
+	var data TransferApproval
+	err := c.call(ctx, http.MethodPost, pathf("/transaction/%d/approve", transactionId), nil, nil, &data)
+	return data, err
+}
+
+// RejectTransfer
+// Rejects the transfer pending approval as one of its approvers, or cancels it as its requestor.
+func (c *Client) RejectTransfer(ctx context.Context, transactionId int64) (TransferApproval, error) {
+	var data TransferApproval
+	err := c.call(ctx, http.MethodPost, pathf("/transaction/%d/reject", transactionId), nil, nil, &data)
+	return data, err
+}
+
+// Members
+// Members of the wallet sorted by username.
+func (c *Client) Members(ctx context.Context, walletId int64) ([]Member, error) {
+	var data struct {
+		Members []Member `json:"members"`
+	}
+	err := c.call(ctx, http.MethodGet, pathf("/wallet/%d/members", walletId), nil, nil, &data)
+	return data.Members, err
+}
+
+// SetMember
+// Adds req.Username to the members of the wallet or changes its role, owners of the wallet only.
+func (c *Client) SetMember(ctx context.Context, req SetMemberRequest) (Member, error) {
+	body := map[string]any{"role": req.Role}
+	if req.SpendLimit != nil {
+		body["spend_limit"] = req.SpendLimit.String()
+	}
+	var data struct {
+		Member Member `json:"member"`
+	}
+	err := c.call(ctx, http.MethodPut, pathf("/wallet/%d/members/%s", req.WalletId, req.Username), nil, body, &data)
+	return data.Member, err
+}
+
+// RemoveMember
+// Removes username from the members of the wallet, owners of the wallet or username leaving it.
+func (c *Client) RemoveMember(ctx context.Context, walletId int64, username string) error {
+	return c.call(ctx, http.MethodDelete, pathf("/wallet/%d/members/%s", walletId, username), nil, nil, nil)
+}
+
+// CreatePaymentRequest
+// Requests req.Payer to pay into req.WalletId, owners and spenders of the wallet only.
+func (c *Client) CreatePaymentRequest(ctx context.Context, req PaymentRequestRequest) (PaymentRequest, error) {
+	body := map[string]any{
+		"payer":     req.Payer,
+		"wallet_id": req.WalletId,
+		"amount":    req.Amount.String(),
+		"currency":  req.Currency,
+		"memo":      req.Memo,
+	}
+	if !req.ExpiresAt.IsZero() {
+		body["expires_at"] = req.ExpiresAt
+	}
+	var data struct {
+		PaymentRequest PaymentRequest `json:"payment_request"`
+	}
+	err := c.call(ctx, http.MethodPost, "/payment-requests", nil, body, &data)
+	return data.PaymentRequest, err
+}
+
+// PaymentRequests
+// Payment requests to be paid by username if direction is incoming, requested by username if outgoing, server default
+// incoming if empty, sorted by newest. All statuses if status is empty.
+func (c *Client) PaymentRequests(ctx context.Context, username string, direction string, status string) ([]PaymentRequest, error) {
+	query := url.Values{}
+	if direction != "" {
+		query.Set("direction", direction)
+	}
+	if status != "" {
+		query.Set("status", status)
+	}
+	var data struct {
+		PaymentRequests []PaymentRequest `json:"payment_requests"`
+	}
+	err := c.call(ctx, http.MethodGet, pathf("/user/%s/payment-requests", username), query, nil, &data)
+	return data.PaymentRequests, err
+}
+
+// PayPaymentRequest
+// Pays the pending request by transfer from req.WalletId, payer only.
+func (c *Client) PayPaymentRequest(ctx context.Context, req PayPaymentRequestRequest) (Transaction, error) {
+	return c.walletOperation(ctx, pathf("/payment-requests/%d/pay", req.PaymentRequestId), map[string]any{
+		"wallet_id": req.WalletId,
+		"nonce":     c.nonce(req.Nonce),
+	})
+}
+
+// CancelPaymentRequest
+// Cancels the pending request, requester only.
+func (c *Client) CancelPaymentRequest(ctx context.Context, id int64) (PaymentRequest, error) {
+	var data struct {
+		PaymentRequest PaymentRequest `json:"payment_request"`
+	}
+	err := c.call(ctx, http.MethodPost, pathf("/payment-requests/%d/cancel", id), nil, nil, &data)
+	return data.PaymentRequest, err
+}
+
+// PendingApprovals
+// Transfers awaiting the decision of username, sorted by oldest.
+func (c *Client) PendingApprovals(ctx context.Context, username string) ([]Transaction, error) {
+	var data struct {
+		Transactions []Transaction `json:"transactions"`
+	}
+	err := c.call(ctx, http.MethodGet, pathf("/user/%s/approvals", username), nil, nil, &data)
+	return data.Transactions, err
+}
+
+// PendingTransactions
+// Transactions pending for longer than olderThan, server default if 0, sorted by oldest, at most limit, server default
+// if 0. Roles support_readonly, operator and admin only.
+func (c *Client) PendingTransactions(ctx context.Context, olderThan time.Duration, limit int) ([]Transaction, error) {
+	query := url.Values{}
+	if olderThan > 0 {
+		query.Set("older_than", olderThan.String())
+	}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	var data struct {
+		Transactions []Transaction `json:"transactions"`
+	}
+	err := c.call(ctx, http.MethodGet, "/admin/transactions/pending", query, nil, &data)
+	return data.Transactions, err
+}
+
+// Reconcile
+// Checks ledgers against wallet balances and transactions, roles support_readonly, operator and admin only. Reads all
+// ledgers, set a longer WithTimeout on large databases.
+func (c *Client) Reconcile(ctx context.Context) (Reconciliation, error) {
+	var data Reconciliation
+	err := c.call(ctx, http.MethodGet, "/admin/reconciliation", nil, nil, &data)
+	return data, err
+}
+
+// Alive
+// Liveness of the server.
+func (c *Client) Alive(ctx context.Context) error {
+	return c.call(ctx, http.MethodGet, "/healthz", nil, nil, nil)
+}
+
+// Ready
+// Readiness of the server, also when not ready (http status 503).
+func (c *Client) Ready(ctx context.Context) (Readiness, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	res, err := c.send(ctx, http.MethodGet, "/readyz", nil, nil, "application/json", nil)
+	if err != nil {
+		return Readiness{}, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusServiceUnavailable {
+		if err := responseError(res); err != nil {
+			return Readiness{}, err
+		}
+	}
+	var data Readiness
+	err = decodeData(res.Body, &data)
+	return data, err
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"net/http"
+)
+
+// Authenticator
+// Sets the credentials of a request before it is sent, once per attempt.
+type Authenticator interface {
+	Authenticate(req *http.Request) error
+}
+
+// AuthenticatorFunc
+// Authenticator of a function, i.e. to fetch a token per request.
+type AuthenticatorFunc func(req *http.Request) error
+
+func (f AuthenticatorFunc) Authenticate(req *http.Request) error {
+	return f(req)
+}
+
+// BasicAuth
+// Authenticates as username with an empty password, as the server expects.
+func BasicAuth(username string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		req.SetBasicAuth(username, "")
+		return nil
+	})
+}
+
+// NoAuth
+// Sends requests without credentials, i.e. when the principal is the client certificate of the transport. See
+// WithHTTPClient.
+func NoAuth() Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) error {
+		return nil
+	})
+}
@@ -0,0 +1,301 @@
+// Package client is the Go client of the wallet API.
+//
+// Methods take a context, send the request as the principal of the client's Authenticator and return the response
+// data, or an *Error of the server's error code. Wallet operations without a nonce are sent with one of the client's
+// NonceSource. Requests which the server has not processed are retried with backoff, see RetryPolicy.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryPolicy
+// Attempts of a request. Requests are retried when the server has not processed them, that is when the connection
+// could not be established or the server answered 429, and GET requests also on other connection errors and 502, 503
+// and 504. Other requests are not retried as they may have been processed, wallet operations would then fail with
+// duplicate_nonce.
+type RetryPolicy struct {
+	// MaxAttempts of a request, 1 to not retry.
+	MaxAttempts int
+	// InitialBackoff before the second attempt, doubled per attempt up to MaxBackoff, with jitter.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:    3,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+}
+
+type Client struct {
+	baseUrl    string
+	httpClient *http.Client
+	auth       Authenticator
+	nonces     NonceSource
+	retry      RetryPolicy
+	timeout    time.Duration
+}
+
+type Option func(c *Client)
+
+// WithHTTPClient
+// Sends requests with httpClient, i.e. with a transport presenting a client certificate. Its Timeout should be zero,
+// it would end event streams. Requests are bounded by WithTimeout instead.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithAuth
+// Principal of requests. Default NoAuth.
+func WithAuth(auth Authenticator) Option {
+	return func(c *Client) {
+		c.auth = auth
+	}
+}
+
+// WithNonceSource
+// Nonces of wallet operations sent without one. Default a ClockNonce of the client.
+func WithNonceSource(nonces NonceSource) Option {
+	return func(c *Client) {
+		c.nonces = nonces
+	}
+}
+
+// WithRetryPolicy
+// Default DefaultRetryPolicy.
+func WithRetryPolicy(retry RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = retry
+	}
+}
+
+// WithTimeout
+// Of one attempt of a request, not of event streams and exports. Default 10 seconds, 0 for none.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = timeout
+	}
+}
+
+// New
+// Client of the server at baseUrl, i.e. https://wallet.internal:8080.
+func New(baseUrl string, options ...Option) (*Client, error) {
+	u, err := url.Parse(baseUrl)
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("want http(s)://host[:port]. got %q", baseUrl)
+	}
+	c := &Client{
+		baseUrl:    strings.TrimRight(baseUrl, "/"),
+		httpClient: &http.Client{},
+		auth:       NoAuth(),
+		nonces:     &ClockNonce{},
+		retry:      DefaultRetryPolicy,
+		timeout:    10 * time.Second,
+	}
+	for _, option := range options {
+		option(c)
+	}
+	if c.retry.MaxAttempts < 1 {
+		c.retry.MaxAttempts = 1
+	}
+	return c, nil
+}
+
+// As
+// Copy of the client sending requests as auth, sharing connections and nonces, i.e. for a service acting for its
+// users.
+func (c *Client) As(auth Authenticator) *Client {
+	clone := *c
+	clone.auth = auth
+	return &clone
+}
+
+// call
+// Sends a json request and decodes the data of the response into out, unless out is nil.
+func (c *Client) call(ctx context.Context, method string, path string, query url.Values, body any, out any) error {
+	var b []byte
+	if body != nil {
+		var err error
+		if b, err = json.Marshal(body); err != nil {
+			return err
+		}
+	}
+	return c.retrying(ctx, method, func(ctx context.Context) (bool, error) {
+		if c.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, c.timeout)
+			defer cancel()
+		}
+		res, err := c.send(ctx, method, path, query, b, "application/json", nil)
+		if err != nil {
+			return retryableError(method, err), err
+		}
+		defer res.Body.Close()
+		if err := responseError(res); err != nil {
+			return retryableStatus(method, res.StatusCode), err
+		}
+		return false, decodeData(res.Body, out)
+	})
+}
+
+// stream
+// Sends a GET request and returns the response of status 200 for the caller to read and close. The response outlives
+// the client timeout, it ends with ctx.
+func (c *Client) stream(ctx context.Context, path string, query url.Values, accept string, headers map[string]string) (*http.Response, error) {
+	var res *http.Response
+	err := c.retrying(ctx, http.MethodGet, func(ctx context.Context) (bool, error) {
+		var err error
+		res, err = c.send(ctx, http.MethodGet, path, query, nil, accept, headers)
+		if err != nil {
+			return retryableError(http.MethodGet, err), err
+		}
+		if err := responseError(res); err != nil {
+			res.Body.Close()
+			return retryableStatus(http.MethodGet, res.StatusCode), err
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (c *Client) send(ctx context.Context, method string, path string, query url.Values, body []byte, accept string, headers map[string]string) (*http.Response, error) {
+	var r io.Reader
+	if body != nil {
+		r = bytes.NewReader(body)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseUrl+path, r)
+	if err != nil {
+		return nil, err
+	}
+	if len(query) > 0 {
+		req.URL.RawQuery = query.Encode()
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", accept)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	if err := c.auth.Authenticate(req); err != nil {
+		return nil, err
+	}
+	return c.httpClient.Do(req)
+}
+
+// retrying
+// Calls attempt until it succeeds, returns a not retryable error or attempts run out.
+func (c *Client) retrying(ctx context.Context, method string, attempt func(ctx context.Context) (retryable bool, err error)) error {
+	backoff := c.retry.InitialBackoff
+	for n := 1; ; n++ {
+		retryable, err := attempt(ctx)
+		if err == nil || !retryable || n >= c.retry.MaxAttempts || ctx.Err() != nil {
+			return err
+		}
+		wait := backoff/2 + rand.N(backoff/2+1)
+		var e *Error
+		if errors.As(err, &e) && e.retryAfter > wait {
+			wait = e.retryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(min(wait, c.retry.MaxBackoff)):
+		}
+		backoff = min(backoff*2, c.retry.MaxBackoff)
+	}
+}
+
+// retryableError
+// Connections not established did not reach the server.
+func retryableError(method string, err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return method == http.MethodGet && errors.Is(err, context.DeadlineExceeded)
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	return method == http.MethodGet
+}
+
+// retryableStatus
+// 429 is answered by the rate limiter before the request is handled.
+func retryableStatus(method string, status int) bool {
+	switch status {
+	case http.StatusTooManyRequests:
+		return true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return method == http.MethodGet
+	}
+	return false
+}
+
+// responseError
+// Error of a response of status other than 2xx, nil otherwise. The body is read to the end.
+func responseError(res *http.Response) error {
+	if res.StatusCode >= 200 && res.StatusCode <= 299 {
+		return nil
+	}
+	b, _ := io.ReadAll(io.LimitReader(res.Body, 64<<10))
+	e := &Error{Status: res.StatusCode}
+	var p problem
+	if json.Unmarshal(b, &p) == nil && p.Code != "" {
+		e.Code = ErrorCode(p.Code)
+		e.Title = p.Title
+		e.Detail = p.Detail
+		e.Instance = p.Instance
+		e.TraceId = p.TraceId
+	}
+	if seconds, err := strconv.Atoi(res.Header.Get("Retry-After")); err == nil && seconds > 0 {
+		e.retryAfter = time.Duration(seconds) * time.Second
+	}
+	return e
+}
+
+// decodeData
+// Decodes the data of {"data": ..., "error": null} into out.
+func decodeData(r io.Reader, out any) error {
+	if out == nil {
+		_, err := io.Copy(io.Discard, r)
+		return err
+	}
+	body := struct {
+		Data any `json:"data"`
+	}{Data: out}
+	return json.NewDecoder(r).Decode(&body)
+}
+
+func pathf(format string, a ...any) string {
+	for i, v := range a {
+		if s, ok := v.(string); ok {
+			a[i] = url.PathEscape(s)
+		}
+	}
+	return fmt.Sprintf(format, a...)
+}
+
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
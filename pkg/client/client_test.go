@@ -0,0 +1,272 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+var fastRetry = WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond})
+
+// server
+// Answers the requests in turn with statuses and bodies, the last one repeated. Records the requests.
+type server struct {
+	mu        sync.Mutex
+	responses []response
+	requests  []request
+}
+
+type response struct {
+	status int
+	body   string
+}
+
+type request struct {
+	method        string
+	path          string
+	query         string
+	authorization string
+	body          map[string]any
+}
+
+func newServer(t *testing.T, responses ...response) (*server, *Client) {
+	s := &server{responses: responses}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		req := request{method: r.Method, path: r.URL.Path, query: r.URL.RawQuery, authorization: r.Header.Get("Authorization")}
+		json.NewDecoder(r.Body).Decode(&req.body)
+		s.requests = append(s.requests, req)
+
+		res := s.responses[min(len(s.requests), len(s.responses))-1]
+		w.WriteHeader(res.status)
+		io.WriteString(w, res.body)
+	}))
+	t.Cleanup(ts.Close)
+	c, err := New(ts.URL, fastRetry, WithAuth(BasicAuth("user1")))
+	if err != nil {
+		t.Fatalf("New err %v", err)
+	}
+	return s, c
+}
+
+const transactionBody = `{"data":{"transaction":{"id":5,"nonce":1749286345000,"status":"success","operation":"deposit",` +
+	`"ledgers":[{"id":3,"wallet_id":4,"transaction_id":5,"entry_type":"credit","amount":"1.5","balance":"2"}]}},"error":null}`
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		response   response
+		wantIs     error
+		wantCode   ErrorCode
+		wantStatus int
+	}{
+		{
+			name: "problem",
+			response: response{http.StatusUnprocessableEntity, `{"type":"about:blank","title":"Unprocessable Entity","status":422,` +
+				`"detail":"insufficient_funds","instance":"/wallet/4/withdrawal","code":"insufficient_funds","trace_id":"t1"}`},
+			wantIs:     ErrInsufficientFunds,
+			wantCode:   CodeInsufficientFunds,
+			wantStatus: http.StatusUnprocessableEntity,
+		},
+		{
+			name:       "without problem details",
+			response:   response{http.StatusBadGateway, `<html>bad gateway</html>`},
+			wantCode:   "",
+			wantStatus: http.StatusBadGateway,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, c := newServer(t, tt.response)
+			_, err := c.Withdraw(context.Background(), WithdrawRequest{WalletId: 4, Amount: decimal.RequireFromString("1")})
+			var e *Error
+			if !errors.As(err, &e) || e.Status != tt.wantStatus || CodeOf(err) != tt.wantCode {
+				t.Fatalf("want status %d code %q. got %v", tt.wantStatus, tt.wantCode, err)
+			}
+			if tt.wantIs != nil && !errors.Is(err, tt.wantIs) {
+				t.Fatalf("want errors.Is %v. got %v", tt.wantIs, err)
+			}
+			if errors.Is(err, ErrNotFound) {
+				t.Fatalf("want not errors.Is ErrNotFound. got %v", err)
+			}
+		})
+	}
+}
+
+func TestRetry(t *testing.T) {
+	unavailable := response{http.StatusServiceUnavailable, `{"data":{"status":"draining"},"error":null}`}
+	tooMany := response{http.StatusTooManyRequests, `{"status":429,"code":"too_many_requests"}`}
+	wallets := response{http.StatusOK, `{"data":{"user":{"id":1,"username":"user1"},"wallets":[]},"error":null}`}
+
+	tests := []struct {
+		name         string
+		responses    []response
+		call         func(c *Client) error
+		wantAttempts int
+		wantErr      error
+	}{
+		{
+			name:         "get retried on 503",
+			responses:    []response{unavailable, wallets},
+			call:         func(c *Client) error { _, err := c.Wallets(context.Background(), "user1"); return err },
+			wantAttempts: 2,
+		},
+		{
+			name:         "get retried up to max attempts",
+			responses:    []response{tooMany},
+			call:         func(c *Client) error { _, err := c.Wallets(context.Background(), "user1"); return err },
+			wantAttempts: 3,
+			wantErr:      ErrTooManyRequests,
+		},
+		{
+			name:      "post not retried on 503",
+			responses: []response{unavailable, {http.StatusOK, transactionBody}},
+			call: func(c *Client) error {
+				_, err := c.Deposit(context.Background(), DepositRequest{WalletId: 4, Amount: decimal.RequireFromString("1.5")})
+				return err
+			},
+			wantAttempts: 1,
+		},
+		{
+			name:      "post retried on 429",
+			responses: []response{tooMany, {http.StatusOK, transactionBody}},
+			call: func(c *Client) error {
+				_, err := c.Deposit(context.Background(), DepositRequest{WalletId: 4, Amount: decimal.RequireFromString("1.5")})
+				return err
+			},
+			wantAttempts: 2,
+		},
+		{
+			name:         "not retried on client error",
+			responses:    []response{{http.StatusNotFound, `{"status":404,"code":"not_found"}`}},
+			call:         func(c *Client) error { _, err := c.Wallets(context.Background(), "user1"); return err },
+			wantErr:      ErrNotFound,
+			wantAttempts: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, c := newServer(t, tt.responses...)
+			err := tt.call(c)
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("want err %v. got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && tt.wantAttempts > 1 && err != nil {
+				t.Fatalf("want nil err. got %v", err)
+			}
+			if len(s.requests) != tt.wantAttempts {
+				t.Fatalf("want %d attempts. got %d", tt.wantAttempts, len(s.requests))
+			}
+			// every attempt of a wallet operation has the same nonce
+			for _, r := range s.requests[1:] {
+				if r.body["nonce"] != s.requests[0].body["nonce"] {
+					t.Fatalf("want the nonce of the first attempt. got %v and %v", s.requests[0].body["nonce"], r.body["nonce"])
+				}
+			}
+		})
+	}
+}
+
+func TestDeposit(t *testing.T) {
+	s, c := newServer(t, response{http.StatusOK, transactionBody})
+	ctx := context.Background()
+
+	transaction, err := c.Deposit(ctx, DepositRequest{WalletId: 4, Amount: decimal.RequireFromString("1.50")})
+	if err != nil {
+		t.Fatalf("Deposit err %v", err)
+	}
+	if transaction.Id != 5 || len(transaction.Ledgers) != 1 || !transaction.Ledgers[0].Balance.Equal(decimal.NewFromInt(2)) {
+		t.Fatalf("unexpected transaction %+v", transaction)
+	}
+	if _, err := c.As(BasicAuth("payments")).Deposit(ctx, DepositRequest{WalletId: 4, Amount: decimal.RequireFromString("1"), Nonce: 7}); err != nil {
+		t.Fatalf("Deposit err %v", err)
+	}
+
+	first, second := s.requests[0], s.requests[1]
+	if first.method != http.MethodPost || first.path != "/wallet/4/deposit" || first.body["amount"] != "1.5" {
+		t.Fatalf("unexpected request %+v", first)
+	}
+	if nonce, _ := first.body["nonce"].(float64); nonce <= 0 {
+		t.Fatalf("want generated nonce. got %v", first.body["nonce"])
+	}
+	if second.body["nonce"] != float64(7) {
+		t.Fatalf("want nonce 7. got %v", second.body["nonce"])
+	}
+	if first.authorization != "Basic dXNlcjE6" || second.authorization != "Basic cGF5bWVudHM6" {
+		t.Fatalf("want basic auth of user1 then payments. got %q %q", first.authorization, second.authorization)
+	}
+}
+
+func TestClockNonce(t *testing.T) {
+	var n ClockNonce
+	var mu sync.Mutex
+	seen := map[int64]bool{}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			last := int64(0)
+			for j := 0; j < 1000; j++ {
+				nonce := n.Nonce()
+				if nonce <= last {
+					t.Errorf("want increasing nonces. got %d after %d", nonce, last)
+				}
+				last = nonce
+				mu.Lock()
+				seen[nonce] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 8000 {
+		t.Fatalf("want 8000 unique nonces. got %d", len(seen))
+	}
+}
+
+func TestEvents(t *testing.T) {
+	var lastEventId string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastEventId = r.Header.Get("Last-Event-ID")
+		w.Header().Set("Content-Type", "text/event-stream")
+		io.WriteString(w, ": keepalive\n\n"+
+			"id: 3\nevent: ledger\ndata: {\"id\":3,\"wallet_id\":4,\"amount\":\"1.5\"}\n\n"+
+			"event: transaction\ndata: {\"id\":5,\"status\":\"success\"}\n\n")
+	}))
+	defer ts.Close()
+	c, _ := New(ts.URL)
+
+	stream, err := c.Events(context.Background(), "user1", "2")
+	if err != nil {
+		t.Fatalf("Events err %v", err)
+	}
+	defer stream.Close()
+	if lastEventId != "2" {
+		t.Fatalf("want Last-Event-ID 2. got %q", lastEventId)
+	}
+
+	e, err := stream.Next()
+	if err != nil || e.Id != "3" || e.Event != EventLedger {
+		t.Fatalf("want ledger event 3. got %+v %v", e, err)
+	}
+	if l, err := e.Ledger(); err != nil || l.WalletId != 4 || !l.Amount.Equal(decimal.RequireFromString("1.5")) {
+		t.Fatalf("unexpected ledger %+v %v", l, err)
+	}
+	e, err = stream.Next()
+	if err != nil || e.Event != EventTransaction {
+		t.Fatalf("want transaction event. got %+v %v", e, err)
+	}
+	if _, err := stream.Next(); err != io.EOF {
+		t.Fatalf("want io.EOF at end of stream. got %v", err)
+	}
+}
@@ -0,0 +1,115 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrorCode
+// Machine-readable code of an error response, the `code` of the problem details. See readme Error Responses.
+type ErrorCode string
+
+const (
+	CodeBadRequest         ErrorCode = "bad_request"
+	CodeInvalidArgument    ErrorCode = "invalid_argument"
+	CodeInvalidAmount      ErrorCode = "invalid_amount"
+	CodeInvalidNonce       ErrorCode = "invalid_nonce"
+	CodeUnauthorized       ErrorCode = "unauthorized"
+	CodeForbidden          ErrorCode = "forbidden"
+	CodeNotFound           ErrorCode = "not_found"
+	CodeAlreadyExists      ErrorCode = "already_exists"
+	CodeDuplicateNonce     ErrorCode = "duplicate_nonce"
+	CodeInsufficientFunds  ErrorCode = "insufficient_funds"
+	CodeCurrencyMismatch   ErrorCode = "currency_mismatch"
+	CodeAccountFrozen      ErrorCode = "account_frozen"
+	CodeWalletFrozen       ErrorCode = "wallet_frozen"
+	CodeWalletClosed       ErrorCode = "wallet_closed"
+	CodeWalletNotEmpty     ErrorCode = "wallet_not_empty"
+	CodeAccountSuspended   ErrorCode = "account_suspended"
+	CodeKycTierRequired    ErrorCode = "kyc_tier_required"
+	CodeKycLimitExceeded   ErrorCode = "kyc_limit_exceeded"
+	CodeApprovalExpired    ErrorCode = "approval_expired"
+	CodeSpendLimitExceeded ErrorCode = "spend_limit_exceeded"
+	CodePayloadTooLarge    ErrorCode = "payload_too_large"
+	CodeTooManyRequests    ErrorCode = "too_many_requests"
+	CodeInternal           ErrorCode = "internal_error"
+)
+
+// Error
+// Error response of the server. Code is empty for responses without problem details, i.e. of a proxy.
+type Error struct {
+	Status   int
+	Code     ErrorCode
+	Title    string
+	Detail   string
+	Instance string
+	TraceId  string
+
+	retryAfter time.Duration
+}
+
+func (e *Error) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("http status %d", e.Status)
+	}
+	if e.Detail == "" || e.Detail == string(e.Code) {
+		return fmt.Sprintf("%s (http status %d)", e.Code, e.Status)
+	}
+	return fmt.Sprintf("%s: %s (http status %d)", e.Code, e.Detail, e.Status)
+}
+
+// Is
+// Errors are equal by Code, so errors.Is(err, ErrInsufficientFunds) holds for any insufficient_funds response.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code != "" && t.Code == e.Code
+}
+
+var (
+	ErrBadRequest         = &Error{Code: CodeBadRequest}
+	ErrInvalidArgument    = &Error{Code: CodeInvalidArgument}
+	ErrInvalidAmount      = &Error{Code: CodeInvalidAmount}
+	ErrInvalidNonce       = &Error{Code: CodeInvalidNonce}
+	ErrUnauthorized       = &Error{Code: CodeUnauthorized}
+	ErrForbidden          = &Error{Code: CodeForbidden}
+	ErrNotFound           = &Error{Code: CodeNotFound}
+	ErrAlreadyExists      = &Error{Code: CodeAlreadyExists}
+	ErrDuplicateNonce     = &Error{Code: CodeDuplicateNonce}
+	ErrInsufficientFunds  = &Error{Code: CodeInsufficientFunds}
+	ErrCurrencyMismatch   = &Error{Code: CodeCurrencyMismatch}
+	ErrAccountFrozen      = &Error{Code: CodeAccountFrozen}
+	ErrWalletFrozen       = &Error{Code: CodeWalletFrozen}
+	ErrWalletClosed       = &Error{Code: CodeWalletClosed}
+	ErrWalletNotEmpty     = &Error{Code: CodeWalletNotEmpty}
+	ErrAccountSuspended   = &Error{Code: CodeAccountSuspended}
+	ErrKycTierRequired    = &Error{Code: CodeKycTierRequired}
+	ErrKycLimitExceeded   = &Error{Code: CodeKycLimitExceeded}
+	ErrApprovalExpired    = &Error{Code: CodeApprovalExpired}
+	ErrSpendLimitExceeded = &Error{Code: CodeSpendLimitExceeded}
+	ErrPayloadTooLarge    = &Error{Code: CodePayloadTooLarge}
+	ErrTooManyRequests    = &Error{Code: CodeTooManyRequests}
+	ErrInternal           = &Error{Code: CodeInternal}
+)
+
+// CodeOf
+// Code of an error response, empty for other errors, i.e. of the connection.
+func CodeOf(err error) ErrorCode {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+// problem
+// RFC 7807 problem details of error responses.
+type problem struct {
+	Type     string `json:"type"`
+	Title    string `json:"title"`
+	Status   int    `json:"status"`
+	Detail   string `json:"detail"`
+	Instance string `json:"instance"`
+	Code     string `json:"code"`
+	TraceId  string `json:"trace_id"`
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+const (
+	EventLedger      = "ledger"
+	EventTransaction = "transaction"
+)
+
+// Event
+// Server-sent event of an activity stream. Id is the ledger id of ledger events, resume from it with Events.
+type Event struct {
+	Id    string
+	Event string
+	Data  json.RawMessage
+}
+
+// Ledger
+// Data of a ledger event.
+func (e Event) Ledger() (Ledger, error) {
+	var l Ledger
+	err := json.Unmarshal(e.Data, &l)
+	return l, err
+}
+
+// Transaction
+// Data of a transaction event, without ledgers and metadata.
+func (e Event) Transaction() (Transaction, error) {
+	var t Transaction
+	err := json.Unmarshal(e.Data, &t)
+	return t, err
+}
+
+// EventStream
+// Activity stream read by Next, until closed or the server ends it.
+type EventStream struct {
+	body    io.ReadCloser
+	scanner *bufio.Scanner
+}
+
+// Events
+// Opens the activity stream of username. Unless lastEventId is empty, the ledgers after it are sent first. The stream
+// ends with ctx or Close.
+func (c *Client) Events(ctx context.Context, username string, lastEventId string) (*EventStream, error) {
+	headers := map[string]string{}
+	if lastEventId != "" {
+		headers["Last-Event-ID"] = lastEventId
+	}
+	res, err := c.stream(ctx, pathf("/user/%s/events", username), nil, "text/event-stream", headers)
+	if err != nil {
+		return nil, err
+	}
+	return &EventStream{body: res.Body, scanner: bufio.NewScanner(res.Body)}, nil
+}
+
+// Next
+// Blocks until the next event. Returns io.EOF when the server ends the stream, resume from the Id of the last ledger
+// event.
+func (s *EventStream) Next() (Event, error) {
+	var e Event
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		if line == "" {
+			if e.Event != "" {
+				return e, nil
+			}
+			e = Event{}
+			continue
+		}
+		// comments, i.e. keepalives, start with a colon
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "id":
+			e.Id = value
+		case "event":
+			e.Event = value
+		case "data":
+			e.Data = json.RawMessage(value)
+		}
+	}
+	if err := s.scanner.Err(); err != nil {
+		return Event{}, err
+	}
+	return Event{}, io.EOF
+}
+
+func (s *EventStream) Close() error {
+	return s.body.Close()
+}
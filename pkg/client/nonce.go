@@ -0,0 +1,30 @@
+package client
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// NonceSource
+// Nonces of wallet operations sent without one. A nonce is used once per principal, a reused one is rejected with
+// duplicate_nonce.
+type NonceSource interface {
+	Nonce() int64
+}
+
+// ClockNonce
+// Unix milliseconds, increased past the last nonce when the clock has not moved, so nonces of the process are unique
+// and increasing. Processes sharing a principal should use distinct sources, i.e. the principal per process.
+type ClockNonce struct {
+	last atomic.Int64
+}
+
+func (n *ClockNonce) Nonce() int64 {
+	for {
+		last := n.last.Load()
+		next := max(time.Now().UnixMilli(), last+1)
+		if n.last.CompareAndSwap(last, next) {
+			return next
+		}
+	}
+}
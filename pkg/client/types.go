@@ -0,0 +1,386 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type User struct {
+	Id       int64  `json:"id"`
+	Username string `json:"username"`
+}
+
+type Wallet struct {
+	Id int64 `json:"id"`
+	// UserAccountId is the creator of the wallet, its first owner member.
+	UserAccountId int64 `json:"user_account_id"`
+	// Name is unique per creator and currency.
+	Name     string          `json:"name"`
+	Currency string          `json:"currency"`
+	Balance  decimal.Decimal `json:"balance"`
+	// Status is active, frozen, debit_frozen or closed.
+	Status string `json:"status"`
+}
+
+type UserWallets struct {
+	User    User     `json:"user"`
+	Wallets []Wallet `json:"wallets"`
+	// At is set for balances as of a past instant.
+	At *time.Time `json:"at"`
+}
+
+type WalletBalance struct {
+	Wallet Wallet    `json:"wallet"`
+	At     time.Time `json:"at"`
+}
+
+type Profile struct {
+	Id          int64   `json:"id"`
+	Username    string  `json:"username"`
+	DisplayName *string `json:"display_name"`
+	Email       *string `json:"email"`
+	// Status is pending, verified or suspended.
+	Status    string    `json:"status"`
+	KycTier   int       `json:"kyc_tier"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ProfileUpdate
+// Nil fields are unchanged. Status and KycTier are set by operators only.
+type ProfileUpdate struct {
+	DisplayName *string `json:"display_name,omitempty"`
+	Email       *string `json:"email,omitempty"`
+	Status      *string `json:"status,omitempty"`
+	KycTier     *int    `json:"kyc_tier,omitempty"`
+}
+
+type Ledger struct {
+	Id            int64 `json:"id"`
+	WalletId      int64 `json:"wallet_id"`
+	TransactionId int64 `json:"transaction_id"`
+	// EntryType is credit or debit.
+	EntryType string          `json:"entry_type"`
+	Amount    decimal.Decimal `json:"amount"`
+	CreatedAt time.Time       `json:"created_at"`
+	// Balance of the wallet after the ledger.
+	Balance decimal.Decimal `json:"balance"`
+}
+
+// TransactionMetaData
+// EntryType, ReasonCode, Reason and CreatedBy are set for adjustments, ApprovedBy or RejectedBy once decided.
+type TransactionMetaData struct {
+	SourceWalletId      *int64           `json:"source_wallet_id"`
+	DestinationWalletId *int64           `json:"destination_wallet_id,omitempty"`
+	Amount              *decimal.Decimal `json:"amount"`
+	// Approvers, ApprovalsRequired and ExpiresAt are set for transfers pending approval.
+	Approvers         []string   `json:"approvers,omitempty"`
+	ApprovalsRequired *int       `json:"approvals_required,omitempty"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty"`
+	// PaymentRequestId is set for transfers paying a payment request.
+	PaymentRequestId *int64  `json:"payment_request_id,omitempty"`
+	EntryType        *string `json:"entry_type"`
+	ReasonCode       *string `json:"reason_code,omitempty"`
+	Reason           *string `json:"reason"`
+	CreatedBy        *string `json:"created_by,omitempty"`
+	ApprovedBy       *string `json:"approved_by,omitempty"`
+	RejectedBy       *string `json:"rejected_by,omitempty"`
+}
+
+type Transaction struct {
+	Id          int64 `json:"id"`
+	RequestorId int64 `json:"requestor_id"`
+	Nonce       int64 `json:"nonce"`
+	// Status is pending, success or error_<error code>, pending_approval or rejected for adjustments and transfers,
+	// expired for transfers.
+	Status string `json:"status"`
+	// Operation is deposit, withdrawal, transfer or adjustment.
+	Operation string              `json:"operation"`
+	CreatedAt time.Time           `json:"created_at"`
+	MetaData  TransactionMetaData `json:"metadata"`
+	Ledgers   []Ledger            `json:"ledgers"`
+}
+
+type Statement struct {
+	WalletId       int64           `json:"wallet_id"`
+	Currency       string          `json:"currency"`
+	From           *time.Time      `json:"from"`
+	To             time.Time       `json:"to"`
+	OpeningBalance decimal.Decimal `json:"opening_balance"`
+	Ledgers        []Ledger        `json:"ledgers"`
+	TotalCredits   decimal.Decimal `json:"total_credits"`
+	TotalDebits    decimal.Decimal `json:"total_debits"`
+	ClosingBalance decimal.Decimal `json:"closing_balance"`
+}
+
+// DepositRequest
+// Also of withdrawals. A zero Nonce is generated by the client's NonceSource.
+type DepositRequest struct {
+	WalletId int64
+	Amount   decimal.Decimal
+	Nonce    int64
+}
+
+type WithdrawRequest = DepositRequest
+
+// TransferRequest
+// A zero Nonce is generated by the client's NonceSource.
+type TransferRequest struct {
+	SourceWalletId      int64
+	DestinationWalletId int64
+	Amount              decimal.Decimal
+	Nonce               int64
+}
+
+// SetMemberRequest
+// SpendLimit is required for spenders and must be nil otherwise. It caps the total of their debits per UTC day.
+type SetMemberRequest struct {
+	WalletId int64
+	Username string
+	// Role is owner, spender or viewer.
+	Role       string
+	SpendLimit *decimal.Decimal
+}
+
+// PaymentRequestRequest
+// Requests Payer to pay Amount into WalletId. A zero ExpiresAt is set by the server.
+type PaymentRequestRequest struct {
+	Payer     string
+	WalletId  int64
+	Amount    decimal.Decimal
+	Currency  string
+	Memo      string
+	ExpiresAt time.Time
+}
+
+// PayPaymentRequestRequest
+// A zero Nonce is generated by the client's NonceSource.
+type PayPaymentRequestRequest struct {
+	PaymentRequestId int64
+	// WalletId is the wallet of the payer to transfer from.
+	WalletId int64
+	Nonce    int64
+}
+
+// AdjustmentRequest
+// A zero Nonce is generated by the client's NonceSource.
+type AdjustmentRequest struct {
+	WalletId int64
+	// EntryType is credit or debit.
+	EntryType string
+	Amount    decimal.Decimal
+	// ReasonCode is correction, chargeback, goodwill, fee_refund or fraud_recovery.
+	ReasonCode string
+	Reason     string
+	Nonce      int64
+}
+
+type Webhook struct {
+	Id         int64     `json:"id"`
+	Url        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	CreatedAt  time.Time `json:"created_at"`
+	// Secret signing deliveries, returned by CreateWebhook only.
+	Secret string `json:"secret,omitempty"`
+}
+
+type WebhookDelivery struct {
+	Id        int64           `json:"id"`
+	WebhookId int64           `json:"webhook_id"`
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	// Status is pending, delivered or dead.
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `json:"next_attempt_at"`
+	LastError     *string    `json:"last_error"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeliveredAt   *time.Time `json:"delivered_at"`
+}
+
+type UserAccount struct {
+	Id       int64  `json:"id"`
+	Username string `json:"username"`
+	// Role is customer, support_readonly, operator or admin.
+	Role    string `json:"role"`
+	Frozen  bool   `json:"frozen"`
+	Status  string `json:"status"`
+	KycTier int    `json:"kyc_tier"`
+}
+
+type AdminUser struct {
+	User    UserAccount `json:"user"`
+	Wallets []Wallet    `json:"wallets"`
+}
+
+// ApprovalPolicy
+// Transfers from WalletId over Threshold are pending approval until RequiredApprovals of Approvers approve, and
+// expire after Timeout.
+type ApprovalPolicy struct {
+	WalletId          int64
+	Threshold         decimal.Decimal
+	RequiredApprovals int
+	Timeout           time.Duration
+	Approvers         []string
+	UpdatedBy         string
+	UpdatedAt         time.Time
+}
+
+// approvalPolicy
+// Wire format of ApprovalPolicy.
+type approvalPolicy struct {
+	WalletId          int64           `json:"wallet_id"`
+	Threshold         decimal.Decimal `json:"threshold"`
+	RequiredApprovals int             `json:"required_approvals"`
+	TimeoutSeconds    int64           `json:"timeout_seconds"`
+	Approvers         []string        `json:"approvers"`
+	UpdatedBy         string          `json:"updated_by"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+func (p approvalPolicy) approvalPolicy() ApprovalPolicy {
+	return ApprovalPolicy{
+		WalletId:          p.WalletId,
+		Threshold:         p.Threshold,
+		RequiredApprovals: p.RequiredApprovals,
+		Timeout:           time.Duration(p.TimeoutSeconds) * time.Second,
+		Approvers:         p.Approvers,
+		UpdatedBy:         p.UpdatedBy,
+		UpdatedAt:         p.UpdatedAt,
+	}
+}
+
+type Approval struct {
+	Approver string `json:"approver"`
+	// Decision is approved or rejected.
+	Decision  string    `json:"decision"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TransferApproval
+// The transfer after a decision, with the decisions so far.
+type TransferApproval struct {
+	Transaction Transaction `json:"transaction"`
+	Approvals   []Approval  `json:"approvals"`
+}
+
+// Member
+// Role of a user on a wallet. Owners operate the wallet and manage its members, spenders operate it with their
+// debits up to SpendLimit in total per UTC day, viewers read it.
+type Member struct {
+	WalletId int64  `json:"wallet_id"`
+	Username string `json:"username"`
+	// Role is owner, spender or viewer.
+	Role       string           `json:"role"`
+	SpendLimit *decimal.Decimal `json:"spend_limit"`
+	UpdatedBy  string           `json:"updated_by"`
+	UpdatedAt  time.Time        `json:"updated_at"`
+}
+
+// PaymentRequest
+// Request of Requester to be paid by Payer. TransactionId is the transfer paying it.
+type PaymentRequest struct {
+	Id        int64           `json:"id"`
+	Requester string          `json:"requester"`
+	Payer     string          `json:"payer"`
+	WalletId  int64           `json:"wallet_id"`
+	Amount    decimal.Decimal `json:"amount"`
+	Currency  string          `json:"currency"`
+	Memo      string          `json:"memo"`
+	// Status is pending, paid, cancelled, or expired for pending requests past ExpiresAt.
+	Status        string    `json:"status"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	TransactionId *int64    `json:"transaction_id"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type WalletStatusChange struct {
+	Id         int64     `json:"id"`
+	WalletId   int64     `json:"wallet_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Reason     string    `json:"reason"`
+	Actor      string    `json:"actor"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type AuditEvent struct {
+	Id          int64     `json:"id"`
+	Principal   *string   `json:"principal"`
+	Ip          string    `json:"ip"`
+	Method      string    `json:"method"`
+	Route       string    `json:"route"`
+	Path        string    `json:"path"`
+	WalletId    *int64    `json:"wallet_id"`
+	PayloadHash string    `json:"payload_hash"`
+	Outcome     string    `json:"outcome"`
+	HttpStatus  int       `json:"http_status"`
+	ErrorCode   *string   `json:"error_code"`
+	TraceId     *string   `json:"trace_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// AuditFilter
+// Zero fields are not filtered on. From is inclusive and To is exclusive.
+type AuditFilter struct {
+	Username string
+	WalletId int64
+	From     time.Time
+	To       time.Time
+	Limit    int
+}
+
+type WalletDiscrepancy struct {
+	WalletId  int64           `json:"wallet_id"`
+	Currency  string          `json:"currency"`
+	Balance   decimal.Decimal `json:"balance"`
+	LedgerSum decimal.Decimal `json:"ledger_sum"`
+	// LastLedgerBalance is nil for wallets without ledgers.
+	LastLedgerBalance *decimal.Decimal `json:"last_ledger_balance"`
+	Ledgers           int64            `json:"ledgers"`
+}
+
+type TransactionDiscrepancy struct {
+	Id        int64           `json:"id"`
+	Status    string          `json:"status"`
+	Operation string          `json:"operation"`
+	Ledgers   int64           `json:"ledgers"`
+	Credits   decimal.Decimal `json:"credits"`
+	Debits    decimal.Decimal `json:"debits"`
+}
+
+// Reconciliation
+// Counts of checked wallets and transactions, and the ones not matching their ledgers.
+type Reconciliation struct {
+	At                       time.Time                `json:"at"`
+	Wallets                  int64                    `json:"wallets"`
+	Transactions             int64                    `json:"transactions"`
+	WalletDiscrepancies      []WalletDiscrepancy      `json:"wallet_discrepancies"`
+	TransactionDiscrepancies []TransactionDiscrepancy `json:"transaction_discrepancies"`
+}
+
+type Readiness struct {
+	// Status is ready, not_ready or draining.
+	Status string `json:"status"`
+	Checks *struct {
+		Database struct {
+			Status    string `json:"status"`
+			LatencyMs int64  `json:"latency_ms"`
+		} `json:"database"`
+		Migrations struct {
+			Status          string `json:"status"`
+			Version         int64  `json:"version"`
+			ExpectedVersion int64  `json:"expected_version"`
+		} `json:"migrations"`
+		Pool struct {
+			Status        string  `json:"status"`
+			AcquiredConns int32   `json:"acquired_conns"`
+			IdleConns     int32   `json:"idle_conns"`
+			TotalConns    int32   `json:"total_conns"`
+			MaxConns      int32   `json:"max_conns"`
+			Saturation    float64 `json:"saturation"`
+		} `json:"pool"`
+	} `json:"checks"`
+}
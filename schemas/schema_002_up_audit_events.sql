@@ -0,0 +1,40 @@
+CREATE TABLE public.audit_events
+(
+    id           bigint GENERATED always AS IDENTITY PRIMARY KEY,
+    principal    text,
+    ip           text                     NOT NULL,
+    method       text                     NOT NULL,
+    route        text                     NOT NULL,
+    path         text                     NOT NULL,
+    wallet_id    bigint,
+    payload_hash text                     NOT NULL,
+    outcome      text                     NOT NULL,
+    http_status  integer                  NOT NULL,
+    error_code   text,
+    trace_id     text,
+    created_at   timestamp WITH TIME ZONE NOT NULL
+);
+
+COMMENT ON COLUMN public.audit_events.principal IS 'username from Authorization header, null if absent or malformed';
+COMMENT ON COLUMN public.audit_events.route IS 'matched route pattern i.e POST /wallet/{wallet_id}/deposit';
+COMMENT ON COLUMN public.audit_events.wallet_id IS 'wallet in route path, not a foreign key as wallet may not exist';
+COMMENT ON COLUMN public.audit_events.payload_hash IS 'hex encoded sha256 of request body';
+COMMENT ON COLUMN public.audit_events.outcome IS 'success, rejected (4xx), failed (5xx)';
+
+CREATE INDEX audit_events_principal_index ON public.audit_events (principal, created_at);
+CREATE INDEX audit_events_wallet_id_index ON public.audit_events (wallet_id, created_at);
+CREATE INDEX audit_events_created_at_index ON public.audit_events (created_at);
+
+CREATE FUNCTION public.audit_events_append_only() RETURNS trigger
+    LANGUAGE plpgsql AS
+$$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$;
+
+CREATE TRIGGER audit_events_append_only
+    BEFORE UPDATE OR DELETE
+    ON public.audit_events
+    FOR EACH ROW
+EXECUTE FUNCTION public.audit_events_append_only();
@@ -0,0 +1,5 @@
+COMMENT ON COLUMN public.transactions.operation IS 'deposit, withdrawal, transfer';
+
+ALTER TABLE public.user_accounts
+    DROP COLUMN IF EXISTS frozen,
+    DROP COLUMN IF EXISTS role;
@@ -0,0 +1,8 @@
+ALTER TABLE public.user_accounts
+    ADD COLUMN role   text    NOT NULL DEFAULT 'customer'
+        CONSTRAINT user_accounts_role_check CHECK (role IN ('customer', 'support_readonly', 'operator', 'admin')),
+    ADD COLUMN frozen boolean NOT NULL DEFAULT false;
+
+COMMENT ON COLUMN public.user_accounts.role IS 'customer, support_readonly, operator, admin';
+COMMENT ON COLUMN public.user_accounts.frozen IS 'frozen accounts cannot transact, nor receive transfers';
+COMMENT ON COLUMN public.transactions.operation IS 'deposit, withdrawal, transfer, adjustment';
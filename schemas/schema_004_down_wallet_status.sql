@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS public.wallet_status_changes;
+DROP FUNCTION IF EXISTS public.wallet_status_changes_append_only();
+
+ALTER TABLE public.wallets
+    DROP COLUMN IF EXISTS status;
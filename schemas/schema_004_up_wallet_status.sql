@@ -0,0 +1,35 @@
+ALTER TABLE public.wallets
+    ADD COLUMN status text NOT NULL DEFAULT 'active'
+        CONSTRAINT wallets_status_check CHECK (status IN ('active', 'frozen', 'debit_frozen', 'closed'));
+
+COMMENT ON COLUMN public.wallets.status IS 'active, frozen (no credit nor debit), debit_frozen (credit only), closed (terminal)';
+
+
+CREATE TABLE public.wallet_status_changes
+(
+    id          bigint GENERATED always AS IDENTITY PRIMARY KEY,
+    wallet_id   bigint                   NOT NULL REFERENCES public.wallets,
+    from_status text                     NOT NULL,
+    to_status   text                     NOT NULL,
+    reason      text                     NOT NULL,
+    actor_id    bigint                   NOT NULL REFERENCES public.user_accounts,
+    created_at  timestamp WITH TIME ZONE NOT NULL
+);
+
+COMMENT ON COLUMN public.wallet_status_changes.actor_id IS 'user who changed the status';
+
+CREATE INDEX wallet_status_changes_wallet_id_index ON public.wallet_status_changes (wallet_id, created_at);
+
+CREATE FUNCTION public.wallet_status_changes_append_only() RETURNS trigger
+    LANGUAGE plpgsql AS
+$$
+BEGIN
+    RAISE EXCEPTION 'wallet_status_changes is append-only';
+END;
+$$;
+
+CREATE TRIGGER wallet_status_changes_append_only
+    BEFORE UPDATE OR DELETE
+    ON public.wallet_status_changes
+    FOR EACH ROW
+EXECUTE FUNCTION public.wallet_status_changes_append_only();
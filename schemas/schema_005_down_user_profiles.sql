@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS public.user_accounts_email_idx;
+
+ALTER TABLE public.user_accounts
+    DROP COLUMN IF EXISTS created_at,
+    DROP COLUMN IF EXISTS kyc_tier,
+    DROP COLUMN IF EXISTS status,
+    DROP COLUMN IF EXISTS email,
+    DROP COLUMN IF EXISTS display_name;
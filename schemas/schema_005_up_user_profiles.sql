@@ -0,0 +1,14 @@
+ALTER TABLE public.user_accounts
+    ADD COLUMN display_name text,
+    ADD COLUMN email        text,
+    ADD COLUMN status       text                     NOT NULL DEFAULT 'pending'
+        CONSTRAINT user_accounts_status_check CHECK (status IN ('pending', 'verified', 'suspended')),
+    ADD COLUMN kyc_tier     smallint                 NOT NULL DEFAULT 0
+        CONSTRAINT user_accounts_kyc_tier_check CHECK (kyc_tier BETWEEN 0 AND 2),
+    ADD COLUMN created_at   timestamp WITH TIME ZONE NOT NULL DEFAULT now();
+
+COMMENT ON COLUMN public.user_accounts.status IS 'pending, verified, suspended (cannot move money)';
+COMMENT ON COLUMN public.user_accounts.kyc_tier IS '0 to 2, gates operations and limits. tiers above 0 are not granted to pending users';
+COMMENT ON COLUMN public.user_accounts.created_at IS 'migration time for users created before schema 005';
+
+CREATE UNIQUE INDEX user_accounts_email_idx ON public.user_accounts (lower(email));
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS public.user_accounts_username_canonical_idx;
+
+COMMENT ON COLUMN public.user_accounts.username IS NULL;
+
+ALTER TABLE public.user_accounts
+    DROP COLUMN IF EXISTS username_canonical;
@@ -0,0 +1,13 @@
+ALTER TABLE public.user_accounts
+    ADD COLUMN username_canonical text;
+
+UPDATE public.user_accounts
+SET username_canonical = lower(normalize(username, NFKC));
+
+ALTER TABLE public.user_accounts
+    ALTER COLUMN username_canonical SET NOT NULL;
+
+COMMENT ON COLUMN public.user_accounts.username IS 'NFKC form as registered, for display';
+COMMENT ON COLUMN public.user_accounts.username_canonical IS 'NFKC case folded username, identifies the user. lower(normalize()) for users created before schema 006';
+
+CREATE UNIQUE INDEX user_accounts_username_canonical_idx ON public.user_accounts (username_canonical);
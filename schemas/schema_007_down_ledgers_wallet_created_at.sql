@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS ledgers_wallet_id_index ON public.ledgers (wallet_id);
+
+DROP INDEX IF EXISTS public.ledgers_wallet_id_created_at_idx;
+
+COMMENT ON COLUMN public.ledgers.created_at IS NULL;
@@ -0,0 +1,5 @@
+CREATE INDEX ledgers_wallet_id_created_at_idx ON public.ledgers (wallet_id, created_at, id);
+
+DROP INDEX IF EXISTS public.ledgers_wallet_id_index;
+
+COMMENT ON COLUMN public.ledgers.created_at IS 'time of append under the wallet lock. transaction start time for ledgers created before schema 007';
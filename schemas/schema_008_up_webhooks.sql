@@ -0,0 +1,38 @@
+CREATE TABLE public.webhook_subscriptions
+(
+    id              bigint GENERATED always AS IDENTITY PRIMARY KEY,
+    user_account_id bigint                   NOT NULL REFERENCES public.user_accounts (id),
+    url             text                     NOT NULL,
+    secret          text                     NOT NULL,
+    event_types     text[]                   NOT NULL,
+    active          boolean                  NOT NULL DEFAULT true,
+    created_at      timestamp WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+COMMENT ON COLUMN public.webhook_subscriptions.secret IS 'HMAC-SHA256 key of the X-Webhook-Signature header, returned on creation only';
+COMMENT ON COLUMN public.webhook_subscriptions.event_types IS 'transaction.succeeded, transaction.failed, balance.changed';
+COMMENT ON COLUMN public.webhook_subscriptions.active IS 'false once deleted, deliveries are kept';
+
+CREATE INDEX webhook_subscriptions_user_account_id_index ON public.webhook_subscriptions (user_account_id) WHERE active;
+
+CREATE TABLE public.webhook_deliveries
+(
+    id              bigint GENERATED always AS IDENTITY PRIMARY KEY,
+    subscription_id bigint                   NOT NULL REFERENCES public.webhook_subscriptions (id),
+    event_type      text                     NOT NULL,
+    payload         jsonb                    NOT NULL,
+    status          text                     NOT NULL DEFAULT 'pending'
+        CONSTRAINT webhook_deliveries_status_check CHECK (status IN ('pending', 'delivered', 'dead')),
+    attempts        integer                  NOT NULL DEFAULT 0,
+    next_attempt_at timestamp WITH TIME ZONE NOT NULL DEFAULT now(),
+    last_error      text,
+    created_at      timestamp WITH TIME ZONE NOT NULL DEFAULT now(),
+    delivered_at    timestamp WITH TIME ZONE
+);
+
+COMMENT ON TABLE public.webhook_deliveries IS 'outbox, written in the transaction of the ledgers the events are about';
+COMMENT ON COLUMN public.webhook_deliveries.status IS 'pending, delivered, dead (attempts exhausted, replayable)';
+COMMENT ON COLUMN public.webhook_deliveries.next_attempt_at IS 'due time of pending deliveries, leased forward while an attempt is in flight';
+
+CREATE INDEX webhook_deliveries_due_index ON public.webhook_deliveries (next_attempt_at) WHERE status = 'pending';
+CREATE INDEX webhook_deliveries_subscription_id_index ON public.webhook_deliveries (subscription_id, id);
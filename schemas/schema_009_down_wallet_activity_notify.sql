@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS transactions_notify_activity ON public.transactions;
+DROP FUNCTION IF EXISTS public.notify_transaction_activity();
+DROP TRIGGER IF EXISTS ledgers_notify_activity ON public.ledgers;
+DROP FUNCTION IF EXISTS public.notify_ledger_activity();
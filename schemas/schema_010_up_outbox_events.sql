@@ -0,0 +1,16 @@
+CREATE TABLE public.outbox_events
+(
+    id           bigint GENERATED always AS IDENTITY PRIMARY KEY,
+    event_type   text                     NOT NULL,
+    key          text                     NOT NULL,
+    payload      jsonb                    NOT NULL,
+    created_at   timestamp WITH TIME ZONE NOT NULL DEFAULT now(),
+    published_at timestamp WITH TIME ZONE
+);
+
+COMMENT ON TABLE public.outbox_events IS 'domain events, written in the transaction of the change they are about and relayed to the configured sink in id order';
+COMMENT ON COLUMN public.outbox_events.event_type IS 'user.created, wallet.created, funds.deposited, funds.withdrawn, transfer.completed, transaction.failed';
+COMMENT ON COLUMN public.outbox_events.key IS 'ordering key, wallet:<id> or user:<id>. ids of a wallet are assigned under its lock, in commit order';
+COMMENT ON COLUMN public.outbox_events.published_at IS 'null until accepted by the sink';
+
+CREATE INDEX outbox_events_unpublished_index ON public.outbox_events (id) WHERE published_at IS NULL;
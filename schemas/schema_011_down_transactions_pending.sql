@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS public.transactions_pending_index;
+COMMENT ON COLUMN public.transactions.status IS 'pending, success, error_*';
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS public.transactions_pending_index;
+CREATE INDEX transactions_pending_index ON public.transactions (created_at) WHERE status = 'pending';
+
+COMMENT ON COLUMN public.transactions.status IS 'pending until the ledgers are written, then success or error_<error code>. pending rows older than a request are left by a crashed process';
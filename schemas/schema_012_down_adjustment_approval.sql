@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS public.transactions_pending_approval_index;
+COMMENT ON COLUMN public.transactions.status IS 'pending until the ledgers are written, then success or error_<error code>. pending rows older than a request are left by a crashed process';
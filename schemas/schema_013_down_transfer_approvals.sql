@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS public.transaction_approvals;
+DROP TABLE IF EXISTS public.wallet_approvers;
+DROP TABLE IF EXISTS public.wallet_approval_policies;
+COMMENT ON COLUMN public.transactions.status IS 'pending until the ledgers are written, then success or error_<error code>. pending rows older than a request are left by a crashed process. adjustments are pending_approval until approved by another operator, or rejected';
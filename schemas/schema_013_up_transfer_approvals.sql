@@ -0,0 +1,35 @@
+CREATE TABLE public.wallet_approval_policies
+(
+    wallet_id          bigint PRIMARY KEY REFERENCES public.wallets (id),
+    threshold          numeric(20, 6)           NOT NULL
+        CONSTRAINT wallet_approval_policies_threshold_check CHECK (threshold >= (0)::numeric),
+    required_approvals integer                  NOT NULL
+        CONSTRAINT wallet_approval_policies_required_approvals_check CHECK (required_approvals >= 1),
+    timeout_seconds    bigint                   NOT NULL
+        CONSTRAINT wallet_approval_policies_timeout_seconds_check CHECK (timeout_seconds > 0),
+    updated_by         text                     NOT NULL,
+    updated_at         timestamp WITH TIME ZONE NOT NULL DEFAULT now()
+);
+
+COMMENT ON TABLE public.wallet_approval_policies IS 'transfers from the wallet over threshold are pending_approval until required_approvals approvers approve';
+
+CREATE TABLE public.wallet_approvers
+(
+    wallet_id       bigint NOT NULL REFERENCES public.wallet_approval_policies (wallet_id) ON DELETE CASCADE,
+    user_account_id bigint NOT NULL REFERENCES public.user_accounts (id),
+    PRIMARY KEY (wallet_id, user_account_id)
+);
+
+CREATE TABLE public.transaction_approvals
+(
+    transaction_id  bigint                   NOT NULL REFERENCES public.transactions (id),
+    user_account_id bigint                   NOT NULL REFERENCES public.user_accounts (id),
+    decision        text                     NOT NULL
+        CONSTRAINT transaction_approvals_decision_check CHECK (decision IN ('approved', 'rejected')),
+    created_at      timestamp WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (transaction_id, user_account_id)
+);
+
+COMMENT ON TABLE public.transaction_approvals IS 'decisions of approvers on transfers pending_approval, one per approver';
+
+COMMENT ON COLUMN public.transactions.status IS 'pending until the ledgers are written, then success or error_<error code>. pending rows older than a request are left by a crashed process. adjustments are pending_approval until approved by another operator, or rejected. transfers over the approval policy of the source wallet are pending_approval until approved by quorum, or rejected, or expired';
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS public.wallet_memberships;
+
+-- fails if a user created several wallets of a currency, close and delete all but one of them first
+DROP INDEX IF EXISTS public.wallets_name_idx;
+CREATE UNIQUE INDEX wallets_currency_idx ON public.wallets (user_account_id, currency);
+
+COMMENT ON COLUMN public.wallets.user_account_id IS NULL;
+
+ALTER TABLE public.wallets
+    DROP COLUMN IF EXISTS name;
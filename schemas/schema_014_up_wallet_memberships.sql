@@ -0,0 +1,31 @@
+ALTER TABLE public.wallets
+    ADD COLUMN name text NOT NULL DEFAULT 'main';
+
+COMMENT ON COLUMN public.wallets.name IS 'unique per creator and currency';
+COMMENT ON COLUMN public.wallets.user_account_id IS 'creator of the wallet, its first owner member';
+
+DROP INDEX public.wallets_currency_idx;
+CREATE UNIQUE INDEX wallets_name_idx ON public.wallets (user_account_id, currency, name);
+
+CREATE TABLE public.wallet_memberships
+(
+    wallet_id       bigint                   NOT NULL REFERENCES public.wallets (id),
+    user_account_id bigint                   NOT NULL REFERENCES public.user_accounts (id),
+    role            text                     NOT NULL
+        CONSTRAINT wallet_memberships_role_check CHECK (role IN ('owner', 'spender', 'viewer')),
+    spend_limit     numeric(20, 6)
+        CONSTRAINT wallet_memberships_spend_limit_check CHECK ((role = 'spender') = (spend_limit IS NOT NULL) AND
+                                                              spend_limit > (0)::numeric),
+    updated_by      text                     NOT NULL,
+    updated_at      timestamp WITH TIME ZONE NOT NULL DEFAULT now(),
+    PRIMARY KEY (wallet_id, user_account_id)
+);
+
+COMMENT ON TABLE public.wallet_memberships IS 'users acting on a wallet. owners operate and manage members, spenders operate up to spend_limit per debit, viewers read';
+
+CREATE INDEX wallet_memberships_user_account_id_idx ON public.wallet_memberships (user_account_id);
+
+INSERT INTO public.wallet_memberships (wallet_id, user_account_id, role, updated_by)
+SELECT w.id, w.user_account_id, 'owner', ua.username
+FROM public.wallets w
+         JOIN public.user_accounts ua ON ua.id = w.user_account_id;
@@ -0,0 +1,26 @@
+CREATE TABLE public.payment_requests
+(
+    id             bigserial PRIMARY KEY,
+    requester_id   bigint                   NOT NULL REFERENCES public.user_accounts (id),
+    payer_id       bigint                   NOT NULL REFERENCES public.user_accounts (id),
+    wallet_id      bigint                   NOT NULL REFERENCES public.wallets (id),
+    amount         numeric(20, 6)           NOT NULL
+        CONSTRAINT payment_requests_amount_check CHECK (amount > (0)::numeric),
+    currency       text                     NOT NULL,
+    memo           text                     NOT NULL DEFAULT '',
+    status         text                     NOT NULL DEFAULT 'pending'
+        CONSTRAINT payment_requests_status_check CHECK (status IN ('pending', 'paid', 'cancelled')),
+    transaction_id bigint REFERENCES public.transactions (id),
+    expires_at     timestamp WITH TIME ZONE NOT NULL,
+    created_at     timestamp WITH TIME ZONE NOT NULL DEFAULT now(),
+    updated_at     timestamp WITH TIME ZONE NOT NULL DEFAULT now(),
+    CONSTRAINT payment_requests_payer_check CHECK (payer_id <> requester_id),
+    CONSTRAINT payment_requests_transaction_id_check CHECK ((status = 'paid') = (transaction_id IS NOT NULL))
+);
+
+COMMENT ON TABLE public.payment_requests IS 'requests of requester to be paid amount into wallet_id by payer. pending until paid by a transfer, in the same transaction, or cancelled by the requester. pending requests past expires_at are expired';
+COMMENT ON COLUMN public.payment_requests.transaction_id IS 'transfer paying the request';
+
+CREATE INDEX payment_requests_payer_id_idx ON public.payment_requests (payer_id, id);
+CREATE INDEX payment_requests_requester_id_idx ON public.payment_requests (requester_id, id);
+CREATE UNIQUE INDEX payment_requests_transaction_id_idx ON public.payment_requests (transaction_id);
@@ -0,0 +1,39 @@
+CREATE OR REPLACE FUNCTION public.notify_ledger_activity() RETURNS trigger
+    LANGUAGE plpgsql AS
+$$
+BEGIN
+    PERFORM pg_notify('wallet_activity', json_build_object(
+            'kind', 'ledger',
+            'user_account_id', (SELECT w.user_account_id FROM public.wallets w WHERE w.id = NEW.wallet_id),
+            'ledger', json_build_object(
+                    'id', NEW.id,
+                    'wallet_id', NEW.wallet_id,
+                    'entry_type', NEW.entry_type,
+                    'amount', NEW.amount,
+                    'created_at', NEW.created_at,
+                    'balance', NEW.balance,
+                    'transaction_id', NEW.transaction_id))::text);
+    RETURN NULL;
+END;
+$$;
+
+COMMENT ON FUNCTION public.notify_ledger_activity() IS 'notifies wallet_activity listeners of a ledger, to the wallet owner. delivered on commit';
+
+CREATE OR REPLACE FUNCTION public.notify_transaction_activity() RETURNS trigger
+    LANGUAGE plpgsql AS
+$$
+BEGIN
+    PERFORM pg_notify('wallet_activity', json_build_object(
+            'kind', 'transaction',
+            'user_account_id', NEW.requestor_id,
+            'transaction', json_build_object(
+                    'id', NEW.id,
+                    'nonce', NEW.nonce,
+                    'operation', NEW.operation,
+                    'status', NEW.status,
+                    'created_at', NEW.created_at))::text);
+    RETURN NULL;
+END;
+$$;
+
+COMMENT ON FUNCTION public.notify_transaction_activity() IS 'notifies wallet_activity listeners of a transaction status, to the requestor. delivered on commit';
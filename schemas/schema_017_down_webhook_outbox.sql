@@ -0,0 +1,6 @@
+COMMENT ON TABLE public.webhook_deliveries IS 'outbox, written in the transaction of the ledgers the events are about';
+
+DROP INDEX IF EXISTS public.outbox_events_webhooks_pending_index;
+
+ALTER TABLE public.outbox_events
+    DROP COLUMN IF EXISTS webhooks_enqueued_at;
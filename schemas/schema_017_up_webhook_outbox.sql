@@ -0,0 +1,11 @@
+ALTER TABLE public.outbox_events
+    ADD COLUMN webhooks_enqueued_at timestamp WITH TIME ZONE;
+
+COMMENT ON COLUMN public.outbox_events.webhooks_enqueued_at IS 'null until the webhook deliveries of the event are written';
+
+-- deliveries of earlier events were written with the change
+UPDATE public.outbox_events SET webhooks_enqueued_at = created_at;
+
+CREATE INDEX outbox_events_webhooks_pending_index ON public.outbox_events (id) WHERE webhooks_enqueued_at IS NULL;
+
+COMMENT ON TABLE public.webhook_deliveries IS 'deliveries of outbox_events to webhook subscriptions, written by the dispatcher';
@@ -0,0 +1,2 @@
+-- Folded canonical usernames are kept, the service looks users up by them.
+COMMENT ON COLUMN public.user_accounts.username_canonical IS 'NFKC case folded username, identifies the user. lower(normalize()) for users created before schema 006';
@@ -0,0 +1,40 @@
+-- Schema 006 backfilled username_canonical with lower(), which does not fully case fold as the service does
+-- (policy.CanonicalUsername). Full foldings lower() misses are replaced explicitly: 'ß' and 'ẞ' fold to 'ss', final
+-- sigma 'ς' to 'σ'.
+CREATE TEMP TABLE username_canonical_fold AS
+SELECT id,
+       username,
+       normalize(replace(replace(lower(normalize(username, NFKC)), 'ß', 'ss'), 'ς', 'σ'), NFKC) AS username_canonical
+FROM public.user_accounts;
+
+DO
+$$
+    DECLARE
+        collisions text;
+    BEGIN
+        SELECT string_agg(format('%s (%s)', c.username_canonical, c.usernames), ', ')
+        INTO collisions
+        FROM (SELECT username_canonical, string_agg(username, ', ' ORDER BY id) AS usernames
+              FROM username_canonical_fold
+              GROUP BY username_canonical
+              HAVING count(*) > 1) c;
+        IF collisions IS NOT NULL THEN
+            RAISE EXCEPTION 'usernames collide by canonical form, rename all but one of each: %', collisions;
+        END IF;
+    END
+$$;
+
+-- rebuilt after the update, values may swap between rows
+DROP INDEX public.user_accounts_username_canonical_idx;
+
+UPDATE public.user_accounts u
+SET username_canonical = f.username_canonical
+FROM username_canonical_fold f
+WHERE f.id = u.id
+  AND u.username_canonical <> f.username_canonical;
+
+CREATE UNIQUE INDEX user_accounts_username_canonical_idx ON public.user_accounts (username_canonical);
+
+DROP TABLE username_canonical_fold;
+
+COMMENT ON COLUMN public.user_accounts.username_canonical IS 'NFKC case folded username, identifies the user';
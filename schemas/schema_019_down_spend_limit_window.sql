@@ -0,0 +1,2 @@
+COMMENT ON COLUMN public.wallet_memberships.spend_limit IS NULL;
+COMMENT ON TABLE public.wallet_memberships IS 'users acting on a wallet. owners operate and manage members, spenders operate up to spend_limit per debit, viewers read';
@@ -0,0 +1,2 @@
+COMMENT ON COLUMN public.wallet_memberships.spend_limit IS 'maximum total of the debits of a spender per UTC day, summed from ledgers of transactions it requested';
+COMMENT ON TABLE public.wallet_memberships IS 'users acting on a wallet. owners operate and manage members, spenders operate up to spend_limit per UTC day, viewers read';
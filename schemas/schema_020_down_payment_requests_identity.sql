@@ -0,0 +1,10 @@
+ALTER TABLE public.payment_requests
+    ALTER COLUMN id DROP IDENTITY;
+
+CREATE SEQUENCE public.payment_requests_id_seq OWNED BY public.payment_requests.id;
+
+SELECT setval('public.payment_requests_id_seq', coalesce(max(id), 0) + 1, false)
+FROM public.payment_requests;
+
+ALTER TABLE public.payment_requests
+    ALTER COLUMN id SET DEFAULT nextval('public.payment_requests_id_seq');
@@ -0,0 +1,17 @@
+-- payment_requests.id was created bigserial by schema 015, identity like the other tables from here, continuing after
+-- the ids in use.
+ALTER TABLE public.payment_requests
+    ALTER COLUMN id DROP DEFAULT;
+
+DROP SEQUENCE public.payment_requests_id_seq;
+
+DO
+$$
+    DECLARE
+        next_id bigint;
+    BEGIN
+        SELECT coalesce(max(id), 0) + 1 INTO next_id FROM public.payment_requests;
+        EXECUTE format('ALTER TABLE public.payment_requests ALTER COLUMN id ADD GENERATED always AS IDENTITY (START WITH %s)',
+                       next_id);
+    END
+$$;
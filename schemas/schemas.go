@@ -0,0 +1,9 @@
+// Package schemas
+// Versioned DDL migrations embedded into the server binary.
+// File name format: schema_<3 digit version>_<up|down>_<name>.sql
+package schemas
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -1,8 +1,13 @@
 package middlewares
 
 import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
 	"net/http"
 	"strings"
+
+	"github.com/cryptonlx/crypto/src/repositories/utils"
 )
 
 func GetSessionIdFromRequest(r *http.Request) string {
@@ -23,7 +28,11 @@ func GetSessionIdFromRequest(r *http.Request) string {
 }
 
 func GetBasicAuthFromRequest(r *http.Request) string {
-	authHeader := r.Header.Get("Authorization")
+	return GetBasicAuthFromAuthorization(r.Header.Get("Authorization"))
+}
+
+// GetBasicAuthFromAuthorization returns the credentials of an Authorization header value of scheme Basic.
+func GetBasicAuthFromAuthorization(authHeader string) string {
 	if authHeader == "" {
 		return ""
 	}
@@ -37,3 +46,62 @@ func GetBasicAuthFromRequest(r *http.Request) string {
 	token := parts[1]
 	return token
 }
+
+// ExtractUsernameFromBasicAuthValue decodes a "Base64(username:)" value and returns the username.
+func ExtractUsernameFromBasicAuthValue(basicAuthB64 string) (string, error) {
+	basicAuth, err := base64.StdEncoding.DecodeString(basicAuthB64)
+	if err != nil {
+		return "", utils.WrapError(utils.ErrorCodeUnauthorized, err)
+	}
+	basicAuthSlice := strings.Split(string(basicAuth), ":")
+	if len(basicAuthSlice) != 2 {
+		return "", utils.NewError(utils.ErrorCodeUnauthorized, "invalid_basic_auth")
+	}
+	principal := basicAuthSlice[0]
+	if principal == "" {
+		return "", utils.NewError(utils.ErrorCodeUnauthorized, "invalid principal")
+	}
+	return principal, nil
+}
+
+// ContextualizeClientCertPrincipal
+// Sets "TLS_PRINCIPAL" to the principal mapped from the subject of a verified client certificate, matched by
+// distinguished name, i.e. `CN=payments,O=Crypto`, then by common name. Unmapped certificates authenticate nobody.
+func ContextualizeClientCertPrincipal(r *http.Request, subjectPrincipals map[string]string) *http.Request {
+	principal := ClientCertPrincipal(r.TLS, subjectPrincipals)
+	if principal == "" {
+		return r
+	}
+	return r.WithContext(context.WithValue(r.Context(), "TLS_PRINCIPAL", principal))
+}
+
+// ClientCertPrincipal
+// Principal mapped from the subject of the verified client certificate of state as ContextualizeClientCertPrincipal,
+// empty if none.
+func ClientCertPrincipal(state *tls.ConnectionState, subjectPrincipals map[string]string) string {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	subject := state.VerifiedChains[0][0].Subject
+	principal, ok := subjectPrincipals[subject.String()]
+	if !ok {
+		principal = subjectPrincipals[subject.CommonName]
+	}
+	return principal
+}
+
+// PrincipalFromRequest
+// Principal of a mapped client certificate if any, otherwise of the basic auth header.
+func PrincipalFromRequest(r *http.Request) (string, error) {
+	return PrincipalFromContext(r.Context())
+}
+
+// PrincipalFromContext
+// Same as PrincipalFromRequest for "TLS_PRINCIPAL" and "BASIC_AUTH" set in ctx, by http or grpc.
+func PrincipalFromContext(ctx context.Context) (string, error) {
+	if principal, _ := ctx.Value("TLS_PRINCIPAL").(string); principal != "" {
+		return principal, nil
+	}
+	basicAuthB64, _ := ctx.Value("BASIC_AUTH").(string)
+	return ExtractUsernameFromBasicAuthValue(basicAuthB64)
+}
@@ -0,0 +1,205 @@
+package activity
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/controllers/middlewares"
+	"github.com/cryptonlx/crypto/src/controllers/response_types"
+	activityrepo "github.com/cryptonlx/crypto/src/repositories/activity"
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+	activityservice "github.com/cryptonlx/crypto/src/services/activity"
+)
+
+const (
+	EventLedger      = "ledger"
+	EventTransaction = "transaction"
+
+	// keepaliveInterval
+	// Between comments on idle streams, for proxies not to time the stream out.
+	keepaliveInterval = 15 * time.Second
+	// writeTimeout
+	// Of each event, the server WriteTimeout would otherwise end the stream.
+	writeTimeout = 10 * time.Second
+)
+
+type Handlers struct {
+	hub *activityservice.Hub
+}
+
+func NewHandlers(hub *activityservice.Hub) *Handlers {
+	return &Handlers{
+		hub: hub,
+	}
+}
+
+// Ledger
+// Data of ledger events.
+type Ledger struct {
+	Id            int64     `json:"id" example:"12222214214"`
+	WalletId      int64     `json:"wallet_id" example:"1021"`
+	TransactionId int64     `json:"transaction_id" example:"1749286345000"`
+	EntryType     string    `json:"entry_type" example:"credit"`
+	Amount        string    `json:"amount" example:"40.1122"`
+	CreatedAt     time.Time `json:"created_at" example:"2025-06-09T02:02:31.213543+08:00"`
+	Balance       string    `json:"balance" example:"2.2324"`
+}
+
+// Transaction
+// Data of transaction events.
+type Transaction struct {
+	Id        int64     `json:"id" example:"1749286345000"`
+	Nonce     int64     `json:"nonce" example:"1749286345000"`
+	Operation string    `json:"operation" example:"transfer"`
+	Status    string    `json:"status" example:"success"`
+	CreatedAt time.Time `json:"created_at" example:"2025-06-09T02:02:31.213543+08:00"`
+}
+
+func ledger(l userrepo.Ledger) Ledger {
+	return Ledger{
+		Id:            l.Id,
+		WalletId:      l.WalletId,
+		TransactionId: l.TransactionId,
+		EntryType:     l.EntryType,
+		Amount:        l.Amount.String(),
+		CreatedAt:     l.CreatedAt,
+		Balance:       l.Balance.String(),
+	}
+}
+
+func transaction(t activityrepo.Transaction) Transaction {
+	return Transaction{Id: t.Id, Nonce: t.Nonce, Operation: t.Operation, Status: t.Status, CreatedAt: t.CreatedAt}
+}
+
+// Events godoc
+// @Summary      Stream activity of user as server-sent events.
+// @Description  Streams postings to wallets of user as `ledger` events with the ledger id as event id, and status changes of transactions requested by user as `transaction` events without id, until the client disconnects. Reconnect with the Last-Event-ID header to receive the ledgers after that id first; transaction events are not replayed. Owner or roles support_readonly, operator and admin only, otherwise 404.
+// @Tags         user
+// @Security     BasicAuth
+// @Produce      text/event-stream,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param 		 Last-Event-ID header int false "id of the last ledger event received"
+// @Param        username   					path      string  true  "username"
+// @Success      200  {object}  Ledger "data of ledger events, transaction events carry a Transaction"
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /user/{username}/events [get]
+func (h Handlers) Events(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	var lastEventId int64 = -1
+	if _lastEventId := r.Header.Get("Last-Event-ID"); _lastEventId != "" {
+		lastEventId, err = strconv.ParseInt(_lastEventId, 10, 64)
+		if err != nil || lastEventId < 0 {
+			response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid Last-Event-ID"))
+			return
+		}
+	}
+
+	subscription, err := h.hub.Subscribe(r.Context(), principal, r.PathValue("username"))
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	defer subscription.Close()
+
+	rc := http.NewResponseController(w)
+	write := func(event string, id string, data any) error {
+		rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := WriteEvent(w, event, id, data); err != nil {
+			return err
+		}
+		return rc.Flush()
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	// replayed ledgers committed after subscribing are also notified, and skipped. Ledgers may commit out of id order,
+	// so the replayed ids are kept rather than the last one.
+	replayed := map[int64]struct{}{}
+	if lastEventId >= 0 {
+		err := h.hub.LedgersAfter(r.Context(), subscription, lastEventId, func(l userrepo.Ledger) error {
+			replayed[l.Id] = struct{}{}
+			return write(EventLedger, strconv.FormatInt(l.Id, 10), ledger(l))
+		})
+		if err != nil {
+			return
+		}
+	}
+
+	keepalive := time.NewTicker(keepaliveInterval)
+	defer keepalive.Stop()
+	for {
+		var err error
+		select {
+		case <-r.Context().Done():
+			return
+		case <-keepalive.C:
+			rc.SetWriteDeadline(time.Now().Add(writeTimeout))
+			if _, err = io.WriteString(w, ": keepalive\n\n"); err == nil {
+				err = rc.Flush()
+			}
+		case a, ok := <-subscription.Events():
+			// dropped, the client reconnects with Last-Event-ID
+			if !ok {
+				return
+			}
+			switch {
+			case a.Kind == activityrepo.KindLedger && a.Ledger != nil:
+				if _, ok := replayed[a.Ledger.Id]; ok {
+					continue
+				}
+				err = write(EventLedger, strconv.FormatInt(a.Ledger.Id, 10), ledger(*a.Ledger))
+			case a.Kind == activityrepo.KindTransaction && a.Transaction != nil:
+				err = write(EventTransaction, "", transaction(*a.Transaction))
+			}
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// WriteEvent
+// Writes one server-sent event with data as json. The id is omitted when empty, keeping the last event id of the client.
+func WriteEvent(w io.Writer, event string, id string, data any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		if _, err := fmt.Fprintf(w, "id: %s\n", id); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, b)
+	return err
+}
+
+// Types
+
+var _ = response_types.ResponseBody[struct{}](ResponseBody[struct{}]{})
+
+type ResponseBody[T any] struct {
+	Data  T       `json:"data"`
+	Error *string `json:"error" example:"" extensions:"x-nullable"`
+}
+
+type ProblemResponseBody = response_types.Problem
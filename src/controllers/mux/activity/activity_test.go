@@ -0,0 +1,53 @@
+package activity
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestWriteEvent(t *testing.T) {
+	l := userrepo.Ledger{Id: 3, WalletId: 4, EntryType: "credit", Amount: decimal.RequireFromString("1.50"),
+		CreatedAt: time.Date(2025, 6, 9, 2, 2, 31, 0, time.UTC), Balance: decimal.RequireFromString("2"), TransactionId: 5}
+
+	tests := []struct {
+		name  string
+		event string
+		id    string
+		data  any
+		want  string
+	}{
+		{
+			name:  "ledger with id",
+			event: EventLedger,
+			id:    "3",
+			data:  ledger(l),
+			want: "id: 3\nevent: ledger\n" +
+				`data: {"id":3,"wallet_id":4,"transaction_id":5,"entry_type":"credit","amount":"1.5","created_at":"2025-06-09T02:02:31Z","balance":"2"}` +
+				"\n\n",
+		},
+		{
+			name:  "transaction without id",
+			event: EventTransaction,
+			data:  Transaction{Id: 5, Nonce: 6, Operation: "deposit", Status: "success", CreatedAt: l.CreatedAt},
+			want: "event: transaction\n" +
+				`data: {"id":5,"nonce":6,"operation":"deposit","status":"success","created_at":"2025-06-09T02:02:31Z"}` +
+				"\n\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := WriteEvent(&b, tt.event, tt.id, tt.data); err != nil {
+				t.Fatalf("WriteEvent err %v", err)
+			}
+			if got := b.String(); got != tt.want {
+				t.Fatalf("want %q. got %q", tt.want, got)
+			}
+		})
+	}
+}
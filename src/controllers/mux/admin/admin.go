@@ -0,0 +1,870 @@
+package admin
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/controllers/middlewares"
+	"github.com/cryptonlx/crypto/src/controllers/response_types"
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+	"github.com/cryptonlx/crypto/src/services/policy"
+	userservice "github.com/cryptonlx/crypto/src/services/user"
+
+	"github.com/shopspring/decimal"
+)
+
+type Handlers struct {
+	service *userservice.Service
+}
+
+func NewHandlers(service *userservice.Service) *Handlers {
+	return &Handlers{
+		service: service,
+	}
+}
+
+type UserAccount struct {
+	Id       int64  `json:"id" example:"1"`
+	Username string `json:"username" example:"user1"`
+	Role     string `json:"role" example:"customer" enums:"customer,support_readonly,operator,admin"`
+	Frozen   bool   `json:"frozen" example:"false"`
+	Status   string `json:"status" example:"verified" enums:"pending,verified,suspended"`
+	KycTier  int    `json:"kyc_tier" example:"1"`
+}
+
+type Wallet struct {
+	Id            int64  `json:"id" example:"1"`
+	UserAccountId int64  `json:"user_account_id" example:"1"`
+	Name          string `json:"name" example:"main"`
+	Currency      string `json:"currency" example:"USD"`
+	Balance       string `json:"balance" example:"10.000123"`
+	Status        string `json:"status" example:"active" enums:"active,frozen,debit_frozen,closed"`
+}
+
+type UserResponseData struct {
+	User    UserAccount `json:"user"`
+	Wallets []Wallet    `json:"wallets"`
+}
+
+type UserResponseBody = ResponseBody[UserResponseData]
+
+// User godoc
+// @Summary      Get account and wallets of any user.
+// @Description  Get account, role, frozen flag and wallets of any user. Roles support_readonly, operator and admin only.
+// @Tags         admin
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        username   path      string  true  "username"
+// @Success      200  {object}  UserResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /admin/user/{username} [get]
+func (h Handlers) User(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	userWallets, err := h.service.UserAccount(r.Context(), principal, r.PathValue("username"))
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	wallets := make([]Wallet, 0, len(userWallets.Wallets))
+	for _, w := range userWallets.Wallets {
+		wallets = append(wallets, wallet(w))
+	}
+	response_types.WriteOkJsonBody(w, UserResponseData{
+		User:    userAccount(userWallets.User),
+		Wallets: wallets,
+	})
+}
+
+type UserAccountResponseData struct {
+	User UserAccount `json:"user"`
+}
+
+type UserAccountResponseBody = ResponseBody[UserAccountResponseData]
+
+// Freeze godoc
+// @Summary      Freeze a user account.
+// @Description  Frozen accounts cannot deposit, withdraw, transfer nor receive transfers. Operations in flight complete first.
+// @Description  Roles operator and admin only, not on own account.
+// @Tags         admin
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        username   path      string  true  "username"
+// @Success      200  {object}  UserAccountResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /admin/user/{username}/freeze [post]
+func (h Handlers) Freeze(w http.ResponseWriter, r *http.Request) {
+	h.setFrozen(w, r, true)
+}
+
+// Unfreeze godoc
+// @Summary      Unfreeze a user account.
+// @Description  Unfreeze a user account. Roles operator and admin only, not on own account.
+// @Tags         admin
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        username   path      string  true  "username"
+// @Success      200  {object}  UserAccountResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /admin/user/{username}/unfreeze [post]
+func (h Handlers) Unfreeze(w http.ResponseWriter, r *http.Request) {
+	h.setFrozen(w, r, false)
+}
+
+func (h Handlers) setFrozen(w http.ResponseWriter, r *http.Request, frozen bool) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	user, err := h.service.SetFrozen(r.Context(), principal, r.PathValue("username"), frozen)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	response_types.WriteOkJsonBody(w, UserAccountResponseData{User: userAccount(user)})
+}
+
+type SetRoleRequestBody struct {
+	Role string `json:"role" example:"support_readonly" enums:"customer,support_readonly,operator,admin"`
+}
+
+// SetRole godoc
+// @Summary      Set role of a user.
+// @Description  Set role of a user. Role admin only, not on own account.
+// @Tags         admin
+// @Security     BasicAuth
+// @Accept       application/json
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        username   path      string  true  "username"
+// @Param        request body SetRoleRequestBody true "Set Role Request Body"
+// @Success      200  {object}  UserAccountResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /admin/user/{username}/role [put]
+func (h Handlers) SetRole(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	form := &SetRoleRequestBody{}
+	if err := json.NewDecoder(r.Body).Decode(form); err != nil {
+		response_types.WriteProblem(w, r, utils.WrapError(utils.ErrorCodeBadRequest, err))
+		return
+	}
+
+	user, err := h.service.SetRole(r.Context(), principal, r.PathValue("username"), policy.Role(form.Role))
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	response_types.WriteOkJsonBody(w, UserAccountResponseData{User: userAccount(user)})
+}
+
+type AdjustmentRequestBody struct {
+	EntryType  string `json:"entry_type" example:"credit" enums:"credit,debit"`
+	Amount     string `json:"amount" example:"10.23"`
+	ReasonCode string `json:"reason_code" example:"chargeback" enums:"correction,chargeback,goodwill,fee_refund,fraud_recovery"`
+	Reason     string `json:"reason" example:"chargeback 1234"`
+	Nonce      int64  `json:"nonce" example:"1749286345000"`
+}
+
+type Ledger struct {
+	Id            int64     `json:"id" example:"12222214214"`
+	WalletId      int64     `json:"wallet_id" example:"1021"`
+	TransactionId int64     `json:"transaction_id" example:"1749286345000"`
+	EntryType     string    `json:"entry_type" example:"credit"`
+	Amount        string    `json:"amount" example:"40.1122"`
+	CreatedAt     time.Time `json:"created_at" example:"2025-06-09T02:02:31.213543+08:00"`
+	Balance       string    `json:"balance" example:"2.2324"`
+}
+
+type TransactionMetaData struct {
+	SourceWalletId *int64  `json:"source_wallet_id" example:"1021"`
+	Amount         *string `json:"amount" example:"40.1122"`
+	EntryType      *string `json:"entry_type" example:"credit"`
+	ReasonCode     *string `json:"reason_code,omitempty" example:"chargeback"`
+	Reason         *string `json:"reason" example:"chargeback 1234"`
+	CreatedBy      *string `json:"created_by,omitempty" example:"ada"`
+	ApprovedBy     *string `json:"approved_by,omitempty" example:"grace"`
+	RejectedBy     *string `json:"rejected_by,omitempty" example:"grace"`
+}
+
+type Transaction struct {
+	Ledgers []Ledger `json:"ledgers"`
+
+	Id          int64     `json:"id" example:"1"`
+	RequestorId int64     `json:"requestor_id" example:"1"`
+	Nonce       int64     `json:"nonce" example:"1749460653395"`
+	Status      string    `json:"status" example:"success" enums:"pending,pending_approval,success,rejected"`
+	Operation   string    `json:"operation" example:"adjustment"`
+	CreatedAt   time.Time `json:"created_at" example:"2025-06-09T02:02:31.213543+08:00"`
+
+	TransactionMetaData `json:"metadata"`
+}
+
+type AdjustmentResponseData struct {
+	Transaction `json:"transaction"`
+}
+
+type AdjustmentResponseBody = ResponseBody[AdjustmentResponseData]
+
+// Adjustment godoc
+// @Summary      Create a manual adjustment of any wallet, pending approval.
+// @Description  Credit or debit any wallet, frozen accounts included, with a reason code and reason. The adjustment is pending_approval without ledgers until approved by another operator. Roles operator and admin only, not on own wallets.
+// @Tags         admin
+// @Security     BasicAuth
+// @Accept       application/json
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        wallet_id   					path      string  true  "Wallet Id"
+// @Param        request body AdjustmentRequestBody true "Adjustment Request Body"
+// @Success      200  {object}  AdjustmentResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      409  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /admin/wallet/{wallet_id}/adjustment [post]
+func (h Handlers) Adjustment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	walletId, err := strconv.ParseInt(r.PathValue("wallet_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid wallet_id"))
+		return
+	}
+	form := &AdjustmentRequestBody{}
+	if err := json.NewDecoder(r.Body).Decode(form); err != nil {
+		response_types.WriteProblem(w, r, utils.WrapError(utils.ErrorCodeBadRequest, err))
+		return
+	}
+	amount, err := decimal.NewFromString(form.Amount)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.InvalidAmountError)
+		return
+	}
+
+	t, err := h.service.CreateAdjustment(ctx, principal, form.Nonce, walletId, form.EntryType, amount,
+		userrepo.AdjustmentReasonCode(form.ReasonCode), form.Reason)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	response_types.WriteOkJsonBody(w, AdjustmentResponseData{Transaction: transaction(t)})
+}
+
+// ApproveAdjustment godoc
+// @Summary      Approve an adjustment pending approval.
+// @Description  Posts the ledger of the adjustment and records the approver. Fails the adjustment if the wallet was closed, or a debit exceeds the balance. Roles operator and admin only, not by the maker of the adjustment nor on own wallets.
+// @Tags         admin
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        transaction_id   			path      string  true  "Transaction Id of the adjustment"
+// @Success      200  {object}  AdjustmentResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /admin/adjustment/{transaction_id}/approve [post]
+func (h Handlers) ApproveAdjustment(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("transaction_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid transaction_id"))
+		return
+	}
+
+	t, ledger, err := h.service.ApproveAdjustment(r.Context(), principal, id)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	response_types.WriteOkJsonBody(w, AdjustmentResponseData{Transaction: transaction(t, ledger)})
+}
+
+// RejectAdjustment godoc
+// @Summary      Reject an adjustment pending approval.
+// @Description  Sets the adjustment to rejected without ledgers and records the actor. The maker may reject own adjustments. Roles operator and admin only, not on own wallets.
+// @Tags         admin
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        transaction_id   			path      string  true  "Transaction Id of the adjustment"
+// @Success      200  {object}  AdjustmentResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /admin/adjustment/{transaction_id}/reject [post]
+func (h Handlers) RejectAdjustment(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("transaction_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid transaction_id"))
+		return
+	}
+
+	t, err := h.service.RejectAdjustment(r.Context(), principal, id)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	response_types.WriteOkJsonBody(w, AdjustmentResponseData{Transaction: transaction(t)})
+}
+
+// PendingAdjustments godoc
+// @Summary      Get adjustments pending approval, sorted by oldest.
+// @Description  Get adjustments created and neither approved nor rejected yet. Roles support_readonly, operator and admin only.
+// @Tags         admin
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        limit      query      int     false  "max number of adjustments, default 100, max 1000"
+// @Success      200  {object}  PendingTransactionsResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /admin/adjustments/pending [get]
+func (h Handlers) PendingAdjustments(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	var limit int
+	if _limit := r.URL.Query().Get("limit"); _limit != "" {
+		limit, err = strconv.Atoi(_limit)
+		if err != nil {
+			response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid_limit"))
+			return
+		}
+	}
+
+	transactions, err := h.service.PendingAdjustments(r.Context(), principal, limit)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	data := PendingTransactionsResponseData{Transactions: make([]Transaction, 0, len(transactions))}
+	for _, t := range transactions {
+		data.Transactions = append(data.Transactions, transaction(t))
+	}
+	response_types.WriteOkJsonBody(w, data)
+}
+
+type SetWalletStatusRequestBody struct {
+	Status string `json:"status" example:"frozen" enums:"active,frozen,debit_frozen,closed"`
+	Reason string `json:"reason" example:"suspected account takeover"`
+}
+
+type WalletResponseData struct {
+	Wallet Wallet `json:"wallet"`
+}
+
+type WalletResponseBody = ResponseBody[WalletResponseData]
+
+// SetWalletStatus godoc
+// @Summary      Freeze, unfreeze or close a wallet.
+// @Description  Transition a wallet to active, frozen (no credit nor debit), debit_frozen (credit only) or closed. Closing requires zero balance and is final. Roles operator and admin only, not on own wallets.
+// @Tags         admin
+// @Security     BasicAuth
+// @Accept       application/json
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        wallet_id   					path      string  true  "Wallet Id"
+// @Param        request body SetWalletStatusRequestBody true "Set Wallet Status Request Body"
+// @Success      200  {object}  WalletResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /admin/wallet/{wallet_id}/status [put]
+func (h Handlers) SetWalletStatus(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	walletId, err := strconv.ParseInt(r.PathValue("wallet_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid wallet_id"))
+		return
+	}
+	form := &SetWalletStatusRequestBody{}
+	if err := json.NewDecoder(r.Body).Decode(form); err != nil {
+		response_types.WriteProblem(w, r, utils.WrapError(utils.ErrorCodeBadRequest, err))
+		return
+	}
+
+	updated, err := h.service.SetWalletStatus(r.Context(), principal, walletId, userrepo.WalletStatus(form.Status), form.Reason)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	response_types.WriteOkJsonBody(w, WalletResponseData{Wallet: wallet(updated)})
+}
+
+type WalletStatusChange struct {
+	Id         int64     `json:"id" example:"1"`
+	WalletId   int64     `json:"wallet_id" example:"1021"`
+	FromStatus string    `json:"from_status" example:"active"`
+	ToStatus   string    `json:"to_status" example:"frozen"`
+	Reason     string    `json:"reason" example:"suspected account takeover"`
+	Actor      string    `json:"actor" example:"operator1"`
+	CreatedAt  time.Time `json:"created_at" example:"2025-06-09T02:02:31.213543+08:00"`
+}
+
+type WalletStatusHistoryResponseData struct {
+	Changes []WalletStatusChange `json:"changes"`
+}
+
+type WalletStatusHistoryResponseBody = ResponseBody[WalletStatusHistoryResponseData]
+
+// WalletStatusHistory godoc
+// @Summary      Get status history of a wallet sorted by newest.
+// @Description  Get status transitions of a wallet with reason and actor. Roles support_readonly, operator and admin only.
+// @Tags         admin
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        wallet_id   					path      string  true  "Wallet Id"
+// @Success      200  {object}  WalletStatusHistoryResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /admin/wallet/{wallet_id}/status/history [get]
+func (h Handlers) WalletStatusHistory(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	walletId, err := strconv.ParseInt(r.PathValue("wallet_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid wallet_id"))
+		return
+	}
+
+	changes, err := h.service.WalletStatusChanges(r.Context(), principal, walletId)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	data := WalletStatusHistoryResponseData{Changes: make([]WalletStatusChange, 0, len(changes))}
+	for _, c := range changes {
+		data.Changes = append(data.Changes, WalletStatusChange{
+			Id:         c.Id,
+			WalletId:   c.WalletId,
+			FromStatus: string(c.FromStatus),
+			ToStatus:   string(c.ToStatus),
+			Reason:     c.Reason,
+			Actor:      c.Actor,
+			CreatedAt:  c.CreatedAt,
+		})
+	}
+	response_types.WriteOkJsonBody(w, data)
+}
+
+type SetApprovalPolicyRequestBody struct {
+	Threshold         string   `json:"threshold" example:"10000"`
+	RequiredApprovals int      `json:"required_approvals" example:"2"`
+	TimeoutSeconds    int64    `json:"timeout_seconds" example:"86400"`
+	Approvers         []string `json:"approvers" example:"grace,linus,margaret"`
+}
+
+type ApprovalPolicy struct {
+	WalletId          int64     `json:"wallet_id" example:"1021"`
+	Threshold         string    `json:"threshold" example:"10000"`
+	RequiredApprovals int       `json:"required_approvals" example:"2"`
+	TimeoutSeconds    int64     `json:"timeout_seconds" example:"86400"`
+	Approvers         []string  `json:"approvers" example:"grace,linus,margaret"`
+	UpdatedBy         string    `json:"updated_by" example:"ada"`
+	UpdatedAt         time.Time `json:"updated_at" example:"2025-06-09T02:02:31.213543+08:00"`
+}
+
+type ApprovalPolicyResponseData struct {
+	ApprovalPolicy `json:"approval_policy"`
+}
+
+type ApprovalPolicyResponseBody = ResponseBody[ApprovalPolicyResponseData]
+
+// SetApprovalPolicy godoc
+// @Summary      Set the approval policy of transfers from a wallet.
+// @Description  Transfers from the wallet over threshold are then pending_approval until approved by required_approvals of the approvers, and expire after timeout_seconds, between 60 and 2592000. Transfers already pending keep the approvers and expiry they were created with. Role admin only, not on own wallets.
+// @Tags         admin
+// @Security     BasicAuth
+// @Accept       application/json
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        wallet_id   					path      string  true  "Wallet Id"
+// @Param        request body SetApprovalPolicyRequestBody true "Set Approval Policy Request Body"
+// @Success      200  {object}  ApprovalPolicyResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /admin/wallet/{wallet_id}/approval-policy [put]
+func (h Handlers) SetApprovalPolicy(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	walletId, err := strconv.ParseInt(r.PathValue("wallet_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid wallet_id"))
+		return
+	}
+	form := &SetApprovalPolicyRequestBody{}
+	if err := json.NewDecoder(r.Body).Decode(form); err != nil {
+		response_types.WriteProblem(w, r, utils.WrapError(utils.ErrorCodeBadRequest, err))
+		return
+	}
+	threshold, err := decimal.NewFromString(form.Threshold)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.InvalidAmountError)
+		return
+	}
+
+	p, err := h.service.SetApprovalPolicy(r.Context(), principal, userrepo.ApprovalPolicy{
+		WalletId:          walletId,
+		Threshold:         threshold,
+		RequiredApprovals: form.RequiredApprovals,
+		Timeout:           time.Duration(form.TimeoutSeconds) * time.Second,
+		Approvers:         form.Approvers,
+	})
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	response_types.WriteOkJsonBody(w, ApprovalPolicyResponseData{ApprovalPolicy: ApprovalPolicy{
+		WalletId:          p.WalletId,
+		Threshold:         p.Threshold.String(),
+		RequiredApprovals: p.RequiredApprovals,
+		TimeoutSeconds:    int64(p.Timeout / time.Second),
+		Approvers:         p.Approvers,
+		UpdatedBy:         p.UpdatedBy,
+		UpdatedAt:         p.UpdatedAt,
+	}})
+}
+
+type DeleteApprovalPolicyResponseData struct {
+	WalletId int64 `json:"wallet_id" example:"1021"`
+}
+
+type DeleteApprovalPolicyResponseBody = ResponseBody[DeleteApprovalPolicyResponseData]
+
+// DeleteApprovalPolicy godoc
+// @Summary      Delete the approval policy of transfers from a wallet.
+// @Description  Transfers from the wallet then need no approval. Transfers already pending approval are unaffected. Role admin only, not on own wallets.
+// @Tags         admin
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        wallet_id   					path      string  true  "Wallet Id"
+// @Success      200  {object}  DeleteApprovalPolicyResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /admin/wallet/{wallet_id}/approval-policy [delete]
+func (h Handlers) DeleteApprovalPolicy(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	walletId, err := strconv.ParseInt(r.PathValue("wallet_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid wallet_id"))
+		return
+	}
+
+	if err := h.service.DeleteApprovalPolicy(r.Context(), principal, walletId); err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	response_types.WriteOkJsonBody(w, DeleteApprovalPolicyResponseData{WalletId: walletId})
+}
+
+type PendingTransactionsResponseData struct {
+	Transactions []Transaction `json:"transactions"`
+}
+
+type PendingTransactionsResponseBody = ResponseBody[PendingTransactionsResponseData]
+
+// PendingTransactions godoc
+// @Summary      Get transactions left pending, sorted by oldest.
+// @Description  Get transactions pending for longer than older_than. Wallet operations complete within their request, pending ones were left by a crashed process and have no ledgers. Roles support_readonly, operator and admin only.
+// @Tags         admin
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        older_than query      string  false  "min age as a Go duration, default 1m"
+// @Param        limit      query      int     false  "max number of transactions, default 100, max 1000"
+// @Success      200  {object}  PendingTransactionsResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /admin/transactions/pending [get]
+func (h Handlers) PendingTransactions(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	query := r.URL.Query()
+	age := time.Minute
+	if _age := query.Get("older_than"); _age != "" {
+		age, err = time.ParseDuration(_age)
+		if err != nil {
+			response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid older_than"))
+			return
+		}
+	}
+	var limit int
+	if _limit := query.Get("limit"); _limit != "" {
+		limit, err = strconv.Atoi(_limit)
+		if err != nil {
+			response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid_limit"))
+			return
+		}
+	}
+
+	transactions, err := h.service.PendingTransactions(r.Context(), principal, age, limit)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	data := PendingTransactionsResponseData{Transactions: make([]Transaction, 0, len(transactions))}
+	for _, t := range transactions {
+		data.Transactions = append(data.Transactions, transaction(t))
+	}
+	response_types.WriteOkJsonBody(w, data)
+}
+
+type WalletDiscrepancy struct {
+	WalletId  int64  `json:"wallet_id" example:"1021"`
+	Currency  string `json:"currency" example:"USD"`
+	Balance   string `json:"balance" example:"10.5"`
+	LedgerSum string `json:"ledger_sum" example:"10"`
+	// LastLedgerBalance is null for wallets without ledgers.
+	LastLedgerBalance *string `json:"last_ledger_balance" example:"10"`
+	Ledgers           int64   `json:"ledgers" example:"3"`
+}
+
+type TransactionDiscrepancy struct {
+	Id        int64  `json:"id" example:"1"`
+	Status    string `json:"status" example:"success"`
+	Operation string `json:"operation" example:"transfer"`
+	Ledgers   int64  `json:"ledgers" example:"1"`
+	Credits   string `json:"credits" example:"0"`
+	Debits    string `json:"debits" example:"10"`
+}
+
+type ReconciliationResponseData struct {
+	At                       time.Time                `json:"at" example:"2025-06-09T02:02:31.213543+08:00"`
+	Wallets                  int64                    `json:"wallets" example:"120"`
+	Transactions             int64                    `json:"transactions" example:"5400"`
+	WalletDiscrepancies      []WalletDiscrepancy      `json:"wallet_discrepancies"`
+	TransactionDiscrepancies []TransactionDiscrepancy `json:"transaction_discrepancies"`
+}
+
+type ReconciliationResponseBody = ResponseBody[ReconciliationResponseData]
+
+// Reconciliation godoc
+// @Summary      Reconcile ledgers with wallet balances and transactions.
+// @Description  Checks in one snapshot that each wallet balance equals the sum of its ledgers and the balance after its newest ledger, that successful transactions have one ledger, two for transfers with equal credit and debit, and that other transactions have none. Reads all ledgers. Roles support_readonly, operator and admin only.
+// @Tags         admin
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Success      200  {object}  ReconciliationResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /admin/reconciliation [get]
+func (h Handlers) Reconciliation(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	reconciliation, err := h.service.Reconcile(r.Context(), principal)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	data := ReconciliationResponseData{
+		At:                       reconciliation.At,
+		Wallets:                  reconciliation.Wallets,
+		Transactions:             reconciliation.Transactions,
+		WalletDiscrepancies:      make([]WalletDiscrepancy, 0, len(reconciliation.WalletDiscrepancies)),
+		TransactionDiscrepancies: make([]TransactionDiscrepancy, 0, len(reconciliation.TransactionDiscrepancies)),
+	}
+	for _, d := range reconciliation.WalletDiscrepancies {
+		var lastLedgerBalance *string
+		if d.LastLedgerBalance != nil {
+			_balance := d.LastLedgerBalance.String()
+			lastLedgerBalance = &_balance
+		}
+		data.WalletDiscrepancies = append(data.WalletDiscrepancies, WalletDiscrepancy{
+			WalletId:          d.WalletId,
+			Currency:          d.Currency,
+			Balance:           d.Balance.String(),
+			LedgerSum:         d.LedgerSum.String(),
+			LastLedgerBalance: lastLedgerBalance,
+			Ledgers:           d.Ledgers,
+		})
+	}
+	for _, d := range reconciliation.TransactionDiscrepancies {
+		data.TransactionDiscrepancies = append(data.TransactionDiscrepancies, TransactionDiscrepancy{
+			Id:        d.Id,
+			Status:    d.Status,
+			Operation: d.Operation,
+			Ledgers:   d.Ledgers,
+			Credits:   d.Credits.String(),
+			Debits:    d.Debits.String(),
+		})
+	}
+	response_types.WriteOkJsonBody(w, data)
+}
+
+func transaction(t userrepo.Transaction, ledgers ...userrepo.Ledger) Transaction {
+	var amount *string
+	if t.MetaData.Amount != nil {
+		_amount := t.MetaData.Amount.String()
+		amount = &_amount
+	}
+	transaction := Transaction{
+		Ledgers:     make([]Ledger, 0, len(ledgers)),
+		Id:          t.Id,
+		RequestorId: t.RequestorId,
+		Nonce:       t.Nonce,
+		Status:      t.Status,
+		Operation:   t.Operation,
+		CreatedAt:   t.CreatedAt,
+		TransactionMetaData: TransactionMetaData{
+			SourceWalletId: t.MetaData.SourceWalletId,
+			Amount:         amount,
+			EntryType:      t.MetaData.EntryType,
+			ReasonCode:     t.MetaData.ReasonCode,
+			Reason:         t.MetaData.Reason,
+			CreatedBy:      t.MetaData.CreatedBy,
+			ApprovedBy:     t.MetaData.ApprovedBy,
+			RejectedBy:     t.MetaData.RejectedBy,
+		},
+	}
+	for _, l := range ledgers {
+		transaction.Ledgers = append(transaction.Ledgers, Ledger{
+			Id:            l.Id,
+			WalletId:      l.WalletId,
+			TransactionId: l.TransactionId,
+			EntryType:     l.EntryType,
+			Amount:        l.Amount.String(),
+			CreatedAt:     l.CreatedAt,
+			Balance:       l.Balance.String(),
+		})
+	}
+	return transaction
+}
+
+func wallet(w userrepo.Wallet) Wallet {
+	return Wallet{
+		Id:            w.Id,
+		UserAccountId: w.UserAccountId,
+		Name:          w.Name,
+		Currency:      w.Currency,
+		Balance:       w.Balance.String(),
+		Status:        string(w.Status),
+	}
+}
+
+func userAccount(user userrepo.User) UserAccount {
+	return UserAccount{
+		Id:       user.Id,
+		Username: user.Username,
+		Role:     user.Role,
+		Frozen:   user.Frozen,
+		Status:   user.Status,
+		KycTier:  user.KycTier,
+	}
+}
+
+// Types
+
+var _ = response_types.ResponseBody[struct{}](ResponseBody[struct{}]{})
+
+type ResponseBody[T any] struct {
+	Data  T       `json:"data"`
+	Error *string `json:"error" example:"" extensions:"x-nullable"`
+}
+
+type ProblemResponseBody = response_types.Problem
@@ -0,0 +1,255 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/controllers/httplog"
+	"github.com/cryptonlx/crypto/src/controllers/middlewares"
+	"github.com/cryptonlx/crypto/src/controllers/response_types"
+	auditrepo "github.com/cryptonlx/crypto/src/repositories/audit"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+	auditservice "github.com/cryptonlx/crypto/src/services/audit"
+)
+
+// MaxPayloadBytes
+// Request bodies are read into memory to be hashed, larger ones are rejected with payload_too_large.
+const MaxPayloadBytes = 1 << 20
+
+type Handlers struct {
+	service *auditservice.Service
+}
+
+func NewHandlers(service *auditservice.Service) *Handlers {
+	return &Handlers{
+		service: service,
+	}
+}
+
+// Middleware
+// Records every request passing through it to the audit log regardless of outcome.
+// Failure to record is logged and does not affect the response. Bodies over MaxPayloadBytes are not handled and
+// recorded with the hash of their first MaxPayloadBytes.
+func (h Handlers) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxPayloadBytes))
+		payloadHash := sha256.Sum256(body)
+
+		var maxBytesErr *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesErr):
+			response_types.WriteProblem(recorder, r, utils.PayloadTooLargeErrorF(MaxPayloadBytes))
+		case err != nil:
+			response_types.WriteProblem(recorder, r, utils.WrapError(utils.ErrorCodeBadRequest, err))
+		default:
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(recorder, r)
+		}
+
+		event := auditrepo.Event{
+			Ip:          remoteIp(r),
+			Method:      r.Method,
+			Route:       r.Pattern,
+			Path:        r.URL.Path,
+			PayloadHash: hex.EncodeToString(payloadHash[:]),
+			HttpStatus:  recorder.status,
+		}
+		if principal, err := middlewares.PrincipalFromRequest(r); err == nil {
+			event.Principal = &principal
+		}
+		if walletId, err := strconv.ParseInt(r.PathValue("wallet_id"), 10, 64); err == nil {
+			event.WalletId = &walletId
+		}
+		if recorder.err != nil {
+			errorCode := string(utils.ErrorCodeOf(recorder.err))
+			event.ErrorCode = &errorCode
+		}
+		if traceId, ok := r.Context().Value("TRACE_ID").(string); ok {
+			event.TraceId = &traceId
+		}
+
+		_, err = h.service.Record(context.WithoutCancel(r.Context()), event)
+		if err != nil {
+			log.Printf("%s [audit record failed] %v\n", httplog.SPrintHttpRequestPrefix(r), err)
+		}
+	})
+}
+
+type AuditEvent struct {
+	Id          int64     `json:"id" example:"1"`
+	Principal   *string   `json:"principal" example:"user1"`
+	Ip          string    `json:"ip" example:"127.0.0.1"`
+	Method      string    `json:"method" example:"POST"`
+	Route       string    `json:"route" example:"POST /wallet/{wallet_id}/withdrawal"`
+	Path        string    `json:"path" example:"/wallet/1/withdrawal"`
+	WalletId    *int64    `json:"wallet_id" example:"1"`
+	PayloadHash string    `json:"payload_hash" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	Outcome     string    `json:"outcome" example:"rejected"`
+	HttpStatus  int       `json:"http_status" example:"400"`
+	ErrorCode   *string   `json:"error_code" example:"insufficient_funds"`
+	TraceId     *string   `json:"trace_id" example:"5d2e3a4c-7f6b-4a51-9d0e-2f1c8b7a6e5d"`
+	CreatedAt   time.Time `json:"created_at" example:"2025-06-09T02:02:31.213543+08:00"`
+}
+
+type EventsResponseData struct {
+	Events []AuditEvent `json:"events"`
+}
+
+type EventsResponseBody = ResponseBody[EventsResponseData]
+
+// Events godoc
+// @Summary      Get audit events of wallet operation attempts sorted by newest.
+// @Description  Get audit events of wallet operation attempts sorted by newest. Roles support_readonly, operator and admin only.
+// @Tags         admin
+// @Security     BasicAuth
+// @Accept       application/json
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        username   query      string  false  "principal of request"
+// @Param        wallet_id  query      int     false  "wallet id in request path"
+// @Param        from       query      string  false  "RFC3339 timestamp, inclusive"
+// @Param        to         query      string  false  "RFC3339 timestamp, exclusive"
+// @Param        limit      query      int     false  "max number of events, default 100, max 1000"
+// @Success      200  {object}  EventsResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /admin/audit [get]
+func (h Handlers) Events(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	filter, err := filterFromQuery(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	events, err := h.service.Events(r.Context(), principal, filter)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	auditEvents := make([]AuditEvent, 0, len(events))
+	for _, e := range events {
+		auditEvents = append(auditEvents, AuditEvent{
+			Id:          e.Id,
+			Principal:   e.Principal,
+			Ip:          e.Ip,
+			Method:      e.Method,
+			Route:       e.Route,
+			Path:        e.Path,
+			WalletId:    e.WalletId,
+			PayloadHash: e.PayloadHash,
+			Outcome:     string(e.Outcome),
+			HttpStatus:  e.HttpStatus,
+			ErrorCode:   e.ErrorCode,
+			TraceId:     e.TraceId,
+			CreatedAt:   e.CreatedAt,
+		})
+	}
+	response_types.WriteOkJsonBody(w, EventsResponseData{
+		Events: auditEvents,
+	})
+}
+
+func filterFromQuery(r *http.Request) (auditrepo.Filter, error) {
+	query := r.URL.Query()
+
+	var filter auditrepo.Filter
+	if username := query.Get("username"); username != "" {
+		filter.Principal = &username
+	}
+	if _walletId := query.Get("wallet_id"); _walletId != "" {
+		walletId, err := strconv.ParseInt(_walletId, 10, 64)
+		if err != nil {
+			return auditrepo.Filter{}, utils.BadRequestErrorF("invalid_wallet_id")
+		}
+		filter.WalletId = &walletId
+	}
+	if _from := query.Get("from"); _from != "" {
+		from, err := time.Parse(time.RFC3339, _from)
+		if err != nil {
+			return auditrepo.Filter{}, utils.BadRequestErrorF("invalid_from")
+		}
+		filter.From = &from
+	}
+	if _to := query.Get("to"); _to != "" {
+		to, err := time.Parse(time.RFC3339, _to)
+		if err != nil {
+			return auditrepo.Filter{}, utils.BadRequestErrorF("invalid_to")
+		}
+		filter.To = &to
+	}
+	if _limit := query.Get("limit"); _limit != "" {
+		limit, err := strconv.Atoi(_limit)
+		if err != nil {
+			return auditrepo.Filter{}, utils.BadRequestErrorF("invalid_limit")
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+func remoteIp(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+type responseRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	err         error
+}
+
+func (rr *responseRecorder) WriteHeader(status int) {
+	if !rr.wroteHeader {
+		rr.status = status
+		rr.wroteHeader = true
+	}
+	rr.ResponseWriter.WriteHeader(status)
+}
+
+func (rr *responseRecorder) Write(b []byte) (int, error) {
+	rr.wroteHeader = true
+	return rr.ResponseWriter.Write(b)
+}
+
+func (rr *responseRecorder) RecordError(err error) {
+	rr.err = err
+}
+
+func (rr *responseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
+}
+
+// Types
+
+var _ = response_types.ResponseBody[struct{}](ResponseBody[struct{}]{})
+
+type ResponseBody[T any] struct {
+	Data  T       `json:"data"`
+	Error *string `json:"error" example:"" extensions:"x-nullable"`
+}
+
+type ProblemResponseBody = response_types.Problem
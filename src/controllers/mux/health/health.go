@@ -0,0 +1,161 @@
+package health
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/controllers/httplog"
+	"github.com/cryptonlx/crypto/src/controllers/response_types"
+	healthservice "github.com/cryptonlx/crypto/src/services/health"
+)
+
+// checkTimeout
+// Upper bound of a readiness probe so that a hung database fails the probe instead of the probe timing out.
+const checkTimeout = 2 * time.Second
+
+type Handlers struct {
+	service  *healthservice.Service
+	draining *atomic.Bool
+}
+
+func NewHandlers(service *healthservice.Service) *Handlers {
+	return &Handlers{
+		service:  service,
+		draining: &atomic.Bool{},
+	}
+}
+
+// SetDraining
+// Fails readiness so that traffic is routed away while in-flight requests are drained.
+func (h Handlers) SetDraining() {
+	h.draining.Store(true)
+}
+
+type AliveResponseData struct {
+	Status string `json:"status" example:"alive"`
+}
+
+type AliveResponseBody = ResponseBody[AliveResponseData]
+
+// Alive godoc
+// @Summary      Liveness of the process.
+// @Description  Liveness of the process. Does not check dependencies, a failing database does not warrant a restart.
+// @Tags         health
+// @Produce      application/json
+// @Success      200  {object}  AliveResponseBody
+// @Router       /healthz [get]
+func (h Handlers) Alive(w http.ResponseWriter, r *http.Request) {
+	response_types.WriteOkJsonBody(w, AliveResponseData{Status: "alive"})
+}
+
+type DatabaseCheck struct {
+	Status    string `json:"status" example:"up"`
+	LatencyMs int64  `json:"latency_ms" example:"1"`
+}
+
+type MigrationsCheck struct {
+	// Status is ahead when the schema is newer than the server expects, as during a rolling deploy. Ready still.
+	Status          string `json:"status" example:"up" enums:"up,ahead,down"`
+	Version         int64  `json:"version" example:"2"`
+	ExpectedVersion int64  `json:"expected_version" example:"2"`
+}
+
+type PoolCheck struct {
+	Status        string  `json:"status" example:"up"`
+	AcquiredConns int32   `json:"acquired_conns" example:"1"`
+	IdleConns     int32   `json:"idle_conns" example:"1"`
+	TotalConns    int32   `json:"total_conns" example:"2"`
+	MaxConns      int32   `json:"max_conns" example:"10"`
+	Saturation    float64 `json:"saturation" example:"0.1"`
+}
+
+type Checks struct {
+	Database   DatabaseCheck   `json:"database"`
+	Migrations MigrationsCheck `json:"migrations"`
+	Pool       PoolCheck       `json:"pool"`
+}
+
+type ReadyResponseData struct {
+	Status string  `json:"status" example:"ready" enums:"ready,not_ready,draining"`
+	Checks *Checks `json:"checks,omitempty"`
+}
+
+type ReadyResponseBody = ResponseBody[ReadyResponseData]
+
+// Ready godoc
+// @Summary      Readiness to serve traffic.
+// @Description  Readiness to serve traffic. Pings the database and checks the schema is migrated to at least the version the server expects. A newer schema is reported as migrations ahead, as during a rolling deploy, and does not fail readiness.
+// @Description  Pool saturation is reported but does not fail readiness. 503 while server is draining for shutdown.
+// @Tags         health
+// @Produce      application/json
+// @Success      200  {object}  ReadyResponseBody
+// @Failure      503  {object}  ReadyResponseBody
+// @Router       /readyz [get]
+func (h Handlers) Ready(w http.ResponseWriter, r *http.Request) {
+	if h.draining.Load() {
+		response_types.WriteJsonBody(w, http.StatusServiceUnavailable, ReadyResponseData{Status: "draining"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), checkTimeout)
+	defer cancel()
+	report := h.service.Check(ctx)
+
+	checks := Checks{
+		Database: DatabaseCheck{
+			Status:    upOrDown(report.Database.Up),
+			LatencyMs: report.Database.Latency.Milliseconds(),
+		},
+		Migrations: MigrationsCheck{
+			Status:          upOrDown(report.Migrations.Up),
+			Version:         report.Migrations.Version,
+			ExpectedVersion: report.Migrations.ExpectedVersion,
+		},
+		Pool: PoolCheck{
+			Status:        "up",
+			AcquiredConns: report.Pool.AcquiredConns,
+			IdleConns:     report.Pool.IdleConns,
+			TotalConns:    report.Pool.TotalConns,
+			MaxConns:      report.Pool.MaxConns,
+			Saturation:    report.Pool.Saturation,
+		},
+	}
+	if report.Migrations.Ahead {
+		checks.Migrations.Status = "ahead"
+	}
+	if report.Pool.Saturated {
+		checks.Pool.Status = "saturated"
+	}
+
+	if !report.Ready() {
+		// Dependency errors are logged rather than returned, probes are unauthenticated.
+		if report.Database.Err != nil {
+			log.Printf("%s [readiness database err] %v\n", httplog.SPrintHttpRequestPrefix(r), report.Database.Err)
+		}
+		if report.Migrations.Err != nil {
+			log.Printf("%s [readiness migrations err] %v\n", httplog.SPrintHttpRequestPrefix(r), report.Migrations.Err)
+		}
+		response_types.WriteJsonBody(w, http.StatusServiceUnavailable, ReadyResponseData{Status: "not_ready", Checks: &checks})
+		return
+	}
+	response_types.WriteOkJsonBody(w, ReadyResponseData{Status: "ready", Checks: &checks})
+}
+
+func upOrDown(up bool) string {
+	if up {
+		return "up"
+	}
+	return "down"
+}
+
+// Types
+
+var _ = response_types.ResponseBody[struct{}](ResponseBody[struct{}]{})
+
+type ResponseBody[T any] struct {
+	Data  T       `json:"data"`
+	Error *string `json:"error" example:"" extensions:"x-nullable"`
+}
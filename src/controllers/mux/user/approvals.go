@@ -0,0 +1,210 @@
+package user
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/controllers/middlewares"
+	"github.com/cryptonlx/crypto/src/controllers/response_types"
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+)
+
+type Approval struct {
+	Approver  string    `json:"approver" example:"grace"`
+	Decision  string    `json:"decision" example:"approved" enums:"approved,rejected"`
+	CreatedAt time.Time `json:"created_at" example:"2025-06-09T02:02:31.213543+08:00"`
+}
+
+type ApprovalResponseData struct {
+	Transaction `json:"transaction"`
+	Approvals   []Approval `json:"approvals"`
+}
+
+type ApprovalResponseBody = ResponseBody[ApprovalResponseData]
+
+// ApproveTransfer godoc
+// @Summary      Approve a transfer pending approval.
+// @Description  Records the approval of one of the approvers of the transfer. Once approved by the required number of approvers, the transfer executes and succeeds or fails like any transfer. Not by the requestor of the transfer. Transfers past their expiry are expired instead, with error approval_expired.
+// @Tags         transaction
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        transaction_id   			path      string  true  "Transaction Id of the transfer"
+// @Success      200  {object}  ApprovalResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      409  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /transaction/{transaction_id}/approve [post]
+func (h Handlers) ApproveTransfer(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("transaction_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid transaction_id"))
+		return
+	}
+
+	t, approvals, ledgers, err := h.service.ApproveTransfer(r.Context(), principal, id)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	response_types.WriteOkJsonBody(w, approvalResponseData(t, approvals, ledgers...))
+}
+
+// RejectTransfer godoc
+// @Summary      Reject a transfer pending approval.
+// @Description  Sets the transfer to rejected without ledgers. By one of the approvers of the transfer, or by its requestor to cancel it.
+// @Tags         transaction
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        transaction_id   			path      string  true  "Transaction Id of the transfer"
+// @Success      200  {object}  ApprovalResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      409  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /transaction/{transaction_id}/reject [post]
+func (h Handlers) RejectTransfer(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("transaction_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid transaction_id"))
+		return
+	}
+
+	t, approvals, err := h.service.RejectTransfer(r.Context(), principal, id)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	response_types.WriteOkJsonBody(w, approvalResponseData(t, approvals))
+}
+
+func approvalResponseData(t userrepo.Transaction, approvals []userrepo.Approval, ledgers ...userrepo.Ledger) ApprovalResponseData {
+	data := ApprovalResponseData{Transaction: transaction(t, ledgers...), Approvals: make([]Approval, 0, len(approvals))}
+	for _, a := range approvals {
+		data.Approvals = append(data.Approvals, Approval{
+			Approver:  a.Approver,
+			Decision:  a.Decision,
+			CreatedAt: a.CreatedAt,
+		})
+	}
+	return data
+}
+
+// PendingApprovals godoc
+// @Summary      Get transfers awaiting the decision of user, sorted by oldest.
+// @Description  Get transfers pending approval, not expired, where user is an approver without a decision yet. Owner or roles support_readonly, operator and admin only, otherwise 404.
+// @Tags         user
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        username   					path      string  true  "username"
+// @Success      200  {object}  TransactionsResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /user/{username}/approvals [get]
+func (h Handlers) PendingApprovals(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	transactions, err := h.service.PendingApprovals(r.Context(), principal, r.PathValue("username"))
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	data := TransactionsResponseData{Transactions: make([]Transaction, 0, len(transactions))}
+	for _, t := range transactions {
+		data.Transactions = append(data.Transactions, transaction(t))
+	}
+	response_types.WriteOkJsonBody(w, data)
+}
+
+type ApprovalPolicy struct {
+	WalletId          int64     `json:"wallet_id" example:"1021"`
+	Threshold         string    `json:"threshold" example:"10000"`
+	RequiredApprovals int       `json:"required_approvals" example:"2"`
+	TimeoutSeconds    int64     `json:"timeout_seconds" example:"86400"`
+	Approvers         []string  `json:"approvers" example:"grace,linus,margaret"`
+	UpdatedBy         string    `json:"updated_by" example:"ada"`
+	UpdatedAt         time.Time `json:"updated_at" example:"2025-06-09T02:02:31.213543+08:00"`
+}
+
+type ApprovalPolicyResponseData struct {
+	ApprovalPolicy `json:"approval_policy"`
+}
+
+type ApprovalPolicyResponseBody = ResponseBody[ApprovalPolicyResponseData]
+
+// ApprovalPolicy godoc
+// @Summary      Get the approval policy of transfers from the wallet.
+// @Description  Transfers over threshold are pending approval until approved by required_approvals of the approvers, and expire after timeout_seconds. 404 if transfers need no approval. Owner or roles support_readonly, operator and admin only, otherwise 404.
+// @Tags         wallet
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        wallet_id   					path      string  true  "Wallet Id"
+// @Success      200  {object}  ApprovalPolicyResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /wallet/{wallet_id}/approval-policy [get]
+func (h Handlers) ApprovalPolicy(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	walletId, err := strconv.ParseInt(r.PathValue("wallet_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid wallet_id"))
+		return
+	}
+
+	p, err := h.service.ApprovalPolicy(r.Context(), principal, walletId)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	response_types.WriteOkJsonBody(w, ApprovalPolicyResponseData{ApprovalPolicy: approvalPolicy(p)})
+}
+
+func approvalPolicy(p userrepo.ApprovalPolicy) ApprovalPolicy {
+	return ApprovalPolicy{
+		WalletId:          p.WalletId,
+		Threshold:         p.Threshold.String(),
+		RequiredApprovals: p.RequiredApprovals,
+		TimeoutSeconds:    int64(p.Timeout / time.Second),
+		Approvers:         p.Approvers,
+		UpdatedBy:         p.UpdatedBy,
+		UpdatedAt:         p.UpdatedAt,
+	}
+}
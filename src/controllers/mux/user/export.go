@@ -0,0 +1,238 @@
+package user
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/controllers/httplog"
+	"github.com/cryptonlx/crypto/src/controllers/response_types"
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	ContentTypeCsv    = "text/csv"
+	ContentTypeNdjson = "application/x-ndjson"
+
+	// exportFlushRows
+	// Rows buffered before flushing to the client.
+	exportFlushRows = 100
+	// exportWriteTimeout
+	// Extended on every flush so that exports are not cut by the server write timeout while rows keep coming.
+	exportWriteTimeout = 30 * time.Second
+	// exportScale
+	// Scale of amounts and balances in ledgers, numeric(20, 6).
+	exportScale = 6
+)
+
+// ExportColumns
+// CSV header and NDJSON keys, in order. Columns are only ever appended.
+var ExportColumns = []string{
+	"transaction_id", "transaction_created_at", "requestor_id", "nonce", "operation", "status",
+	"ledger_id", "wallet_id", "entry_type", "amount", "balance", "ledger_created_at",
+}
+
+// ExportRow
+// One row per ledger of the user's wallets. Ledger fields are null for transactions without them.
+type ExportRow struct {
+	TransactionId        int64   `json:"transaction_id"`
+	TransactionCreatedAt string  `json:"transaction_created_at"`
+	RequestorId          int64   `json:"requestor_id"`
+	Nonce                int64   `json:"nonce"`
+	Operation            string  `json:"operation"`
+	Status               string  `json:"status"`
+	LedgerId             *int64  `json:"ledger_id"`
+	WalletId             *int64  `json:"wallet_id"`
+	EntryType            *string `json:"entry_type"`
+	Amount               *string `json:"amount"`
+	Balance              *string `json:"balance"`
+	LedgerCreatedAt      *string `json:"ledger_created_at"`
+}
+
+func exportRow(row userrepo.TransactionLedgerRow) ExportRow {
+	t := row.Transaction
+	e := ExportRow{
+		TransactionId:        t.Id,
+		TransactionCreatedAt: exportTime(t.CreatedAt),
+		RequestorId:          t.RequestorId,
+		Nonce:                t.Nonce,
+		Operation:            t.Operation,
+		Status:               t.Status,
+	}
+	if l := row.Ledger; l != nil {
+		amount, balance, createdAt := exportDecimal(l.Amount), exportDecimal(l.Balance), exportTime(l.CreatedAt)
+		e.LedgerId, e.WalletId, e.EntryType = &l.Id, &l.WalletId, &l.EntryType
+		e.Amount, e.Balance, e.LedgerCreatedAt = &amount, &balance, &createdAt
+	}
+	return e
+}
+
+// record
+// CSV record in the order of ExportColumns, empty for null.
+func (e ExportRow) record() []string {
+	optional := func(s *string) string {
+		if s == nil {
+			return ""
+		}
+		return *s
+	}
+	optionalInt := func(i *int64) string {
+		if i == nil {
+			return ""
+		}
+		return strconv.FormatInt(*i, 10)
+	}
+	return []string{
+		strconv.FormatInt(e.TransactionId, 10), e.TransactionCreatedAt, strconv.FormatInt(e.RequestorId, 10),
+		strconv.FormatInt(e.Nonce, 10), e.Operation, e.Status,
+		optionalInt(e.LedgerId), optionalInt(e.WalletId), optional(e.EntryType), optional(e.Amount), optional(e.Balance),
+		optional(e.LedgerCreatedAt),
+	}
+}
+
+// exportDecimal
+// Fixed point with the scale of the column, never in exponent notation, i.e. 10.500000.
+func exportDecimal(d decimal.Decimal) string {
+	return d.StringFixed(exportScale)
+}
+
+func exportTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+// exportContentType
+// ContentTypeCsv or ContentTypeNdjson if accepted by the request, empty for JSON.
+func exportContentType(r *http.Request) string {
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		switch mediaType {
+		case ContentTypeCsv:
+			return ContentTypeCsv
+		case ContentTypeNdjson, "application/ndjson":
+			return ContentTypeNdjson
+		}
+	}
+	return ""
+}
+
+// exportEncoder
+// Writes rows of one content type.
+type exportEncoder interface {
+	header() error
+	row(ExportRow) error
+	flush() error
+}
+
+type csvEncoder struct {
+	w *csv.Writer
+}
+
+func (e csvEncoder) header() error {
+	return e.w.Write(ExportColumns)
+}
+
+func (e csvEncoder) row(row ExportRow) error {
+	return e.w.Write(row.record())
+}
+
+func (e csvEncoder) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+type ndjsonEncoder struct {
+	w *json.Encoder
+}
+
+func (e ndjsonEncoder) header() error {
+	return nil
+}
+
+func (e ndjsonEncoder) row(row ExportRow) error {
+	return e.w.Encode(row)
+}
+
+func (e ndjsonEncoder) flush() error {
+	return nil
+}
+
+func newExportEncoder(contentType string, w io.Writer) exportEncoder {
+	if contentType == ContentTypeCsv {
+		return csvEncoder{w: csv.NewWriter(w)}
+	}
+	return ndjsonEncoder{w: json.NewEncoder(w)}
+}
+
+// exportTransactions
+// Streams transactions as rows of contentType. Headers are written with the first row, so that authorization and
+// lookup errors are still problem responses. Errors after that abort the connection, the client sees a truncated body
+// rather than a complete looking export.
+func (h Handlers) exportTransactions(w http.ResponseWriter, r *http.Request, principal string, username string, contentType string) {
+	controller := http.NewResponseController(w)
+	encoder := newExportEncoder(contentType, w)
+	rows := 0
+	start := func() error {
+		w.Header().Set("Content-Type", contentType)
+		extension := ".ndjson"
+		if contentType == ContentTypeCsv {
+			extension = ".csv"
+		}
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": "transactions" + extension}))
+		w.WriteHeader(http.StatusOK)
+		return encoder.header()
+	}
+	flush := func() error {
+		if err := encoder.flush(); err != nil {
+			return err
+		}
+		controller.SetWriteDeadline(time.Now().Add(exportWriteTimeout))
+		if err := controller.Flush(); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+		return nil
+	}
+
+	err := h.service.StreamTransactions(r.Context(), principal, username, func(row userrepo.TransactionLedgerRow) error {
+		rows++
+		if rows == 1 {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		if err := encoder.row(exportRow(row)); err != nil {
+			return err
+		}
+		if rows%exportFlushRows == 0 {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil && rows == 0 {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	if err != nil {
+		log.Printf("%s [export aborted after %d rows] %v\n", httplog.SPrintHttpRequestPrefix(r), rows, err)
+		panic(http.ErrAbortHandler)
+	}
+	if rows == 0 {
+		if err := start(); err != nil {
+			panic(http.ErrAbortHandler)
+		}
+	}
+	if err := flush(); err != nil {
+		panic(http.ErrAbortHandler)
+	}
+}
@@ -0,0 +1,82 @@
+package user
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestExportContentType(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", ""},
+		{"application/json", ""},
+		{"*/*", ""},
+		{"text/csv", ContentTypeCsv},
+		{"text/csv; charset=utf-8", ContentTypeCsv},
+		{"application/json;q=0.9, application/x-ndjson", ContentTypeNdjson},
+		{"application/ndjson", ContentTypeNdjson},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/user/alice/transactions", nil)
+			r.Header.Set("Accept", tt.accept)
+			if got := exportContentType(r); got != tt.want {
+				t.Fatalf("exportContentType() want %q. got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExportEncoder(t *testing.T) {
+	createdAt := time.Date(2025, 6, 9, 2, 2, 31, 213543000, time.FixedZone("SGT", 8*60*60))
+	rows := []userrepo.TransactionLedgerRow{
+		{
+			Transaction: userrepo.Transaction{Id: 2, RequestorId: 1, Nonce: 1749460653395, Status: "success", Operation: "deposit", CreatedAt: createdAt},
+			Ledger: &userrepo.Ledger{Id: 3, WalletId: 4, EntryType: "credit", Amount: decimal.RequireFromString("1e-6"),
+				Balance: decimal.RequireFromString("1000.5"), CreatedAt: createdAt, TransactionId: 2},
+		},
+		{
+			Transaction: userrepo.Transaction{Id: 1, RequestorId: 1, Nonce: 1749460653394, Status: "success", Operation: "transfer", CreatedAt: createdAt},
+		},
+	}
+
+	tests := []struct {
+		contentType string
+		want        string
+	}{
+		{ContentTypeCsv, strings.Join(ExportColumns, ",") + "\n" +
+			"2,2025-06-08T18:02:31.213543Z,1,1749460653395,deposit,success,3,4,credit,0.000001,1000.500000,2025-06-08T18:02:31.213543Z\n" +
+			"1,2025-06-08T18:02:31.213543Z,1,1749460653394,transfer,success,,,,,,\n"},
+		{ContentTypeNdjson, `{"transaction_id":2,"transaction_created_at":"2025-06-08T18:02:31.213543Z","requestor_id":1,"nonce":1749460653395,"operation":"deposit","status":"success","ledger_id":3,"wallet_id":4,"entry_type":"credit","amount":"0.000001","balance":"1000.500000","ledger_created_at":"2025-06-08T18:02:31.213543Z"}` + "\n" +
+			`{"transaction_id":1,"transaction_created_at":"2025-06-08T18:02:31.213543Z","requestor_id":1,"nonce":1749460653394,"operation":"transfer","status":"success","ledger_id":null,"wallet_id":null,"entry_type":null,"amount":null,"balance":null,"ledger_created_at":null}` + "\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			var b bytes.Buffer
+			encoder := newExportEncoder(tt.contentType, &b)
+			if err := encoder.header(); err != nil {
+				t.Fatal(err)
+			}
+			for _, row := range rows {
+				if err := encoder.row(exportRow(row)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := encoder.flush(); err != nil {
+				t.Fatal(err)
+			}
+			if b.String() != tt.want {
+				t.Fatalf("export want\n%s\ngot\n%s", tt.want, b.String())
+			}
+		})
+	}
+}
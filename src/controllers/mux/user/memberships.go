@@ -0,0 +1,196 @@
+package user
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/controllers/middlewares"
+	"github.com/cryptonlx/crypto/src/controllers/response_types"
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+	"github.com/cryptonlx/crypto/src/services/policy"
+
+	"github.com/shopspring/decimal"
+)
+
+type Member struct {
+	WalletId int64  `json:"wallet_id" example:"1021"`
+	Username string `json:"username" example:"grace"`
+	Role     string `json:"role" example:"spender" enums:"owner,spender,viewer"`
+	// SpendLimit is the maximum total of debits per UTC day, spenders only.
+	SpendLimit *string   `json:"spend_limit,omitempty" example:"100"`
+	UpdatedBy  string    `json:"updated_by" example:"ada"`
+	UpdatedAt  time.Time `json:"updated_at" example:"2025-06-09T02:02:31.213543+08:00"`
+}
+
+type MembersResponseData struct {
+	Members []Member `json:"members"`
+}
+
+type MembersResponseBody = ResponseBody[MembersResponseData]
+
+// Members godoc
+// @Summary      Get the members of the wallet.
+// @Description  Owners operate the wallet and manage its members, spenders operate it with their debits up to spend_limit in total per UTC day, viewers read it. Members or roles support_readonly, operator and admin only, otherwise 404.
+// @Tags         wallet
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        wallet_id   					path      string  true  "Wallet Id"
+// @Success      200  {object}  MembersResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /wallet/{wallet_id}/members [get]
+func (h Handlers) Members(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	walletId, err := strconv.ParseInt(r.PathValue("wallet_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid wallet_id"))
+		return
+	}
+
+	members, err := h.service.Members(r.Context(), principal, walletId)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	data := MembersResponseData{Members: make([]Member, 0, len(members))}
+	for _, m := range members {
+		data.Members = append(data.Members, member(m))
+	}
+	response_types.WriteOkJsonBody(w, data)
+}
+
+type SetMemberRequestBody struct {
+	Role string `json:"role" example:"spender" enums:"owner,spender,viewer"`
+	// SpendLimit is required for spenders, not allowed otherwise.
+	SpendLimit *string `json:"spend_limit,omitempty" example:"100"`
+}
+
+type MemberResponseData struct {
+	Member `json:"member"`
+}
+
+type MemberResponseBody = ResponseBody[MemberResponseData]
+
+// SetMember godoc
+// @Summary      Add a member to the wallet or change its role.
+// @Description  Spenders require spend_limit, the maximum total of their debits of the wallet per UTC day (00:00 to 24:00 UTC), successful withdrawals and transfers out summed. The wallet keeps at least one owner. Owners of the wallet only.
+// @Tags         wallet
+// @Security     BasicAuth
+// @Accept       application/json
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        wallet_id   					path      string  true  "Wallet Id"
+// @Param        username   					path      string  true  "username of the member"
+// @Param        request body SetMemberRequestBody true "Set Member Request Body"
+// @Success      200  {object}  MemberResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /wallet/{wallet_id}/members/{username} [put]
+func (h Handlers) SetMember(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	walletId, err := strconv.ParseInt(r.PathValue("wallet_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid wallet_id"))
+		return
+	}
+	form := &SetMemberRequestBody{}
+	if err := json.NewDecoder(r.Body).Decode(form); err != nil {
+		response_types.WriteProblem(w, r, utils.WrapError(utils.ErrorCodeBadRequest, err))
+		return
+	}
+	var spendLimit *decimal.Decimal
+	if form.SpendLimit != nil {
+		limit, err := decimal.NewFromString(*form.SpendLimit)
+		if err != nil {
+			response_types.WriteProblem(w, r, utils.InvalidAmountError)
+			return
+		}
+		spendLimit = &limit
+	}
+
+	m, err := h.service.SetMember(r.Context(), principal, walletId, r.PathValue("username"), policy.MemberRole(form.Role), spendLimit)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	response_types.WriteOkJsonBody(w, MemberResponseData{Member: member(m)})
+}
+
+type RemoveMemberResponseData struct {
+	WalletId int64  `json:"wallet_id" example:"1021"`
+	Username string `json:"username" example:"grace"`
+}
+
+type RemoveMemberResponseBody = ResponseBody[RemoveMemberResponseData]
+
+// RemoveMember godoc
+// @Summary      Remove a member from the wallet.
+// @Description  Owners of the wallet, or the member leaving it. The last owner cannot be removed.
+// @Tags         wallet
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        wallet_id   					path      string  true  "Wallet Id"
+// @Param        username   					path      string  true  "username of the member"
+// @Success      200  {object}  RemoveMemberResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /wallet/{wallet_id}/members/{username} [delete]
+func (h Handlers) RemoveMember(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	walletId, err := strconv.ParseInt(r.PathValue("wallet_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid wallet_id"))
+		return
+	}
+
+	username := r.PathValue("username")
+	if err := h.service.RemoveMember(r.Context(), principal, walletId, username); err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	response_types.WriteOkJsonBody(w, RemoveMemberResponseData{WalletId: walletId, Username: username})
+}
+
+func member(m userrepo.Membership) Member {
+	var spendLimit *string
+	if m.SpendLimit != nil {
+		s := m.SpendLimit.String()
+		spendLimit = &s
+	}
+	return Member{
+		WalletId:   m.WalletId,
+		Username:   m.Username,
+		Role:       string(m.Role),
+		SpendLimit: spendLimit,
+		UpdatedBy:  m.UpdatedBy,
+		UpdatedAt:  m.UpdatedAt,
+	}
+}
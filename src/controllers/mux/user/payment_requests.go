@@ -0,0 +1,247 @@
+package user
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/controllers/middlewares"
+	"github.com/cryptonlx/crypto/src/controllers/response_types"
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+
+	"github.com/shopspring/decimal"
+)
+
+type PaymentRequest struct {
+	Id        int64  `json:"id" example:"3"`
+	Requester string `json:"requester" example:"ada"`
+	Payer     string `json:"payer" example:"grace"`
+	WalletId  int64  `json:"wallet_id" example:"1021"`
+	Amount    string `json:"amount" example:"12.5"`
+	Currency  string `json:"currency" example:"USD"`
+	Memo      string `json:"memo" example:"dinner"`
+	// Status is expired for pending requests past expires_at.
+	Status        string    `json:"status" example:"pending" enums:"pending,paid,cancelled,expired"`
+	ExpiresAt     time.Time `json:"expires_at" example:"2025-06-16T02:02:31.213543+08:00"`
+	TransactionId *int64    `json:"transaction_id" example:"21" extensions:"x-nullable"`
+	CreatedAt     time.Time `json:"created_at" example:"2025-06-09T02:02:31.213543+08:00"`
+	UpdatedAt     time.Time `json:"updated_at" example:"2025-06-09T02:02:31.213543+08:00"`
+}
+
+type PaymentRequestResponseData struct {
+	PaymentRequest `json:"payment_request"`
+}
+
+type PaymentRequestResponseBody = ResponseBody[PaymentRequestResponseData]
+
+type CreatePaymentRequestRequestBody struct {
+	Payer string `json:"payer" example:"grace"`
+	// WalletId is the wallet of the requestor to be paid into.
+	WalletId int64  `json:"wallet_id" example:"1021"`
+	Amount   string `json:"amount" example:"12.5"`
+	Currency string `json:"currency" example:"USD"`
+	Memo     string `json:"memo" example:"dinner"`
+	// ExpiresAt defaults to 7 days from now, at most 90 days from now.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" example:"2025-06-16T02:02:31.213543+08:00"`
+}
+
+// CreatePaymentRequest godoc
+// @Summary      Request a payment from another user.
+// @Description  Requests payer to pay amount into wallet_id, whose currency must be currency. The payer lists it in the incoming payment requests and pays it by transfer before expires_at. Owners and spenders of the wallet only.
+// @Tags         payment
+// @Security     BasicAuth
+// @Accept       application/json
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        request body CreatePaymentRequestRequestBody true "Create Payment Request Request Body"
+// @Success      200  {object}  PaymentRequestResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /payment-requests [post]
+func (h Handlers) CreatePaymentRequest(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	form := &CreatePaymentRequestRequestBody{}
+	if err := json.NewDecoder(r.Body).Decode(form); err != nil {
+		response_types.WriteProblem(w, r, utils.WrapError(utils.ErrorCodeBadRequest, err))
+		return
+	}
+	amount, err := decimal.NewFromString(form.Amount)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.InvalidAmountError)
+		return
+	}
+	var expiresAt time.Time
+	if form.ExpiresAt != nil {
+		expiresAt = *form.ExpiresAt
+	}
+
+	p, err := h.service.CreatePaymentRequest(r.Context(), principal, userrepo.PaymentRequest{
+		Payer:     form.Payer,
+		WalletId:  form.WalletId,
+		Amount:    amount,
+		Currency:  form.Currency,
+		Memo:      form.Memo,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	response_types.WriteOkJsonBody(w, PaymentRequestResponseData{PaymentRequest: paymentRequest(p)})
+}
+
+type PaymentRequestsResponseData struct {
+	PaymentRequests []PaymentRequest `json:"payment_requests"`
+}
+
+type PaymentRequestsResponseBody = ResponseBody[PaymentRequestsResponseData]
+
+// PaymentRequests godoc
+// @Summary      Get payment requests of user sorted by newest.
+// @Description  Incoming payment requests are to be paid by user, outgoing ones are requested by user. Owner or roles support_readonly, operator and admin only, otherwise 404.
+// @Tags         payment
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        username   					path      string  true  "username"
+// @Param        direction  query      string  false  "incoming (default) or outgoing"
+// @Param        status     query      string  false  "pending, paid, cancelled or expired, all if omitted"
+// @Success      200  {object}  PaymentRequestsResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /user/{username}/payment-requests [get]
+func (h Handlers) PaymentRequests(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	query := r.URL.Query()
+	requests, err := h.service.PaymentRequests(r.Context(), principal, r.PathValue("username"), query.Get("direction"), query.Get("status"))
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	data := PaymentRequestsResponseData{PaymentRequests: make([]PaymentRequest, 0, len(requests))}
+	for _, p := range requests {
+		data.PaymentRequests = append(data.PaymentRequests, paymentRequest(p))
+	}
+	response_types.WriteOkJsonBody(w, data)
+}
+
+type PayPaymentRequestRequestBody struct {
+	// WalletId is the wallet of the payer to transfer from.
+	WalletId int64 `json:"wallet_id" example:"1022"`
+	Nonce    int64 `json:"nonce" example:"1749286345000"`
+}
+
+// PayPaymentRequest godoc
+// @Summary      Pay a payment request.
+// @Description  Transfers the amount of the pending payment request from wallet_id to the wallet of the request, and sets the request paid in the same database transaction. The transaction metadata has the payment_request_id. Amounts over the threshold of the approval policy of wallet_id cannot be paid by request. Payer only, otherwise 403, or 404 for other users.
+// @Tags         payment
+// @Security     BasicAuth
+// @Accept       application/json
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        payment_request_id   		path      string  true  "Payment Request Id"
+// @Param        request body PayPaymentRequestRequestBody true "Pay Payment Request Request Body"
+// @Success      200  {object}  TransferResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      409  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /payment-requests/{payment_request_id}/pay [post]
+func (h Handlers) PayPaymentRequest(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("payment_request_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid payment_request_id"))
+		return
+	}
+	form := &PayPaymentRequestRequestBody{}
+	if err := json.NewDecoder(r.Body).Decode(form); err != nil {
+		response_types.WriteProblem(w, r, utils.WrapError(utils.ErrorCodeBadRequest, err))
+		return
+	}
+
+	t, ledgers, err := h.service.PayPaymentRequest(r.Context(), principal, form.Nonce, id, form.WalletId)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	response_types.WriteOkJsonBody(w, TransferResponseData{Transaction: transaction(t, ledgers...)})
+}
+
+// CancelPaymentRequest godoc
+// @Summary      Cancel a payment request.
+// @Description  Cancels the pending payment request. Requester only, otherwise 403, or 404 for other users.
+// @Tags         payment
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        payment_request_id   		path      string  true  "Payment Request Id"
+// @Success      200  {object}  PaymentRequestResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /payment-requests/{payment_request_id}/cancel [post]
+func (h Handlers) CancelPaymentRequest(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	id, err := strconv.ParseInt(r.PathValue("payment_request_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid payment_request_id"))
+		return
+	}
+
+	p, err := h.service.CancelPaymentRequest(r.Context(), principal, id)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	response_types.WriteOkJsonBody(w, PaymentRequestResponseData{PaymentRequest: paymentRequest(p)})
+}
+
+func paymentRequest(p userrepo.PaymentRequest) PaymentRequest {
+	return PaymentRequest{
+		Id:            p.Id,
+		Requester:     p.Requester,
+		Payer:         p.Payer,
+		WalletId:      p.WalletId,
+		Amount:        p.Amount.String(),
+		Currency:      p.Currency,
+		Memo:          p.Memo,
+		Status:        p.Status,
+		ExpiresAt:     p.ExpiresAt,
+		TransactionId: p.TransactionId,
+		CreatedAt:     p.CreatedAt,
+		UpdatedAt:     p.UpdatedAt,
+	}
+}
@@ -1,15 +1,15 @@
 package user
 
 import (
-	"encoding/base64"
 	"encoding/json"
-	"errors"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
+	"github.com/cryptonlx/crypto/src/controllers/middlewares"
 	"github.com/cryptonlx/crypto/src/controllers/response_types"
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
 	userservice "github.com/cryptonlx/crypto/src/services/user"
 
 	"github.com/shopspring/decimal"
@@ -33,33 +33,62 @@ type User struct {
 type Wallet struct {
 	Id            int64  `json:"id" example:"1"`
 	UserAccountId int64  `json:"user_account_id" example:"1"`
+	Name          string `json:"name" example:"main"`
 	Currency      string `json:"currency" example:"USD"`
 	Balance       string `json:"balance" example:"10.000123"`
+	Status        string `json:"status" example:"active" enums:"active,frozen,debit_frozen,closed"`
 }
 
 type GetWalletsResponseData struct {
 	User    User     `json:"user"`
 	Wallets []Wallet `json:"wallets"`
+	// At is set for balances as of a past instant.
+	At *time.Time `json:"at,omitempty" example:"2025-06-30T23:59:59Z"`
 }
 
 type GetWalletsResponseBody = ResponseBody[GetWalletsResponseData]
 
 // Wallets godoc
 // @Summary      Get balances of user's wallets.
-// @Description  Get balances of user's wallets.
+// @Description  Get balances of user's wallets. Owner or roles support_readonly, operator and admin only, otherwise 404.
+// @Description  With at, balances as of that instant from ledger balance snapshots. Statuses are current.
 // @Tags         user
+// @Security     BasicAuth
 // @Accept       application/json
-// @Produce      application/json
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
 // @Param        user_id   					path      string  true  "username"
+// @Param        at         query      string  false  "RFC3339 timestamp, inclusive, not in the future"
 // @Success      200  {object}  GetWalletsResponseBody
-// @Failure      500  {object}  ErrorResponseBody500
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
 // @Router       /user/{username}/wallets [get]
 func (h Handlers) Wallets(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
 	userName := r.PathValue("username")
+	at, err := queryTime(r, "at")
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
 
-	walletBalances, err := h.service.GetUserWalletBalanceByUserName(r.Context(), userName)
+	var walletBalances userrepo.UserWallets
+	var atP *time.Time
+	if at.IsZero() {
+		walletBalances, err = h.service.GetUserWalletBalanceByUserName(r.Context(), principal, userName)
+	} else {
+		walletBalances, err = h.service.UserWalletsAt(r.Context(), principal, userName, at)
+		atP = &at
+	}
 	if err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, err)
+		response_types.WriteProblem(w, r, err)
 		return
 	}
 
@@ -68,17 +97,204 @@ func (h Handlers) Wallets(w http.ResponseWriter, r *http.Request) {
 		wallets = append(wallets, Wallet{
 			Id:            wallet.Id,
 			UserAccountId: wallet.UserAccountId,
+			Name:          wallet.Name,
 			Currency:      wallet.Currency,
 			Balance:       wallet.Balance.String(),
+			Status:        string(wallet.Status),
 		})
 	}
 
 	response_types.WriteOkJsonBody(w, GetWalletsResponseData{
-		User:    User(walletBalances.User),
+		User:    User{Id: walletBalances.User.Id, Username: walletBalances.User.Username},
 		Wallets: wallets,
+		At:      atP,
+	})
+}
+
+type WalletBalanceResponseData struct {
+	Wallet Wallet    `json:"wallet"`
+	At     time.Time `json:"at" example:"2025-06-30T23:59:59Z"`
+}
+
+type WalletBalanceResponseBody = ResponseBody[WalletBalanceResponseData]
+
+// Balance godoc
+// @Summary      Get balance of wallet as of an instant.
+// @Description  Get balance of wallet as of an instant from ledger balance snapshots, now if at is omitted. Status is current. Owner or roles support_readonly, operator and admin only, otherwise 404.
+// @Tags         wallet
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        wallet_id  path       int     true   "Wallet Id"
+// @Param        at         query      string  false  "RFC3339 timestamp, inclusive, not in the future"
+// @Success      200  {object}  WalletBalanceResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /wallet/{wallet_id}/balance [get]
+func (h Handlers) Balance(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	walletId, err := strconv.ParseInt(r.PathValue("wallet_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid wallet_id"))
+		return
+	}
+	at, err := queryTime(r, "at")
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	wallet, err := h.service.WalletAt(r.Context(), principal, walletId, at)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	response_types.WriteOkJsonBody(w, WalletBalanceResponseData{
+		Wallet: Wallet{
+			Id:            wallet.Id,
+			UserAccountId: wallet.UserAccountId,
+			Name:          wallet.Name,
+			Currency:      wallet.Currency,
+			Balance:       wallet.Balance.String(),
+			Status:        string(wallet.Status),
+		},
+		At: at,
 	})
 }
 
+// queryTime
+// Optional RFC3339 query parameter, zero if omitted.
+func queryTime(r *http.Request, key string) (time.Time, error) {
+	v := r.URL.Query().Get(key)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, v)
+	if err != nil {
+		return time.Time{}, utils.BadRequestErrorF("invalid_%s", key)
+	}
+	return t, nil
+}
+
+type Profile struct {
+	Id          int64     `json:"id" example:"1"`
+	Username    string    `json:"username" example:"user1"`
+	DisplayName *string   `json:"display_name" example:"User One" extensions:"x-nullable"`
+	Email       *string   `json:"email" example:"user1@example.com" extensions:"x-nullable"`
+	Status      string    `json:"status" example:"verified" enums:"pending,verified,suspended"`
+	KycTier     int       `json:"kyc_tier" example:"1"`
+	CreatedAt   time.Time `json:"created_at" example:"2025-06-09T02:02:31.213543+08:00"`
+}
+
+type ProfileResponseData struct {
+	User Profile `json:"user"`
+}
+
+type ProfileResponseBody = ResponseBody[ProfileResponseData]
+
+// Profile godoc
+// @Summary      Get profile of user.
+// @Description  Get profile, account status and KYC tier of user. Owner or roles support_readonly, operator and admin only, otherwise 404.
+// @Tags         user
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        username   					path      string  true  "username"
+// @Success      200  {object}  ProfileResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /user/{username} [get]
+func (h Handlers) Profile(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	user, err := h.service.Profile(r.Context(), principal, r.PathValue("username"))
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	response_types.WriteOkJsonBody(w, ProfileResponseData{User: profile(user)})
+}
+
+// UpdateProfileRequestBody
+// Omitted fields are left unchanged.
+type UpdateProfileRequestBody struct {
+	DisplayName *string `json:"display_name" example:"User One"`
+	Email       *string `json:"email" example:"user1@example.com"`
+	Status      *string `json:"status" example:"verified" enums:"pending,verified,suspended"`
+	KycTier     *int    `json:"kyc_tier" example:"1"`
+}
+
+// UpdateProfile godoc
+// @Summary      Update profile of user.
+// @Description  display_name and email are updated by the user. status and kyc_tier are updated by roles operator and admin, not on own account.
+// @Tags         user
+// @Security     BasicAuth
+// @Accept       application/json
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        username   					path      string  true  "username"
+// @Param        request body UpdateProfileRequestBody true "Update Profile Request Body"
+// @Success      200  {object}  ProfileResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      409  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /user/{username} [patch]
+func (h Handlers) UpdateProfile(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	form := &UpdateProfileRequestBody{}
+	if err := json.NewDecoder(r.Body).Decode(form); err != nil {
+		response_types.WriteProblem(w, r, utils.WrapError(utils.ErrorCodeBadRequest, err))
+		return
+	}
+
+	user, err := h.service.UpdateProfile(r.Context(), principal, r.PathValue("username"), userrepo.ProfileUpdate{
+		DisplayName: form.DisplayName,
+		Email:       form.Email,
+		Status:      form.Status,
+		KycTier:     form.KycTier,
+	})
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	response_types.WriteOkJsonBody(w, ProfileResponseData{User: profile(user)})
+}
+
+func profile(user userrepo.User) Profile {
+	return Profile{
+		Id:          user.Id,
+		Username:    user.Username,
+		DisplayName: user.DisplayName,
+		Email:       user.Email,
+		Status:      user.Status,
+		KycTier:     user.KycTier,
+		CreatedAt:   user.CreatedAt,
+	}
+}
+
 type CreateUserRequestBody struct {
 	UserName string `json:"username" example:"user1"`
 }
@@ -95,34 +311,43 @@ type CreateUserResponseBody = ResponseBody[CreateUserResponseData]
 
 // CreateUser Create godoc
 // @Summary      Create a new user.
-// @Description  Create a new user.
+// @Description  Create a new user. Usernames are 3 to 64 letters and digits separated by single ., _ or -, unique regardless of case.
 // @Tags         user
 // @Accept       application/json
-// @Produce      application/json
+// @Produce      application/json,application/problem+json
 // @Param        request body CreateUserRequestBody true "Create User Request Body"
 // @Success      200  {object}  CreateUserResponseBody
-// @Failure      400  {object}  ErrorResponseBody400
-// @Failure      500  {object}  ErrorResponseBody500
+// @Failure      409  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
 // @Router       /user [post]
 func (h Handlers) CreateUser(w http.ResponseWriter, r *http.Request) {
 	form := &CreateUserRequestBody{}
 	json.NewDecoder(r.Body).Decode(form)
 	if form.UserName == "" {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, errors.New("user name is required"))
+		response_types.WriteProblem(w, r, utils.InvalidArgumentErrorF("user name is required"))
+		return
 	}
 
 	user, err := h.service.CreateUser(r.Context(), form.UserName)
 	if err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, err)
+		response_types.WriteProblem(w, r, err)
 		return
 	}
-	c := CreatedUser(user)
+	c := CreatedUser{Id: user.Id, Username: user.Username}
 	response_types.WriteOkJsonBody(w, CreateUserResponseData{User: &c})
 }
 
 type TransactionMetaData struct {
-	SourceWalletId *int64  `json:"source_wallet_id" example:"1021"`
-	Amount         *string `json:"amount" example:"40.1122"`
+	SourceWalletId      *int64  `json:"source_wallet_id" example:"1021"`
+	DestinationWalletId *int64  `json:"destination_wallet_id,omitempty" example:"1022"`
+	Amount              *string `json:"amount" example:"40.1122"`
+	// Approvers, ApprovalsRequired and ExpiresAt are set for transfers pending approval.
+	Approvers         []string   `json:"approvers,omitempty" example:"grace"`
+	ApprovalsRequired *int       `json:"approvals_required,omitempty" example:"2"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty" example:"2025-06-10T02:02:31.213543+08:00"`
+	// PaymentRequestId is set for transfers paying a payment request.
+	PaymentRequestId *int64 `json:"payment_request_id,omitempty" example:"3"`
 }
 
 type Transaction struct {
@@ -151,20 +376,36 @@ type TransactionsResponseBody = ResponseBody[TransactionsResponseData]
 
 // Transactions godoc
 // @Summary      Get transactions of user's wallets sorted by newest.
-// @Description  Get transactions of user's wallets sorted by newest.
+// @Description  Get transactions of user's wallets sorted by newest. Owner or roles support_readonly, operator and admin only, otherwise 404.
+// @Description  With Accept text/csv or application/x-ndjson, streams one row per ledger of the user's wallets with columns transaction_id, transaction_created_at, requestor_id, nonce, operation, status, ledger_id, wallet_id, entry_type, amount, balance, ledger_created_at.
 // @Tags         user
+// @Security     BasicAuth
 // @Accept       application/json
-// @Produce      application/json
+// @Produce      application/json,application/problem+json,text/csv,application/x-ndjson
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param 		 Accept header string false "application/json (default), text/csv or application/x-ndjson"
 // @Param        user_id   					path      string  true  "username"
 // @Success      200  {object}  TransactionsResponseBody
-// @Failure      400  {object}  ErrorResponseBody400
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
 // @Router       /user/{username}/transactions [get]
 func (h Handlers) Transactions(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
 	userName := r.PathValue("username")
+	if contentType := exportContentType(r); contentType != "" {
+		h.exportTransactions(w, r, principal, userName, contentType)
+		return
+	}
 
-	transactionLedgers, err := h.service.GetUserTransactionsByUserName(r.Context(), userName)
+	transactionLedgers, err := h.service.GetUserTransactionsByUserName(r.Context(), principal, userName)
 	if err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, err)
+		response_types.WriteProblem(w, r, err)
 		return
 	}
 
@@ -208,16 +449,112 @@ func (h Handlers) Transactions(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+type Statement struct {
+	WalletId       int64      `json:"wallet_id" example:"1"`
+	Currency       string     `json:"currency" example:"USD"`
+	From           *time.Time `json:"from" example:"2025-06-01T00:00:00Z" extensions:"x-nullable"`
+	To             time.Time  `json:"to" example:"2025-07-01T00:00:00Z"`
+	OpeningBalance string     `json:"opening_balance" example:"10.5"`
+	Ledgers        []Ledger   `json:"ledgers"`
+	TotalCredits   string     `json:"total_credits" example:"100"`
+	TotalDebits    string     `json:"total_debits" example:"40.25"`
+	ClosingBalance string     `json:"closing_balance" example:"70.25"`
+}
+
+type StatementResponseData struct {
+	Statement Statement `json:"statement"`
+}
+
+type StatementResponseBody = ResponseBody[StatementResponseData]
+
+// Statement godoc
+// @Summary      Get statement of wallet.
+// @Description  Get opening balance, ledgers sorted by oldest with running balance, totals and closing balance of wallet in a period. Owner or roles support_readonly, operator and admin only, otherwise 404.
+// @Tags         wallet
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        wallet_id  path       int     true   "Wallet Id"
+// @Param        from       query      string  false  "RFC3339 timestamp, inclusive. Defaults to the first ledger"
+// @Param        to         query      string  false  "RFC3339 timestamp, exclusive. Defaults to now"
+// @Success      200  {object}  StatementResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /wallet/{wallet_id}/statement [get]
+func (h Handlers) Statement(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	walletId, err := strconv.ParseInt(r.PathValue("wallet_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid wallet_id"))
+		return
+	}
+	from, err := queryTime(r, "from")
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	to, err := queryTime(r, "to")
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	statement, err := h.service.Statement(r.Context(), principal, walletId, from, to)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	ledgers := make([]Ledger, 0, len(statement.Ledgers))
+	for _, ledger := range statement.Ledgers {
+		ledgers = append(ledgers, Ledger{
+			Id:            ledger.Id,
+			WalletId:      ledger.WalletId,
+			TransactionId: ledger.TransactionId,
+			EntryType:     ledger.EntryType,
+			Amount:        ledger.Amount.String(),
+			CreatedAt:     ledger.CreatedAt,
+			Balance:       ledger.Balance.String(),
+		})
+	}
+	var fromP *time.Time
+	if !statement.From.IsZero() {
+		fromP = &statement.From
+	}
+	response_types.WriteOkJsonBody(w, StatementResponseData{Statement: Statement{
+		WalletId:       statement.Wallet.Id,
+		Currency:       statement.Wallet.Currency,
+		From:           fromP,
+		To:             statement.To,
+		OpeningBalance: statement.OpeningBalance.String(),
+		Ledgers:        ledgers,
+		TotalCredits:   statement.TotalCredits.String(),
+		TotalDebits:    statement.TotalDebits.String(),
+		ClosingBalance: statement.ClosingBalance.String(),
+	}})
+}
+
 type CreateWalletRequestBody struct {
 	UserName string `json:"username" example:"username1"`
 	Currency string `json:"currency" example:"USD"`
+	// Name is unique per user and currency, main if empty.
+	Name string `json:"name,omitempty" example:"savings"`
 }
 
 type CreatedWallet struct {
 	Id            int64  `json:"id" example:"1"`
 	UserAccountId int64  `json:"user_account_id" example:"1"`
+	Name          string `json:"name" example:"main"`
 	Balance       string `json:"balance" example:"user1"`
 	Currency      string `json:"currency" example:"USD"`
+	Status        string `json:"status" example:"active"`
 }
 type CreateWalletResponseData struct {
 	Wallet *CreatedWallet `json:"wallet"`
@@ -227,33 +564,47 @@ type CreateWalletResponseBody = ResponseBody[CreateWalletResponseData]
 
 // CreateWallet Create godoc
 // @Summary      Create a new wallet for user.
-// @Description  Create a new wallet for user.
+// @Description  Create a new wallet for user. User only, otherwise 403.
 // @Tags         wallet
+// @Security     BasicAuth
 // @Accept       application/json
-// @Produce      application/json
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
 // @Param        request body CreateWalletRequestBody true "Create Wallet Request Body"
 // @Success      200  {object}  CreateWalletResponseBody
-// @Failure      400  {object}  ErrorResponseBody400
-// @Failure      500  {object}  ErrorResponseBody500
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      409  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
 // @Router       /wallet [post]
 func (h Handlers) CreateWallet(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
 	form := &CreateWalletRequestBody{}
 	json.NewDecoder(r.Body).Decode(form)
 	if form.UserName == "" {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, errors.New("user name is required"))
+		response_types.WriteProblem(w, r, utils.InvalidArgumentErrorF("user name is required"))
+		return
 	}
 
-	wallet, err := h.service.CreateWallet(r.Context(), form.UserName, form.Currency)
+	wallet, err := h.service.CreateWallet(r.Context(), principal, form.UserName, form.Currency, form.Name)
 	if err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, err)
+		response_types.WriteProblem(w, r, err)
 		return
 	}
 
 	response_types.WriteOkJsonBody(w, CreateWalletResponseData{Wallet: &CreatedWallet{
 		Id:            wallet.Id,
 		UserAccountId: wallet.UserAccountId,
+		Name:          wallet.Name,
 		Balance:       wallet.Balance.String(),
 		Currency:      wallet.Currency,
+		Status:        string(wallet.Status),
 	}})
 }
 
@@ -284,42 +635,46 @@ type DepositResponseBody = ResponseBody[DepositResponseData]
 // @Tags         wallet
 // @Security     BasicAuth
 // @Accept       application/json
-// @Produce      application/json
+// @Produce      application/json,application/problem+json
 // @Param 		 Authorization header string true "Basic Authorization"
 // @Param        wallet_id   					path      string  true  "Wallet Id"
 // @Param        request body DepositRequestBody true "Create Deposit Request Body"
 // @Success      200  {object}  DepositResponseBody
-// @Failure      400  {object}  ErrorResponseBody400
-// @Failure      500  {object}  ErrorResponseBody500
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      409  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
 // @Router       /wallet/{wallet_id}/deposit [post]
 func (h Handlers) Deposit(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	basicAuthB64, _ := ctx.Value("BASIC_AUTH").(string)
-	principal, err := mustExtractUsernameFromBasicAuthValue(basicAuthB64)
+	principal, err := middlewares.PrincipalFromRequest(r)
 	if err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusUnauthorized, err)
+		response_types.WriteProblem(w, r, err)
 		return
 	}
 
 	_walletId := r.PathValue("wallet_id")
 	walletId, err := strconv.Atoi(_walletId)
 	if err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, err)
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid wallet_id"))
 		return
 	}
 	form := &DepositRequestBody{}
 	if err := json.NewDecoder(r.Body).Decode(form); err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, err)
+		response_types.WriteProblem(w, r, utils.WrapError(utils.ErrorCodeBadRequest, err))
 		return
 	}
 	amount, err := decimal.NewFromString(form.Amount)
 	if err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, err)
+		response_types.WriteProblem(w, r, utils.InvalidAmountError)
 		return
 	}
 	transaction, ledger, err := h.service.Deposit(ctx, principal, form.Nonce, int64(walletId), amount)
 	if err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, err)
+		response_types.WriteProblem(w, r, err)
 		return
 	}
 	response_types.WriteOkJsonBody(w, DepositResponseData{
@@ -387,42 +742,46 @@ type WithdrawResponseBody = ResponseBody[WithdrawResponseData]
 // @Tags         wallet
 // @Security     BasicAuth
 // @Accept       application/json
-// @Produce      application/json
+// @Produce      application/json,application/problem+json
 // @Param 		 Authorization header string true "Basic Authorization"
 // @Param        wallet_id   					path      string  true  "Wallet Id"
 // @Param        request body WithdrawRequestBody true "Create Withdraw Request Body"
 // @Success      200  {object}  WithdrawResponseBody
-// @Failure      400  {object}  ErrorResponseBody400
-// @Failure      500  {object}  ErrorResponseBody500
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      409  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
 // @Router       /wallet/{wallet_id}/withdrawal [post]
 func (h Handlers) Withdraw(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	basicAuthB64, _ := ctx.Value("BASIC_AUTH").(string)
-	principal, err := mustExtractUsernameFromBasicAuthValue(basicAuthB64)
+	principal, err := middlewares.PrincipalFromRequest(r)
 	if err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusUnauthorized, err)
+		response_types.WriteProblem(w, r, err)
 		return
 	}
 
 	_walletId := r.PathValue("wallet_id")
 	walletId, err := strconv.Atoi(_walletId)
 	if err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, err)
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid wallet_id"))
 		return
 	}
 	form := &WithdrawRequestBody{}
 	if err := json.NewDecoder(r.Body).Decode(form); err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, err)
+		response_types.WriteProblem(w, r, utils.WrapError(utils.ErrorCodeBadRequest, err))
 		return
 	}
 	amount, err := decimal.NewFromString(form.Amount)
 	if err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, err)
+		response_types.WriteProblem(w, r, utils.InvalidAmountError)
 		return
 	}
 	transaction, ledger, err := h.service.Withdraw(ctx, principal, form.Nonce, int64(walletId), amount)
 	if err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, err)
+		response_types.WriteProblem(w, r, err)
 		return
 	}
 
@@ -473,100 +832,93 @@ type TransferResponseBody = ResponseBody[TransferResponseData]
 
 // Transfer Create godoc
 // @Summary      Transfer to another wallet.
-// @Description  Transfer to another wallet.
+// @Description  Transfer to another wallet. Transfers over the threshold of the approval policy of the wallet are pending_approval without ledgers, and execute once approved by the required number of its approvers.
 // @Tags         wallet
 // @Security     BasicAuth
 // @Accept       application/json
-// @Produce      application/json
+// @Produce      application/json,application/problem+json
 // @Param 		 Authorization header string true "Basic Authorization"
 // @Param        wallet_id   					path      string  true  "Wallet Id"
 // @Param        request body TransferRequestBody true "Create Transfer Request Body"
 // @Success      200  {object}  TransferResponseBody
-// @Failure      400  {object}  ErrorResponseBody400
-// @Failure      500  {object}  ErrorResponseBody500
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      409  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
 // @Router       /wallet/{wallet_id}/transfer [post]
 func (h Handlers) Transfer(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
-	basicAuthB64, _ := ctx.Value("BASIC_AUTH").(string)
-	principal, err := mustExtractUsernameFromBasicAuthValue(basicAuthB64)
+	principal, err := middlewares.PrincipalFromRequest(r)
 	if err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusUnauthorized, err)
+		response_types.WriteProblem(w, r, err)
 		return
 	}
 
 	_walletId := r.PathValue("wallet_id")
 	walletId, err := strconv.Atoi(_walletId)
 	if err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, err)
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid wallet_id"))
 		return
 	}
 	form := &TransferRequestBody{}
 	if err := json.NewDecoder(r.Body).Decode(form); err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, err)
+		response_types.WriteProblem(w, r, utils.WrapError(utils.ErrorCodeBadRequest, err))
 		return
 	}
 	amount, err := decimal.NewFromString(form.Amount)
 	if err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, err)
+		response_types.WriteProblem(w, r, utils.InvalidAmountError)
 		return
 	}
 
-	transaction, ledgersS, err := h.service.Transfer(ctx, principal, form.Nonce, int64(walletId), form.DestinationWalletId, amount)
+	t, ledgers, err := h.service.Transfer(ctx, principal, form.Nonce, int64(walletId), form.DestinationWalletId, amount)
 	if err != nil {
-		response_types.WriteErrorNoBody(w, http.StatusBadRequest, err)
+		response_types.WriteProblem(w, r, err)
 		return
 	}
 
-	var amountP *string
-	if transaction.MetaData.Amount != nil {
-		_amount := transaction.MetaData.Amount.String()
-		amountP = &_amount
-	}
-
-	ledgers := make([]Ledger, 0, len(ledgersS))
-	for _, ledger := range ledgersS {
-		ledgers = append(ledgers, Ledger{
-			Id:            ledger.Id,
-			WalletId:      ledger.WalletId,
-			TransactionId: ledger.TransactionId,
-			EntryType:     ledger.EntryType,
-			Amount:        ledger.Amount.String(),
-			CreatedAt:     ledger.CreatedAt,
-			Balance:       ledger.Balance.String(),
-		})
-	}
-
-	response_types.WriteOkJsonBody(w, TransferResponseData{
-		Transaction: Transaction{
-			Ledgers:     ledgers,
-			Id:          transaction.Id,
-			RequestorId: transaction.RequestorId,
-			Nonce:       transaction.Nonce,
-			Status:      transaction.Status,
-			Operation:   transaction.Operation,
-			CreatedAt:   transaction.CreatedAt,
-			TransactionMetaData: TransactionMetaData{
-				SourceWalletId: transaction.MetaData.SourceWalletId,
-				Amount:         amountP,
-			},
-		},
-	})
+	response_types.WriteOkJsonBody(w, TransferResponseData{Transaction: transaction(t, ledgers...)})
 }
 
-func mustExtractUsernameFromBasicAuthValue(basicAuthB64 string) (string, error) {
-	basicAuth, err := base64.StdEncoding.DecodeString(basicAuthB64)
-	if err != nil {
-		return "", err
+func transaction(t userrepo.Transaction, ledgers ...userrepo.Ledger) Transaction {
+	var amount *string
+	if t.MetaData.Amount != nil {
+		_amount := t.MetaData.Amount.String()
+		amount = &_amount
 	}
-	basicAuthSlice := strings.Split(string(basicAuth), ":")
-	if len(basicAuthSlice) != 2 {
-		return "", errors.New("invalid_basic_auth")
+	transaction := Transaction{
+		Ledgers:     make([]Ledger, 0, len(ledgers)),
+		Id:          t.Id,
+		RequestorId: t.RequestorId,
+		Nonce:       t.Nonce,
+		Status:      t.Status,
+		Operation:   t.Operation,
+		CreatedAt:   t.CreatedAt,
+		TransactionMetaData: TransactionMetaData{
+			SourceWalletId:      t.MetaData.SourceWalletId,
+			DestinationWalletId: t.MetaData.DestinationWalletId,
+			Amount:              amount,
+			Approvers:           t.MetaData.Approvers,
+			ApprovalsRequired:   t.MetaData.ApprovalsRequired,
+			ExpiresAt:           t.MetaData.ExpiresAt,
+			PaymentRequestId:    t.MetaData.PaymentRequestId,
+		},
 	}
-	principal := basicAuthSlice[0]
-	if principal == "" {
-		return "", errors.New("invalid principal")
+	for _, l := range ledgers {
+		transaction.Ledgers = append(transaction.Ledgers, Ledger{
+			Id:            l.Id,
+			WalletId:      l.WalletId,
+			TransactionId: l.TransactionId,
+			EntryType:     l.EntryType,
+			Amount:        l.Amount.String(),
+			CreatedAt:     l.CreatedAt,
+			Balance:       l.Balance.String(),
+		})
 	}
-	return principal, nil
+	return transaction
 }
 
 // Types
@@ -578,17 +930,4 @@ type ResponseBody[T any] struct {
 	Error *string `json:"error" example:"" extensions:"x-nullable"`
 }
 
-type ErrorResponseBody = struct {
-	Data  *int    `json:"data" example:"0"`
-	Error *string `json:"error" example:"general_error"`
-}
-
-type ErrorResponseBody500 = struct {
-	Data  *int    `json:"data" example:"0"`
-	Error *string `json:"error" example:"internal_server_error"`
-}
-
-type ErrorResponseBody400 = struct {
-	Data  *int    `json:"data" example:"0"`
-	Error *string `json:"error" example:"error_bad_request"`
-}
+type ProblemResponseBody = response_types.Problem
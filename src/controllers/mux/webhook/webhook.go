@@ -0,0 +1,314 @@
+package webhook
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/controllers/middlewares"
+	"github.com/cryptonlx/crypto/src/controllers/response_types"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+	webhookrepo "github.com/cryptonlx/crypto/src/repositories/webhook"
+	webhookservice "github.com/cryptonlx/crypto/src/services/webhook"
+)
+
+type Handlers struct {
+	service *webhookservice.Service
+}
+
+func NewHandlers(service *webhookservice.Service) *Handlers {
+	return &Handlers{
+		service: service,
+	}
+}
+
+type Webhook struct {
+	Id         int64     `json:"id" example:"1"`
+	Url        string    `json:"url" example:"https://example.com/webhooks/crypto"`
+	EventTypes []string  `json:"event_types" example:"transaction.succeeded,balance.changed"`
+	CreatedAt  time.Time `json:"created_at" example:"2025-06-09T02:02:31.213543+08:00"`
+}
+
+// CreatedWebhook
+// Webhook with the secret signing its deliveries, only returned on creation.
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret" example:"whsec_5d2e3a4c7f6b4a519d0e2f1c8b7a6e5d5d2e3a4c7f6b4a519d0e2f1c8b7a6e5d"`
+}
+
+type Delivery struct {
+	Id        int64           `json:"id" example:"1"`
+	WebhookId int64           `json:"webhook_id" example:"1"`
+	EventType string          `json:"event_type" example:"balance.changed"`
+	Payload   json.RawMessage `json:"payload" swaggertype:"object"`
+	Status    string          `json:"status" example:"dead" enums:"pending,delivered,dead"`
+	Attempts  int             `json:"attempts" example:"8"`
+	// NextAttemptAt is the due time of pending deliveries.
+	NextAttemptAt time.Time  `json:"next_attempt_at" example:"2025-06-09T02:02:31.213543+08:00"`
+	LastError     *string    `json:"last_error" example:"http status 503" extensions:"x-nullable"`
+	CreatedAt     time.Time  `json:"created_at" example:"2025-06-09T02:02:31.213543+08:00"`
+	DeliveredAt   *time.Time `json:"delivered_at" example:"2025-06-09T02:02:31.213543+08:00" extensions:"x-nullable"`
+}
+
+func webhook(s webhookrepo.Subscription) Webhook {
+	eventTypes := make([]string, 0, len(s.EventTypes))
+	for _, t := range s.EventTypes {
+		eventTypes = append(eventTypes, string(t))
+	}
+	return Webhook{Id: s.Id, Url: s.Url, EventTypes: eventTypes, CreatedAt: s.CreatedAt}
+}
+
+func delivery(d webhookrepo.Delivery) Delivery {
+	return Delivery{
+		Id:            d.Id,
+		WebhookId:     d.SubscriptionId,
+		EventType:     string(d.EventType),
+		Payload:       d.Payload,
+		Status:        string(d.Status),
+		Attempts:      d.Attempts,
+		NextAttemptAt: d.NextAttemptAt,
+		LastError:     d.LastError,
+		CreatedAt:     d.CreatedAt,
+		DeliveredAt:   d.DeliveredAt,
+	}
+}
+
+type CreateWebhookRequestBody struct {
+	Url        string   `json:"url" example:"https://example.com/webhooks/crypto"`
+	EventTypes []string `json:"event_types" example:"transaction.succeeded,transaction.failed,balance.changed"`
+}
+
+type CreateWebhookResponseData struct {
+	Webhook CreatedWebhook `json:"webhook"`
+}
+
+type CreateWebhookResponseBody = ResponseBody[CreateWebhookResponseData]
+
+// CreateWebhook godoc
+// @Summary      Subscribe a url to events of user.
+// @Description  The url must be https and resolve to public addresses, checked again on every delivery. Events are POSTed as json with headers X-Webhook-Delivery (id, deliveries are sent at least once), X-Webhook-Event and X-Webhook-Signature t=<unix seconds>,v1=<hex hmac-sha256 of "<t>.<body>" keyed by secret>. Non 2xx responses are retried with exponential backoff until dead. The secret is only returned here. Owner only.
+// @Tags         webhook
+// @Security     BasicAuth
+// @Accept       application/json
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        username   					path      string  true  "username"
+// @Param        request body CreateWebhookRequestBody true "Create Webhook Request Body"
+// @Success      200  {object}  CreateWebhookResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /user/{username}/webhooks [post]
+func (h Handlers) CreateWebhook(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	form := &CreateWebhookRequestBody{}
+	if err := json.NewDecoder(r.Body).Decode(form); err != nil {
+		response_types.WriteProblem(w, r, utils.WrapError(utils.ErrorCodeBadRequest, err))
+		return
+	}
+
+	subscription, err := h.service.CreateSubscription(r.Context(), principal, r.PathValue("username"), form.Url, form.EventTypes)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	response_types.WriteOkJsonBody(w, CreateWebhookResponseData{
+		Webhook: CreatedWebhook{Webhook: webhook(subscription), Secret: subscription.Secret},
+	})
+}
+
+type WebhooksResponseData struct {
+	Webhooks []Webhook `json:"webhooks"`
+}
+
+type WebhooksResponseBody = ResponseBody[WebhooksResponseData]
+
+// Webhooks godoc
+// @Summary      Get webhooks of user.
+// @Description  Get webhooks of user sorted by oldest, without secrets. Owner or roles support_readonly, operator and admin only, otherwise 404.
+// @Tags         webhook
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        username   					path      string  true  "username"
+// @Success      200  {object}  WebhooksResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /user/{username}/webhooks [get]
+func (h Handlers) Webhooks(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+
+	subscriptions, err := h.service.Subscriptions(r.Context(), principal, r.PathValue("username"))
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	webhooks := make([]Webhook, 0, len(subscriptions))
+	for _, s := range subscriptions {
+		webhooks = append(webhooks, webhook(s))
+	}
+	response_types.WriteOkJsonBody(w, WebhooksResponseData{Webhooks: webhooks})
+}
+
+type DeleteWebhookResponseData struct {
+	WebhookId int64 `json:"webhook_id" example:"1"`
+}
+
+type DeleteWebhookResponseBody = ResponseBody[DeleteWebhookResponseData]
+
+// DeleteWebhook godoc
+// @Summary      Delete webhook of user.
+// @Description  Stops deliveries to the webhook, pending deliveries are dead. Owner only.
+// @Tags         webhook
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        username   					path      string  true  "username"
+// @Param        webhook_id   					path      int  true  "webhook id"
+// @Success      200  {object}  DeleteWebhookResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /user/{username}/webhooks/{webhook_id} [delete]
+func (h Handlers) DeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	webhookId, err := strconv.ParseInt(r.PathValue("webhook_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid webhook_id"))
+		return
+	}
+
+	err = h.service.DeleteSubscription(r.Context(), principal, r.PathValue("username"), webhookId)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	response_types.WriteOkJsonBody(w, DeleteWebhookResponseData{WebhookId: webhookId})
+}
+
+type DeliveriesResponseData struct {
+	Deliveries []Delivery `json:"deliveries"`
+}
+
+type DeliveriesResponseBody = ResponseBody[DeliveriesResponseData]
+
+// Deliveries godoc
+// @Summary      Get deliveries of webhook sorted by newest.
+// @Description  Get deliveries of webhook with their status, attempts and last error. Owner or roles support_readonly, operator and admin only, otherwise 404.
+// @Tags         webhook
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        username   					path      string  true  "username"
+// @Param        webhook_id   					path      int  true  "webhook id"
+// @Param        limit      query      int     false  "max number of deliveries, default 100, max 1000"
+// @Success      200  {object}  DeliveriesResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      422  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /user/{username}/webhooks/{webhook_id}/deliveries [get]
+func (h Handlers) Deliveries(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	webhookId, err := strconv.ParseInt(r.PathValue("webhook_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid webhook_id"))
+		return
+	}
+	var limit int
+	if _limit := r.URL.Query().Get("limit"); _limit != "" {
+		limit, err = strconv.Atoi(_limit)
+		if err != nil {
+			response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid_limit"))
+			return
+		}
+	}
+
+	deliveries, err := h.service.Deliveries(r.Context(), principal, r.PathValue("username"), webhookId, limit)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	data := DeliveriesResponseData{Deliveries: make([]Delivery, 0, len(deliveries))}
+	for _, d := range deliveries {
+		data.Deliveries = append(data.Deliveries, delivery(d))
+	}
+	response_types.WriteOkJsonBody(w, data)
+}
+
+type ReplayResponseData struct {
+	Delivery Delivery `json:"delivery"`
+}
+
+type ReplayResponseBody = ResponseBody[ReplayResponseData]
+
+// Replay godoc
+// @Summary      Send a delivery again.
+// @Description  Schedules a delivery, typically a dead one, for now with a fresh retry budget. The payload is unchanged and signed at send time. Owner only.
+// @Tags         webhook
+// @Security     BasicAuth
+// @Produce      application/json,application/problem+json
+// @Param 		 Authorization header string true "Basic Authorization"
+// @Param        username   					path      string  true  "username"
+// @Param        delivery_id   					path      int  true  "delivery id"
+// @Success      200  {object}  ReplayResponseBody
+// @Failure      400  {object}  ProblemResponseBody
+// @Failure      401  {object}  ProblemResponseBody
+// @Failure      403  {object}  ProblemResponseBody
+// @Failure      404  {object}  ProblemResponseBody
+// @Failure      500  {object}  ProblemResponseBody
+// @Router       /user/{username}/webhooks/deliveries/{delivery_id}/replay [post]
+func (h Handlers) Replay(w http.ResponseWriter, r *http.Request) {
+	principal, err := middlewares.PrincipalFromRequest(r)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	deliveryId, err := strconv.ParseInt(r.PathValue("delivery_id"), 10, 64)
+	if err != nil {
+		response_types.WriteProblem(w, r, utils.BadRequestErrorF("invalid delivery_id"))
+		return
+	}
+
+	d, err := h.service.Replay(r.Context(), principal, r.PathValue("username"), deliveryId)
+	if err != nil {
+		response_types.WriteProblem(w, r, err)
+		return
+	}
+	response_types.WriteOkJsonBody(w, ReplayResponseData{Delivery: delivery(d)})
+}
+
+// Types
+
+var _ = response_types.ResponseBody[struct{}](ResponseBody[struct{}]{})
+
+type ResponseBody[T any] struct {
+	Data  T       `json:"data"`
+	Error *string `json:"error" example:"" extensions:"x-nullable"`
+}
+
+type ProblemResponseBody = response_types.Problem
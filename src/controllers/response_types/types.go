@@ -2,7 +2,12 @@ package response_types
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+
+	"github.com/cryptonlx/crypto/src/controllers/httplog"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
 )
 
 type ResponseBody[T any] struct {
@@ -10,24 +15,87 @@ type ResponseBody[T any] struct {
 	Error *string `json:"error"`
 }
 
-func Write[T any](w http.ResponseWriter, httpCode int, err error, data T) {
-	w.WriteHeader(httpCode)
-	w.Header().Set("Content-Type", "application/json")
+// Problem
+// RFC 7807 problem details with extension members code and trace_id.
+type Problem struct {
+	Type     string `json:"type" example:"about:blank"`
+	Title    string `json:"title" example:"Unprocessable Entity"`
+	Status   int    `json:"status" example:"422"`
+	Detail   string `json:"detail" example:"insufficient_funds"`
+	Instance string `json:"instance" example:"/wallet/1/withdrawal"`
+	Code     string `json:"code" example:"insufficient_funds"`
+	TraceId  string `json:"trace_id,omitempty" example:"5d2e3a4c-7f6b-4a51-9d0e-2f1c8b7a6e5d"`
+}
 
-	var r ResponseBody[T]
+// ErrorRecorder is implemented by response writers which observe the error returned to the client, e.g. for auditing.
+type ErrorRecorder interface {
+	RecordError(err error)
+}
 
-	r.Data = data
-	if err != nil {
-		msg := err.Error()
-		r.Error = &msg
+func HttpStatusFromErrorCode(code utils.ErrorCode) int {
+	switch code {
+	case utils.ErrorCodeBadRequest:
+		return http.StatusBadRequest
+	case utils.ErrorCodeUnauthorized:
+		return http.StatusUnauthorized
+	case utils.ErrorCodeForbidden, utils.ErrorCodeAccountFrozen, utils.ErrorCodeWalletFrozen, utils.ErrorCodeAccountSuspended,
+		utils.ErrorCodeKycTierRequired:
+		return http.StatusForbidden
+	case utils.ErrorCodeNotFound:
+		return http.StatusNotFound
+	case utils.ErrorCodeAlreadyExists, utils.ErrorCodeDuplicateNonce:
+		return http.StatusConflict
+	case utils.ErrorCodeInvalidArgument, utils.ErrorCodeInvalidAmount, utils.ErrorCodeInvalidNonce,
+		utils.ErrorCodeInsufficientFunds, utils.ErrorCodeCurrencyMismatch, utils.ErrorCodeWalletClosed, utils.ErrorCodeWalletNotEmpty,
+		utils.ErrorCodeKycLimitExceeded, utils.ErrorCodeApprovalExpired, utils.ErrorCodeSpendLimitExceeded:
+		return http.StatusUnprocessableEntity
+	case utils.ErrorCodePayloadTooLarge:
+		return http.StatusRequestEntityTooLarge
+	case utils.ErrorCodeTooManyRequests:
+		return http.StatusTooManyRequests
 	}
+	return http.StatusInternalServerError
+}
 
-	b, _ := json.Marshal(r)
-	w.Write(b)
+// NewProblem
+// Errors outside the catalogue are reported as internal_error without detail.
+func NewProblem(r *http.Request, err error) Problem {
+	p := Problem{
+		Type:     "about:blank",
+		Instance: r.URL.Path,
+		Code:     string(utils.ErrorCodeInternal),
+	}
+	var e *utils.Error
+	if errors.As(err, &e) {
+		p.Code = string(e.Code)
+		p.Detail = e.Error()
+	}
+	p.Status = HttpStatusFromErrorCode(utils.ErrorCode(p.Code))
+	if p.Status == http.StatusInternalServerError {
+		p.Code = string(utils.ErrorCodeInternal)
+		p.Detail = "internal server error"
+	}
+	p.Title = http.StatusText(p.Status)
+	p.TraceId, _ = r.Context().Value("TRACE_ID").(string)
+	return p
 }
 
-func WriteErrorNoBody(w http.ResponseWriter, httpCode int, err error) {
-	Write[any](w, httpCode, err, nil)
+// WriteProblem
+// Writes err as application/problem+json with http status derived from its code.
+func WriteProblem(w http.ResponseWriter, r *http.Request, err error) {
+	if recorder, ok := w.(ErrorRecorder); ok && err != nil {
+		recorder.RecordError(err)
+	}
+
+	p := NewProblem(r, err)
+	if p.Status == http.StatusInternalServerError {
+		log.Printf("%s [internal error] %v\n", httplog.SPrintHttpRequestPrefix(r), err)
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(p.Status)
+	b, _ := json.Marshal(p)
+	w.Write(b)
 }
 
 func WriteOkEmptyJsonBody(w http.ResponseWriter) {
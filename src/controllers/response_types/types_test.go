@@ -0,0 +1,77 @@
+package response_types
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+)
+
+func TestNewProblem(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+	}{
+		{"not found", utils.NotFoundErrorF("user"), http.StatusNotFound, "not_found", "resource: user not found"},
+		{"insufficient funds", utils.InsufficientFundsError, http.StatusUnprocessableEntity, "insufficient_funds", "insufficient_funds"},
+		{"wrapped currency mismatch", fmt.Errorf("transfer: %w", utils.CurrencyMismatchError), http.StatusUnprocessableEntity, "currency_mismatch", "currency_mismatch"},
+		{"duplicate nonce", utils.ToError(&pgconn.PgError{Code: "23505", ConstraintName: "transactions_nonce_idx"}), http.StatusConflict, "duplicate_nonce", "duplicate_nonce"},
+		{"unique violation", utils.ToError(&pgconn.PgError{Code: "23505", ConstraintName: "user_accounts_username_key"}), http.StatusConflict, "already_exists", "already_exists"},
+		{"balance check", utils.ToError(&pgconn.PgError{Code: "23514", ConstraintName: "wallets_balance_check"}), http.StatusUnprocessableEntity, "insufficient_funds", "insufficient_funds"},
+		{"forbidden", utils.ForbiddenErrorF("requestor and wallet owner mismatch"), http.StatusForbidden, "forbidden", "requestor and wallet owner mismatch"},
+		{"account frozen", utils.AccountFrozenError, http.StatusForbidden, "account_frozen", "account_frozen"},
+		{"wallet frozen", utils.WalletFrozenError, http.StatusForbidden, "wallet_frozen", "wallet_frozen"},
+		{"wallet closed", utils.WalletClosedError, http.StatusUnprocessableEntity, "wallet_closed", "wallet_closed"},
+		{"account suspended", utils.AccountSuspendedError, http.StatusForbidden, "account_suspended", "account_suspended"},
+		{"kyc limit", utils.KycLimitExceededErrorF(decimal.NewFromInt(1000)), http.StatusUnprocessableEntity, "kyc_limit_exceeded", "amount exceeds limit 1000 of kyc tier"},
+		{"payload too large", utils.PayloadTooLargeErrorF(1024), http.StatusRequestEntityTooLarge, "payload_too_large", "request body exceeds 1024 bytes"},
+		{"unauthorized", utils.UnauthorizedError, http.StatusUnauthorized, "unauthorized", "unauthorized"},
+		{"raw pg error is not leaked", &pgconn.PgError{Code: "08006", Message: "connection failure"}, http.StatusInternalServerError, "internal_error", "internal server error"},
+		{"uncatalogued error is not leaked", errors.New("dial tcp 10.0.0.1:5432: i/o timeout"), http.StatusInternalServerError, "internal_error", "internal server error"},
+		{"internal code is not leaked", utils.WrapError(utils.ErrorCodeInternal, errors.New("secret")), http.StatusInternalServerError, "internal_error", "internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/wallet/1/withdrawal", nil)
+			p := NewProblem(r, tt.err)
+			if p.Status != tt.wantStatus {
+				t.Errorf("status want %d. got %d", tt.wantStatus, p.Status)
+			}
+			if p.Code != tt.wantCode {
+				t.Errorf("code want %s. got %s", tt.wantCode, p.Code)
+			}
+			if p.Detail != tt.wantDetail {
+				t.Errorf("detail want %q. got %q", tt.wantDetail, p.Detail)
+			}
+			if p.Title != http.StatusText(tt.wantStatus) {
+				t.Errorf("title want %q. got %q", http.StatusText(tt.wantStatus), p.Title)
+			}
+			if p.Instance != "/wallet/1/withdrawal" {
+				t.Errorf("instance want %q. got %q", "/wallet/1/withdrawal", p.Instance)
+			}
+		})
+	}
+}
+
+func TestWriteProblem(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/user/alice/wallets", nil)
+	w := httptest.NewRecorder()
+	WriteProblem(w, r, utils.NotFoundErrorF("user"))
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("status want %d. got %d", http.StatusNotFound, w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/problem+json" {
+		t.Fatalf("content type want application/problem+json. got %s", contentType)
+	}
+}
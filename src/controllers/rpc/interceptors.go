@@ -0,0 +1,277 @@
+package rpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+
+	"github.com/cryptonlx/crypto/src/controllers/middlewares"
+	"github.com/cryptonlx/crypto/src/controllers/response_types"
+	"github.com/cryptonlx/crypto/src/controllers/rpc/walletpb"
+	auditrepo "github.com/cryptonlx/crypto/src/repositories/audit"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+
+	"github.com/google/uuid"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// AuditMethod
+// Method of audit events of rpcs, routes are "GRPC <full method>".
+const AuditMethod = "GRPC"
+
+// publicMethods
+// Served without credentials, as their http routes.
+var publicMethods = map[string]bool{
+	walletpb.WalletService_CreateUser_FullMethodName: true,
+}
+
+// readMethods
+// Not audited, as GET routes.
+var readMethods = map[string]bool{
+	walletpb.WalletService_GetWallets_FullMethodName:       true,
+	walletpb.WalletService_ListTransactions_FullMethodName: true,
+}
+
+// Recorder
+// Implemented by auditservice.Service.
+type Recorder interface {
+	Record(ctx context.Context, event auditrepo.Event) (auditrepo.Event, error)
+}
+
+// Interceptors
+// Same request handling as the http server: rate limit, credentials of the basic authorization metadata or a mapped
+// client certificate, audit of mutations and errors as status.
+type Interceptors struct {
+	limiter           *rate.Limiter
+	subjectPrincipals map[string]string
+	recorder          Recorder
+}
+
+func NewInterceptors(limiter *rate.Limiter, subjectPrincipals map[string]string, recorder Recorder) *Interceptors {
+	return &Interceptors{
+		limiter:           limiter,
+		subjectPrincipals: subjectPrincipals,
+		recorder:          recorder,
+	}
+}
+
+// ServerOptions
+// Options installing the interceptors.
+func (i *Interceptors) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(i.Unary),
+		grpc.StreamInterceptor(i.Stream),
+	}
+}
+
+func (i *Interceptors) Unary(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !i.limiter.Allow() {
+		return nil, Status(utils.TooManyRequestsError)
+	}
+	ctx = i.contextualize(ctx, info.FullMethod)
+
+	var resp any
+	err := authenticate(ctx, info.FullMethod)
+	if err == nil {
+		resp, err = handler(ctx, req)
+	}
+	if !readMethods[info.FullMethod] {
+		i.record(ctx, info.FullMethod, req, err)
+	}
+	if err != nil {
+		return nil, statusWithLog(ctx, err)
+	}
+	return resp, nil
+}
+
+func (i *Interceptors) Stream(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !i.limiter.Allow() {
+		return Status(utils.TooManyRequestsError)
+	}
+	ctx := i.contextualize(ss.Context(), info.FullMethod)
+	if err := authenticate(ctx, info.FullMethod); err != nil {
+		return Status(err)
+	}
+	if err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx}); err != nil {
+		return statusWithLog(ctx, err)
+	}
+	return nil
+}
+
+// contextualize
+// Sets the same context values as httplog.ContextualizeHttpRequest and middlewares.ContextualizeClientCertPrincipal,
+// so that middlewares.PrincipalFromContext applies.
+func (i *Interceptors) contextualize(ctx context.Context, method string) context.Context {
+	ctx = context.WithValue(ctx, "METHOD", AuditMethod)
+	ctx = context.WithValue(ctx, "TRACE_ID", uuid.New().String())
+	ctx = context.WithValue(ctx, "URL/PATH", method)
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			ctx = context.WithValue(ctx, "BASIC_AUTH", middlewares.GetBasicAuthFromAuthorization(values[0]))
+		}
+		if values := md.Get("user-agent"); len(values) > 0 {
+			ctx = context.WithValue(ctx, "USER-AGENT", values[0])
+		}
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			if principal := middlewares.ClientCertPrincipal(&tlsInfo.State, i.subjectPrincipals); principal != "" {
+				ctx = context.WithValue(ctx, "TLS_PRINCIPAL", principal)
+			}
+		}
+	}
+	log.Printf("%s [request received]\n", sprintPrefix(ctx))
+	return ctx
+}
+
+func authenticate(ctx context.Context, method string) error {
+	if publicMethods[method] {
+		return nil
+	}
+	_, err := middlewares.PrincipalFromContext(ctx)
+	return err
+}
+
+// record
+// Records the call to the audit log as the audit middleware records requests, with the http status of the error code.
+// Failure to record is logged and does not affect the response.
+func (i *Interceptors) record(ctx context.Context, method string, req any, err error) {
+	event := auditrepo.Event{
+		Ip:         remoteIp(ctx),
+		Method:     AuditMethod,
+		Route:      AuditMethod + " " + method,
+		Path:       method,
+		HttpStatus: http.StatusOK,
+	}
+	if m, ok := req.(proto.Message); ok {
+		b, _ := proto.MarshalOptions{Deterministic: true}.Marshal(m)
+		payloadHash := sha256.Sum256(b)
+		event.PayloadHash = hex.EncodeToString(payloadHash[:])
+	}
+	if principal, err := middlewares.PrincipalFromContext(ctx); err == nil {
+		event.Principal = &principal
+	}
+	switch r := req.(type) {
+	case interface{ GetWalletId() int64 }:
+		walletId := r.GetWalletId()
+		event.WalletId = &walletId
+	case interface{ GetSourceWalletId() int64 }:
+		walletId := r.GetSourceWalletId()
+		event.WalletId = &walletId
+	}
+	if err != nil {
+		errorCode := utils.ErrorCodeOf(err)
+		event.HttpStatus = response_types.HttpStatusFromErrorCode(errorCode)
+		event.ErrorCode = (*string)(&errorCode)
+	}
+	if traceId, ok := ctx.Value("TRACE_ID").(string); ok {
+		event.TraceId = &traceId
+	}
+
+	if _, err := i.recorder.Record(context.WithoutCancel(ctx), event); err != nil {
+		log.Printf("%s [audit record failed] %v\n", sprintPrefix(ctx), err)
+	}
+}
+
+// Status
+// Status of err with the code of its domain error code, and the domain error code as message prefix. Errors outside
+// the catalogue are internal without detail, as in http problems. Status errors, i.e. of sending to the stream, are
+// returned as is.
+func Status(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	var e *utils.Error
+	if !errors.As(err, &e) {
+		return status.Error(codes.Internal, "internal server error")
+	}
+	code := CodeFromErrorCode(e.Code)
+	if code == codes.Internal {
+		return status.Error(codes.Internal, "internal server error")
+	}
+	if e.Message == "" || e.Message == string(e.Code) {
+		return status.Error(code, string(e.Code))
+	}
+	return status.Error(code, string(e.Code)+": "+e.Message)
+}
+
+// CodeFromErrorCode
+// Status code of a domain error code, the counterpart of response_types.HttpStatusFromErrorCode.
+func CodeFromErrorCode(code utils.ErrorCode) codes.Code {
+	switch code {
+	case utils.ErrorCodeBadRequest, utils.ErrorCodeInvalidArgument, utils.ErrorCodeInvalidAmount, utils.ErrorCodeInvalidNonce:
+		return codes.InvalidArgument
+	case utils.ErrorCodeUnauthorized:
+		return codes.Unauthenticated
+	case utils.ErrorCodeForbidden, utils.ErrorCodeAccountFrozen, utils.ErrorCodeWalletFrozen, utils.ErrorCodeAccountSuspended,
+		utils.ErrorCodeKycTierRequired:
+		return codes.PermissionDenied
+	case utils.ErrorCodeNotFound:
+		return codes.NotFound
+	case utils.ErrorCodeAlreadyExists, utils.ErrorCodeDuplicateNonce:
+		return codes.AlreadyExists
+	case utils.ErrorCodeInsufficientFunds, utils.ErrorCodeCurrencyMismatch, utils.ErrorCodeWalletClosed,
+		utils.ErrorCodeWalletNotEmpty, utils.ErrorCodeKycLimitExceeded, utils.ErrorCodeApprovalExpired,
+		utils.ErrorCodeSpendLimitExceeded:
+		return codes.FailedPrecondition
+	case utils.ErrorCodeTooManyRequests:
+		return codes.ResourceExhausted
+	}
+	return codes.Internal
+}
+
+func statusWithLog(ctx context.Context, err error) error {
+	s := Status(err)
+	if status.Code(s) == codes.Internal {
+		log.Printf("%s [internal error] %v\n", sprintPrefix(ctx), err)
+	}
+	return s
+}
+
+func sprintPrefix(ctx context.Context) string {
+	return fmt.Sprintf("[METHOD:%s URL:%s UA: %s TRACE_ID:%s]", ctx.Value("METHOD"), ctx.Value("URL/PATH"), ctx.Value("USER-AGENT"), ctx.Value("TRACE_ID"))
+}
+
+func remoteIp(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// serverStream
+// Stream with the contextualized ctx.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,185 @@
+package rpc
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/cryptonlx/crypto/src/controllers/middlewares"
+	"github.com/cryptonlx/crypto/src/controllers/rpc/walletpb"
+	auditrepo "github.com/cryptonlx/crypto/src/repositories/audit"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantCode    codes.Code
+		wantMessage string
+	}{
+		{name: "nil", err: nil, wantCode: codes.OK, wantMessage: ""},
+		{name: "code only", err: utils.NewError(utils.ErrorCodeInsufficientFunds, ""), wantCode: codes.FailedPrecondition, wantMessage: "insufficient_funds"},
+		{name: "code and message", err: utils.InvalidArgumentErrorF("user name is required"), wantCode: codes.InvalidArgument, wantMessage: "invalid_argument: user name is required"},
+		{name: "wrapped", err: errors.Join(errors.New("tx"), utils.NotFoundErrorF("wallet")), wantCode: codes.NotFound, wantMessage: "not_found: resource: wallet not found"},
+		{name: "frozen", err: utils.NewError(utils.ErrorCodeWalletFrozen, ""), wantCode: codes.PermissionDenied, wantMessage: "wallet_frozen"},
+		{name: "internal code hides detail", err: utils.NewError(utils.ErrorCodeInternal, "pool closed"), wantCode: codes.Internal, wantMessage: "internal server error"},
+		{name: "outside catalogue hides detail", err: errors.New("conn reset"), wantCode: codes.Internal, wantMessage: "internal server error"},
+		{name: "canceled", err: context.Canceled, wantCode: codes.Canceled, wantMessage: "context canceled"},
+		{name: "status as is", err: status.Error(codes.Unavailable, "transport closing"), wantCode: codes.Unavailable, wantMessage: "transport closing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := status.Convert(Status(tt.err))
+			if s.Code() != tt.wantCode || s.Message() != tt.wantMessage {
+				t.Fatalf("want %s %q. got %s %q", tt.wantCode, tt.wantMessage, s.Code(), s.Message())
+			}
+		})
+	}
+}
+
+// principalServer
+// Answers with the principal of the call, Deposit fails with insufficient funds.
+type principalServer struct {
+	walletpb.UnimplementedWalletServiceServer
+}
+
+func (principalServer) CreateUser(ctx context.Context, req *walletpb.CreateUserRequest) (*walletpb.CreateUserResponse, error) {
+	return &walletpb.CreateUserResponse{User: &walletpb.User{Id: 1, Username: req.GetUsername()}}, nil
+}
+
+func (principalServer) GetWallets(ctx context.Context, req *walletpb.GetWalletsRequest) (*walletpb.GetWalletsResponse, error) {
+	principal, err := middlewares.PrincipalFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &walletpb.GetWalletsResponse{User: &walletpb.User{Username: principal}}, nil
+}
+
+func (principalServer) ListTransactions(req *walletpb.ListTransactionsRequest, stream walletpb.WalletService_ListTransactionsServer) error {
+	principal, err := middlewares.PrincipalFromContext(stream.Context())
+	if err != nil {
+		return err
+	}
+	for id := int64(1); id <= 2; id++ {
+		if err := stream.Send(&walletpb.Transaction{Id: id, Status: principal}); err != nil {
+			return err
+		}
+	}
+	return utils.NotFoundErrorF("user")
+}
+
+func (principalServer) Deposit(ctx context.Context, req *walletpb.DepositRequest) (*walletpb.DepositResponse, error) {
+	return nil, utils.NewError(utils.ErrorCodeInsufficientFunds, "")
+}
+
+type recorder struct {
+	mu     sync.Mutex
+	events []auditrepo.Event
+}
+
+func (r *recorder) Record(ctx context.Context, event auditrepo.Event) (auditrepo.Event, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return event, nil
+}
+
+func dial(t *testing.T, recorder Recorder) walletpb.WalletServiceClient {
+	l := bufconn.Listen(1 << 20)
+	server := grpc.NewServer(NewInterceptors(rate.NewLimiter(rate.Inf, 0), nil, recorder).ServerOptions()...)
+	walletpb.RegisterWalletServiceServer(server, principalServer{})
+	go server.Serve(l)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return l.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient err %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return walletpb.NewWalletServiceClient(conn)
+}
+
+func basicAuth(ctx context.Context, username string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(username+":")))
+}
+
+func TestInterceptors(t *testing.T) {
+	recorder := &recorder{}
+	client := dial(t, recorder)
+	ctx := context.Background()
+
+	if _, err := client.GetWallets(ctx, &walletpb.GetWalletsRequest{Username: "user1"}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("GetWallets without credentials want Unauthenticated. got %v", err)
+	}
+	res, err := client.GetWallets(basicAuth(ctx, "user1"), &walletpb.GetWalletsRequest{Username: "user1"})
+	if err != nil || res.GetUser().GetUsername() != "user1" {
+		t.Fatalf("GetWallets want principal user1. got %v %v", res, err)
+	}
+
+	if _, err := client.CreateUser(ctx, &walletpb.CreateUserRequest{Username: "user2"}); err != nil {
+		t.Fatalf("CreateUser without credentials err %v", err)
+	}
+	if _, err := client.CreateWallet(ctx, &walletpb.CreateWalletRequest{Username: "user2", Currency: "USD"}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("CreateWallet without credentials want Unauthenticated. got %v", err)
+	}
+
+	_, err = client.Deposit(basicAuth(ctx, "user1"), &walletpb.DepositRequest{WalletId: 4, Amount: "1", Nonce: 1})
+	if s := status.Convert(err); s.Code() != codes.FailedPrecondition || s.Message() != "insufficient_funds" {
+		t.Fatalf("Deposit want FailedPrecondition insufficient_funds. got %v", err)
+	}
+
+	if len(recorder.events) != 3 {
+		t.Fatalf("want CreateUser, CreateWallet and Deposit audited. got %+v", recorder.events)
+	}
+	if createWallet := recorder.events[1]; createWallet.Principal != nil || createWallet.HttpStatus != http.StatusUnauthorized {
+		t.Fatalf("unexpected CreateWallet audit event %+v", createWallet)
+	}
+	deposit := recorder.events[2]
+	if deposit.Route != "GRPC /crypto.wallet.v1.WalletService/Deposit" || deposit.Principal == nil || *deposit.Principal != "user1" ||
+		deposit.WalletId == nil || *deposit.WalletId != 4 || deposit.HttpStatus != http.StatusUnprocessableEntity ||
+		deposit.ErrorCode == nil || *deposit.ErrorCode != "insufficient_funds" || deposit.PayloadHash == "" || deposit.TraceId == nil {
+		t.Fatalf("unexpected Deposit audit event %+v", deposit)
+	}
+}
+
+func TestStreamInterceptor(t *testing.T) {
+	client := dial(t, &recorder{})
+	ctx := context.Background()
+
+	stream, err := client.ListTransactions(ctx, &walletpb.ListTransactionsRequest{Username: "user1"})
+	if err != nil {
+		t.Fatalf("ListTransactions err %v", err)
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("ListTransactions without credentials want Unauthenticated. got %v", err)
+	}
+
+	stream, err = client.ListTransactions(basicAuth(ctx, "user1"), &walletpb.ListTransactionsRequest{Username: "user1"})
+	if err != nil {
+		t.Fatalf("ListTransactions err %v", err)
+	}
+	for id := int64(1); id <= 2; id++ {
+		transaction, err := stream.Recv()
+		if err != nil || transaction.GetId() != id || transaction.GetStatus() != "user1" {
+			t.Fatalf("want transaction %d of user1. got %v %v", id, transaction, err)
+		}
+	}
+	if _, err := stream.Recv(); status.Code(err) != codes.NotFound {
+		t.Fatalf("want NotFound after transactions. got %v", err)
+	}
+}
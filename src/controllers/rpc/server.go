@@ -0,0 +1,199 @@
+package rpc
+
+import (
+	"context"
+
+	"github.com/cryptonlx/crypto/src/controllers/middlewares"
+	"github.com/cryptonlx/crypto/src/controllers/rpc/walletpb"
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+	userservice "github.com/cryptonlx/crypto/src/services/user"
+
+	"github.com/shopspring/decimal"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Server
+// walletpb.WalletServiceServer calling the same service as the http handlers. Errors are domain errors, converted to
+// status by the interceptors.
+type Server struct {
+	walletpb.UnimplementedWalletServiceServer
+	service *userservice.Service
+}
+
+func NewServer(service *userservice.Service) *Server {
+	return &Server{
+		service: service,
+	}
+}
+
+func (s *Server) CreateUser(ctx context.Context, req *walletpb.CreateUserRequest) (*walletpb.CreateUserResponse, error) {
+	if req.GetUsername() == "" {
+		return nil, utils.InvalidArgumentErrorF("user name is required")
+	}
+	user, err := s.service.CreateUser(ctx, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	return &walletpb.CreateUserResponse{User: &walletpb.User{Id: user.Id, Username: user.Username}}, nil
+}
+
+func (s *Server) CreateWallet(ctx context.Context, req *walletpb.CreateWalletRequest) (*walletpb.CreateWalletResponse, error) {
+	principal, err := middlewares.PrincipalFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.GetUsername() == "" {
+		return nil, utils.InvalidArgumentErrorF("user name is required")
+	}
+	w, err := s.service.CreateWallet(ctx, principal, req.GetUsername(), req.GetCurrency(), "")
+	if err != nil {
+		return nil, err
+	}
+	return &walletpb.CreateWalletResponse{Wallet: wallet(w)}, nil
+}
+
+func (s *Server) GetWallets(ctx context.Context, req *walletpb.GetWalletsRequest) (*walletpb.GetWalletsResponse, error) {
+	principal, err := middlewares.PrincipalFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	userWallets, err := s.service.GetUserWalletBalanceByUserName(ctx, principal, req.GetUsername())
+	if err != nil {
+		return nil, err
+	}
+	wallets := make([]*walletpb.Wallet, 0, len(userWallets.Wallets))
+	for _, w := range userWallets.Wallets {
+		wallets = append(wallets, wallet(w))
+	}
+	return &walletpb.GetWalletsResponse{
+		User:    &walletpb.User{Id: userWallets.User.Id, Username: userWallets.User.Username},
+		Wallets: wallets,
+	}, nil
+}
+
+// ListTransactions
+// Streams transactions as they are read, each sent once its last ledger is read. Rows of a transaction are
+// consecutive.
+func (s *Server) ListTransactions(req *walletpb.ListTransactionsRequest, stream walletpb.WalletService_ListTransactionsServer) error {
+	ctx := stream.Context()
+	principal, err := middlewares.PrincipalFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	var current *walletpb.Transaction
+	err = s.service.StreamTransactions(ctx, principal, req.GetUsername(), func(row userrepo.TransactionLedgerRow) error {
+		if current != nil && current.Id != row.Transaction.Id {
+			if err := stream.Send(current); err != nil {
+				return err
+			}
+			current = nil
+		}
+		if current == nil {
+			current = transaction(row.Transaction)
+		}
+		if row.Ledger != nil {
+			current.Ledgers = append(current.Ledgers, ledger(*row.Ledger))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if current != nil {
+		return stream.Send(current)
+	}
+	return nil
+}
+
+func (s *Server) Deposit(ctx context.Context, req *walletpb.DepositRequest) (*walletpb.DepositResponse, error) {
+	principal, err := middlewares.PrincipalFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := decimal.NewFromString(req.GetAmount())
+	if err != nil {
+		return nil, utils.InvalidAmountError
+	}
+	t, l, err := s.service.Deposit(ctx, principal, req.GetNonce(), req.GetWalletId(), amount)
+	if err != nil {
+		return nil, err
+	}
+	return &walletpb.DepositResponse{Transaction: transaction(t, l)}, nil
+}
+
+func (s *Server) Withdraw(ctx context.Context, req *walletpb.WithdrawRequest) (*walletpb.WithdrawResponse, error) {
+	principal, err := middlewares.PrincipalFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := decimal.NewFromString(req.GetAmount())
+	if err != nil {
+		return nil, utils.InvalidAmountError
+	}
+	t, l, err := s.service.Withdraw(ctx, principal, req.GetNonce(), req.GetWalletId(), amount)
+	if err != nil {
+		return nil, err
+	}
+	return &walletpb.WithdrawResponse{Transaction: transaction(t, l)}, nil
+}
+
+func (s *Server) Transfer(ctx context.Context, req *walletpb.TransferRequest) (*walletpb.TransferResponse, error) {
+	principal, err := middlewares.PrincipalFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := decimal.NewFromString(req.GetAmount())
+	if err != nil {
+		return nil, utils.InvalidAmountError
+	}
+	t, ledgers, err := s.service.Transfer(ctx, principal, req.GetNonce(), req.GetSourceWalletId(), req.GetDestinationWalletId(), amount)
+	if err != nil {
+		return nil, err
+	}
+	return &walletpb.TransferResponse{Transaction: transaction(t, ledgers...)}, nil
+}
+
+func wallet(w userrepo.Wallet) *walletpb.Wallet {
+	return &walletpb.Wallet{
+		Id:            w.Id,
+		UserAccountId: w.UserAccountId,
+		Currency:      w.Currency,
+		Balance:       w.Balance.String(),
+		Status:        string(w.Status),
+	}
+}
+
+func ledger(l userrepo.Ledger) *walletpb.Ledger {
+	return &walletpb.Ledger{
+		Id:            l.Id,
+		WalletId:      l.WalletId,
+		TransactionId: l.TransactionId,
+		EntryType:     l.EntryType,
+		Amount:        l.Amount.String(),
+		CreatedAt:     timestamppb.New(l.CreatedAt),
+		Balance:       l.Balance.String(),
+	}
+}
+
+func transaction(t userrepo.Transaction, ledgers ...userrepo.Ledger) *walletpb.Transaction {
+	pb := &walletpb.Transaction{
+		Id:             t.Id,
+		RequestorId:    t.RequestorId,
+		Nonce:          t.Nonce,
+		Status:         t.Status,
+		Operation:      t.Operation,
+		CreatedAt:      timestamppb.New(t.CreatedAt),
+		Ledgers:        make([]*walletpb.Ledger, 0, len(ledgers)),
+		SourceWalletId: t.MetaData.SourceWalletId,
+	}
+	if t.MetaData.Amount != nil {
+		amount := t.MetaData.Amount.String()
+		pb.Amount = &amount
+	}
+	for _, l := range ledgers {
+		pb.Ledgers = append(pb.Ledgers, ledger(l))
+	}
+	return pb
+}
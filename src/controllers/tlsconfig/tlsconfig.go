@@ -0,0 +1,168 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+type Options struct {
+	CertFile string
+	KeyFile  string
+	// ClientCAFile is the PEM bundle client certificates are verified against.
+	ClientCAFile string
+	// ClientAuth is one of none, optional (verified if presented) or require.
+	ClientAuth string
+	// MinVersion is 1.2 or 1.3.
+	MinVersion string
+	// CipherSuites are names of tls.CipherSuites, i.e. TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256.
+	// Empty for go defaults. Not configurable for TLS 1.3.
+	CipherSuites []string
+}
+
+// Validate
+// Checks options without reading the files.
+func (o Options) Validate() error {
+	var errs []error
+	if (o.CertFile == "") != (o.KeyFile == "") {
+		errs = append(errs, errors.New("cert_file and key_file must be set together"))
+	}
+	clientAuth, err := ParseClientAuth(o.ClientAuth)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if clientAuth != tls.NoClientCert && o.ClientCAFile == "" {
+		errs = append(errs, fmt.Errorf("client_auth %s requires client_ca_file", o.ClientAuth))
+	}
+	if clientAuth == tls.NoClientCert && o.ClientCAFile != "" {
+		errs = append(errs, errors.New("client_ca_file requires client_auth optional or require"))
+	}
+	if (clientAuth != tls.NoClientCert || o.ClientCAFile != "") && o.CertFile == "" {
+		errs = append(errs, errors.New("client certificate verification requires cert_file and key_file"))
+	}
+	if _, err := ParseMinVersion(o.MinVersion); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := ParseCipherSuites(o.CipherSuites); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// Reloader
+// Serves the certificate and client CAs read by the latest successful Reload, so that rotated files are picked up
+// without restarting the server. In-flight connections keep their negotiated certificate.
+type Reloader struct {
+	options Options
+	current atomic.Pointer[tls.Config]
+}
+
+func New(options Options) (*Reloader, error) {
+	if err := options.Validate(); err != nil {
+		return nil, err
+	}
+	r := &Reloader{options: options}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload
+// Reads the certificate, key and client CA files. The previous config is kept if any file is invalid.
+func (r *Reloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.options.CertFile, r.options.KeyFile)
+	if err != nil {
+		return fmt.Errorf("loading certificate: %w", err)
+	}
+
+	config := r.baseConfig()
+	config.Certificates = []tls.Certificate{cert}
+	if r.options.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.options.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("loading client ca: %w", err)
+		}
+		config.ClientCAs = x509.NewCertPool()
+		if !config.ClientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("loading client ca: no certificate in %s", r.options.ClientCAFile)
+		}
+	}
+	r.current.Store(config)
+	return nil
+}
+
+// Config
+// For http.Server.TLSConfig. Handshakes use the config of the latest Reload.
+func (r *Reloader) Config() *tls.Config {
+	config := r.baseConfig()
+	config.GetCertificate = func(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+		return &r.current.Load().Certificates[0], nil
+	}
+	config.GetConfigForClient = func(*tls.ClientHelloInfo) (*tls.Config, error) {
+		return r.current.Load(), nil
+	}
+	return config
+}
+
+func (r *Reloader) baseConfig() *tls.Config {
+	// validated by New
+	minVersion, _ := ParseMinVersion(r.options.MinVersion)
+	cipherSuites, _ := ParseCipherSuites(r.options.CipherSuites)
+	clientAuth, _ := ParseClientAuth(r.options.ClientAuth)
+	return &tls.Config{
+		MinVersion:   minVersion,
+		CipherSuites: cipherSuites,
+		ClientAuth:   clientAuth,
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+}
+
+func ParseMinVersion(v string) (uint16, error) {
+	switch v {
+	case "", "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	}
+	return 0, fmt.Errorf("min_version must be 1.2 or 1.3. got %q", v)
+}
+
+// ParseCipherSuites
+// Only suites of tls.CipherSuites are allowed, insecure suites are rejected.
+func ParseCipherSuites(names []string) ([]uint16, error) {
+	var ids []uint16
+	for _, name := range names {
+		id, ok := cipherSuiteId(name)
+		if !ok {
+			return nil, fmt.Errorf("unsupported cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func cipherSuiteId(name string) (uint16, bool) {
+	for _, suite := range tls.CipherSuites() {
+		if suite.Name == strings.TrimSpace(name) {
+			return suite.ID, true
+		}
+	}
+	return 0, false
+}
+
+func ParseClientAuth(v string) (tls.ClientAuthType, error) {
+	switch v {
+	case "", "none":
+		return tls.NoClientCert, nil
+	case "optional":
+		return tls.VerifyClientCertIfGiven, nil
+	case "require":
+		return tls.RequireAndVerifyClientCert, nil
+	}
+	return tls.NoClientCert, fmt.Errorf("client_auth must be none, optional or require. got %q", v)
+}
@@ -0,0 +1,209 @@
+package tlsconfig_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/controllers/middlewares"
+	"github.com/cryptonlx/crypto/src/controllers/tlsconfig"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		options tlsconfig.Options
+		wantErr string
+	}{
+		{"disabled", tlsconfig.Options{}, ""},
+		{"server only", tlsconfig.Options{CertFile: "c", KeyFile: "k", MinVersion: "1.3"}, ""},
+		{"mtls", tlsconfig.Options{CertFile: "c", KeyFile: "k", ClientCAFile: "ca", ClientAuth: "require"}, ""},
+		{"cert without key", tlsconfig.Options{CertFile: "c"}, "cert_file and key_file"},
+		{"client auth without ca", tlsconfig.Options{CertFile: "c", KeyFile: "k", ClientAuth: "optional"}, "requires client_ca_file"},
+		{"ca without client auth", tlsconfig.Options{CertFile: "c", KeyFile: "k", ClientCAFile: "ca"}, "requires client_auth"},
+		{"mtls without cert", tlsconfig.Options{ClientCAFile: "ca", ClientAuth: "require"}, "requires cert_file"},
+		{"client auth", tlsconfig.Options{ClientAuth: "always"}, "client_auth must be"},
+		{"min version", tlsconfig.Options{MinVersion: "1.1"}, "min_version must be"},
+		{"insecure cipher", tlsconfig.Options{CipherSuites: []string{"TLS_RSA_WITH_RC4_128_SHA"}}, "unsupported cipher suite"},
+		{"cipher", tlsconfig.Options{CipherSuites: []string{"TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.options.Validate()
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Validate() want nil err. got %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Validate() want err containing %q. got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	ca, caKey := newCertificate(t, nil, nil, "Test CA", 1)
+	writePem(t, filepath.Join(dir, "ca.pem"), ca, nil)
+	server, serverKey := newCertificate(t, ca, caKey, "localhost", 2)
+	writePem(t, filepath.Join(dir, "server.pem"), server, serverKey)
+	client, clientKey := newCertificate(t, ca, caKey, "payments-svc", 3)
+	unmappedClient, unmappedClientKey := newCertificate(t, ca, caKey, "unknown-svc", 4)
+
+	reloader, err := tlsconfig.New(tlsconfig.Options{
+		CertFile:     filepath.Join(dir, "server.pem"),
+		KeyFile:      filepath.Join(dir, "server.pem"),
+		ClientCAFile: filepath.Join(dir, "ca.pem"),
+		ClientAuth:   "require",
+	})
+	if err != nil {
+		t.Fatalf("New() want nil err. got %v", err)
+	}
+
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r = middlewares.ContextualizeClientCertPrincipal(r, map[string]string{"payments-svc": "payments"})
+		principal, err := middlewares.PrincipalFromRequest(r)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(principal))
+	}))
+	ts.TLS = reloader.Config()
+	ts.StartTLS()
+	defer ts.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	get := func(cert *x509.Certificate, key *ecdsa.PrivateKey) (*http.Response, error) {
+		config := &tls.Config{RootCAs: roots, ServerName: "localhost"}
+		if cert != nil {
+			config.Certificates = []tls.Certificate{{Certificate: [][]byte{cert.Raw}, PrivateKey: key}}
+		}
+		httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: config}}
+		return httpClient.Get(ts.URL)
+	}
+
+	// no client certificate
+	if _, err := get(nil, nil); err == nil {
+		t.Fatalf("Get() without client certificate want handshake err. got nil")
+	}
+
+	// mapped client certificate
+	resp, err := get(client, clientKey)
+	if err != nil {
+		t.Fatalf("Get() want nil err. got %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "payments" {
+		t.Fatalf("Get() want principal payments. got %q", body)
+	}
+	if serial := resp.TLS.PeerCertificates[0].SerialNumber.Int64(); serial != 2 {
+		t.Fatalf("Get() want server certificate serial 2. got %d", serial)
+	}
+
+	// unmapped client certificate authenticates nobody
+	resp, err = get(unmappedClient, unmappedClientKey)
+	if err != nil {
+		t.Fatalf("Get() want nil err. got %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("Get() unmapped client certificate want 401. got %d", resp.StatusCode)
+	}
+
+	// rotated server certificate
+	rotated, rotatedKey := newCertificate(t, ca, caKey, "localhost", 5)
+	writePem(t, filepath.Join(dir, "server.pem"), rotated, rotatedKey)
+	if err := reloader.Reload(); err != nil {
+		t.Fatalf("Reload() want nil err. got %v", err)
+	}
+	resp, err = get(client, clientKey)
+	if err != nil {
+		t.Fatalf("Get() after Reload() want nil err. got %v", err)
+	}
+	resp.Body.Close()
+	if serial := resp.TLS.PeerCertificates[0].SerialNumber.Int64(); serial != 5 {
+		t.Fatalf("Get() after Reload() want server certificate serial 5. got %d", serial)
+	}
+
+	// invalid files keep the previous certificate
+	if err := os.WriteFile(filepath.Join(dir, "server.pem"), []byte("invalid"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := reloader.Reload(); err == nil {
+		t.Fatalf("Reload() invalid certificate want err. got nil")
+	}
+	resp, err = get(client, clientKey)
+	if err != nil {
+		t.Fatalf("Get() after failed Reload() want nil err. got %v", err)
+	}
+	resp.Body.Close()
+	if serial := resp.TLS.PeerCertificates[0].SerialNumber.Int64(); serial != 5 {
+		t.Fatalf("Get() after failed Reload() want server certificate serial 5. got %d", serial)
+	}
+}
+
+// newCertificate
+// Self-signed CA if parent is nil.
+func newCertificate(t *testing.T, parent *x509.Certificate, parentKey *ecdsa.PrivateKey, commonName string, serial int64) (*x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		DNSNames:     []string{commonName},
+		IPAddresses:  []net.IP{net.IPv4(127, 0, 0, 1)},
+	}
+	if parent == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+		parent, parentKey = template, key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func writePem(t *testing.T, path string, cert *x509.Certificate, key *ecdsa.PrivateKey) {
+	t.Helper()
+	b := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+	if key != nil {
+		der, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b = append(b, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})...)
+	}
+	if err := os.WriteFile(path, b, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
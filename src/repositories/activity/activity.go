@@ -0,0 +1,139 @@
+package activity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Channel
+// Notified by triggers on ledgers and transactions, see schemas 009 and 016.
+const Channel = "wallet_activity"
+
+type Kind string
+
+const (
+	// KindLedger is sent to the members of the wallet.
+	KindLedger Kind = "ledger"
+	// KindTransaction is a transaction status, sent to the requestor.
+	KindTransaction Kind = "transaction"
+)
+
+type Transaction struct {
+	Id        int64     `json:"id"`
+	Nonce     int64     `json:"nonce"`
+	Operation string    `json:"operation"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Activity
+// Payload of a notification. Ledger is set for KindLedger, Transaction for KindTransaction. UserAccountIds are the
+// recipients.
+type Activity struct {
+	Kind           Kind             `json:"kind"`
+	UserAccountIds []int64          `json:"user_account_ids"`
+	Ledger         *userrepo.Ledger `json:"ledger"`
+	Transaction    *Transaction     `json:"transaction"`
+}
+
+type Repo struct {
+	conn *pgxpool.Pool
+}
+
+func New(conn *pgxpool.Pool) *Repo {
+	return &Repo{
+		conn: conn,
+	}
+}
+
+// Listen
+// Holds a connection listening to Channel and calls fn per notification, in commit order, until ctx is done or the
+// connection fails. Notifications committed while not listening are lost, readers resume from ledgers.
+func (r *Repo) Listen(ctx context.Context, fn func(Activity)) error {
+	conn, err := r.conn.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	// the session is LISTENing, it is not returned to the pool
+	defer conn.Hijack().Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "listen "+Channel); err != nil {
+		return err
+	}
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		var a Activity
+		if err := json.Unmarshal([]byte(notification.Payload), &a); err != nil {
+			return err
+		}
+		fn(a)
+	}
+}
+
+// UserAccountId
+// Id of the user by canonical username.
+func (r *Repo) UserAccountId(ctx context.Context, username string) (int64, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.RepeatableRead,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var id int64
+	err = tx.QueryRow(ctx, "select id from user_accounts where username_canonical = $1", username).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, utils.NotFoundErrorF("user")
+	}
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// LedgersAfter
+// Ledgers of wallets the user is a member of with id after afterId, sorted by id, at most limit.
+func (r *Repo) LedgersAfter(ctx context.Context, userAccountId int64, afterId int64, limit int) ([]userrepo.Ledger, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.RepeatableRead,
+	})
+	if err != nil {
+		return []userrepo.Ledger{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `select l.id, l.wallet_id, l.entry_type, l.amount, l.created_at, l.balance, l.transaction_id
+from ledgers l join wallet_memberships m on m.wallet_id = l.wallet_id
+where m.user_account_id = $1 and l.id > $2
+order by l.id
+limit $3`, userAccountId, afterId, limit)
+	if err != nil {
+		return []userrepo.Ledger{}, err
+	}
+	defer rows.Close()
+
+	ledgers := []userrepo.Ledger{}
+	for rows.Next() {
+		var l userrepo.Ledger
+		if err := rows.Scan(&l.Id, &l.WalletId, &l.EntryType, &l.Amount, &l.CreatedAt, &l.Balance, &l.TransactionId); err != nil {
+			return []userrepo.Ledger{}, err
+		}
+		ledgers = append(ledgers, l)
+	}
+	if err := rows.Err(); err != nil {
+		return []userrepo.Ledger{}, err
+	}
+	return ledgers, nil
+}
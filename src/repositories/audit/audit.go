@@ -0,0 +1,136 @@
+package audit
+
+import (
+	"context"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type Outcome string
+
+const (
+	OutcomeSuccess  Outcome = "success"
+	OutcomeRejected Outcome = "rejected"
+	OutcomeFailed   Outcome = "failed"
+)
+
+type Event struct {
+	Id          int64
+	Principal   *string
+	Ip          string
+	Method      string
+	Route       string
+	Path        string
+	WalletId    *int64
+	PayloadHash string
+	Outcome     Outcome
+	HttpStatus  int
+	ErrorCode   *string
+	TraceId     *string
+	CreatedAt   time.Time
+}
+
+// Filter
+// Nil fields are not filtered on. From is inclusive and To is exclusive.
+type Filter struct {
+	Principal *string
+	WalletId  *int64
+	From      *time.Time
+	To        *time.Time
+	Limit     int
+}
+
+type Repo struct {
+	conn *pgxpool.Pool
+}
+
+func New(conn *pgxpool.Pool) *Repo {
+	return &Repo{
+		conn: conn,
+	}
+}
+
+func (r *Repo) Append(ctx context.Context, event Event) (Event, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.ReadCommitted,
+	})
+	if err != nil {
+		return Event{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	event, err = r.appendEvent(ctx, tx, event)
+	if err != nil {
+		return Event{}, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return Event{}, err
+	}
+	return event, nil
+}
+
+func (r *Repo) Events(ctx context.Context, filter Filter) ([]Event, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.RepeatableRead,
+	})
+	if err != nil {
+		return []Event{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	return r.events(ctx, tx, filter)
+}
+
+func (r *Repo) appendEvent(ctx context.Context, tx pgx.Tx, e Event) (Event, error) {
+	if tx == nil {
+		return Event{}, utils.NilTxError
+	}
+	row := tx.QueryRow(ctx, `insert into audit_events(principal, ip, method, route, path, wallet_id, payload_hash, outcome, http_status, error_code, trace_id, created_at)
+		values ($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,now()) returning id, created_at`,
+		e.Principal, e.Ip, e.Method, e.Route, e.Path, e.WalletId, e.PayloadHash, e.Outcome, e.HttpStatus, e.ErrorCode, e.TraceId)
+
+	err := row.Scan(&e.Id, &e.CreatedAt)
+	if err != nil {
+		return Event{}, err
+	}
+	return e, nil
+}
+
+func (r *Repo) events(ctx context.Context, tx pgx.Tx, filter Filter) ([]Event, error) {
+	if tx == nil {
+		return []Event{}, utils.NilTxError
+	}
+
+	rows, err := tx.Query(ctx, `select id, principal, ip, method, route, path, wallet_id, payload_hash, outcome, http_status, error_code, trace_id, created_at
+from audit_events
+where ($1::text is null or principal = $1)
+  and ($2::bigint is null or wallet_id = $2)
+  and ($3::timestamptz is null or created_at >= $3)
+  and ($4::timestamptz is null or created_at < $4)
+order by id desc
+limit $5`, filter.Principal, filter.WalletId, filter.From, filter.To, filter.Limit)
+	if err != nil {
+		return []Event{}, err
+	}
+	defer rows.Close()
+
+	events := []Event{}
+	for rows.Next() {
+		var e Event
+		err := rows.Scan(&e.Id, &e.Principal, &e.Ip, &e.Method, &e.Route, &e.Path, &e.WalletId, &e.PayloadHash, &e.Outcome, &e.HttpStatus, &e.ErrorCode, &e.TraceId, &e.CreatedAt)
+		if err != nil {
+			return []Event{}, err
+		}
+		events = append(events, e)
+	}
+	if err := rows.Err(); err != nil {
+		return []Event{}, err
+	}
+	return events, nil
+}
@@ -0,0 +1,287 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// advisoryLockId
+// Serializes migration runners across server instances.
+const advisoryLockId = 7_365_001
+
+var fileNamePattern = regexp.MustCompile(`^schema_(\d{3})_(up|down)_(\w+)\.sql$`)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// Parse
+// Reads migrations from fsys sorted by version. Every version must have both an up and a down file of the same name.
+func Parse(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, entry := range entries {
+		matches := fileNamePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || matches == nil {
+			continue
+		}
+		version, _ := strconv.ParseInt(matches[1], 10, 64)
+		direction, name := matches[2], matches[3]
+
+		b, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		}
+		if m.Name != name {
+			return nil, fmt.Errorf("migration %03d: name mismatch %q and %q", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.Up = string(b)
+		} else {
+			m.Down = string(b)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %03d_%s: missing up or down file", m.Version, m.Name)
+		}
+		migrations = append(migrations, *m)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int {
+		return int(a.Version - b.Version)
+	})
+	for i, m := range migrations {
+		if m.Version != int64(i+1) {
+			return nil, fmt.Errorf("migration %03d_%s: versions must be contiguous from 001", m.Version, m.Name)
+		}
+	}
+	return migrations, nil
+}
+
+type Migrator struct {
+	conn       *pgxpool.Pool
+	migrations []Migration
+}
+
+func New(conn *pgxpool.Pool, fsys fs.FS) (*Migrator, error) {
+	migrations, err := Parse(fsys)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{
+		conn:       conn,
+		migrations: migrations,
+	}, nil
+}
+
+// LatestVersion
+// Version the embedded migrations bring the schema to.
+func (m *Migrator) LatestVersion() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version
+// Version the database schema is currently at. 0 if no migration is applied.
+// Does not wait for a running migration to complete.
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	var version int64
+	err := m.conn.QueryRow(ctx, `select coalesce(max(version), 0) from schema_migrations`).Scan(&version)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "42P01" /*undefined_table*/ {
+		return 0, nil
+	}
+	return version, err
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	var statuses []MigrationStatus
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			status := MigrationStatus{Migration: migration}
+			if appliedAt, ok := applied[migration.Version]; ok {
+				status.AppliedAt = &appliedAt
+			}
+			statuses = append(statuses, status)
+		}
+		return nil
+	})
+	return statuses, err
+}
+
+// Up
+// Applies pending migrations in order, each in its own transaction. Returns the applied migrations.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var applied []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for _, migration := range m.migrations {
+			if migration.Version <= version {
+				continue
+			}
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, migration.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, `insert into schema_migrations(version, name, applied_at) values ($1,$2,now())`, migration.Version, migration.Name)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %03d_%s up: %w", migration.Version, migration.Name, err)
+			}
+			applied = append(applied, migration)
+		}
+		return nil
+	})
+	return applied, err
+}
+
+// Down
+// Reverts the latest steps applied migrations in reverse order. Returns the reverted migrations.
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var reverted []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		version, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		for i := len(m.migrations) - 1; i >= 0 && len(reverted) < steps; i-- {
+			migration := m.migrations[i]
+			if migration.Version > version {
+				continue
+			}
+			err := pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, migration.Down); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, `delete from schema_migrations where version = $1`, migration.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %03d_%s down: %w", migration.Version, migration.Name, err)
+			}
+			reverted = append(reverted, migration)
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// Baseline
+// Marks migrations up to version as applied without executing them.
+// For databases set up manually from ./schemas before the migration runner existed.
+func (m *Migrator) Baseline(ctx context.Context, version int64) ([]Migration, error) {
+	if version < 1 || version > m.LatestVersion() {
+		return nil, fmt.Errorf("baseline version must be between 1 and %d", m.LatestVersion())
+	}
+	var marked []Migration
+	err := m.withLock(ctx, func(conn *pgxpool.Conn) error {
+		current, err := m.version(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if current != 0 {
+			return fmt.Errorf("baseline requires an unmigrated database. current version %d", current)
+		}
+		return pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+			for _, migration := range m.migrations[:version] {
+				_, err := tx.Exec(ctx, `insert into schema_migrations(version, name, applied_at) values ($1,$2,now())`, migration.Version, migration.Name)
+				if err != nil {
+					return err
+				}
+				marked = append(marked, migration)
+			}
+			return nil
+		})
+	})
+	return marked, err
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgxpool.Conn) error) error {
+	conn, err := m.conn.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	_, err = conn.Exec(ctx, `select pg_advisory_lock($1)`, advisoryLockId)
+	if err != nil {
+		return err
+	}
+	defer conn.Exec(context.WithoutCancel(ctx), `select pg_advisory_unlock($1)`, advisoryLockId)
+
+	_, err = conn.Exec(ctx, `create table if not exists public.schema_migrations
+(
+    version    bigint PRIMARY KEY,
+    name       text                     NOT NULL,
+    applied_at timestamp WITH TIME ZONE NOT NULL
+)`)
+	if err != nil {
+		return err
+	}
+	return fn(conn)
+}
+
+func (m *Migrator) version(ctx context.Context, conn *pgxpool.Conn) (int64, error) {
+	var version int64
+	err := conn.QueryRow(ctx, `select coalesce(max(version), 0) from schema_migrations`).Scan(&version)
+	return version, err
+}
+
+func (m *Migrator) applied(ctx context.Context, conn *pgxpool.Conn) (map[int64]time.Time, error) {
+	rows, err := conn.Query(ctx, `select version, applied_at from schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int64]time.Time{}
+	for rows.Next() {
+		var version int64
+		var appliedAt time.Time
+		if err := rows.Scan(&version, &appliedAt); err != nil {
+			return nil, err
+		}
+		applied[version] = appliedAt
+	}
+	return applied, rows.Err()
+}
@@ -0,0 +1,94 @@
+package migrations
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/cryptonlx/crypto/schemas"
+)
+
+func TestParseEmbeddedSchemas(t *testing.T) {
+	migrations, err := Parse(schemas.FS)
+	if err != nil {
+		t.Fatalf("Parse want nil err. got %v", err)
+	}
+	if len(migrations) == 0 {
+		t.Fatalf("Parse want migrations. got 0")
+	}
+	if migrations[0].Version != 1 || migrations[0].Name != "init" {
+		t.Fatalf("Parse want first migration 001_init. got %03d_%s", migrations[0].Version, migrations[0].Name)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name         string
+		fsys         fstest.MapFS
+		wantVersions []int64
+		wantErr      bool
+	}{
+		{
+			name: "sorted by version",
+			fsys: fstest.MapFS{
+				"schema_002_up_b.sql":   {Data: []byte("up b")},
+				"schema_002_down_b.sql": {Data: []byte("down b")},
+				"schema_001_up_a.sql":   {Data: []byte("up a")},
+				"schema_001_down_a.sql": {Data: []byte("down a")},
+				"schemas.go":            {Data: []byte("package schemas")},
+				"model.png":             {Data: []byte{}},
+			},
+			wantVersions: []int64{1, 2},
+		},
+		{
+			name: "missing down",
+			fsys: fstest.MapFS{
+				"schema_001_up_a.sql": {Data: []byte("up a")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "name mismatch",
+			fsys: fstest.MapFS{
+				"schema_001_up_a.sql":   {Data: []byte("up a")},
+				"schema_001_down_b.sql": {Data: []byte("down b")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "gap in versions",
+			fsys: fstest.MapFS{
+				"schema_001_up_a.sql":   {Data: []byte("up a")},
+				"schema_001_down_a.sql": {Data: []byte("down a")},
+				"schema_003_up_c.sql":   {Data: []byte("up c")},
+				"schema_003_down_c.sql": {Data: []byte("down c")},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migrations, err := Parse(tt.fsys)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse want err. got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse want nil err. got %v", err)
+			}
+			if len(migrations) != len(tt.wantVersions) {
+				t.Fatalf("Parse want %d migrations. got %d", len(tt.wantVersions), len(migrations))
+			}
+			for i, m := range migrations {
+				if m.Version != tt.wantVersions[i] {
+					t.Fatalf("Parse want migrations[%d].version=%d. got %d", i, tt.wantVersions[i], m.Version)
+				}
+				if m.Up == "" || m.Down == "" {
+					t.Fatalf("Parse want migrations[%d] up and down. got %#v", i, m)
+				}
+			}
+		})
+	}
+}
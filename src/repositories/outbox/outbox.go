@@ -0,0 +1,321 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+type EventType string
+
+const (
+	EventUserCreated       EventType = "user.created"
+	EventWalletCreated     EventType = "wallet.created"
+	EventFundsDeposited    EventType = "funds.deposited"
+	EventFundsWithdrawn    EventType = "funds.withdrawn"
+	EventTransferCompleted EventType = "transfer.completed"
+	EventTransactionFailed EventType = "transaction.failed"
+)
+
+// relayLock
+// Advisory lock held by the relaying instance, so that events are relayed by one instance at a time in id order.
+const relayLock = 7_100_043
+
+// Event
+// Domain event. Events of a wallet are published under the wallet lock, so they are relayed in commit order.
+type Event interface {
+	Type() EventType
+	Key() string
+}
+
+// EventPublisher
+// Publishes events in tx, so that they are relayed if and only if tx commits.
+type EventPublisher interface {
+	Publish(ctx context.Context, tx pgx.Tx, events ...Event) error
+}
+
+func WalletKey(walletId int64) string {
+	return "wallet:" + strconv.FormatInt(walletId, 10)
+}
+
+func UserKey(userAccountId int64) string {
+	return "user:" + strconv.FormatInt(userAccountId, 10)
+}
+
+type UserCreated struct {
+	UserAccountId int64  `json:"user_account_id"`
+	Username      string `json:"username"`
+}
+
+func (e UserCreated) Type() EventType { return EventUserCreated }
+func (e UserCreated) Key() string     { return UserKey(e.UserAccountId) }
+
+type WalletCreated struct {
+	WalletId      int64  `json:"wallet_id"`
+	UserAccountId int64  `json:"user_account_id"`
+	Currency      string `json:"currency"`
+}
+
+func (e WalletCreated) Type() EventType { return EventWalletCreated }
+func (e WalletCreated) Key() string     { return WalletKey(e.WalletId) }
+
+// Funds
+// Ledger of a deposit, withdrawal or adjustment. Operation, RequestorId, Nonce and MetaData are the ones of the
+// transaction.
+type Funds struct {
+	TransactionId int64           `json:"transaction_id"`
+	RequestorId   int64           `json:"requestor_id"`
+	Nonce         int64           `json:"nonce"`
+	Operation     string          `json:"operation"`
+	WalletId      int64           `json:"wallet_id"`
+	UserAccountId int64           `json:"user_account_id"`
+	Currency      string          `json:"currency"`
+	LedgerId      int64           `json:"ledger_id"`
+	Amount        decimal.Decimal `json:"amount"`
+	Balance       decimal.Decimal `json:"balance"`
+	MetaData      json.RawMessage `json:"metadata"`
+}
+
+type FundsDeposited struct {
+	Funds
+}
+
+func (e FundsDeposited) Type() EventType { return EventFundsDeposited }
+func (e FundsDeposited) Key() string     { return WalletKey(e.WalletId) }
+
+type FundsWithdrawn struct {
+	Funds
+}
+
+func (e FundsWithdrawn) Type() EventType { return EventFundsWithdrawn }
+func (e FundsWithdrawn) Key() string     { return WalletKey(e.WalletId) }
+
+// TransferCompleted
+// Keyed by the source wallet. Both wallets are locked when published, so it is also ordered among events of the
+// destination wallet by relay order.
+type TransferCompleted struct {
+	TransactionId       int64           `json:"transaction_id"`
+	RequestorId         int64           `json:"requestor_id"`
+	Nonce               int64           `json:"nonce"`
+	SourceWalletId      int64           `json:"source_wallet_id"`
+	DestinationWalletId int64           `json:"destination_wallet_id"`
+	Currency            string          `json:"currency"`
+	Amount              decimal.Decimal `json:"amount"`
+	SourceLedgerId      int64           `json:"source_ledger_id"`
+	SourceBalance       decimal.Decimal `json:"source_balance"`
+	DestinationLedgerId int64           `json:"destination_ledger_id"`
+	DestinationBalance  decimal.Decimal `json:"destination_balance"`
+	MetaData            json.RawMessage `json:"metadata"`
+}
+
+func (e TransferCompleted) Type() EventType { return EventTransferCompleted }
+func (e TransferCompleted) Key() string     { return WalletKey(e.SourceWalletId) }
+
+// TransactionFailed
+// Status is error_<error code>, or rejected or expired for transfers pending approval. Keyed by the source wallet, or
+// the requestor if the transaction has none.
+type TransactionFailed struct {
+	TransactionId  int64           `json:"transaction_id"`
+	RequestorId    int64           `json:"requestor_id"`
+	Nonce          int64           `json:"nonce"`
+	Operation      string          `json:"operation"`
+	Status         string          `json:"status"`
+	SourceWalletId *int64          `json:"source_wallet_id"`
+	MetaData       json.RawMessage `json:"metadata"`
+}
+
+func (e TransactionFailed) Type() EventType { return EventTransactionFailed }
+func (e TransactionFailed) Key() string {
+	if e.SourceWalletId != nil {
+		return WalletKey(*e.SourceWalletId)
+	}
+	return UserKey(e.RequestorId)
+}
+
+// Message
+// Published event as relayed to sinks. Messages are relayed at least once, consumers deduplicate by Id.
+type Message struct {
+	Id        int64           `json:"id"`
+	Type      EventType       `json:"type"`
+	Key       string          `json:"key"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Event
+// Decodes Data into the event of Type.
+func (m Message) Event() (Event, error) {
+	var e Event
+	var err error
+	switch m.Type {
+	case EventUserCreated:
+		e, err = decode[UserCreated](m.Data)
+	case EventWalletCreated:
+		e, err = decode[WalletCreated](m.Data)
+	case EventFundsDeposited:
+		e, err = decode[FundsDeposited](m.Data)
+	case EventFundsWithdrawn:
+		e, err = decode[FundsWithdrawn](m.Data)
+	case EventTransferCompleted:
+		e, err = decode[TransferCompleted](m.Data)
+	case EventTransactionFailed:
+		e, err = decode[TransactionFailed](m.Data)
+	default:
+		return nil, fmt.Errorf("unknown outbox event type %q", m.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("outbox event %d: %w", m.Id, err)
+	}
+	return e, nil
+}
+
+func decode[T Event](data json.RawMessage) (T, error) {
+	var e T
+	err := json.Unmarshal(data, &e)
+	return e, err
+}
+
+// Outbox
+// EventPublisher writing to the outbox_events table.
+type Outbox struct{}
+
+func (Outbox) Publish(ctx context.Context, tx pgx.Tx, events ...Event) error {
+	if tx == nil {
+		return utils.NilTxError
+	}
+	for _, e := range events {
+		_, err := tx.Exec(ctx, `insert into outbox_events(event_type, key, payload, created_at) values ($1,$2,$3,now())`,
+			e.Type(), e.Key(), e)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type Repo struct {
+	conn *pgxpool.Pool
+}
+
+func New(conn *pgxpool.Pool) *Repo {
+	return &Repo{
+		conn: conn,
+	}
+}
+
+// Relay
+// Calls fn per unpublished message in id order, at most limit, and marks the messages fn accepted as published. Stops
+// at the first message fn fails, it is relayed first next time. Returns the number of messages published, 0 if another
+// instance is relaying.
+func (r *Repo) Relay(ctx context.Context, limit int, fn func(Message) error) (int, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	if err := tx.QueryRow(ctx, "select pg_try_advisory_xact_lock($1)", relayLock).Scan(&locked); err != nil {
+		return 0, err
+	}
+	if !locked {
+		return 0, nil
+	}
+
+	rows, err := tx.Query(ctx, `select id, event_type, key, created_at, payload from outbox_events
+where published_at is null order by id limit $1`, limit)
+	if err != nil {
+		return 0, err
+	}
+	messages := []Message{}
+	for rows.Next() {
+		var m Message
+		if err := rows.Scan(&m.Id, &m.Type, &m.Key, &m.CreatedAt, &m.Data); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		messages = append(messages, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	published := make([]int64, 0, len(messages))
+	var fnErr error
+	for _, m := range messages {
+		if fnErr = fn(m); fnErr != nil {
+			break
+		}
+		published = append(published, m.Id)
+	}
+
+	if len(published) > 0 {
+		_, err = tx.Exec(ctx, "update outbox_events set published_at = now() where id = any($1)", published)
+		if err != nil {
+			return 0, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return 0, err
+		}
+	}
+	return len(published), fnErr
+}
+
+// EnqueueWebhooks
+// Calls fn per message not yet enqueued to webhooks in id order, at most limit, and marks the messages enqueued, all in
+// one db transaction, so that fn writes the webhook deliveries of each message exactly once. Messages locked by
+// another instance are skipped. Returns the number of messages enqueued.
+func (r *Repo) EnqueueWebhooks(ctx context.Context, limit int, fn func(ctx context.Context, tx pgx.Tx, m Message) error) (int, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `select id, event_type, key, created_at, payload from outbox_events
+where webhooks_enqueued_at is null order by id limit $1 for update skip locked`, limit)
+	if err != nil {
+		return 0, err
+	}
+	messages := []Message{}
+	for rows.Next() {
+		var m Message
+		if err := rows.Scan(&m.Id, &m.Type, &m.Key, &m.CreatedAt, &m.Data); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		messages = append(messages, m)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(messages) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]int64, 0, len(messages))
+	for _, m := range messages {
+		if err := fn(ctx, tx, m); err != nil {
+			return 0, err
+		}
+		ids = append(ids, m.Id)
+	}
+	_, err = tx.Exec(ctx, "update outbox_events set webhooks_enqueued_at = now() where id = any($1)", ids)
+	if err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
@@ -0,0 +1,484 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+)
+
+// ApprovalPolicy
+// Transfers from WalletId over Threshold are pending approval until RequiredApprovals of Approvers approve, and
+// expire after Timeout.
+type ApprovalPolicy struct {
+	WalletId          int64
+	Threshold         decimal.Decimal
+	RequiredApprovals int
+	Timeout           time.Duration
+	// Approvers are usernames, sorted.
+	Approvers []string
+	UpdatedBy string
+	UpdatedAt time.Time
+}
+
+const (
+	ApprovalDecisionApproved = "approved"
+	ApprovalDecisionRejected = "rejected"
+
+	// TransactionStatusExpired is the final status of transfers not approved by quorum before expires_at.
+	TransactionStatusExpired = "expired"
+
+	// expireTransfersBatch bounds the transfers expired, and locked, by one call of ExpireTransfers.
+	expireTransfersBatch = 1000
+)
+
+// Approval
+// Decision of an approver on a transfer pending approval.
+type Approval struct {
+	TransactionId int64
+	Approver      string
+	// Decision is approved or rejected.
+	Decision  string
+	CreatedAt time.Time
+}
+
+// ApprovalPolicy
+// Approval policy of the wallet, nil if transfers from the wallet need no approval.
+func (r *Repo) ApprovalPolicy(ctx context.Context, walletId int64) (*ApprovalPolicy, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.RepeatableRead,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	return r.approvalPolicy(ctx, tx, walletId)
+}
+
+func (r *Repo) approvalPolicy(ctx context.Context, tx pgx.Tx, walletId int64) (*ApprovalPolicy, error) {
+	if tx == nil {
+		return nil, utils.NilTxError
+	}
+	var p ApprovalPolicy
+	var timeoutSeconds int64
+	err := tx.QueryRow(ctx, `select wallet_id, threshold, required_approvals, timeout_seconds, updated_by, updated_at,
+		array(select ua.username from wallet_approvers a join user_accounts ua on ua.id = a.user_account_id
+			where a.wallet_id = p.wallet_id order by ua.username)
+		from wallet_approval_policies p where wallet_id = $1`, walletId).
+		Scan(&p.WalletId, &p.Threshold, &p.RequiredApprovals, &timeoutSeconds, &p.UpdatedBy, &p.UpdatedAt, &p.Approvers)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	p.Timeout = time.Duration(timeoutSeconds) * time.Second
+	return &p, nil
+}
+
+// SetApprovalPolicy
+// Creates or replaces the approval policy of policy.WalletId, approvers by canonical username. Transfers pending
+// approval keep the policy they were created with. Validation and authorization are the caller's.
+func (r *Repo) SetApprovalPolicy(ctx context.Context, actor string, policy ApprovalPolicy) (ApprovalPolicy, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return ApprovalPolicy{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `insert into wallet_approval_policies(wallet_id, threshold, required_approvals, timeout_seconds, updated_by)
+		values ($1,$2,$3,$4,$5)
+		on conflict (wallet_id) do update set threshold = excluded.threshold, required_approvals = excluded.required_approvals,
+			timeout_seconds = excluded.timeout_seconds, updated_by = excluded.updated_by, updated_at = now()`,
+		policy.WalletId, policy.Threshold, policy.RequiredApprovals, int64(policy.Timeout/time.Second), actor)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			err = utils.ToError(pgErr)
+		}
+		return ApprovalPolicy{}, err
+	}
+	_, err = tx.Exec(ctx, "delete from wallet_approvers where wallet_id = $1", policy.WalletId)
+	if err != nil {
+		return ApprovalPolicy{}, err
+	}
+	for _, username := range policy.Approvers {
+		approver, err := r.user(ctx, tx, username)
+		if err != nil {
+			return ApprovalPolicy{}, err
+		}
+		_, err = tx.Exec(ctx, "insert into wallet_approvers(wallet_id, user_account_id) values ($1,$2) on conflict do nothing",
+			policy.WalletId, approver.Id)
+		if err != nil {
+			return ApprovalPolicy{}, err
+		}
+	}
+
+	p, err := r.approvalPolicy(ctx, tx, policy.WalletId)
+	if err != nil {
+		return ApprovalPolicy{}, err
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return ApprovalPolicy{}, err
+	}
+	return *p, nil
+}
+
+// DeleteApprovalPolicy
+// Transfers pending approval keep the policy they were created with.
+func (r *Repo) DeleteApprovalPolicy(ctx context.Context, walletId int64) error {
+	tag, err := r.conn.Exec(ctx, "delete from wallet_approval_policies where wallet_id = $1", walletId)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return utils.NotFoundErrorF("approval policy")
+	}
+	return nil
+}
+
+// CreatePendingTransfer
+// Records a transfer pending approval by policy, without ledgers. The approvers, required approvals and expiry of
+// policy are kept in the metadata of the transfer. Authorization is the caller's.
+func (r *Repo) CreatePendingTransfer(requestor string, ctx context.Context, nonce int64, sourceWalletId, destinationWalletId int64, amount decimal.Decimal, policy ApprovalPolicy) (Transaction, error) {
+	if !amount.IsPositive() {
+		return Transaction{}, utils.InvalidAmountError
+	}
+
+	user, err := r.User(ctx, requestor)
+	if err != nil {
+		return Transaction{}, err
+	}
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return Transaction{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	// checked again when executed, rejected early so that approvers are not asked for a transfer bound to fail
+	var sameCurrency bool
+	err = tx.QueryRow(ctx, `select s.currency = d.currency from wallets s, wallets d where s.id = $1 and d.id = $2`,
+		sourceWalletId, destinationWalletId).Scan(&sameCurrency)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Transaction{}, utils.NotFoundErrorF("wallet")
+	}
+	if err != nil {
+		return Transaction{}, err
+	}
+	if !sameCurrency {
+		return Transaction{}, utils.CurrencyMismatchError
+	}
+
+	transaction, err := r.insertTransaction(ctx, tx, nonce, user.Id, TransactionStatusPendingApproval, "transfer", map[string]any{
+		"amount":                amount.String(),
+		"source_wallet_id":      sourceWalletId,
+		"destination_wallet_id": destinationWalletId,
+		"approvers":             policy.Approvers,
+		"approvals_required":    policy.RequiredApprovals,
+		"expires_at":            time.Now().Add(policy.Timeout).UTC(),
+	})
+	if err != nil {
+		return Transaction{}, err
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return Transaction{}, err
+	}
+	return transaction, nil
+}
+
+// ApproveTransfer
+// Records the approval of approver, one of the approvers of the transfer other than its requestor. Once approved by
+// the required approvals, the transfer is executed like Transfer in the same database transaction as the approval,
+// and its ledgers are returned. If it fails, the approval is recorded with the failed transfer. Transfers past
+// expires_at are set expired instead.
+func (r *Repo) ApproveTransfer(approver string, ctx context.Context, id int64) (Transaction, []Approval, []Ledger, error) {
+	user, err := r.User(ctx, approver)
+	if err != nil {
+		return Transaction{}, nil, nil, err
+	}
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return Transaction{}, nil, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	transaction, err := r.pendingTransferForUpdate(ctx, tx, id)
+	if err != nil {
+		return Transaction{}, nil, nil, err
+	}
+	if err := r.expireTransfer(ctx, tx, transaction); err != nil {
+		return Transaction{}, nil, nil, err
+	}
+	if !slices.Contains(transaction.MetaData.Approvers, user.Username) {
+		return Transaction{}, nil, nil, utils.ForbiddenErrorF("not an approver of the transfer")
+	}
+	if transaction.RequestorId == user.Id {
+		return Transaction{}, nil, nil, utils.ForbiddenErrorF("transfer must be approved by another approver than its requestor")
+	}
+	err = r.insertApproval(ctx, tx, transaction.Id, user.Id, ApprovalDecisionApproved)
+	if err != nil {
+		return Transaction{}, nil, nil, err
+	}
+	approvals, err := r.approvals(ctx, tx, transaction.Id)
+	if err != nil {
+		return Transaction{}, nil, nil, err
+	}
+	if transaction.MetaData.ApprovalsRequired == nil || len(approvals) < *transaction.MetaData.ApprovalsRequired {
+		err = tx.Commit(ctx)
+		if err != nil {
+			return Transaction{}, nil, nil, err
+		}
+		return transaction, approvals, []Ledger{}, nil
+	}
+
+	// quorum is reached. the transfer runs in a savepoint, as a failed balance update aborts it, so that the approval
+	// is committed with the transfer either succeeded or failed, never left in between
+	savepoint, err := tx.Begin(ctx)
+	if err != nil {
+		return Transaction{}, nil, nil, err
+	}
+	ledgers, failed, err := r.transferTx(ctx, savepoint, transaction, *transaction.MetaData.SourceWalletId,
+		*transaction.MetaData.DestinationWalletId, *transaction.MetaData.Amount)
+	if err != nil {
+		return Transaction{}, nil, nil, err
+	}
+	if failed != nil {
+		if err := savepoint.Rollback(ctx); err != nil {
+			return Transaction{}, nil, nil, err
+		}
+		if err := r.failTransactionTx(ctx, tx, transaction, failed); err != nil {
+			return Transaction{}, nil, nil, errors.Join(err, failed)
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return Transaction{}, nil, nil, errors.Join(err, failed)
+		}
+		return Transaction{}, nil, nil, failed
+	}
+	if err := savepoint.Commit(ctx); err != nil {
+		return Transaction{}, nil, nil, err
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return Transaction{}, nil, nil, err
+	}
+	transaction.Status = "success"
+	return transaction, approvals, ledgers, nil
+}
+
+// RejectTransfer
+// Sets the transfer pending approval to rejected. actor is one of its approvers, recorded as a rejection, or its
+// requestor cancelling it. A single rejection vetoes the transfer whatever the approvals so far: approvers are the
+// designated controls of the wallet, and a transfer one of them objects to is not executed on the approval of others.
+func (r *Repo) RejectTransfer(actor string, ctx context.Context, id int64) (Transaction, []Approval, error) {
+	user, err := r.User(ctx, actor)
+	if err != nil {
+		return Transaction{}, nil, err
+	}
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return Transaction{}, nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	transaction, err := r.pendingTransferForUpdate(ctx, tx, id)
+	if err != nil {
+		return Transaction{}, nil, err
+	}
+	if err := r.expireTransfer(ctx, tx, transaction); err != nil {
+		return Transaction{}, nil, err
+	}
+	isApprover := slices.Contains(transaction.MetaData.Approvers, user.Username)
+	if !isApprover && transaction.RequestorId != user.Id {
+		return Transaction{}, nil, utils.ForbiddenErrorF("not an approver nor the requestor of the transfer")
+	}
+	if isApprover && transaction.RequestorId != user.Id {
+		err = r.insertApproval(ctx, tx, transaction.Id, user.Id, ApprovalDecisionRejected)
+		if err != nil {
+			return Transaction{}, nil, err
+		}
+	}
+	transaction.Status = TransactionStatusRejected
+	err = r.endTransactionTx(ctx, tx, transaction, transaction.Status)
+	if err != nil {
+		return Transaction{}, nil, err
+	}
+	approvals, err := r.approvals(ctx, tx, transaction.Id)
+	if err != nil {
+		return Transaction{}, nil, err
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return Transaction{}, nil, err
+	}
+	return transaction, approvals, nil
+}
+
+// PendingApprovals
+// Transfers pending approval of username, not yet decided by username nor expired, sorted by oldest.
+func (r *Repo) PendingApprovals(ctx context.Context, username string) ([]Transaction, error) {
+	user, err := r.User(ctx, username)
+	if err != nil {
+		return []Transaction{}, err
+	}
+	rows, err := r.conn.Query(ctx, `select t.id, t.requestor_id, t.nonce, t.status, t.operation, t.created_at, t.metadata
+		from transactions t
+		where t.status = $1 and t.operation = 'transfer' and t.metadata->'approvers' ? $2
+			and (t.metadata->>'expires_at')::timestamptz > now()
+			and not exists (select 1 from transaction_approvals a where a.transaction_id = t.id and a.user_account_id = $3)
+		order by t.created_at, t.id`, TransactionStatusPendingApproval, user.Username, user.Id)
+	if err != nil {
+		return []Transaction{}, err
+	}
+	defer rows.Close()
+
+	transactions := []Transaction{}
+	for rows.Next() {
+		var t Transaction
+		err := rows.Scan(&t.Id, &t.RequestorId, &t.Nonce, &t.Status, &t.Operation, &t.CreatedAt, &t.MetaData)
+		if err != nil {
+			return []Transaction{}, err
+		}
+		transactions = append(transactions, t)
+	}
+	if err := rows.Err(); err != nil {
+		return []Transaction{}, err
+	}
+	return transactions, nil
+}
+
+// ExpireTransfers
+// Sets transfers pending approval past expires_at to expired, with their failure events, at most expireTransfersBatch
+// per call. Transfers being decided are skipped, the decision expires them. Returns the number of transfers expired.
+func (r *Repo) ExpireTransfers(ctx context.Context) (int64, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `select id, requestor_id, nonce, status, operation, created_at, metadata
+		from transactions
+		where status = $1 and operation = 'transfer' and (metadata->>'expires_at')::timestamptz <= now()
+		order by id limit $2 FOR UPDATE SKIP LOCKED`, TransactionStatusPendingApproval, expireTransfersBatch)
+	if err != nil {
+		return 0, err
+	}
+	var transactions []Transaction
+	for rows.Next() {
+		var t Transaction
+		err := rows.Scan(&t.Id, &t.RequestorId, &t.Nonce, &t.Status, &t.Operation, &t.CreatedAt, &t.MetaData)
+		if err != nil {
+			rows.Close()
+			return 0, err
+		}
+		transactions = append(transactions, t)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, t := range transactions {
+		if err := r.endTransactionTx(ctx, tx, t, TransactionStatusExpired); err != nil {
+			return 0, err
+		}
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(transactions)), nil
+}
+
+// pendingTransferForUpdate
+// Locks the transfer so that decisions on it are serialized.
+func (r *Repo) pendingTransferForUpdate(ctx context.Context, tx pgx.Tx, id int64) (Transaction, error) {
+	if tx == nil {
+		return Transaction{}, utils.NilTxError
+	}
+	var t Transaction
+	err := tx.QueryRow(ctx, `select id, requestor_id, nonce, status, operation, created_at, metadata
+		from transactions where id = $1 and operation = 'transfer' FOR UPDATE`, id).
+		Scan(&t.Id, &t.RequestorId, &t.Nonce, &t.Status, &t.Operation, &t.CreatedAt, &t.MetaData)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Transaction{}, utils.NotFoundErrorF("transaction")
+	}
+	if err != nil {
+		return Transaction{}, err
+	}
+	if t.Status == TransactionStatusExpired {
+		return Transaction{}, utils.ApprovalExpiredError
+	}
+	if t.Status != TransactionStatusPendingApproval {
+		return Transaction{}, utils.InvalidArgumentErrorF("transaction is %s, not pending approval", t.Status)
+	}
+	return t, nil
+}
+
+// expireTransfer
+// Sets the locked transfer to expired and commits tx if past expires_at, returning ApprovalExpiredError.
+func (r *Repo) expireTransfer(ctx context.Context, tx pgx.Tx, transaction Transaction) error {
+	if transaction.MetaData.ExpiresAt == nil || time.Now().Before(*transaction.MetaData.ExpiresAt) {
+		return nil
+	}
+	err := r.endTransactionTx(ctx, tx, transaction, TransactionStatusExpired)
+	if err != nil {
+		return err
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return err
+	}
+	return utils.ApprovalExpiredError
+}
+
+func (r *Repo) insertApproval(ctx context.Context, tx pgx.Tx, transactionId int64, userId int64, decision string) error {
+	if tx == nil {
+		return utils.NilTxError
+	}
+	_, err := tx.Exec(ctx, "insert into transaction_approvals(transaction_id, user_account_id, decision) values ($1,$2,$3)",
+		transactionId, userId, decision)
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		err = utils.ToError(pgErr)
+	}
+	return err
+}
+
+// approvals
+// Decisions on the transaction sorted by oldest.
+func (r *Repo) approvals(ctx context.Context, tx pgx.Tx, transactionId int64) ([]Approval, error) {
+	if tx == nil {
+		return nil, utils.NilTxError
+	}
+	rows, err := tx.Query(ctx, `select a.transaction_id, ua.username, a.decision, a.created_at
+		from transaction_approvals a join user_accounts ua on ua.id = a.user_account_id
+		where a.transaction_id = $1 order by a.created_at, ua.username`, transactionId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	approvals := []Approval{}
+	for rows.Next() {
+		var a Approval
+		err := rows.Scan(&a.TransactionId, &a.Approver, &a.Decision, &a.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		approvals = append(approvals, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
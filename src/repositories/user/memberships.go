@@ -0,0 +1,244 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+)
+
+// MemberRole
+// Stored per member of a wallet in wallet_memberships.role.
+type MemberRole string
+
+const (
+	// MemberRoleOwner operates the wallet and manages its members.
+	MemberRoleOwner MemberRole = "owner"
+	// MemberRoleSpender operates the wallet, debits up to the spend limit per spend window in total.
+	MemberRoleSpender MemberRole = "spender"
+	// MemberRoleViewer reads the wallet.
+	MemberRoleViewer MemberRole = "viewer"
+)
+
+var MemberRoles = []MemberRole{MemberRoleOwner, MemberRoleSpender, MemberRoleViewer}
+
+// Membership
+// Role of a user on a wallet. The creator of a wallet is its first owner.
+type Membership struct {
+	WalletId      int64
+	UserAccountId int64
+	Username      string
+	Role          MemberRole
+	// SpendLimit is the maximum total of the debits of a spender per spend window, see spent. nil for other roles.
+	SpendLimit *decimal.Decimal
+	UpdatedBy  string
+	UpdatedAt  time.Time
+}
+
+// allows
+// Checks that the member may move amount in or out of the wallet, having debited spent in the current spend window. A
+// nil member is not a member.
+func (m *Membership) allows(entryType string, amount decimal.Decimal, spent decimal.Decimal) error {
+	if m == nil || m.Role == MemberRoleViewer {
+		return utils.ForbiddenErrorF("requestor is not an owner nor spender of the wallet")
+	}
+	if entryType == "debit" && m.SpendLimit != nil && spent.Add(amount).GreaterThan(*m.SpendLimit) {
+		return utils.SpendLimitExceededError
+	}
+	return nil
+}
+
+// spent
+// Total of the successful debits of the wallet requested by the member in the current spend window, the UTC day. Zero
+// for members without spend limit. Called under the wallet lock, so that concurrent debits of a spender are summed.
+func (r *Repo) spent(ctx context.Context, tx pgx.Tx, m *Membership) (decimal.Decimal, error) {
+	if m == nil || m.SpendLimit == nil {
+		return decimal.Zero, nil
+	}
+	var spent decimal.Decimal
+	err := tx.QueryRow(ctx, `select coalesce(sum(l.amount), 0) from ledgers l join transactions t on t.id = l.transaction_id
+		where l.wallet_id=$1 and l.entry_type='debit' and t.requestor_id=$2 and l.created_at >= date_trunc('day', now(), 'UTC')`,
+		m.WalletId, m.UserAccountId).Scan(&spent)
+	return spent, err
+}
+
+const membershipColumns = "m.wallet_id, m.user_account_id, ua.username, m.role, m.spend_limit, m.updated_by, m.updated_at"
+
+func (m *Membership) scanTargets() []any {
+	return []any{&m.WalletId, &m.UserAccountId, &m.Username, &m.Role, &m.SpendLimit, &m.UpdatedBy, &m.UpdatedAt}
+}
+
+// Membership
+// Membership of the user in the wallet, nil if the user is not a member. The wallet must exist.
+func (r *Repo) Membership(ctx context.Context, walletId int64, userId int64) (*Membership, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.RepeatableRead,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var exists bool
+	err = tx.QueryRow(ctx, "select exists(select 1 from wallets where id=$1)", walletId).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, utils.NotFoundErrorF("wallet")
+	}
+	return r.membership(ctx, tx, walletId, userId)
+}
+
+func (r *Repo) membership(ctx context.Context, tx pgx.Tx, walletId int64, userId int64) (*Membership, error) {
+	if tx == nil {
+		return nil, utils.NilTxError
+	}
+	var m Membership
+	err := tx.QueryRow(ctx, "select "+membershipColumns+` from wallet_memberships m
+		join user_accounts ua on ua.id = m.user_account_id where m.wallet_id=$1 and m.user_account_id=$2`, walletId, userId).
+		Scan(m.scanTargets()...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Memberships
+// Members of the wallet sorted by username.
+func (r *Repo) Memberships(ctx context.Context, walletId int64) ([]Membership, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.RepeatableRead,
+	})
+	if err != nil {
+		return []Membership{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	return r.memberships(ctx, tx, walletId)
+}
+
+func (r *Repo) memberships(ctx context.Context, tx pgx.Tx, walletId int64) ([]Membership, error) {
+	rows, err := tx.Query(ctx, "select "+membershipColumns+` from wallet_memberships m
+		join user_accounts ua on ua.id = m.user_account_id where m.wallet_id=$1 order by ua.username`, walletId)
+	if err != nil {
+		return []Membership{}, err
+	}
+	defer rows.Close()
+
+	memberships := []Membership{}
+	for rows.Next() {
+		var m Membership
+		if err := rows.Scan(m.scanTargets()...); err != nil {
+			return []Membership{}, err
+		}
+		memberships = append(memberships, m)
+	}
+	if err := rows.Err(); err != nil {
+		return []Membership{}, err
+	}
+	return memberships, nil
+}
+
+// SetMembership
+// Creates or replaces the membership of membership.Username, by canonical username, in membership.WalletId. Locks the
+// wallet so that operations in flight on it complete first, and so that it keeps an owner. Validation and
+// authorization are the caller's.
+func (r *Repo) SetMembership(ctx context.Context, actor string, membership Membership) (Membership, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return Membership{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := r.userWalletByWalletIdForUpdate(ctx, tx, membership.WalletId); err != nil {
+		return Membership{}, err
+	}
+	user, err := r.user(ctx, tx, membership.Username)
+	if err != nil {
+		return Membership{}, err
+	}
+	_, err = tx.Exec(ctx, `insert into wallet_memberships(wallet_id, user_account_id, role, spend_limit, updated_by)
+		values ($1,$2,$3,$4,$5)
+		on conflict (wallet_id, user_account_id) do update set role = excluded.role, spend_limit = excluded.spend_limit,
+			updated_by = excluded.updated_by, updated_at = now()`,
+		membership.WalletId, user.Id, membership.Role, membership.SpendLimit, actor)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			err = utils.ToError(pgErr)
+		}
+		return Membership{}, err
+	}
+	if err := r.checkOwned(ctx, tx, membership.WalletId); err != nil {
+		return Membership{}, err
+	}
+
+	m, err := r.membership(ctx, tx, membership.WalletId, user.Id)
+	if err != nil {
+		return Membership{}, err
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return Membership{}, err
+	}
+	return *m, nil
+}
+
+// DeleteMembership
+// Removes username, by canonical username, from the members of the wallet. Same locking as SetMembership.
+func (r *Repo) DeleteMembership(ctx context.Context, walletId int64, username string) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := r.userWalletByWalletIdForUpdate(ctx, tx, walletId); err != nil {
+		return err
+	}
+	user, err := r.user(ctx, tx, username)
+	if err != nil {
+		return err
+	}
+	tag, err := tx.Exec(ctx, "delete from wallet_memberships where wallet_id=$1 and user_account_id=$2", walletId, user.Id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return utils.NotFoundErrorF("membership")
+	}
+	if err := r.checkOwned(ctx, tx, walletId); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// checkOwned
+// Wallets keep at least one owner, who can manage the other members.
+func (r *Repo) checkOwned(ctx context.Context, tx pgx.Tx, walletId int64) error {
+	var owned bool
+	err := tx.QueryRow(ctx, "select exists(select 1 from wallet_memberships where wallet_id=$1 and role=$2)",
+		walletId, MemberRoleOwner).Scan(&owned)
+	if err != nil {
+		return err
+	}
+	if !owned {
+		return utils.InvalidArgumentErrorF("wallet must keep an owner")
+	}
+	return nil
+}
+
+func (r *Repo) insertMembership(ctx context.Context, tx pgx.Tx, walletId int64, userId int64, role MemberRole, actor string) error {
+	_, err := tx.Exec(ctx, "insert into wallet_memberships(wallet_id, user_account_id, role, updated_by) values ($1,$2,$3,$4)",
+		walletId, userId, role, actor)
+	return err
+}
@@ -0,0 +1,275 @@
+package user
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	PaymentRequestStatusPending   = "pending"
+	PaymentRequestStatusPaid      = "paid"
+	PaymentRequestStatusCancelled = "cancelled"
+	// PaymentRequestStatusExpired is reported for pending requests past expires_at, it is not stored.
+	PaymentRequestStatusExpired = "expired"
+
+	// PaymentRequestsIncoming lists requests to be paid by the user, PaymentRequestsOutgoing requests of the user.
+	PaymentRequestsIncoming = "incoming"
+	PaymentRequestsOutgoing = "outgoing"
+)
+
+// PaymentRequest
+// Request of Requester to be paid Amount into WalletId by Payer, before ExpiresAt. TransactionId is the transfer
+// paying it.
+type PaymentRequest struct {
+	Id int64
+	// Requester and Payer are display usernames of the users RequesterId and PayerId.
+	RequesterId   int64
+	Requester     string
+	PayerId       int64
+	Payer         string
+	WalletId      int64
+	Amount        decimal.Decimal
+	Currency      string
+	Memo          string
+	Status        string
+	ExpiresAt     time.Time
+	TransactionId *int64
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+// paymentRequestStatus
+// Status of payment_requests aliased p, expired if pending past expires_at.
+const paymentRequestStatus = "(case when p.status = 'pending' and p.expires_at <= now() then 'expired' else p.status end)"
+
+const paymentRequestColumns = "p.id, p.requester_id, r.username, p.payer_id, py.username, p.wallet_id, p.amount, p.currency, p.memo, " +
+	paymentRequestStatus + ", p.expires_at, p.transaction_id, p.created_at, p.updated_at"
+
+const paymentRequestJoins = `payment_requests p join user_accounts r on r.id = p.requester_id
+	join user_accounts py on py.id = p.payer_id`
+
+func (p *PaymentRequest) scanTargets() []any {
+	return []any{&p.Id, &p.RequesterId, &p.Requester, &p.PayerId, &p.Payer, &p.WalletId, &p.Amount, &p.Currency, &p.Memo, &p.Status, &p.ExpiresAt,
+		&p.TransactionId, &p.CreatedAt, &p.UpdatedAt}
+}
+
+// CreatePaymentRequest
+// Records the request of requester to be paid by request.Payer, by canonical username, into request.WalletId, whose
+// currency must be request.Currency. Validation and authorization are the caller's.
+func (r *Repo) CreatePaymentRequest(ctx context.Context, requester string, request PaymentRequest) (PaymentRequest, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	user, err := r.user(ctx, tx, requester)
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+	payer, err := r.user(ctx, tx, request.Payer)
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+	var currency string
+	err = tx.QueryRow(ctx, "select currency from wallets where id=$1", request.WalletId).Scan(&currency)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return PaymentRequest{}, utils.NotFoundErrorF("wallet")
+	}
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+	if currency != request.Currency {
+		return PaymentRequest{}, utils.CurrencyMismatchError
+	}
+
+	var id int64
+	err = tx.QueryRow(ctx, `insert into payment_requests(requester_id, payer_id, wallet_id, amount, currency, memo, expires_at)
+		values ($1,$2,$3,$4,$5,$6,$7) returning id`,
+		user.Id, payer.Id, request.WalletId, request.Amount, request.Currency, request.Memo, request.ExpiresAt).Scan(&id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			err = utils.ToError(pgErr)
+		}
+		return PaymentRequest{}, err
+	}
+	p, err := r.paymentRequest(ctx, tx, id, false)
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+	return p, nil
+}
+
+// PaymentRequest
+// Payment request by id.
+func (r *Repo) PaymentRequest(ctx context.Context, id int64) (PaymentRequest, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.RepeatableRead,
+	})
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	return r.paymentRequest(ctx, tx, id, false)
+}
+
+// paymentRequest
+// Locks the payment request if forUpdate.
+func (r *Repo) paymentRequest(ctx context.Context, tx pgx.Tx, id int64, forUpdate bool) (PaymentRequest, error) {
+	if tx == nil {
+		return PaymentRequest{}, utils.NilTxError
+	}
+	query := "select " + paymentRequestColumns + " from " + paymentRequestJoins + " where p.id=$1"
+	if forUpdate {
+		query += " FOR UPDATE OF p"
+	}
+	var p PaymentRequest
+	err := tx.QueryRow(ctx, query, id).Scan(p.scanTargets()...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return PaymentRequest{}, utils.NotFoundErrorF("payment request")
+	}
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+	return p, nil
+}
+
+// PaymentRequests
+// Incoming or outgoing payment requests of username, by canonical username, sorted by newest. All statuses if status is
+// empty.
+func (r *Repo) PaymentRequests(ctx context.Context, username string, direction string, status string) ([]PaymentRequest, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.RepeatableRead,
+	})
+	if err != nil {
+		return []PaymentRequest{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	user, err := r.user(ctx, tx, username)
+	if err != nil {
+		return []PaymentRequest{}, err
+	}
+	column := "p.payer_id"
+	if direction == PaymentRequestsOutgoing {
+		column = "p.requester_id"
+	}
+	rows, err := tx.Query(ctx, "select "+paymentRequestColumns+" from "+paymentRequestJoins+" where "+column+"=$1 and ($2 = '' or "+
+		paymentRequestStatus+" = $2) order by p.id desc", user.Id, status)
+	if err != nil {
+		return []PaymentRequest{}, err
+	}
+	defer rows.Close()
+
+	requests := []PaymentRequest{}
+	for rows.Next() {
+		var p PaymentRequest
+		if err := rows.Scan(p.scanTargets()...); err != nil {
+			return []PaymentRequest{}, err
+		}
+		requests = append(requests, p)
+	}
+	if err := rows.Err(); err != nil {
+		return []PaymentRequest{}, err
+	}
+	return requests, nil
+}
+
+// CancelPaymentRequest
+// Sets the pending request of requester to cancelled. Authorization is the caller's.
+func (r *Repo) CancelPaymentRequest(requester string, ctx context.Context, id int64) (PaymentRequest, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	user, err := r.user(ctx, tx, requester)
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+	p, err := r.paymentRequest(ctx, tx, id, true)
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+	if p.RequesterId != user.Id {
+		return PaymentRequest{}, utils.ForbiddenErrorF("payment request must be cancelled by its requester")
+	}
+	if p.Status != PaymentRequestStatusPending {
+		return PaymentRequest{}, utils.InvalidArgumentErrorF("payment request is %s, not pending", p.Status)
+	}
+	_, err = tx.Exec(ctx, "update payment_requests set status=$1, updated_at=now() where id=$2", PaymentRequestStatusCancelled, id)
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+	p, err = r.paymentRequest(ctx, tx, id, false)
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return PaymentRequest{}, err
+	}
+	return p, nil
+}
+
+// PayPaymentRequest
+// Transfers the amount of the pending request from sourceWalletId of payer to the wallet of the request, like
+// Transfer. The request is set paid in the same database transaction as the ledgers, the transfer fails if the request
+// is no longer pending. Authorization is the caller's.
+func (r *Repo) PayPaymentRequest(payer string, ctx context.Context, nonce int64, id int64, sourceWalletId int64) (Transaction, []Ledger, error) {
+	user, err := r.User(ctx, payer)
+	if err != nil {
+		return Transaction{}, []Ledger{}, err
+	}
+	request, err := r.PaymentRequest(ctx, id)
+	if err != nil {
+		return Transaction{}, []Ledger{}, err
+	}
+	// checked again with the ledgers, rejected early so that no transaction is recorded for a request already settled
+	if request.PayerId != user.Id {
+		return Transaction{}, []Ledger{}, utils.ForbiddenErrorF("payment request must be paid by its payer")
+	}
+	if request.Status != PaymentRequestStatusPending {
+		return Transaction{}, []Ledger{}, utils.InvalidArgumentErrorF("payment request is %s, not pending", request.Status)
+	}
+
+	transaction, err := r.newTransaction(ctx, nonce, user.Id, "transfer", map[string]any{
+		"amount":                request.Amount.String(),
+		"source_wallet_id":      sourceWalletId,
+		"destination_wallet_id": request.WalletId,
+		"payment_request_id":    request.Id,
+	})
+	if err != nil {
+		return Transaction{}, []Ledger{}, err
+	}
+	return r.transfer(ctx, transaction, sourceWalletId, request.WalletId, request.Amount)
+}
+
+// settlePaymentRequest
+// Sets the payment request paid by the transfer, under the lock of the wallets of the transfer.
+func (r *Repo) settlePaymentRequest(ctx context.Context, tx pgx.Tx, id int64, transaction Transaction) error {
+	p, err := r.paymentRequest(ctx, tx, id, true)
+	if err != nil {
+		return err
+	}
+	if p.Status != PaymentRequestStatusPending {
+		return utils.InvalidArgumentErrorF("payment request is %s, not pending", p.Status)
+	}
+	_, err = tx.Exec(ctx, "update payment_requests set status=$1, transaction_id=$2, updated_at=now() where id=$3",
+		PaymentRequestStatusPaid, transaction.Id, id)
+	return err
+}
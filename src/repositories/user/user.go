@@ -28,13 +28,111 @@ const (
 type User struct {
 	Id       int64
 	Username string
+	// Role is one of policy.Roles.
+	Role   string
+	Frozen bool
+
+	DisplayName *string
+	Email       *string
+	// Status is one of policy.AccountStatuses.
+	Status    string
+	KycTier   int
+	CreatedAt time.Time
+}
+
+// userColumns
+// Columns of user_accounts aliased ua, in the order of User.scanTargets.
+const userColumns = "ua.id, ua.username, ua.role, ua.frozen, ua.display_name, ua.email, ua.status, ua.kyc_tier, ua.created_at"
+
+func (u *User) scanTargets() []any {
+	return []any{&u.Id, &u.Username, &u.Role, &u.Frozen, &u.DisplayName, &u.Email, &u.Status, &u.KycTier, &u.CreatedAt}
+}
+
+// ProfileUpdate
+// Nil fields are left unchanged.
+type ProfileUpdate struct {
+	DisplayName *string
+	Email       *string
+	Status      *string
+	KycTier     *int
+}
+
+// WalletStatus
+// Lifecycle state of a wallet. Closed is terminal.
+type WalletStatus string
+
+const (
+	WalletStatusActive WalletStatus = "active"
+	// WalletStatusFrozen rejects credits and debits.
+	WalletStatusFrozen WalletStatus = "frozen"
+	// WalletStatusDebitFrozen rejects debits, credits are allowed.
+	WalletStatusDebitFrozen WalletStatus = "debit_frozen"
+	// WalletStatusClosed rejects credits, debits and adjustments. Requires zero balance.
+	WalletStatusClosed WalletStatus = "closed"
+)
+
+var WalletStatuses = []WalletStatus{WalletStatusActive, WalletStatusFrozen, WalletStatusDebitFrozen, WalletStatusClosed}
+
+// allows
+// Whether a wallet in status may be credited or debited, entryType is credit or debit.
+func (s WalletStatus) allows(entryType string) error {
+	switch s {
+	case WalletStatusClosed:
+		return utils.WalletClosedError
+	case WalletStatusFrozen:
+		return utils.WalletFrozenError
+	case WalletStatusDebitFrozen:
+		if entryType == "debit" {
+			return utils.WalletFrozenError
+		}
+	}
+	return nil
 }
 
 type Wallet struct {
-	Id            int64
+	Id int64
+	// UserAccountId is the creator of the wallet, its first owner member.
 	UserAccountId int64
-	Currency      string
-	Balance       decimal.Decimal
+	// Name is unique per creator and currency.
+	Name     string
+	Currency string
+	Balance  decimal.Decimal
+	Status   WalletStatus
+}
+
+// walletColumns
+// Columns of wallets aliased w, in the order of Wallet.scanTargets.
+const walletColumns = "w.id, w.user_account_id, w.name, w.currency, w.balance, w.status"
+
+func (w *Wallet) scanTargets() []any {
+	return []any{&w.Id, &w.UserAccountId, &w.Name, &w.Currency, &w.Balance, &w.Status}
+}
+
+// WalletStatusChange
+// Append-only history of wallet status transitions.
+type WalletStatusChange struct {
+	Id         int64
+	WalletId   int64
+	FromStatus WalletStatus
+	ToStatus   WalletStatus
+	Reason     string
+	Actor      string
+	CreatedAt  time.Time
+}
+
+// Statement
+// Ledgers of a wallet created in [From, To) with the balances around them.
+type Statement struct {
+	Wallet Wallet
+	From   time.Time
+	To     time.Time
+	// OpeningBalance is the balance after the last ledger before From, zero if none.
+	OpeningBalance decimal.Decimal
+	// Ledgers are sorted by oldest, each with the running balance after it.
+	Ledgers        []Ledger
+	TotalCredits   decimal.Decimal
+	TotalDebits    decimal.Decimal
+	ClosingBalance decimal.Decimal
 }
 
 type UserWallet struct {
@@ -47,8 +145,23 @@ type UserWallets struct {
 }
 
 type TransactionMetaData struct {
-	SourceWalletId *int64           `json:"source_wallet_id" example:"1"`
-	Amount         *decimal.Decimal `json:"amount" example:"1"`
+	SourceWalletId      *int64           `json:"source_wallet_id" example:"1"`
+	DestinationWalletId *int64           `json:"destination_wallet_id,omitempty" example:"2"`
+	Amount              *decimal.Decimal `json:"amount" example:"1"`
+	// Approvers, ApprovalsRequired and ExpiresAt are set for transfers pending approval by the policy of the source
+	// wallet.
+	Approvers         []string   `json:"approvers,omitempty" example:"grace"`
+	ApprovalsRequired *int       `json:"approvals_required,omitempty" example:"2"`
+	ExpiresAt         *time.Time `json:"expires_at,omitempty" example:"2025-06-10T02:02:31Z"`
+	// PaymentRequestId is set for transfers paying a payment request.
+	PaymentRequestId *int64 `json:"payment_request_id,omitempty" example:"3"`
+	// EntryType, ReasonCode, Reason and CreatedBy are set for adjustments, ApprovedBy or RejectedBy once decided.
+	EntryType  *string `json:"entry_type,omitempty" example:"credit"`
+	ReasonCode *string `json:"reason_code,omitempty" example:"chargeback"`
+	Reason     *string `json:"reason,omitempty" example:"chargeback 1234"`
+	CreatedBy  *string `json:"created_by,omitempty" example:"ada"`
+	ApprovedBy *string `json:"approved_by,omitempty" example:"grace"`
+	RejectedBy *string `json:"rejected_by,omitempty" example:"grace"`
 }
 
 type Transaction struct {
@@ -71,23 +184,50 @@ type Ledger struct {
 	TransactionId int64           `json:"transaction_id"`
 }
 
+// TransactionLedgerRow
+// Transaction with one of its ledgers, one row per ledger. Ledger is nil for transactions requested by the user without
+// ledgers of the user's wallets.
+type TransactionLedgerRow struct {
+	Transaction Transaction
+	Ledger      *Ledger
+}
+
 type TransactionLedgers struct {
 	Transaction Transaction
 	// Ledgers     []Ledger
 	Ledgers []Ledger
 }
 
+// Publisher
+// Publishes the domain events of changes in the db transaction of the change, so that they are published if and only if
+// the change commits. Implemented by outboxservice.Publisher.
+type Publisher interface {
+	UserCreated(ctx context.Context, tx pgx.Tx, user User) error
+	WalletCreated(ctx context.Context, tx pgx.Tx, wallet Wallet) error
+	// TransactionSucceeded is called with the wallet of each ledger, wallets[i] of ledgers[i].
+	TransactionSucceeded(ctx context.Context, tx pgx.Tx, transaction Transaction, wallets []Wallet, ledgers []Ledger) error
+	// TransactionFailed is called with transactions ended without ledgers, of status error_<error code>, rejected or
+	// expired.
+	TransactionFailed(ctx context.Context, tx pgx.Tx, transaction Transaction) error
+}
+
+// Repo
+// Users are looked up by canonical username, see policy.CanonicalUsername. Callers canonicalize usernames.
 type Repo struct {
-	conn *pgxpool.Pool
+	conn      *pgxpool.Pool
+	publisher Publisher
 }
 
-func New(conn *pgxpool.Pool) *Repo {
+func New(conn *pgxpool.Pool, publisher Publisher) *Repo {
 	return &Repo{
-		conn: conn,
+		conn:      conn,
+		publisher: publisher,
 	}
 }
 
-func (r *Repo) CreateUser(ctx context.Context, username string) (User, error) {
+// CreateUser
+// Validation and canonicalization are the caller's.
+func (r *Repo) CreateUser(ctx context.Context, username string, canonicalUsername string) (User, error) {
 	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
 		IsoLevel: pgx.ReadCommitted,
 	})
@@ -96,12 +236,19 @@ func (r *Repo) CreateUser(ctx context.Context, username string) (User, error) {
 	}
 	defer tx.Rollback(ctx)
 
-	user, err := r.createUser(ctx, tx, username)
+	user, err := r.createUser(ctx, tx, username, canonicalUsername)
+	if err != nil {
+		return User{}, err
+	}
+	err = r.publisher.UserCreated(ctx, tx, user)
 	if err != nil {
 		return User{}, err
 	}
 
-	tx.Commit(ctx)
+	err = tx.Commit(ctx)
+	if err != nil {
+		return User{}, err
+	}
 	return user, nil
 }
 
@@ -167,7 +314,200 @@ func (r *Repo) Transactions(ctx context.Context, username string) ([]Transaction
 	return transactions, nil
 }
 
-func (r *Repo) CreateWallet(ctx context.Context, username string, currency CurrencyType) (Wallet, error) {
+// StreamTransactions
+// Same transactions as Transactions, sorted by newest then ledger id, passed to fn row by row as read from the
+// connection instead of collected in memory. Stops at the first error of fn.
+func (r *Repo) StreamTransactions(ctx context.Context, username string, fn func(TransactionLedgerRow) error) error {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.RepeatableRead,
+	})
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	user, err := r.user(ctx, tx, username)
+	if err != nil {
+		return err
+	}
+
+	rows, err := tx.Query(ctx, `with l as (select l.id, l.wallet_id, l.transaction_id, l.entry_type, l.amount, l.created_at, l.balance, m.user_account_id uaid
+    from ledgers l join wallet_memberships m on m.wallet_id = l.wallet_id where m.user_account_id = $1)
+select t.id, t.requestor_id, t.nonce, t.status, t.operation, t.created_at, t.metadata,
+    l.id, l.wallet_id, l.entry_type, l.amount, l.created_at, l.balance
+from transactions t left join l on l.transaction_id = t.id
+where t.requestor_id = $1 or l.uaid = $1
+order by t.created_at desc, t.id desc, l.id`, user.Id)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t Transaction
+		var ledgerId, walletId *int64
+		var entryType *string
+		var amount, balance decimal.NullDecimal
+		var createdAt *time.Time
+		err := rows.Scan(&t.Id, &t.RequestorId, &t.Nonce, &t.Status, &t.Operation, &t.CreatedAt, &t.MetaData,
+			&ledgerId, &walletId, &entryType, &amount, &createdAt, &balance)
+		if err != nil {
+			return err
+		}
+		row := TransactionLedgerRow{Transaction: t}
+		if ledgerId != nil {
+			row.Ledger = &Ledger{
+				Id:            *ledgerId,
+				WalletId:      *walletId,
+				EntryType:     *entryType,
+				Amount:        amount.Decimal,
+				CreatedAt:     *createdAt,
+				Balance:       balance.Decimal,
+				TransactionId: t.Id,
+			}
+		}
+		if err := fn(row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// WalletAt
+// Wallet with the balance as of at. Status is the current status.
+func (r *Repo) WalletAt(ctx context.Context, walletId int64, at time.Time) (Wallet, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.RepeatableRead,
+	})
+	if err != nil {
+		return Wallet{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var wallet Wallet
+	err = tx.QueryRow(ctx, "select "+walletColumns+" from wallets w where w.id=$1", walletId).
+		Scan(wallet.scanTargets()...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Wallet{}, utils.NotFoundErrorF("wallet")
+	}
+	if err != nil {
+		return Wallet{}, err
+	}
+	wallet.Balance, err = r.balanceAt(ctx, tx, walletId, at)
+	if err != nil {
+		return Wallet{}, err
+	}
+	return wallet, nil
+}
+
+// UserWalletsAt
+// Wallets of the user with balances as of at, zero for wallets without ledgers by then.
+func (r *Repo) UserWalletsAt(ctx context.Context, username string, at time.Time) (UserWallets, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.RepeatableRead,
+	})
+	if err != nil {
+		return UserWallets{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	user, err := r.user(ctx, tx, username)
+	if err != nil {
+		return UserWallets{}, err
+	}
+	wallets, err := r.walletsByUserId(ctx, tx, user.Id)
+	if err != nil {
+		return UserWallets{}, err
+	}
+	for i := range wallets {
+		wallets[i].Balance, err = r.balanceAt(ctx, tx, wallets[i].Id, at)
+		if err != nil {
+			return UserWallets{}, err
+		}
+	}
+	return UserWallets{
+		User:    *user,
+		Wallets: wallets,
+	}, nil
+}
+
+// balanceAt
+// Balance snapshot of the last ledger of the wallet created at or before at, zero if none.
+// Uses ledgers_wallet_id_created_at_idx.
+func (r *Repo) balanceAt(ctx context.Context, tx pgx.Tx, walletId int64, at time.Time) (decimal.Decimal, error) {
+	if tx == nil {
+		return decimal.Zero, utils.NilTxError
+	}
+	var balance decimal.Decimal
+	err := tx.QueryRow(ctx, "select balance from ledgers where wallet_id=$1 and created_at <= $2 order by created_at desc, id desc limit 1", walletId, at).
+		Scan(&balance)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return decimal.Zero, nil
+	}
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return balance, nil
+}
+
+// Statement
+// Computed from the ledgers of the wallet.
+func (r *Repo) Statement(ctx context.Context, walletId int64, from, to time.Time) (Statement, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.RepeatableRead,
+	})
+	if err != nil {
+		return Statement{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	statement := Statement{From: from, To: to, Ledgers: []Ledger{}}
+	err = tx.QueryRow(ctx, "select "+walletColumns+" from wallets w where w.id=$1", walletId).
+		Scan(statement.Wallet.scanTargets()...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Statement{}, utils.NotFoundErrorF("wallet")
+	}
+	if err != nil {
+		return Statement{}, err
+	}
+
+	err = tx.QueryRow(ctx, "select balance from ledgers where wallet_id=$1 and created_at < $2 order by created_at desc, id desc limit 1", walletId, from).
+		Scan(&statement.OpeningBalance)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return Statement{}, err
+	}
+
+	rows, err := tx.Query(ctx, `select id, wallet_id, transaction_id, entry_type, amount, created_at, balance from ledgers
+		where wallet_id=$1 and created_at >= $2 and created_at < $3 order by created_at, id`, walletId, from, to)
+	if err != nil {
+		return Statement{}, err
+	}
+	defer rows.Close()
+
+	statement.ClosingBalance = statement.OpeningBalance
+	for rows.Next() {
+		var l Ledger
+		err := rows.Scan(&l.Id, &l.WalletId, &l.TransactionId, &l.EntryType, &l.Amount, &l.CreatedAt, &l.Balance)
+		if err != nil {
+			return Statement{}, err
+		}
+		if l.EntryType == "credit" {
+			statement.TotalCredits = statement.TotalCredits.Add(l.Amount)
+		} else {
+			statement.TotalDebits = statement.TotalDebits.Add(l.Amount)
+		}
+		statement.ClosingBalance = l.Balance
+		statement.Ledgers = append(statement.Ledgers, l)
+	}
+	if err := rows.Err(); err != nil {
+		return Statement{}, err
+	}
+	return statement, nil
+}
+
+// CreateWallet
+// Creates the wallet named name with username as its owner member. Names are unique per user and currency.
+func (r *Repo) CreateWallet(ctx context.Context, username string, currency CurrencyType, name string) (Wallet, error) {
 	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
 		IsoLevel: pgx.ReadCommitted,
 	})
@@ -180,7 +520,15 @@ func (r *Repo) CreateWallet(ctx context.Context, username string, currency Curre
 		return Wallet{}, err
 	}
 
-	wallet, err := r.createWallet(ctx, tx, user.Id, currency)
+	wallet, err := r.createWallet(ctx, tx, user.Id, currency, name)
+	if err != nil {
+		return Wallet{}, err
+	}
+	err = r.insertMembership(ctx, tx, wallet.Id, user.Id, MemberRoleOwner, user.Username)
+	if err != nil {
+		return Wallet{}, err
+	}
+	err = r.publisher.WalletCreated(ctx, tx, wallet)
 	if err != nil {
 		return Wallet{}, err
 	}
@@ -192,14 +540,14 @@ func (r *Repo) CreateWallet(ctx context.Context, username string, currency Curre
 	return wallet, nil
 }
 
-func (r *Repo) createUser(ctx context.Context, tx pgx.Tx, username string) (User, error) {
+func (r *Repo) createUser(ctx context.Context, tx pgx.Tx, username string, canonicalUsername string) (User, error) {
 	if tx == nil {
 		return User{}, utils.NilTxError
 	}
-	row := tx.QueryRow(ctx, "insert into user_accounts(username) VALUES ($1) RETURNING id, username", username)
+	row := tx.QueryRow(ctx, "insert into user_accounts as ua(username, username_canonical) VALUES ($1, $2) RETURNING "+userColumns, username, canonicalUsername)
 
 	var user User
-	err := row.Scan(&user.Id, &user.Username)
+	err := row.Scan(user.scanTargets()...)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
@@ -214,7 +562,7 @@ func (r *Repo) user(ctx context.Context, tx pgx.Tx, username string) (*User, err
 	if tx == nil {
 		return nil, utils.NilTxError
 	}
-	rows, err := tx.Query(ctx, "select id, username from user_accounts where username=$1", username)
+	rows, err := tx.Query(ctx, "select "+userColumns+" from user_accounts ua where ua.username_canonical=$1", username)
 	if err != nil {
 		return nil, err
 	}
@@ -223,7 +571,7 @@ func (r *Repo) user(ctx context.Context, tx pgx.Tx, username string) (*User, err
 	var users []User
 	for rows.Next() {
 		var t User
-		rows.Scan(&t.Id, &t.Username)
+		rows.Scan(t.scanTargets()...)
 		if err := rows.Err(); err != nil {
 			return nil, err
 		}
@@ -243,7 +591,7 @@ func (r *Repo) userWalletByWalletIdForUpdate(ctx context.Context, tx pgx.Tx, wal
 	if tx == nil {
 		return nil, utils.NilTxError
 	}
-	rows, err := tx.Query(ctx, "select ua.id, ua.username, w.id, w.user_account_id, w.currency, w.balance from user_accounts ua join wallets w on w.user_account_id = ua.id  where w.id=$1 FOR UPDATE OF w", walletId)
+	rows, err := tx.Query(ctx, "select "+userColumns+", "+walletColumns+" from user_accounts ua join wallets w on w.user_account_id = ua.id  where w.id=$1 FOR UPDATE OF w", walletId)
 	if err != nil {
 		return nil, err
 	}
@@ -252,7 +600,7 @@ func (r *Repo) userWalletByWalletIdForUpdate(ctx context.Context, tx pgx.Tx, wal
 	var users []UserWallet
 	for rows.Next() {
 		var t UserWallet
-		rows.Scan(&t.User.Id, &t.User.Username, &t.Wallet.Id, &t.Wallet.UserAccountId, &t.Wallet.Currency, &t.Wallet.Balance)
+		rows.Scan(append(t.User.scanTargets(), t.Wallet.scanTargets()...)...)
 		if err := rows.Err(); err != nil {
 			return nil, err
 		}
@@ -260,7 +608,7 @@ func (r *Repo) userWalletByWalletIdForUpdate(ctx context.Context, tx pgx.Tx, wal
 	}
 
 	if len(users) == 0 {
-		return nil, utils.NotFoundErrorF("user")
+		return nil, utils.NotFoundErrorF("wallet")
 	}
 	if len(users) > 1 {
 		return nil, utils.RowLengthShouldBeAtMost1Error
@@ -268,11 +616,41 @@ func (r *Repo) userWalletByWalletIdForUpdate(ctx context.Context, tx pgx.Tx, wal
 	return &users[0], nil
 }
 
-func (r *Repo) createWallet(ctx context.Context, tx pgx.Tx, username int64, currency CurrencyType) (Wallet, error) {
-	row := tx.QueryRow(ctx, "insert into wallets(user_account_id, currency, balance) VALUES ($1,$2,$3) RETURNING id, user_account_id, currency, balance", username, currency, decimal.Zero)
+// requestorForShare
+// Locks the requestor of an operation on a wallet FOR SHARE after the wallet, so that freezing or suspending it waits
+// for the operation and applies to the operations after it. The creator of the wallet is locked with the wallet.
+func (r *Repo) requestorForShare(ctx context.Context, tx pgx.Tx, userId int64) (*User, error) {
+	if tx == nil {
+		return nil, utils.NilTxError
+	}
+	var u User
+	err := tx.QueryRow(ctx, "select "+userColumns+" from user_accounts ua where ua.id=$1 FOR SHARE", userId).Scan(u.scanTargets()...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, utils.NotFoundErrorF("user")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// operates
+// Checks that the requestor may move money: neither frozen nor suspended (policy.AccountStatusSuspended).
+func (u *User) operates() error {
+	if u.Frozen {
+		return utils.AccountFrozenError
+	}
+	if u.Status == "suspended" {
+		return utils.AccountSuspendedError
+	}
+	return nil
+}
+
+func (r *Repo) createWallet(ctx context.Context, tx pgx.Tx, userId int64, currency CurrencyType, name string) (Wallet, error) {
+	row := tx.QueryRow(ctx, "insert into wallets as w(user_account_id, currency, name, balance) VALUES ($1,$2,$3,$4) RETURNING "+walletColumns, userId, currency, name, decimal.Zero)
 
 	var wallet Wallet
-	err := row.Scan(&wallet.Id, &wallet.UserAccountId, &wallet.Currency, &wallet.Balance)
+	err := row.Scan(wallet.scanTargets()...)
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) {
@@ -289,7 +667,7 @@ func (r *Repo) walletsByUserId(ctx context.Context, tx pgx.Tx, userId int64) ([]
 		return []Wallet{}, utils.NilTxError
 	}
 
-	rows, err := tx.Query(ctx, "select id, user_account_id, currency, balance from wallets where user_account_id=$1", userId)
+	rows, err := tx.Query(ctx, "select "+walletColumns+" from wallets w join wallet_memberships m on m.wallet_id = w.id where m.user_account_id=$1 order by w.id", userId)
 	if err != nil {
 		return []Wallet{}, err
 	}
@@ -298,7 +676,7 @@ func (r *Repo) walletsByUserId(ctx context.Context, tx pgx.Tx, userId int64) ([]
 	var wallets []Wallet
 	for rows.Next() {
 		var t Wallet
-		rows.Scan(&t.Id, &t.UserAccountId, &t.Currency, &t.Balance)
+		rows.Scan(t.scanTargets()...)
 		if err := rows.Err(); err != nil {
 			return []Wallet{}, err
 		}
@@ -309,16 +687,16 @@ func (r *Repo) walletsByUserId(ctx context.Context, tx pgx.Tx, userId int64) ([]
 }
 
 // transactionLedgersByUserId
-// Get transactions requested by user. Ledgers of other user's wallet will be omitted.
-// Includes ledgers of user's wallet not recorded from a transaction requested by the user.
+// Get transactions requested by user. Ledgers of wallets the user is not a member of will be omitted.
+// Includes ledgers of the user's member wallets not recorded from a transaction requested by the user.
 func (r *Repo) transactionLedgersByUserId(ctx context.Context, tx pgx.Tx, userId int64) ([]TransactionLedgers, error) {
 	if tx == nil {
 		return []TransactionLedgers{}, utils.NilTxError
 	}
 
-	rows, err := tx.Query(ctx, `with l as (select l.id, l.wallet_id,l.transaction_id,l.entry_type,l.amount,l.created_at,l.balance, ua.id uaid from ledgers l left join wallets w on w.id = l.wallet_id
-    left join user_accounts ua on ua.id = w.user_account_id where ua.id = $1)
-select t.id,t.requestor_id, t.nonce, t.status, t.operation,t.created_at, t.metadata, COALESCE(json_agg(json_build_object('id',l.wallet_id,'transaction_id',l.transaction_id,'entry_type', l.entry_type,'amount', l.amount,'created_at', l.created_at,'balance', l.balance)) filter (where l.id is not null), '[]'::json)
+	rows, err := tx.Query(ctx, `with l as (select l.id, l.wallet_id,l.transaction_id,l.entry_type,l.amount,l.created_at,l.balance, m.user_account_id uaid from ledgers l
+    join wallet_memberships m on m.wallet_id = l.wallet_id where m.user_account_id = $1)
+select t.id,t.requestor_id, t.nonce, t.status, t.operation,t.created_at, t.metadata, COALESCE(json_agg(json_build_object('id',l.id,'wallet_id',l.wallet_id,'transaction_id',l.transaction_id,'entry_type', l.entry_type,'amount', l.amount,'created_at', l.created_at,'balance', l.balance)) filter (where l.id is not null), '[]'::json)
 from transactions t left join l on l.transaction_id = t.id
 where t.requestor_id = $2 or l.uaid = $3
 group by t.id order by t.created_at desc
@@ -358,7 +736,7 @@ group by t.id order by t.created_at desc
 
 func (r *Repo) Deposit(requestor string, ctx context.Context, nonce int64, walletId int64, amount decimal.Decimal) (Transaction, Ledger, error) {
 	if !amount.IsPositive() {
-		return Transaction{}, Ledger{}, errors.New("amount negative")
+		return Transaction{}, Ledger{}, utils.InvalidAmountError
 	}
 
 	user, err := r.User(ctx, requestor)
@@ -384,8 +762,28 @@ func (r *Repo) Deposit(requestor string, ctx context.Context, nonce int64, walle
 	if err != nil {
 		return Transaction{}, Ledger{}, err
 	}
-	if requestor != userWallet.User.Username {
-		return Transaction{}, Ledger{}, errors.New("requestor and wallet owner mismatch")
+	// Checked under the wallet lock, SetFrozen locks the wallets of the user and SetMembership the wallet.
+	member, err := r.membership(ctx, tx, walletId, user.Id)
+	if err != nil {
+		return Transaction{}, Ledger{}, err
+	}
+	requestorUser, err := r.requestorForShare(ctx, tx, user.Id)
+	if err != nil {
+		return Transaction{}, Ledger{}, err
+	}
+	if userWallet.User.Frozen {
+		err = utils.AccountFrozenError
+	} else if err = requestorUser.operates(); err == nil {
+		if err = member.allows("credit", amount, decimal.Zero); err == nil {
+			err = userWallet.Wallet.Status.allows("credit")
+		}
+	}
+	if err != nil {
+		tsErr := r.failTransaction(context.Background(), transaction, err)
+		if tsErr != nil {
+			return Transaction{}, Ledger{}, errors.Join(tsErr, err)
+		}
+		return Transaction{}, Ledger{}, err
 	}
 
 	newBalance := userWallet.Wallet.Balance.Add(amount)
@@ -399,7 +797,7 @@ func (r *Repo) Deposit(requestor string, ctx context.Context, nonce int64, walle
 		return Transaction{}, Ledger{}, err
 	}
 
-	err = r.updateTransactionStatus(ctx, tx, transaction.Id, "success")
+	err = r.succeedTransaction(ctx, tx, transaction, []Wallet{userWallet.Wallet}, []Ledger{ledger})
 	if err != nil {
 		return Transaction{}, Ledger{}, err
 	}
@@ -413,7 +811,7 @@ func (r *Repo) Deposit(requestor string, ctx context.Context, nonce int64, walle
 
 func (r *Repo) Withdraw(requestor string, ctx context.Context, nonce int64, walletId int64, amount decimal.Decimal) (Transaction, Ledger, error) {
 	if !amount.IsPositive() {
-		return Transaction{}, Ledger{}, errors.New("amount negative")
+		return Transaction{}, Ledger{}, utils.InvalidAmountError
 	}
 
 	user, err := r.User(ctx, requestor)
@@ -437,14 +835,37 @@ func (r *Repo) Withdraw(requestor string, ctx context.Context, nonce int64, wall
 	if err != nil {
 		return Transaction{}, Ledger{}, err
 	}
-	if requestor != userWallet.User.Username {
-		return Transaction{}, Ledger{}, errors.New("requestor and wallet owner mismatch")
+	member, err := r.membership(ctx, tx, walletId, user.Id)
+	if err != nil {
+		return Transaction{}, Ledger{}, err
+	}
+	spent, err := r.spent(ctx, tx, member)
+	if err != nil {
+		return Transaction{}, Ledger{}, err
+	}
+	requestorUser, err := r.requestorForShare(ctx, tx, user.Id)
+	if err != nil {
+		return Transaction{}, Ledger{}, err
+	}
+	if userWallet.User.Frozen {
+		err = utils.AccountFrozenError
+	} else if err = requestorUser.operates(); err == nil {
+		if err = member.allows("debit", amount, spent); err == nil {
+			err = userWallet.Wallet.Status.allows("debit")
+		}
+	}
+	if err != nil {
+		tsErr := r.failTransaction(context.Background(), transaction, err)
+		if tsErr != nil {
+			return Transaction{}, Ledger{}, errors.Join(tsErr, err)
+		}
+		return Transaction{}, Ledger{}, err
 	}
 
 	newBalance := userWallet.Wallet.Balance.Sub(amount)
 	err = r.updateBalance(ctx, tx, walletId, newBalance)
 	if err != nil {
-		tsErr := r.UpdateTransactionStatus(context.Background(), transaction.Id, fmt.Sprintf("error_%s", err.Error()))
+		tsErr := r.failTransaction(context.Background(), transaction, err)
 		if tsErr != nil {
 			return Transaction{}, Ledger{}, errors.Join(tsErr, err)
 		}
@@ -456,7 +877,7 @@ func (r *Repo) Withdraw(requestor string, ctx context.Context, nonce int64, wall
 		return Transaction{}, Ledger{}, err
 	}
 
-	err = r.updateTransactionStatus(ctx, tx, transaction.Id, "success")
+	err = r.succeedTransaction(ctx, tx, transaction, []Wallet{userWallet.Wallet}, []Ledger{ledger})
 	if err != nil {
 		return Transaction{}, Ledger{}, err
 	}
@@ -470,7 +891,7 @@ func (r *Repo) Withdraw(requestor string, ctx context.Context, nonce int64, wall
 
 func (r *Repo) Transfer(requestor string, ctx context.Context, nonce int64, sourceWalletId, destinationWalletId int64, amount decimal.Decimal) (Transaction, []Ledger, error) {
 	if !amount.IsPositive() {
-		return Transaction{}, []Ledger{}, errors.New("amount negative")
+		return Transaction{}, []Ledger{}, utils.InvalidAmountError
 	}
 
 	user, err := r.User(ctx, requestor)
@@ -485,97 +906,783 @@ func (r *Repo) Transfer(requestor string, ctx context.Context, nonce int64, sour
 	if err != nil {
 		return Transaction{}, []Ledger{}, err
 	}
+	return r.transfer(ctx, transaction, sourceWalletId, destinationWalletId, amount)
+}
+
+// transfer
+// Moves amount between the wallets for the pending transaction, succeeding or failing it.
+func (r *Repo) transfer(ctx context.Context, transaction Transaction, sourceWalletId, destinationWalletId int64, amount decimal.Decimal) (Transaction, []Ledger, error) {
 	tx, err := r.conn.Begin(ctx)
 	if err != nil {
 		return Transaction{}, []Ledger{}, err
 	}
 	defer tx.Rollback(ctx)
 
-	sourceUserWallet, err := r.userWalletByWalletIdForUpdate(ctx, tx, sourceWalletId)
+	ledgers, failed, err := r.transferTx(ctx, tx, transaction, sourceWalletId, destinationWalletId, amount)
+	if err != nil {
+		return Transaction{}, []Ledger{}, err
+	}
+	if failed != nil {
+		tsErr := r.failTransaction(context.Background(), transaction, failed)
+		if tsErr != nil {
+			return Transaction{}, []Ledger{}, errors.Join(tsErr, failed)
+		}
+		return Transaction{}, []Ledger{}, failed
+	}
+
+	err = tx.Commit(ctx)
 	if err != nil {
 		return Transaction{}, []Ledger{}, err
 	}
-	if requestor != sourceUserWallet.User.Username {
-		return Transaction{}, []Ledger{}, errors.New("requestor and wallet owner mismatch")
+	return transaction, ledgers, nil
+}
+
+// transferTx
+// Locks the wallets, checks and writes the ledgers of the transfer, and succeeds the transaction in tx. Failures of the
+// checks and balance updates are returned as failed, for the caller to fail the transaction, other errors as err.
+func (r *Repo) transferTx(ctx context.Context, tx pgx.Tx, transaction Transaction, sourceWalletId, destinationWalletId int64, amount decimal.Decimal) (ledgers []Ledger, failed error, err error) {
+	sourceUserWallet, err := r.userWalletByWalletIdForUpdate(ctx, tx, sourceWalletId)
+	if err != nil {
+		return []Ledger{}, nil, err
 	}
 
 	destinationUserWallet, err := r.userWalletByWalletIdForUpdate(ctx, tx, destinationWalletId)
 	if err != nil {
-		return Transaction{}, []Ledger{}, err
+		return []Ledger{}, nil, err
 	}
-	if destinationUserWallet.Wallet.Currency != sourceUserWallet.Wallet.Currency {
-		err = errors.New("currency_mismatch")
-		tsErr := r.UpdateTransactionStatus(context.Background(), transaction.Id, fmt.Sprintf("error_%s", err.Error()))
-		if tsErr != nil {
-			return Transaction{}, []Ledger{}, errors.Join(tsErr, err)
+	// the requestor must still be a member when a transfer pending approval executes
+	member, err := r.membership(ctx, tx, sourceWalletId, transaction.RequestorId)
+	if err != nil {
+		return []Ledger{}, nil, err
+	}
+	spent, err := r.spent(ctx, tx, member)
+	if err != nil {
+		return []Ledger{}, nil, err
+	}
+	requestorUser, err := r.requestorForShare(ctx, tx, transaction.RequestorId)
+	if err != nil {
+		return []Ledger{}, nil, err
+	}
+	if sourceUserWallet.User.Frozen || destinationUserWallet.User.Frozen {
+		failed = utils.AccountFrozenError
+	} else if failed = requestorUser.operates(); failed == nil {
+		if failed = member.allows("debit", amount, spent); failed == nil {
+			failed = sourceUserWallet.Wallet.Status.allows("debit")
 		}
-		return Transaction{}, []Ledger{}, err
+	}
+	if failed == nil {
+		failed = destinationUserWallet.Wallet.Status.allows("credit")
+	}
+	if failed == nil && transaction.MetaData.PaymentRequestId != nil {
+		failed = r.settlePaymentRequest(ctx, tx, *transaction.MetaData.PaymentRequestId, transaction)
+	}
+	if failed == nil && destinationUserWallet.Wallet.Currency != sourceUserWallet.Wallet.Currency {
+		failed = utils.CurrencyMismatchError
+	}
+	if failed != nil {
+		return []Ledger{}, failed, nil
 	}
 
 	sourceNewBalance := sourceUserWallet.Wallet.Balance.Sub(amount)
-	err = r.updateBalance(ctx, tx, sourceWalletId, sourceNewBalance)
-	if err != nil {
-		tsErr := r.UpdateTransactionStatus(context.Background(), transaction.Id, fmt.Sprintf("error_%s", err.Error()))
-		if tsErr != nil {
-			return Transaction{}, []Ledger{}, errors.Join(tsErr, err)
-		}
-		return Transaction{}, []Ledger{}, err
+	if failed := r.updateBalance(ctx, tx, sourceWalletId, sourceNewBalance); failed != nil {
+		return []Ledger{}, failed, nil
 	}
 	withdrawLedger, err := r.appendLedger(ctx, tx, sourceWalletId, transaction.Id, "debit", amount, sourceNewBalance)
 	if err != nil {
-		return Transaction{}, []Ledger{}, err
+		return []Ledger{}, nil, err
 	}
 
 	destinationNewBalance := destinationUserWallet.Wallet.Balance.Add(amount)
-	err = r.updateBalance(ctx, tx, destinationWalletId, destinationNewBalance)
-	if err != nil {
-		tsErr := r.UpdateTransactionStatus(context.Background(), transaction.Id, fmt.Sprintf("error_%s", err.Error()))
-		if tsErr != nil {
-			return Transaction{}, []Ledger{}, errors.Join(tsErr, err)
-		}
-		return Transaction{}, []Ledger{}, err
+	if failed := r.updateBalance(ctx, tx, destinationWalletId, destinationNewBalance); failed != nil {
+		return []Ledger{}, failed, nil
 	}
 	depositledger, err := r.appendLedger(ctx, tx, destinationWalletId, transaction.Id, "credit", amount, destinationNewBalance)
 	if err != nil {
-		return Transaction{}, []Ledger{}, err
+		return []Ledger{}, nil, err
 	}
 
-	err = r.updateTransactionStatus(ctx, tx, transaction.Id, "success")
+	err = r.succeedTransaction(ctx, tx, transaction,
+		[]Wallet{sourceUserWallet.Wallet, destinationUserWallet.Wallet}, []Ledger{withdrawLedger, depositledger})
 	if err != nil {
-		return Transaction{}, []Ledger{}, err
+		return []Ledger{}, nil, err
 	}
+	return []Ledger{withdrawLedger, depositledger}, nil, nil
+}
 
-	err = tx.Commit(ctx)
-	if err != nil {
-		return Transaction{}, []Ledger{}, err
+// AdjustmentReasonCode
+// Mandatory category of a manual adjustment, the free text reason details it.
+type AdjustmentReasonCode string
+
+const (
+	AdjustmentReasonCorrection    AdjustmentReasonCode = "correction"
+	AdjustmentReasonChargeback    AdjustmentReasonCode = "chargeback"
+	AdjustmentReasonGoodwill      AdjustmentReasonCode = "goodwill"
+	AdjustmentReasonFeeRefund     AdjustmentReasonCode = "fee_refund"
+	AdjustmentReasonFraudRecovery AdjustmentReasonCode = "fraud_recovery"
+)
+
+var AdjustmentReasonCodes = []AdjustmentReasonCode{AdjustmentReasonCorrection, AdjustmentReasonChargeback,
+	AdjustmentReasonGoodwill, AdjustmentReasonFeeRefund, AdjustmentReasonFraudRecovery}
+
+const (
+	// TransactionStatusPendingApproval is the status of adjustments created and not yet approved nor rejected.
+	TransactionStatusPendingApproval = "pending_approval"
+	// TransactionStatusRejected is the final status of adjustments rejected before approval. They have no ledgers.
+	TransactionStatusRejected = "rejected"
+)
+
+// CreateAdjustment
+// Records a manual credit or debit of any wallet as an adjustment pending approval, without ledgers. The maker is the
+// requestor of the transaction and created_by of its metadata. Closed wallets are excluded. Authorization is the
+// caller's.
+func (r *Repo) CreateAdjustment(maker string, ctx context.Context, nonce int64, walletId int64, entryType string, amount decimal.Decimal, reasonCode AdjustmentReasonCode, reason string) (Transaction, error) {
+	if !amount.IsPositive() {
+		return Transaction{}, utils.InvalidAmountError
+	}
+	if entryType != "credit" && entryType != "debit" {
+		return Transaction{}, utils.InvalidArgumentErrorF("entry_type must be credit or debit")
 	}
-	return transaction, []Ledger{withdrawLedger, depositledger}, nil
-}
 
-func (r *Repo) UpdateTransactionStatus(ctx context.Context, id int64, status string) error {
+	user, err := r.User(ctx, maker)
+	if err != nil {
+		return Transaction{}, err
+	}
 	tx, err := r.conn.Begin(ctx)
 	if err != nil {
-		return err
+		return Transaction{}, err
 	}
-
 	defer tx.Rollback(ctx)
-	_, err = tx.Exec(ctx, `
-    UPDATE transactions
-    SET status = $1 where id = $2`, status, id)
+
+	var status WalletStatus
+	err = tx.QueryRow(ctx, "select status from wallets where id=$1", walletId).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Transaction{}, utils.NotFoundErrorF("wallet")
+	}
 	if err != nil {
-		return err
+		return Transaction{}, err
 	}
-
+	if status == WalletStatusClosed {
+		return Transaction{}, utils.WalletClosedError
+	}
+	transaction, err := r.insertTransaction(ctx, tx, nonce, user.Id, TransactionStatusPendingApproval, "adjustment", map[string]any{
+		"amount":           amount.String(),
+		"source_wallet_id": walletId,
+		"entry_type":       entryType,
+		"reason_code":      reasonCode,
+		"reason":           reason,
+		"created_by":       user.Username,
+	})
+	if err != nil {
+		return Transaction{}, err
+	}
+	err = tx.Commit(ctx)
+	if err != nil {
+		return Transaction{}, err
+	}
+	return transaction, nil
+}
+
+// Adjustment
+// Adjustment of any status by transaction id.
+func (r *Repo) Adjustment(ctx context.Context, id int64) (Transaction, error) {
+	var t Transaction
+	err := r.conn.QueryRow(ctx, `select id, requestor_id, nonce, status, operation, created_at, metadata
+		from transactions where id = $1 and operation = 'adjustment'`, id).
+		Scan(&t.Id, &t.RequestorId, &t.Nonce, &t.Status, &t.Operation, &t.CreatedAt, &t.MetaData)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Transaction{}, utils.NotFoundErrorF("adjustment")
+	}
+	if err != nil {
+		return Transaction{}, err
+	}
+	return t, nil
+}
+
+// ApproveAdjustment
+// Posts the ledger of an adjustment pending approval and records the checker as approved_by. The checker must not be
+// the maker. Adjustments of wallets closed, or debits exceeding the balance, since creation fail with the error
+// status. Frozen accounts and wallets are adjusted. Authorization is the caller's.
+func (r *Repo) ApproveAdjustment(checker string, ctx context.Context, id int64) (Transaction, Ledger, error) {
+	user, err := r.User(ctx, checker)
+	if err != nil {
+		return Transaction{}, Ledger{}, err
+	}
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return Transaction{}, Ledger{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	transaction, err := r.pendingAdjustmentForUpdate(ctx, tx, id)
+	if err != nil {
+		return Transaction{}, Ledger{}, err
+	}
+	if transaction.RequestorId == user.Id {
+		return Transaction{}, Ledger{}, utils.ForbiddenErrorF("adjustment must be approved by another operator than its maker")
+	}
+	transaction, err = r.setTransactionMetaData(ctx, tx, transaction, "approved_by", user.Username)
+	if err != nil {
+		return Transaction{}, Ledger{}, err
+	}
+
+	walletId, entryType, amount := *transaction.MetaData.SourceWalletId, *transaction.MetaData.EntryType, *transaction.MetaData.Amount
+	userWallet, err := r.userWalletByWalletIdForUpdate(ctx, tx, walletId)
+	if err != nil {
+		return Transaction{}, Ledger{}, err
+	}
+	newBalance := userWallet.Wallet.Balance.Add(amount)
+	if entryType == "debit" {
+		newBalance = userWallet.Wallet.Balance.Sub(amount)
+	}
+	if userWallet.Wallet.Status == WalletStatusClosed {
+		err = utils.WalletClosedError
+	} else if newBalance.IsNegative() {
+		err = utils.InsufficientFundsError
+	}
+	if err != nil {
+		// The transaction row is locked by tx, so it fails in tx rather than in its own db transaction.
+		if tsErr := r.failTransactionTx(ctx, tx, transaction, err); tsErr != nil {
+			return Transaction{}, Ledger{}, errors.Join(tsErr, err)
+		}
+		if tsErr := tx.Commit(ctx); tsErr != nil {
+			return Transaction{}, Ledger{}, errors.Join(tsErr, err)
+		}
+		return Transaction{}, Ledger{}, err
+	}
+
+	err = r.updateBalance(ctx, tx, walletId, newBalance)
+	if err != nil {
+		return Transaction{}, Ledger{}, err
+	}
+	ledger, err := r.appendLedger(ctx, tx, walletId, transaction.Id, entryType, amount, newBalance)
+	if err != nil {
+		return Transaction{}, Ledger{}, err
+	}
+
+	err = r.succeedTransaction(ctx, tx, transaction, []Wallet{userWallet.Wallet}, []Ledger{ledger})
+	if err != nil {
+		return Transaction{}, Ledger{}, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return Transaction{}, Ledger{}, err
+	}
+	transaction.Status = "success"
+	return transaction, ledger, nil
+}
+
+// RejectAdjustment
+// Sets an adjustment pending approval to rejected and records the actor as rejected_by. The maker may reject, i.e. to
+// withdraw a mistaken adjustment. Authorization is the caller's.
+func (r *Repo) RejectAdjustment(actor string, ctx context.Context, id int64) (Transaction, error) {
+	user, err := r.User(ctx, actor)
+	if err != nil {
+		return Transaction{}, err
+	}
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return Transaction{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	transaction, err := r.pendingAdjustmentForUpdate(ctx, tx, id)
+	if err != nil {
+		return Transaction{}, err
+	}
+	transaction, err = r.setTransactionMetaData(ctx, tx, transaction, "rejected_by", user.Username)
+	if err != nil {
+		return Transaction{}, err
+	}
+	err = r.updateTransactionStatus(ctx, tx, transaction.Id, TransactionStatusRejected)
+	if err != nil {
+		return Transaction{}, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return Transaction{}, err
+	}
+	transaction.Status = TransactionStatusRejected
+	return transaction, nil
+}
+
+// PendingAdjustments
+// Adjustments pending approval sorted by oldest, up to limit.
+func (r *Repo) PendingAdjustments(ctx context.Context, limit int) ([]Transaction, error) {
+	rows, err := r.conn.Query(ctx, `select id, requestor_id, nonce, status, operation, created_at, metadata
+		from transactions where status = $1 and operation = 'adjustment' order by created_at, id limit $2`,
+		TransactionStatusPendingApproval, limit)
+	if err != nil {
+		return []Transaction{}, err
+	}
+	defer rows.Close()
+
+	transactions := []Transaction{}
+	for rows.Next() {
+		var t Transaction
+		err := rows.Scan(&t.Id, &t.RequestorId, &t.Nonce, &t.Status, &t.Operation, &t.CreatedAt, &t.MetaData)
+		if err != nil {
+			return []Transaction{}, err
+		}
+		transactions = append(transactions, t)
+	}
+	if err := rows.Err(); err != nil {
+		return []Transaction{}, err
+	}
+	return transactions, nil
+}
+
+// pendingAdjustmentForUpdate
+// Locks the adjustment so that it is approved or rejected once.
+func (r *Repo) pendingAdjustmentForUpdate(ctx context.Context, tx pgx.Tx, id int64) (Transaction, error) {
+	if tx == nil {
+		return Transaction{}, utils.NilTxError
+	}
+	var t Transaction
+	err := tx.QueryRow(ctx, `select id, requestor_id, nonce, status, operation, created_at, metadata
+		from transactions where id = $1 and operation = 'adjustment' FOR UPDATE`, id).
+		Scan(&t.Id, &t.RequestorId, &t.Nonce, &t.Status, &t.Operation, &t.CreatedAt, &t.MetaData)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Transaction{}, utils.NotFoundErrorF("adjustment")
+	}
+	if err != nil {
+		return Transaction{}, err
+	}
+	if t.Status != TransactionStatusPendingApproval {
+		return Transaction{}, utils.InvalidArgumentErrorF("adjustment is %s, not pending approval", t.Status)
+	}
+	return t, nil
+}
+
+// setTransactionMetaData
+// Sets key of the metadata of transaction to value in tx.
+func (r *Repo) setTransactionMetaData(ctx context.Context, tx pgx.Tx, transaction Transaction, key string, value string) (Transaction, error) {
+	if tx == nil {
+		return Transaction{}, utils.NilTxError
+	}
+	err := tx.QueryRow(ctx, `update transactions set metadata = metadata || jsonb_build_object($2::text, to_jsonb($3::text))
+		where id = $1 returning metadata`, transaction.Id, key, value).Scan(&transaction.MetaData)
+	if err != nil {
+		return Transaction{}, err
+	}
+	return transaction, nil
+}
+
+// WalletOwner
+// User who created the wallet, its first owner member. Wallets never change creator, frozen and suspended creators
+// block operations on the wallet.
+func (r *Repo) WalletOwner(ctx context.Context, walletId int64) (*User, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.RepeatableRead,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var user User
+	err = tx.QueryRow(ctx, "select "+userColumns+" from user_accounts ua join wallets w on w.user_account_id = ua.id where w.id=$1", walletId).
+		Scan(user.scanTargets()...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, utils.NotFoundErrorF("wallet")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// SetFrozen
+// Locks the wallets of the user first so that operations in flight on them complete before the account is frozen.
+func (r *Repo) SetFrozen(ctx context.Context, username string, frozen bool) (User, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.ReadCommitted,
+	})
+	if err != nil {
+		return User{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	user, err := r.user(ctx, tx, username)
+	if err != nil {
+		return User{}, err
+	}
+	_, err = tx.Exec(ctx, "select id from wallets where user_account_id=$1 order by id FOR UPDATE", user.Id)
+	if err != nil {
+		return User{}, err
+	}
+	_, err = tx.Exec(ctx, "update user_accounts set frozen=$1 where id=$2", frozen, user.Id)
+	if err != nil {
+		return User{}, err
+	}
+	user.Frozen = frozen
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return User{}, err
+	}
+	return *user, nil
+}
+
+func (r *Repo) SetRole(ctx context.Context, username string, role string) (User, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.ReadCommitted,
+	})
+	if err != nil {
+		return User{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	user, err := r.user(ctx, tx, username)
+	if err != nil {
+		return User{}, err
+	}
+	_, err = tx.Exec(ctx, "update user_accounts set role=$1 where id=$2", role, user.Id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			err = utils.ToError(pgErr)
+		}
+		return User{}, err
+	}
+	user.Role = role
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return User{}, err
+	}
+	return *user, nil
+}
+
+// SetWalletStatus
+// Transitions the wallet under its row lock and records the change. Closed wallets cannot transition, closing requires
+// zero balance. Authorization is the caller's.
+func (r *Repo) SetWalletStatus(ctx context.Context, actor string, walletId int64, status WalletStatus, reason string) (Wallet, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.ReadCommitted,
+	})
+	if err != nil {
+		return Wallet{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	actorUser, err := r.user(ctx, tx, actor)
+	if err != nil {
+		return Wallet{}, err
+	}
+	userWallet, err := r.userWalletByWalletIdForUpdate(ctx, tx, walletId)
+	if err != nil {
+		return Wallet{}, err
+	}
+	wallet := userWallet.Wallet
+	if wallet.Status == WalletStatusClosed {
+		return Wallet{}, utils.WalletClosedError
+	}
+	if wallet.Status == status {
+		return Wallet{}, utils.InvalidArgumentErrorF("wallet status is already %s", status)
+	}
+	if status == WalletStatusClosed && !wallet.Balance.IsZero() {
+		return Wallet{}, utils.WalletNotEmptyError
+	}
+
+	_, err = tx.Exec(ctx, "update wallets set status=$1 where id=$2", status, walletId)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			err = utils.ToError(pgErr)
+		}
+		return Wallet{}, err
+	}
+	_, err = tx.Exec(ctx, `insert into wallet_status_changes(wallet_id, from_status, to_status, reason, actor_id, created_at)
+		values ($1,$2,$3,$4,$5,now())`, walletId, wallet.Status, status, reason, actorUser.Id)
+	if err != nil {
+		return Wallet{}, err
+	}
+	wallet.Status = status
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return Wallet{}, err
+	}
+	return wallet, nil
+}
+
+// WalletStatusChanges
+// Status history of the wallet sorted by newest.
+func (r *Repo) WalletStatusChanges(ctx context.Context, walletId int64) ([]WalletStatusChange, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.RepeatableRead,
+	})
+	if err != nil {
+		return []WalletStatusChange{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `select c.id, c.wallet_id, c.from_status, c.to_status, c.reason, ua.username, c.created_at
+		from wallet_status_changes c join user_accounts ua on ua.id = c.actor_id
+		where c.wallet_id=$1 order by c.created_at desc, c.id desc`, walletId)
+	if err != nil {
+		return []WalletStatusChange{}, err
+	}
+	defer rows.Close()
+
+	changes := []WalletStatusChange{}
+	for rows.Next() {
+		var c WalletStatusChange
+		err := rows.Scan(&c.Id, &c.WalletId, &c.FromStatus, &c.ToStatus, &c.Reason, &c.Actor, &c.CreatedAt)
+		if err != nil {
+			return []WalletStatusChange{}, err
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return []WalletStatusChange{}, err
+	}
+	return changes, nil
+}
+
+// PendingTransactions
+// Transactions pending since before olderThan sorted by oldest, up to limit. Wallet operations complete within their
+// request, so these were left by a crashed process and have no ledgers.
+func (r *Repo) PendingTransactions(ctx context.Context, olderThan time.Time, limit int) ([]Transaction, error) {
+	rows, err := r.conn.Query(ctx, `select id, requestor_id, nonce, status, operation, created_at, metadata
+		from transactions where status = 'pending' and created_at < $1 order by created_at, id limit $2`, olderThan, limit)
+	if err != nil {
+		return []Transaction{}, err
+	}
+	defer rows.Close()
+
+	transactions := []Transaction{}
+	for rows.Next() {
+		var t Transaction
+		err := rows.Scan(&t.Id, &t.RequestorId, &t.Nonce, &t.Status, &t.Operation, &t.CreatedAt, &t.MetaData)
+		if err != nil {
+			return []Transaction{}, err
+		}
+		transactions = append(transactions, t)
+	}
+	if err := rows.Err(); err != nil {
+		return []Transaction{}, err
+	}
+	return transactions, nil
+}
+
+// WalletDiscrepancy
+// Wallet whose balance differs from its ledgers.
+type WalletDiscrepancy struct {
+	WalletId int64
+	Currency string
+	Balance  decimal.Decimal
+	// LedgerSum is the credits less the debits of the wallet's ledgers.
+	LedgerSum decimal.Decimal
+	// LastLedgerBalance is the balance after the newest ledger, nil if the wallet has none.
+	LastLedgerBalance *decimal.Decimal
+	Ledgers           int64
+}
+
+// TransactionDiscrepancy
+// Transaction whose ledgers do not match its status and operation.
+type TransactionDiscrepancy struct {
+	Id        int64
+	Status    string
+	Operation string
+	Ledgers   int64
+	Credits   decimal.Decimal
+	Debits    decimal.Decimal
+}
+
+// Reconciliation
+// Ledgers checked against wallet balances and transactions in one snapshot.
+type Reconciliation struct {
+	At                       time.Time
+	Wallets                  int64
+	Transactions             int64
+	WalletDiscrepancies      []WalletDiscrepancy
+	TransactionDiscrepancies []TransactionDiscrepancy
+}
+
+// Reconcile
+// Checks in one snapshot that the balance of each wallet equals the sum of its ledgers and the balance after its newest
+// ledger, that successful transactions have one ledger, two for transfers with equal credit and debit, and that other
+// transactions have none. Reads all ledgers.
+func (r *Repo) Reconcile(ctx context.Context) (Reconciliation, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel:   pgx.RepeatableRead,
+		AccessMode: pgx.ReadOnly,
+	})
+	if err != nil {
+		return Reconciliation{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	reconciliation := Reconciliation{
+		WalletDiscrepancies:      []WalletDiscrepancy{},
+		TransactionDiscrepancies: []TransactionDiscrepancy{},
+	}
+	err = tx.QueryRow(ctx, `select now(), (select count(*) from wallets), (select count(*) from transactions)`).
+		Scan(&reconciliation.At, &reconciliation.Wallets, &reconciliation.Transactions)
+	if err != nil {
+		return Reconciliation{}, err
+	}
+
+	rows, err := tx.Query(ctx, `select w.id, w.currency, w.balance, coalesce(s.sum, 0), last.balance, s.count
+		from wallets w
+		cross join lateral (select sum(case when entry_type = 'credit' then amount else -amount end) sum, count(*) count
+			from ledgers where wallet_id = w.id) s
+		left join lateral (select balance from ledgers where wallet_id = w.id order by created_at desc, id desc limit 1) last on true
+		where w.balance <> coalesce(s.sum, 0) or w.balance <> coalesce(last.balance, 0)
+		order by w.id`)
+	if err != nil {
+		return Reconciliation{}, err
+	}
+	for rows.Next() {
+		var d WalletDiscrepancy
+		err := rows.Scan(&d.WalletId, &d.Currency, &d.Balance, &d.LedgerSum, &d.LastLedgerBalance, &d.Ledgers)
+		if err != nil {
+			rows.Close()
+			return Reconciliation{}, err
+		}
+		reconciliation.WalletDiscrepancies = append(reconciliation.WalletDiscrepancies, d)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return Reconciliation{}, err
+	}
+
+	rows, err = tx.Query(ctx, `select t.id, t.status, t.operation, count(l.id),
+			coalesce(sum(l.amount) filter (where l.entry_type = 'credit'), 0),
+			coalesce(sum(l.amount) filter (where l.entry_type = 'debit'), 0)
+		from transactions t left join ledgers l on l.transaction_id = t.id
+		group by t.id
+		having (t.status = 'success' and (count(l.id) <> case t.operation when 'transfer' then 2 else 1 end
+				or (t.operation = 'transfer' and coalesce(sum(l.amount) filter (where l.entry_type = 'credit'), 0)
+					<> coalesce(sum(l.amount) filter (where l.entry_type = 'debit'), 0))))
+			or (t.status <> 'success' and count(l.id) > 0)
+		order by t.id`)
+	if err != nil {
+		return Reconciliation{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var d TransactionDiscrepancy
+		err := rows.Scan(&d.Id, &d.Status, &d.Operation, &d.Ledgers, &d.Credits, &d.Debits)
+		if err != nil {
+			return Reconciliation{}, err
+		}
+		reconciliation.TransactionDiscrepancies = append(reconciliation.TransactionDiscrepancies, d)
+	}
+	if err := rows.Err(); err != nil {
+		return Reconciliation{}, err
+	}
+	return reconciliation, nil
+}
+
+// UpdateProfile
+// Validation and authorization are the caller's.
+func (r *Repo) UpdateProfile(ctx context.Context, username string, update ProfileUpdate) (User, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.ReadCommitted,
+	})
+	if err != nil {
+		return User{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var user User
+	err = tx.QueryRow(ctx, `update user_accounts ua set display_name=coalesce($1, display_name), email=coalesce($2, email),
+		status=coalesce($3, status), kyc_tier=coalesce($4, kyc_tier) where username_canonical=$5 returning `+userColumns,
+		update.DisplayName, update.Email, update.Status, update.KycTier, username).Scan(user.scanTargets()...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return User{}, utils.NotFoundErrorF("user")
+	}
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			err = utils.ToError(pgErr)
+		}
+		return User{}, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return User{}, err
+	}
+	return user, nil
+}
+
+func (r *Repo) UpdateTransactionStatus(ctx context.Context, id int64, status string) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+	_, err = tx.Exec(ctx, `
+    UPDATE transactions
+    SET status = $1 where id = $2`, status, id)
+	if err != nil {
+		return err
+	}
+
 	return tx.Commit(ctx)
 }
 
+// succeedTransaction
+// Sets the transaction status to success and publishes its ledgers in tx. wallets[i] is the wallet of ledgers[i].
+func (r *Repo) succeedTransaction(ctx context.Context, tx pgx.Tx, transaction Transaction, wallets []Wallet, ledgers []Ledger) error {
+	err := r.updateTransactionStatus(ctx, tx, transaction.Id, "success")
+	if err != nil {
+		return err
+	}
+	transaction.Status = "success"
+	return r.publisher.TransactionSucceeded(ctx, tx, transaction, wallets, ledgers)
+}
+
+// failTransaction
+// Sets the transaction status to error_<error code of cause> and publishes it, in its own db transaction as the one of
+// the operation is rolled back.
+func (r *Repo) failTransaction(ctx context.Context, transaction Transaction, cause error) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	err = r.failTransactionTx(ctx, tx, transaction, cause)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// failTransactionTx
+// Same as failTransaction in tx.
+func (r *Repo) failTransactionTx(ctx context.Context, tx pgx.Tx, transaction Transaction, cause error) error {
+	return r.endTransactionTx(ctx, tx, transaction, fmt.Sprintf("error_%s", utils.ErrorCodeOf(cause)))
+}
+
+// endTransactionTx
+// Sets the transaction to status, error_<error code>, rejected or expired, without ledgers and publishes it in tx.
+func (r *Repo) endTransactionTx(ctx context.Context, tx pgx.Tx, transaction Transaction, status string) error {
+	transaction.Status = status
+	err := r.updateTransactionStatus(ctx, tx, transaction.Id, transaction.Status)
+	if err != nil {
+		return err
+	}
+	return r.publisher.TransactionFailed(ctx, tx, transaction)
+}
+
+// appendLedger
+// created_at is the time of append under the wallet lock, so that ledgers of a wallet sorted by created_at are sorted
+// by balance snapshot.
 func (r *Repo) appendLedger(ctx context.Context, tx pgx.Tx, walletId int64, transactionId int64, entryType string, amount, balance decimal.Decimal) (Ledger, error) {
 	if tx == nil {
 		return Ledger{}, utils.NilTxError
 	}
 
 	row := tx.QueryRow(ctx, `insert into ledgers(wallet_id, entry_type, amount, balance, transaction_id, created_at)
-		values ($1,$2,$3,$4,$5,now()) returning id, wallet_id, entry_type, amount, created_at, balance, transaction_id`,
+		values ($1,$2,$3,$4,$5,clock_timestamp()) returning id, wallet_id, entry_type, amount, created_at, balance, transaction_id`,
 		walletId, entryType, amount, balance, transactionId)
 
 	var l Ledger
@@ -596,12 +1703,7 @@ func (r *Repo) newTransaction(ctx context.Context, nonce, requestorId int64, ope
 	}
 	defer tx.Rollback(ctx)
 
-	row := tx.QueryRow(ctx, `insert into transactions(requestor_id, nonce, status, operation, metadata, created_at) values ($1,$2,$3,$4,$5,now())
-	returning id, requestor_id, nonce, status, operation, created_at, metadata`,
-		requestorId, nonce, "pending", operation, metaData)
-
-	var l Transaction
-	err = row.Scan(&l.Id, &l.RequestorId, &l.Nonce, &l.Status, &l.Operation, &l.CreatedAt, &l.MetaData)
+	l, err := r.insertTransaction(ctx, tx, nonce, requestorId, "pending", operation, metaData)
 	if err != nil {
 		return Transaction{}, err
 	}
@@ -612,6 +1714,26 @@ func (r *Repo) newTransaction(ctx context.Context, nonce, requestorId int64, ope
 	return l, nil
 }
 
+func (r *Repo) insertTransaction(ctx context.Context, tx pgx.Tx, nonce, requestorId int64, status string, operation string, metaData map[string]any) (Transaction, error) {
+	if tx == nil {
+		return Transaction{}, utils.NilTxError
+	}
+	row := tx.QueryRow(ctx, `insert into transactions(requestor_id, nonce, status, operation, metadata, created_at) values ($1,$2,$3,$4,$5,now())
+	returning id, requestor_id, nonce, status, operation, created_at, metadata`,
+		requestorId, nonce, status, operation, metaData)
+
+	var l Transaction
+	err := row.Scan(&l.Id, &l.RequestorId, &l.Nonce, &l.Status, &l.Operation, &l.CreatedAt, &l.MetaData)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) {
+			err = utils.ToError(pgErr)
+		}
+		return Transaction{}, err
+	}
+	return l, nil
+}
+
 func (r *Repo) updateBalance(ctx context.Context, tx pgx.Tx, id int64, balance decimal.Decimal) error {
 	if tx == nil {
 		return utils.NilTxError
@@ -7,20 +7,134 @@ import (
 	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// ErrorCode
+// Stable machine-readable error identifier returned to clients. Do not rename existing codes.
+type ErrorCode string
+
+const (
+	ErrorCodeBadRequest         ErrorCode = "bad_request"
+	ErrorCodeInvalidArgument    ErrorCode = "invalid_argument"
+	ErrorCodeInvalidAmount      ErrorCode = "invalid_amount"
+	ErrorCodeInvalidNonce       ErrorCode = "invalid_nonce"
+	ErrorCodeUnauthorized       ErrorCode = "unauthorized"
+	ErrorCodeForbidden          ErrorCode = "forbidden"
+	ErrorCodeNotFound           ErrorCode = "not_found"
+	ErrorCodeAlreadyExists      ErrorCode = "already_exists"
+	ErrorCodeDuplicateNonce     ErrorCode = "duplicate_nonce"
+	ErrorCodeInsufficientFunds  ErrorCode = "insufficient_funds"
+	ErrorCodeCurrencyMismatch   ErrorCode = "currency_mismatch"
+	ErrorCodeAccountFrozen      ErrorCode = "account_frozen"
+	ErrorCodeWalletFrozen       ErrorCode = "wallet_frozen"
+	ErrorCodeWalletClosed       ErrorCode = "wallet_closed"
+	ErrorCodeWalletNotEmpty     ErrorCode = "wallet_not_empty"
+	ErrorCodeAccountSuspended   ErrorCode = "account_suspended"
+	ErrorCodeKycTierRequired    ErrorCode = "kyc_tier_required"
+	ErrorCodeKycLimitExceeded   ErrorCode = "kyc_limit_exceeded"
+	ErrorCodeApprovalExpired    ErrorCode = "approval_expired"
+	ErrorCodeSpendLimitExceeded ErrorCode = "spend_limit_exceeded"
+	ErrorCodePayloadTooLarge    ErrorCode = "payload_too_large"
+	ErrorCodeTooManyRequests    ErrorCode = "too_many_requests"
+	ErrorCodeInternal           ErrorCode = "internal_error"
+)
+
+// Error
+// Domain error with a stable Code. Message is safe to return to clients, Err is the internal cause and is never exposed.
+type Error struct {
+	Code    ErrorCode
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Message != "" {
+		return e.Message
+	}
+	return string(e.Code)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is
+// Errors are equal by Code, so errors.Is(err, InsufficientFundsError) holds for any insufficient_funds error.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+func NewError(code ErrorCode, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func WrapError(code ErrorCode, err error) *Error {
+	var message string
+	if err != nil {
+		message = err.Error()
+	}
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+// ErrorCodeOf
+// Returns ErrorCodeInternal for errors outside the catalogue, i.e. raw pgx errors.
+func ErrorCodeOf(err error) ErrorCode {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ErrorCodeInternal
+}
+
 var (
-	NilTxError           = errors.New("nil transaction")
-	UniqueViolationError = errors.New("unique_violation")
+	NilTxError              = errors.New("nil transaction")
+	UniqueViolationError    = NewError(ErrorCodeAlreadyExists, "")
+	DuplicateNonceError     = NewError(ErrorCodeDuplicateNonce, "")
+	InsufficientFundsError  = NewError(ErrorCodeInsufficientFunds, "")
+	CurrencyMismatchError   = NewError(ErrorCodeCurrencyMismatch, "")
+	AccountFrozenError      = NewError(ErrorCodeAccountFrozen, "")
+	WalletFrozenError       = NewError(ErrorCodeWalletFrozen, "")
+	WalletClosedError       = NewError(ErrorCodeWalletClosed, "")
+	WalletNotEmptyError     = NewError(ErrorCodeWalletNotEmpty, "")
+	AccountSuspendedError   = NewError(ErrorCodeAccountSuspended, "")
+	KycTierRequiredError    = NewError(ErrorCodeKycTierRequired, "")
+	ApprovalExpiredError    = NewError(ErrorCodeApprovalExpired, "")
+	SpendLimitExceededError = NewError(ErrorCodeSpendLimitExceeded, "")
+	InvalidAmountError      = NewError(ErrorCodeInvalidAmount, "")
+	InvalidNonceError       = NewError(ErrorCodeInvalidNonce, "")
+	UnauthorizedError       = NewError(ErrorCodeUnauthorized, "")
+	ForbiddenError          = NewError(ErrorCodeForbidden, "")
+	TooManyRequestsError    = NewError(ErrorCodeTooManyRequests, "")
 )
 
 func NotFoundErrorF(resourceName string) error {
-	return fmt.Errorf("resource: %s not found", resourceName)
+	return NewError(ErrorCodeNotFound, fmt.Sprintf("resource: %s not found", resourceName))
+}
+
+func InvalidArgumentErrorF(format string, a ...any) error {
+	return NewError(ErrorCodeInvalidArgument, fmt.Sprintf(format, a...))
+}
+
+func BadRequestErrorF(format string, a ...any) error {
+	return NewError(ErrorCodeBadRequest, fmt.Sprintf(format, a...))
+}
+
+func PayloadTooLargeErrorF(maxBytes int64) error {
+	return NewError(ErrorCodePayloadTooLarge, fmt.Sprintf("request body exceeds %d bytes", maxBytes))
+}
+
+func ForbiddenErrorF(format string, a ...any) error {
+	return NewError(ErrorCodeForbidden, fmt.Sprintf(format, a...))
+}
+
+func KycLimitExceededErrorF(maxAmount fmt.Stringer) error {
+	return NewError(ErrorCodeKycLimitExceeded, fmt.Sprintf("amount exceeds limit %s of kyc tier", maxAmount))
 }
 
 func ConstraintViolationErrorF(constraintName string) error {
 	if constraintName == "wallets_balance_check" {
-		return fmt.Errorf("insufficient_funds")
+		return InsufficientFundsError
 	}
-	return fmt.Errorf("constraint violation: %s", constraintName)
+	return NewError(ErrorCodeInvalidArgument, fmt.Sprintf("constraint violation: %s", constraintName))
 }
 
 var RowLengthShouldBeAtMost1Error = errors.New("length of rows should be at most 1")
@@ -32,7 +146,10 @@ func ToError(err *pgconn.PgError) error {
 
 	switch err.Code {
 	case "23505":
-		return UniqueViolationError
+		if err.ConstraintName == "transactions_nonce_idx" {
+			return &Error{Code: ErrorCodeDuplicateNonce, Err: err}
+		}
+		return &Error{Code: ErrorCodeAlreadyExists, Err: err}
 	case "23514":
 		return ConstraintViolationErrorF(err.ConstraintName)
 	}
@@ -0,0 +1,404 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/shopspring/decimal"
+)
+
+type EventType string
+
+const (
+	// EventTransactionSucceeded and EventTransactionFailed are sent to the requestor of the transaction.
+	EventTransactionSucceeded EventType = "transaction.succeeded"
+	EventTransactionFailed    EventType = "transaction.failed"
+	// EventBalanceChanged is sent to the members of the wallet, once per ledger.
+	EventBalanceChanged EventType = "balance.changed"
+)
+
+var EventTypes = []EventType{EventTransactionSucceeded, EventTransactionFailed, EventBalanceChanged}
+
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"
+	DeliveryStatusDelivered DeliveryStatus = "delivered"
+	// DeliveryStatusDead is not retried until replayed.
+	DeliveryStatusDead DeliveryStatus = "dead"
+)
+
+// Event
+// Payload of a delivery and the body of the webhook request.
+type Event struct {
+	Type      EventType `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// TransactionData
+// Data of EventTransactionSucceeded and EventTransactionFailed. Status is success or error_<error code>, or rejected or
+// expired for transfers pending approval.
+type TransactionData struct {
+	TransactionId int64  `json:"transaction_id"`
+	RequestorId   int64  `json:"requestor_id"`
+	Nonce         int64  `json:"nonce"`
+	Operation     string `json:"operation"`
+	Status        string `json:"status"`
+	MetaData      any    `json:"metadata"`
+}
+
+// BalanceData
+// Data of EventBalanceChanged.
+type BalanceData struct {
+	WalletId      int64           `json:"wallet_id"`
+	Currency      string          `json:"currency"`
+	TransactionId int64           `json:"transaction_id"`
+	LedgerId      int64           `json:"ledger_id"`
+	EntryType     string          `json:"entry_type"`
+	Amount        decimal.Decimal `json:"amount"`
+	Balance       decimal.Decimal `json:"balance"`
+}
+
+type Subscription struct {
+	Id            int64
+	UserAccountId int64
+	Url           string
+	// Secret is only returned by CreateSubscription.
+	Secret     string
+	EventTypes []EventType
+	CreatedAt  time.Time
+}
+
+type Delivery struct {
+	Id             int64
+	SubscriptionId int64
+	EventType      EventType
+	// Payload is the json encoded Event.
+	Payload       []byte
+	Status        DeliveryStatus
+	Attempts      int
+	NextAttemptAt time.Time
+	LastError     *string
+	CreatedAt     time.Time
+	DeliveredAt   *time.Time
+}
+
+// deliveryColumns
+// Columns of webhook_deliveries aliased d, in the order of Delivery.scanTargets.
+const deliveryColumns = "d.id, d.subscription_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at, d.last_error, d.created_at, d.delivered_at"
+
+func (d *Delivery) scanTargets() []any {
+	return []any{&d.Id, &d.SubscriptionId, &d.EventType, &d.Payload, &d.Status, &d.Attempts, &d.NextAttemptAt, &d.LastError, &d.CreatedAt, &d.DeliveredAt}
+}
+
+// ClaimedDelivery
+// Delivery with the endpoint of its subscription.
+type ClaimedDelivery struct {
+	Delivery Delivery
+	Url      string
+	Secret   string
+}
+
+// Repo
+// Users are looked up by canonical username, see policy.CanonicalUsername. Callers canonicalize usernames.
+type Repo struct {
+	conn *pgxpool.Pool
+}
+
+func New(conn *pgxpool.Pool) *Repo {
+	return &Repo{
+		conn: conn,
+	}
+}
+
+// Enqueue
+// Writes a delivery of the event to every active subscription of the user to it. Called with the transaction marking
+// the outbox event enqueued, see outboxrepo.Repo.EnqueueWebhooks.
+func Enqueue(ctx context.Context, tx pgx.Tx, userAccountId int64, event Event) error {
+	if tx == nil {
+		return utils.NilTxError
+	}
+	_, err := tx.Exec(ctx, `insert into webhook_deliveries(subscription_id, event_type, payload, status, attempts, next_attempt_at, created_at)
+select s.id, $2, $3, 'pending', 0, now(), now()
+from webhook_subscriptions s
+where s.user_account_id = $1 and s.active and $2 = any(s.event_types)`, userAccountId, event.Type, event)
+	return err
+}
+
+// EnqueueMembers
+// Same as Enqueue for every member of the wallet, of any role.
+func EnqueueMembers(ctx context.Context, tx pgx.Tx, walletId int64, event Event) error {
+	if tx == nil {
+		return utils.NilTxError
+	}
+	_, err := tx.Exec(ctx, `insert into webhook_deliveries(subscription_id, event_type, payload, status, attempts, next_attempt_at, created_at)
+select s.id, $2, $3, 'pending', 0, now(), now()
+from webhook_subscriptions s join wallet_memberships m on m.user_account_id = s.user_account_id
+where m.wallet_id = $1 and s.active and $2 = any(s.event_types)`, walletId, event.Type, event)
+	return err
+}
+
+func (r *Repo) CreateSubscription(ctx context.Context, username string, url string, secret string, eventTypes []EventType) (Subscription, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return Subscription{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	userAccountId, err := r.userAccountId(ctx, tx, username)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	s := Subscription{UserAccountId: userAccountId, Url: url, Secret: secret, EventTypes: eventTypes}
+	row := tx.QueryRow(ctx, `insert into webhook_subscriptions(user_account_id, url, secret, event_types, active, created_at)
+		values ($1,$2,$3,$4,true,now()) returning id, created_at`, userAccountId, url, secret, eventTypes)
+	if err := row.Scan(&s.Id, &s.CreatedAt); err != nil {
+		return Subscription{}, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return Subscription{}, err
+	}
+	return s, nil
+}
+
+// Subscriptions
+// Active subscriptions of the user sorted by oldest, without secrets.
+func (r *Repo) Subscriptions(ctx context.Context, username string) ([]Subscription, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.RepeatableRead,
+	})
+	if err != nil {
+		return []Subscription{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	userAccountId, err := r.userAccountId(ctx, tx, username)
+	if err != nil {
+		return []Subscription{}, err
+	}
+
+	rows, err := tx.Query(ctx, `select id, user_account_id, url, event_types, created_at from webhook_subscriptions
+		where user_account_id = $1 and active order by id`, userAccountId)
+	if err != nil {
+		return []Subscription{}, err
+	}
+	defer rows.Close()
+
+	subscriptions := []Subscription{}
+	for rows.Next() {
+		var s Subscription
+		if err := rows.Scan(&s.Id, &s.UserAccountId, &s.Url, &s.EventTypes, &s.CreatedAt); err != nil {
+			return []Subscription{}, err
+		}
+		subscriptions = append(subscriptions, s)
+	}
+	if err := rows.Err(); err != nil {
+		return []Subscription{}, err
+	}
+	return subscriptions, nil
+}
+
+// DeleteSubscription
+// Deactivates the subscription of the user. Its pending deliveries are dead, delivered and dead ones are kept.
+func (r *Repo) DeleteSubscription(ctx context.Context, username string, id int64) error {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	userAccountId, err := r.userAccountId(ctx, tx, username)
+	if err != nil {
+		return err
+	}
+
+	tag, err := tx.Exec(ctx, `update webhook_subscriptions set active = false where id = $1 and user_account_id = $2 and active`, id, userAccountId)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return utils.NotFoundErrorF("webhook")
+	}
+	_, err = tx.Exec(ctx, `update webhook_deliveries set status = 'dead', last_error = 'subscription deleted'
+		where subscription_id = $1 and status = 'pending'`, id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// Deliveries
+// Deliveries of the subscription of the user sorted by newest.
+func (r *Repo) Deliveries(ctx context.Context, username string, subscriptionId int64, limit int) ([]Delivery, error) {
+	tx, err := r.conn.BeginTx(ctx, pgx.TxOptions{
+		IsoLevel: pgx.RepeatableRead,
+	})
+	if err != nil {
+		return []Delivery{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := r.subscription(ctx, tx, username, subscriptionId); err != nil {
+		return []Delivery{}, err
+	}
+
+	rows, err := tx.Query(ctx, "select "+deliveryColumns+" from webhook_deliveries d where d.subscription_id = $1 order by d.id desc limit $2",
+		subscriptionId, limit)
+	if err != nil {
+		return []Delivery{}, err
+	}
+	defer rows.Close()
+
+	deliveries := []Delivery{}
+	for rows.Next() {
+		var d Delivery
+		if err := rows.Scan(d.scanTargets()...); err != nil {
+			return []Delivery{}, err
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return []Delivery{}, err
+	}
+	return deliveries, nil
+}
+
+// Replay
+// Schedules a delivery of an active subscription of the user for now with a fresh retry budget, whatever its status.
+func (r *Repo) Replay(ctx context.Context, username string, deliveryId int64) (Delivery, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return Delivery{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	userAccountId, err := r.userAccountId(ctx, tx, username)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	var d Delivery
+	row := tx.QueryRow(ctx, `update webhook_deliveries d
+set status = 'pending', attempts = 0, next_attempt_at = now(), last_error = null, delivered_at = null
+from webhook_subscriptions s
+where d.id = $1 and s.id = d.subscription_id and s.user_account_id = $2 and s.active
+returning `+deliveryColumns, deliveryId, userAccountId)
+	err = row.Scan(d.scanTargets()...)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Delivery{}, utils.NotFoundErrorF("delivery")
+	}
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return Delivery{}, err
+	}
+	return d, nil
+}
+
+// Claim
+// Takes up to limit due pending deliveries, oldest due first, and counts an attempt of each. Claimed deliveries are not
+// due again for lease, so that concurrent dispatchers do not send them twice and deliveries of a dispatcher stopped
+// mid-attempt are retried.
+func (r *Repo) Claim(ctx context.Context, limit int, lease time.Duration) ([]ClaimedDelivery, error) {
+	tx, err := r.conn.Begin(ctx)
+	if err != nil {
+		return []ClaimedDelivery{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, `update webhook_deliveries d
+set attempts = d.attempts + 1, next_attempt_at = now() + $2::bigint * interval '1 millisecond'
+from webhook_subscriptions s
+where s.id = d.subscription_id
+  and d.id in (select id from webhook_deliveries
+               where status = 'pending' and next_attempt_at <= now()
+               order by next_attempt_at
+               limit $1 for update skip locked)
+returning `+deliveryColumns+`, s.url, s.secret`, limit, lease.Milliseconds())
+	if err != nil {
+		return []ClaimedDelivery{}, err
+	}
+	defer rows.Close()
+
+	claimed := []ClaimedDelivery{}
+	for rows.Next() {
+		var c ClaimedDelivery
+		if err := rows.Scan(append(c.Delivery.scanTargets(), &c.Url, &c.Secret)...); err != nil {
+			return []ClaimedDelivery{}, err
+		}
+		claimed = append(claimed, c)
+	}
+	if err := rows.Err(); err != nil {
+		return []ClaimedDelivery{}, err
+	}
+	rows.Close()
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return []ClaimedDelivery{}, err
+	}
+	return claimed, nil
+}
+
+func (r *Repo) MarkDelivered(ctx context.Context, id int64) error {
+	_, err := r.conn.Exec(ctx, `update webhook_deliveries set status = 'delivered', delivered_at = now(), last_error = null
+		where id = $1 and status = 'pending'`, id)
+	return err
+}
+
+// MarkFailed
+// Records a failed attempt. The delivery is retried at retryAt, or dead if retryAt is nil.
+func (r *Repo) MarkFailed(ctx context.Context, id int64, lastError string, retryAt *time.Time) error {
+	_, err := r.conn.Exec(ctx, `update webhook_deliveries
+set status = case when $3::timestamptz is null then 'dead' else 'pending' end,
+    next_attempt_at = coalesce($3, next_attempt_at),
+    last_error = $2
+where id = $1 and status = 'pending'`, id, lastError, retryAt)
+	return err
+}
+
+func (r *Repo) userAccountId(ctx context.Context, tx pgx.Tx, username string) (int64, error) {
+	if tx == nil {
+		return 0, utils.NilTxError
+	}
+	var id int64
+	err := tx.QueryRow(ctx, "select id from user_accounts where username_canonical = $1", username).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, utils.NotFoundErrorF("user")
+	}
+	if err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// subscription
+// Active subscription of the user, without secret.
+func (r *Repo) subscription(ctx context.Context, tx pgx.Tx, username string, id int64) (Subscription, error) {
+	if tx == nil {
+		return Subscription{}, utils.NilTxError
+	}
+	var s Subscription
+	err := tx.QueryRow(ctx, `select s.id, s.user_account_id, s.url, s.event_types, s.created_at
+from webhook_subscriptions s join user_accounts ua on ua.id = s.user_account_id
+where s.id = $1 and ua.username_canonical = $2 and s.active`, id, username).Scan(&s.Id, &s.UserAccountId, &s.Url, &s.EventTypes, &s.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return Subscription{}, utils.NotFoundErrorF("webhook")
+	}
+	if err != nil {
+		return Subscription{}, err
+	}
+	return s, nil
+}
@@ -0,0 +1,201 @@
+package activity
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	activityrepo "github.com/cryptonlx/crypto/src/repositories/activity"
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+	"github.com/cryptonlx/crypto/src/services/policy"
+)
+
+const (
+	// SubscriptionBuffer
+	// Activities queued per subscriber. Subscribers falling further behind are dropped and resume from ledgers.
+	SubscriptionBuffer = 64
+
+	ledgersPageSize = 500
+	maxListenRetry  = 30 * time.Second
+)
+
+var ClosedError = errors.New("activity hub closed")
+
+// Authorizer
+// Implemented by userservice.Service.
+type Authorizer interface {
+	Authorize(ctx context.Context, principal string, action policy.Action, owner string) error
+}
+
+// Hub
+// Fans out activities notified on activityrepo.Channel to subscribers of the user, over one database connection per
+// server.
+type Hub struct {
+	repo       *activityrepo.Repo
+	authorizer Authorizer
+
+	mu          sync.Mutex
+	subscribers map[int64]map[*Subscription]struct{}
+	closed      bool
+}
+
+// Subscription
+// Activities of one user. Events is closed when the subscription is dropped, the client resumes from the last ledger.
+type Subscription struct {
+	UserAccountId int64
+	events        chan activityrepo.Activity
+	hub           *Hub
+}
+
+func (s *Subscription) Events() <-chan activityrepo.Activity {
+	return s.events
+}
+
+func (s *Subscription) Close() {
+	s.hub.drop(s)
+}
+
+func New(repo *activityrepo.Repo, authorizer Authorizer) *Hub {
+	return &Hub{
+		repo:        repo,
+		authorizer:  authorizer,
+		subscribers: map[int64]map[*Subscription]struct{}{},
+	}
+}
+
+// Run
+// Listens until ctx is done, reconnecting on failure. Subscribers are dropped on failure as notifications are lost
+// until reconnected.
+func (h *Hub) Run(ctx context.Context) {
+	retry := time.Second
+	for {
+		started := time.Now()
+		err := h.repo.Listen(ctx, h.publish)
+		if ctx.Err() != nil {
+			return
+		}
+		log.Printf("activity listen err %v ; retrying in %s\n", err, retry)
+		h.dropAll()
+
+		if time.Since(started) > maxListenRetry {
+			retry = time.Second
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry):
+		}
+		retry = min(2*retry, maxListenRetry)
+	}
+}
+
+// Subscribe
+// Activities of username for principal from now on. Principals that cannot read the user get user not found.
+func (h *Hub) Subscribe(ctx context.Context, principal string, username string) (*Subscription, error) {
+	if username == "" {
+		return nil, utils.InvalidArgumentErrorF("user name cannot be empty")
+	}
+	err := h.authorizer.Authorize(ctx, principal, policy.ActionReadUser, username)
+	if utils.ErrorCodeOf(err) == utils.ErrorCodeForbidden {
+		return nil, utils.NotFoundErrorF("user")
+	}
+	if err != nil {
+		return nil, err
+	}
+	userAccountId, err := h.repo.UserAccountId(ctx, policy.CanonicalUsername(username))
+	if err != nil {
+		return nil, err
+	}
+	return h.subscribe(userAccountId)
+}
+
+// LedgersAfter
+// Ledgers of the subscribed user after ledger afterId sorted by id, to resume a stream. Subscribe first, so that
+// ledgers committed meanwhile are not missed.
+func (h *Hub) LedgersAfter(ctx context.Context, s *Subscription, afterId int64, fn func(userrepo.Ledger) error) error {
+	for {
+		ledgers, err := h.repo.LedgersAfter(ctx, s.UserAccountId, afterId, ledgersPageSize)
+		if err != nil {
+			return err
+		}
+		for _, l := range ledgers {
+			if err := fn(l); err != nil {
+				return err
+			}
+			afterId = l.Id
+		}
+		if len(ledgers) < ledgersPageSize {
+			return nil
+		}
+	}
+}
+
+// Close
+// Drops every subscriber and rejects new ones, for streams to end on server shutdown.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	h.mu.Unlock()
+	h.dropAll()
+}
+
+func (h *Hub) subscribe(userAccountId int64) (*Subscription, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, ClosedError
+	}
+	s := &Subscription{UserAccountId: userAccountId, events: make(chan activityrepo.Activity, SubscriptionBuffer), hub: h}
+	if h.subscribers[userAccountId] == nil {
+		h.subscribers[userAccountId] = map[*Subscription]struct{}{}
+	}
+	h.subscribers[userAccountId][s] = struct{}{}
+	return s, nil
+}
+
+// publish
+// Never blocks the listener, subscribers with a full buffer are dropped.
+func (h *Hub) publish(a activityrepo.Activity) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, userAccountId := range a.UserAccountIds {
+		for s := range h.subscribers[userAccountId] {
+			select {
+			case s.events <- a:
+			default:
+				h.dropLocked(s)
+			}
+		}
+	}
+}
+
+func (h *Hub) drop(s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.dropLocked(s)
+}
+
+func (h *Hub) dropLocked(s *Subscription) {
+	subscribers := h.subscribers[s.UserAccountId]
+	if _, ok := subscribers[s]; !ok {
+		return
+	}
+	delete(subscribers, s)
+	if len(subscribers) == 0 {
+		delete(h.subscribers, s.UserAccountId)
+	}
+	close(s.events)
+}
+
+func (h *Hub) dropAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, subscribers := range h.subscribers {
+		for s := range subscribers {
+			h.dropLocked(s)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package activity
+
+import (
+	"testing"
+
+	activityrepo "github.com/cryptonlx/crypto/src/repositories/activity"
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+)
+
+func TestHubPublish(t *testing.T) {
+	h := New(nil, nil)
+	alice, _ := h.subscribe(1)
+	alice2, _ := h.subscribe(1)
+	bob, _ := h.subscribe(2)
+
+	h.publish(activityrepo.Activity{Kind: activityrepo.KindLedger, UserAccountIds: []int64{1}, Ledger: &userrepo.Ledger{Id: 10}})
+
+	for _, s := range []*Subscription{alice, alice2} {
+		if a := <-s.Events(); a.Ledger == nil || a.Ledger.Id != 10 {
+			t.Fatalf("subscriber of user 1 want ledger 10. got %+v", a)
+		}
+	}
+	select {
+	case a := <-bob.Events():
+		t.Fatalf("subscriber of user 2 want nothing. got %+v", a)
+	default:
+	}
+
+	alice2.Close()
+	alice2.Close()
+	if _, ok := <-alice2.Events(); ok {
+		t.Fatalf("closed subscription want closed events")
+	}
+	if n := len(h.subscribers[1]); n != 1 {
+		t.Fatalf("subscribers of user 1 want 1. got %d", n)
+	}
+}
+
+func TestHubPublishToMembers(t *testing.T) {
+	h := New(nil, nil)
+	owner, _ := h.subscribe(1)
+	viewer, _ := h.subscribe(2)
+	stranger, _ := h.subscribe(3)
+
+	h.publish(activityrepo.Activity{Kind: activityrepo.KindLedger, UserAccountIds: []int64{1, 2}, Ledger: &userrepo.Ledger{Id: 11}})
+
+	for _, s := range []*Subscription{owner, viewer} {
+		if a := <-s.Events(); a.Ledger == nil || a.Ledger.Id != 11 {
+			t.Fatalf("subscriber of member %d want ledger 11. got %+v", s.UserAccountId, a)
+		}
+	}
+	select {
+	case a := <-stranger.Events():
+		t.Fatalf("subscriber of non-member want nothing. got %+v", a)
+	default:
+	}
+}
+
+func TestHubDropsSlowSubscriber(t *testing.T) {
+	h := New(nil, nil)
+	slow, _ := h.subscribe(1)
+
+	for i := 0; i <= SubscriptionBuffer; i++ {
+		h.publish(activityrepo.Activity{Kind: activityrepo.KindTransaction, UserAccountIds: []int64{1}, Transaction: &activityrepo.Transaction{Id: int64(i)}})
+	}
+
+	n := 0
+	for range slow.Events() {
+		n++
+	}
+	if n != SubscriptionBuffer {
+		t.Fatalf("slow subscriber want %d buffered activities then closed. got %d", SubscriptionBuffer, n)
+	}
+	if len(h.subscribers) != 0 {
+		t.Fatalf("slow subscriber want dropped. got %v", h.subscribers)
+	}
+}
+
+func TestHubClose(t *testing.T) {
+	h := New(nil, nil)
+	s, _ := h.subscribe(1)
+
+	h.Close()
+	if _, ok := <-s.Events(); ok {
+		t.Fatalf("subscription want closed on hub close")
+	}
+	if _, err := h.subscribe(1); err != ClosedError {
+		t.Fatalf("subscribe after close want ClosedError. got %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+
+	auditrepo "github.com/cryptonlx/crypto/src/repositories/audit"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+	"github.com/cryptonlx/crypto/src/services/policy"
+)
+
+const (
+	DefaultEventsLimit = 100
+	MaxEventsLimit     = 1000
+)
+
+// Authorizer
+// Implemented by userservice.Service.
+type Authorizer interface {
+	Authorize(ctx context.Context, principal string, action policy.Action, owner string) error
+}
+
+type Service struct {
+	repo       *auditrepo.Repo
+	authorizer Authorizer
+}
+
+func New(repo *auditrepo.Repo, authorizer Authorizer) *Service {
+	return &Service{repo: repo, authorizer: authorizer}
+}
+
+// OutcomeFromHttpStatus
+// 4xx are rejected by validation or authorization, 5xx are failures of the server.
+func OutcomeFromHttpStatus(httpStatus int) auditrepo.Outcome {
+	switch {
+	case httpStatus >= http.StatusInternalServerError:
+		return auditrepo.OutcomeFailed
+	case httpStatus >= http.StatusBadRequest:
+		return auditrepo.OutcomeRejected
+	}
+	return auditrepo.OutcomeSuccess
+}
+
+func (s Service) Record(ctx context.Context, event auditrepo.Event) (auditrepo.Event, error) {
+	if event.Route == "" {
+		return auditrepo.Event{}, utils.InvalidArgumentErrorF("route cannot be empty")
+	}
+	event.Outcome = OutcomeFromHttpStatus(event.HttpStatus)
+
+	return s.repo.Append(ctx, event)
+}
+
+func (s Service) Events(ctx context.Context, requestor string, filter auditrepo.Filter) ([]auditrepo.Event, error) {
+	if err := s.authorizer.Authorize(ctx, requestor, policy.ActionReadAudit, ""); err != nil {
+		return []auditrepo.Event{}, err
+	}
+	if filter.Limit < 0 || filter.Limit > MaxEventsLimit {
+		return []auditrepo.Event{}, utils.InvalidArgumentErrorF("invalid_limit")
+	}
+	if filter.Limit == 0 {
+		filter.Limit = DefaultEventsLimit
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return []auditrepo.Event{}, utils.InvalidArgumentErrorF("invalid_time_range")
+	}
+
+	return s.repo.Events(ctx, filter)
+}
@@ -0,0 +1,91 @@
+package health
+
+import (
+	"context"
+	"time"
+
+	"github.com/cryptonlx/crypto/src/repositories/migrations"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type DatabaseCheck struct {
+	Up      bool
+	Latency time.Duration
+	Err     error
+}
+
+// MigrationsCheck
+// Up once the schema is migrated to at least the version the server expects. A newer schema is Ahead, as during a
+// rolling deploy after the new release migrated, and does not fail readiness: migrations keep the previous release
+// working.
+type MigrationsCheck struct {
+	Up              bool
+	Ahead           bool
+	Version         int64
+	ExpectedVersion int64
+	Err             error
+}
+
+// PoolCheck
+// Saturated when every connection is acquired, new queries wait for a connection to be released.
+// Informational only, a saturated pool does not fail readiness.
+type PoolCheck struct {
+	AcquiredConns int32
+	IdleConns     int32
+	TotalConns    int32
+	MaxConns      int32
+	Saturation    float64
+	Saturated     bool
+}
+
+type Report struct {
+	Database   DatabaseCheck
+	Migrations MigrationsCheck
+	Pool       PoolCheck
+}
+
+func (r Report) Ready() bool {
+	return r.Database.Up && r.Migrations.Up
+}
+
+type Service struct {
+	conn     *pgxpool.Pool
+	migrator *migrations.Migrator
+}
+
+func New(conn *pgxpool.Pool, migrator *migrations.Migrator) *Service {
+	return &Service{
+		conn:     conn,
+		migrator: migrator,
+	}
+}
+
+func (s Service) Check(ctx context.Context) Report {
+	var report Report
+
+	start := time.Now()
+	report.Database.Err = s.conn.Ping(ctx)
+	report.Database.Latency = time.Since(start)
+	report.Database.Up = report.Database.Err == nil
+
+	report.Migrations.ExpectedVersion = s.migrator.LatestVersion()
+	if report.Database.Up {
+		report.Migrations.Version, report.Migrations.Err = s.migrator.Version(ctx)
+		report.Migrations.Up = report.Migrations.Err == nil && report.Migrations.Version >= report.Migrations.ExpectedVersion
+		report.Migrations.Ahead = report.Migrations.Up && report.Migrations.Version > report.Migrations.ExpectedVersion
+	}
+
+	stat := s.conn.Stat()
+	report.Pool = PoolCheck{
+		AcquiredConns: stat.AcquiredConns(),
+		IdleConns:     stat.IdleConns(),
+		TotalConns:    stat.TotalConns(),
+		MaxConns:      stat.MaxConns(),
+	}
+	if report.Pool.MaxConns > 0 {
+		report.Pool.Saturation = float64(report.Pool.AcquiredConns) / float64(report.Pool.MaxConns)
+		report.Pool.Saturated = report.Pool.AcquiredConns >= report.Pool.MaxConns
+	}
+	return report
+}
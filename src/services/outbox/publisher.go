@@ -0,0 +1,99 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+
+	outboxrepo "github.com/cryptonlx/crypto/src/repositories/outbox"
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Publisher
+// userrepo.Publisher emitting the domain events of changes to events, i.e. outboxrepo.Outbox.
+type Publisher struct {
+	events outboxrepo.EventPublisher
+}
+
+func NewPublisher(events outboxrepo.EventPublisher) *Publisher {
+	return &Publisher{
+		events: events,
+	}
+}
+
+func (p *Publisher) UserCreated(ctx context.Context, tx pgx.Tx, user userrepo.User) error {
+	return p.events.Publish(ctx, tx, outboxrepo.UserCreated{UserAccountId: user.Id, Username: user.Username})
+}
+
+func (p *Publisher) WalletCreated(ctx context.Context, tx pgx.Tx, wallet userrepo.Wallet) error {
+	return p.events.Publish(ctx, tx, outboxrepo.WalletCreated{
+		WalletId:      wallet.Id,
+		UserAccountId: wallet.UserAccountId,
+		Currency:      wallet.Currency,
+	})
+}
+
+// TransactionSucceeded
+// Publishes TransferCompleted for transfers, FundsDeposited or FundsWithdrawn per ledger otherwise.
+func (p *Publisher) TransactionSucceeded(ctx context.Context, tx pgx.Tx, transaction userrepo.Transaction, wallets []userrepo.Wallet, ledgers []userrepo.Ledger) error {
+	metaData, err := json.Marshal(transaction.MetaData)
+	if err != nil {
+		return err
+	}
+
+	if transaction.Operation == "transfer" && len(ledgers) == 2 {
+		return p.events.Publish(ctx, tx, outboxrepo.TransferCompleted{
+			TransactionId:       transaction.Id,
+			RequestorId:         transaction.RequestorId,
+			Nonce:               transaction.Nonce,
+			SourceWalletId:      ledgers[0].WalletId,
+			DestinationWalletId: ledgers[1].WalletId,
+			Currency:            wallets[0].Currency,
+			Amount:              ledgers[0].Amount,
+			SourceLedgerId:      ledgers[0].Id,
+			SourceBalance:       ledgers[0].Balance,
+			DestinationLedgerId: ledgers[1].Id,
+			DestinationBalance:  ledgers[1].Balance,
+			MetaData:            metaData,
+		})
+	}
+	events := make([]outboxrepo.Event, 0, len(ledgers))
+	for i, l := range ledgers {
+		funds := outboxrepo.Funds{
+			TransactionId: transaction.Id,
+			RequestorId:   transaction.RequestorId,
+			Nonce:         transaction.Nonce,
+			Operation:     transaction.Operation,
+			WalletId:      l.WalletId,
+			UserAccountId: wallets[i].UserAccountId,
+			Currency:      wallets[i].Currency,
+			LedgerId:      l.Id,
+			Amount:        l.Amount,
+			Balance:       l.Balance,
+			MetaData:      metaData,
+		}
+		if l.EntryType == "credit" {
+			events = append(events, outboxrepo.FundsDeposited{Funds: funds})
+		} else {
+			events = append(events, outboxrepo.FundsWithdrawn{Funds: funds})
+		}
+	}
+	return p.events.Publish(ctx, tx, events...)
+}
+
+func (p *Publisher) TransactionFailed(ctx context.Context, tx pgx.Tx, transaction userrepo.Transaction) error {
+	metaData, err := json.Marshal(transaction.MetaData)
+	if err != nil {
+		return err
+	}
+	return p.events.Publish(ctx, tx, outboxrepo.TransactionFailed{
+		TransactionId:  transaction.Id,
+		RequestorId:    transaction.RequestorId,
+		Nonce:          transaction.Nonce,
+		Operation:      transaction.Operation,
+		Status:         transaction.Status,
+		SourceWalletId: transaction.MetaData.SourceWalletId,
+		MetaData:       metaData,
+	})
+}
@@ -0,0 +1,78 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	outboxrepo "github.com/cryptonlx/crypto/src/repositories/outbox"
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/shopspring/decimal"
+)
+
+type events []outboxrepo.Event
+
+func (e *events) Publish(ctx context.Context, tx pgx.Tx, published ...outboxrepo.Event) error {
+	*e = append(*e, published...)
+	return nil
+}
+
+func TestPublisherTransactionSucceeded(t *testing.T) {
+	source, destination := int64(4), int64(5)
+	transfer := userrepo.Transaction{Id: 9, RequestorId: 2, Nonce: 1749286345000, Operation: "transfer", Status: "success",
+		MetaData: userrepo.TransactionMetaData{SourceWalletId: &source, DestinationWalletId: &destination}}
+	wallets := []userrepo.Wallet{{Id: 4, UserAccountId: 2, Currency: "SGD"}, {Id: 5, UserAccountId: 3, Currency: "SGD"}}
+	ledgers := []userrepo.Ledger{
+		{Id: 11, WalletId: 4, EntryType: "debit", Amount: decimal.NewFromInt(3), Balance: decimal.NewFromInt(7), TransactionId: 9},
+		{Id: 12, WalletId: 5, EntryType: "credit", Amount: decimal.NewFromInt(3), Balance: decimal.NewFromInt(3), TransactionId: 9},
+	}
+
+	var published events
+	if err := NewPublisher(&published).TransactionSucceeded(context.Background(), nil, transfer, wallets, ledgers); err != nil {
+		t.Fatalf("TransactionSucceeded err %v", err)
+	}
+	metaData, _ := json.Marshal(transfer.MetaData)
+	want := events{outboxrepo.TransferCompleted{
+		TransactionId:       9,
+		RequestorId:         2,
+		Nonce:               1749286345000,
+		SourceWalletId:      4,
+		DestinationWalletId: 5,
+		Currency:            "SGD",
+		Amount:              decimal.NewFromInt(3),
+		SourceLedgerId:      11,
+		SourceBalance:       decimal.NewFromInt(7),
+		DestinationLedgerId: 12,
+		DestinationBalance:  decimal.NewFromInt(3),
+		MetaData:            metaData,
+	}}
+	if !reflect.DeepEqual(published, want) {
+		t.Fatalf("want %+v. got %+v", want, published)
+	}
+
+	published = nil
+	deposit := userrepo.Transaction{Id: 10, RequestorId: 2, Nonce: 1, Operation: "deposit", Status: "success"}
+	if err := NewPublisher(&published).TransactionSucceeded(context.Background(), nil, deposit, wallets[:1], ledgers[1:]); err != nil {
+		t.Fatalf("TransactionSucceeded err %v", err)
+	}
+	if len(published) != 1 || published[0].Type() != outboxrepo.EventFundsDeposited || published[0].Key() != "wallet:5" {
+		t.Fatalf("want funds.deposited of wallet:5. got %+v", published)
+	}
+}
+
+func TestPublisherTransactionFailed(t *testing.T) {
+	var published events
+	transaction := userrepo.Transaction{Id: 9, RequestorId: 2, Nonce: 1, Operation: "withdraw", Status: "error_insufficient_funds"}
+	if err := NewPublisher(&published).TransactionFailed(context.Background(), nil, transaction); err != nil {
+		t.Fatalf("TransactionFailed err %v", err)
+	}
+	if len(published) != 1 || published[0].Type() != outboxrepo.EventTransactionFailed || published[0].Key() != "user:2" {
+		t.Fatalf("want transaction.failed of user:2. got %+v", published)
+	}
+	if failed := published[0].(outboxrepo.TransactionFailed); failed.Status != "error_insufficient_funds" || failed.Nonce != 1 {
+		t.Fatalf("unexpected event %+v", failed)
+	}
+}
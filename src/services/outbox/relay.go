@@ -0,0 +1,112 @@
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	outboxrepo "github.com/cryptonlx/crypto/src/repositories/outbox"
+)
+
+const (
+	SinkLog   = "log"
+	SinkFile  = "file"
+	SinkNats  = "nats"
+	SinkKafka = "kafka"
+)
+
+var Sinks = []string{SinkLog, SinkFile, SinkNats, SinkKafka}
+
+// Sink
+// Destination of relayed messages. Send returns once the message is accepted, messages are sent one at a time in
+// order.
+type Sink interface {
+	Send(ctx context.Context, m outboxrepo.Message) error
+	Close() error
+}
+
+type SinkParams struct {
+	// Sink is one of Sinks.
+	Sink string
+	// FilePath of the file sink, appended with one json message per line.
+	FilePath string
+	// NatsUrl of the nats sink, i.e. nats://localhost:4222. Messages are published to <NatsSubject>.<event type>.
+	NatsUrl     string
+	NatsSubject string
+	// KafkaRestUrl of the kafka sink, a Kafka REST proxy i.e. http://localhost:8082. Messages are produced to KafkaTopic
+	// keyed by the message key.
+	KafkaRestUrl string
+	KafkaTopic   string
+	// Timeout of one send.
+	Timeout time.Duration
+}
+
+func NewSink(params SinkParams) (Sink, error) {
+	switch params.Sink {
+	case SinkLog:
+		return LogSink{}, nil
+	case SinkFile:
+		return NewFileSink(params.FilePath)
+	case SinkNats:
+		return NewNatsSink(params.NatsUrl, params.NatsSubject, params.Timeout)
+	case SinkKafka:
+		return NewKafkaSink(params.KafkaRestUrl, params.KafkaTopic, params.Timeout), nil
+	}
+	return nil, fmt.Errorf("unknown outbox sink %q", params.Sink)
+}
+
+type RelayParams struct {
+	// Interval between polls of unpublished messages.
+	Interval time.Duration
+	// BatchSize is the messages relayed per poll.
+	BatchSize int
+}
+
+// Relay
+// Relays published events to a sink, at least once and in id order. One instance relays at a time.
+type Relay struct {
+	repo   *outboxrepo.Repo
+	sink   Sink
+	params RelayParams
+}
+
+func NewRelay(repo *outboxrepo.Repo, sink Sink, params RelayParams) *Relay {
+	return &Relay{
+		repo:   repo,
+		sink:   sink,
+		params: params,
+	}
+}
+
+// Run
+// Relays every Interval until ctx is done. A message in flight when ctx is done is relayed again.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.params.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for {
+			n, err := r.RelayBatch(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("outbox relay err %v\n", err)
+			}
+			// a full batch suggests a backlog, poll again without waiting
+			if err != nil || n < r.params.BatchSize {
+				break
+			}
+		}
+	}
+}
+
+// RelayBatch
+// Sends one batch of messages. Returns the number of messages published.
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	return r.repo.Relay(ctx, r.params.BatchSize, func(m outboxrepo.Message) error {
+		return r.sink.Send(ctx, m)
+	})
+}
@@ -0,0 +1,270 @@
+package outbox
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	outboxrepo "github.com/cryptonlx/crypto/src/repositories/outbox"
+)
+
+// LogSink
+// Logs messages, for development.
+type LogSink struct{}
+
+func (LogSink) Send(ctx context.Context, m outboxrepo.Message) error {
+	log.Printf("outbox event id=%d type=%s key=%s data=%s\n", m.Id, m.Type, m.Key, m.Data)
+	return nil
+}
+
+func (LogSink) Close() error {
+	return nil
+}
+
+// FileSink
+// Appends messages as json lines, each synced to disk before it is accepted.
+type FileSink struct {
+	file *os.File
+}
+
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o640)
+	if err != nil {
+		return nil, err
+	}
+	return &FileSink{file: file}, nil
+}
+
+func (s *FileSink) Send(ctx context.Context, m outboxrepo.Message) error {
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if _, err := s.file.Write(append(b, '\n')); err != nil {
+		return err
+	}
+	return s.file.Sync()
+}
+
+func (s *FileSink) Close() error {
+	return s.file.Close()
+}
+
+// NatsSink
+// Publishes messages with the NATS client protocol, each confirmed by a PING round trip before it is accepted. The
+// connection is opened on first send and reopened after a failure. TLS is not supported.
+type NatsSink struct {
+	address string
+	user    *url.Userinfo
+	subject string
+	timeout time.Duration
+	conn    net.Conn
+	reader  *bufio.Reader
+}
+
+func NewNatsSink(natsUrl string, subject string, timeout time.Duration) (*NatsSink, error) {
+	u, err := url.Parse(natsUrl)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "nats" || u.Host == "" {
+		return nil, fmt.Errorf("want nats://host:port. got %q", natsUrl)
+	}
+	if subject == "" || strings.ContainsAny(subject, " \t\r\n*>") {
+		return nil, fmt.Errorf("invalid nats subject %q", subject)
+	}
+	return &NatsSink{address: u.Host, user: u.User, subject: subject, timeout: timeout}, nil
+}
+
+func (s *NatsSink) Send(ctx context.Context, m outboxrepo.Message) error {
+	err := s.send(ctx, m)
+	if err != nil {
+		s.Close()
+	}
+	return err
+}
+
+func (s *NatsSink) send(ctx context.Context, m outboxrepo.Message) error {
+	if s.conn == nil {
+		if err := s.connect(ctx); err != nil {
+			return err
+		}
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	s.conn.SetDeadline(deadline(ctx, s.timeout))
+	if _, err := fmt.Fprintf(s.conn, "PUB %s.%s %d\r\n%s\r\nPING\r\n", s.subject, m.Type, len(b), b); err != nil {
+		return err
+	}
+	return s.awaitPong()
+}
+
+func (s *NatsSink) connect(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: s.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", s.address)
+	if err != nil {
+		return err
+	}
+	s.conn, s.reader = conn, bufio.NewReader(conn)
+	s.conn.SetDeadline(deadline(ctx, s.timeout))
+
+	line, err := s.readLine()
+	if err != nil {
+		return err
+	}
+	infoJson, ok := strings.CutPrefix(line, "INFO ")
+	if !ok {
+		return fmt.Errorf("nats want INFO. got %q", line)
+	}
+	var info struct {
+		TLSRequired bool `json:"tls_required"`
+	}
+	if err := json.Unmarshal([]byte(infoJson), &info); err != nil {
+		return err
+	}
+	if info.TLSRequired {
+		return errors.New("nats server requires TLS")
+	}
+
+	options := map[string]any{"verbose": false, "pedantic": false, "name": "crypto-outbox", "lang": "go", "version": "1"}
+	if s.user != nil {
+		options["user"] = s.user.Username()
+		if pass, ok := s.user.Password(); ok {
+			options["pass"] = pass
+		}
+	}
+	b, err := json.Marshal(options)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(s.conn, "CONNECT %s\r\nPING\r\n", b); err != nil {
+		return err
+	}
+	return s.awaitPong()
+}
+
+// awaitPong
+// Reads until the PONG of the last PING. Errors reported by the server before are returned.
+func (s *NatsSink) awaitPong() error {
+	for {
+		line, err := s.readLine()
+		if err != nil {
+			return err
+		}
+		switch {
+		case line == "PONG":
+			return nil
+		case line == "PING":
+			if _, err := io.WriteString(s.conn, "PONG\r\n"); err != nil {
+				return err
+			}
+		case strings.HasPrefix(line, "-ERR"):
+			return fmt.Errorf("nats %s", line)
+		}
+	}
+}
+
+func (s *NatsSink) readLine() (string, error) {
+	line, err := s.reader.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+func (s *NatsSink) Close() error {
+	if s.conn == nil {
+		return nil
+	}
+	err := s.conn.Close()
+	s.conn, s.reader = nil, nil
+	return err
+}
+
+// KafkaSink
+// Produces messages through a Kafka REST proxy (v2 api, i.e. Confluent REST Proxy or Redpanda), keyed by the message
+// key so that messages of a wallet are in one partition.
+type KafkaSink struct {
+	url    string
+	client *http.Client
+}
+
+func NewKafkaSink(restUrl string, topic string, timeout time.Duration) *KafkaSink {
+	return &KafkaSink{
+		url:    strings.TrimRight(restUrl, "/") + "/topics/" + url.PathEscape(topic),
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *KafkaSink) Send(ctx context.Context, m outboxrepo.Message) error {
+	b, err := json.Marshal(map[string]any{
+		"records": []map[string]any{{"key": m.Key, "value": m}},
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/vnd.kafka.json.v2+json")
+	req.Header.Set("Accept", "application/vnd.kafka.v2+json")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+		return fmt.Errorf("kafka rest http status %d", res.StatusCode)
+	}
+	var produced struct {
+		Offsets []struct {
+			ErrorCode *int    `json:"error_code"`
+			Error     *string `json:"error"`
+		} `json:"offsets"`
+	}
+	if err := json.NewDecoder(io.LimitReader(res.Body, 64<<10)).Decode(&produced); err != nil {
+		return err
+	}
+	if len(produced.Offsets) != 1 {
+		return fmt.Errorf("kafka rest want 1 offset. got %d", len(produced.Offsets))
+	}
+	if o := produced.Offsets[0]; o.ErrorCode != nil {
+		var detail string
+		if o.Error != nil {
+			detail = *o.Error
+		}
+		return fmt.Errorf("kafka rest error_code %d %s", *o.ErrorCode, detail)
+	}
+	return nil
+}
+
+func (s *KafkaSink) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// deadline
+// Of one operation, timeout from now or the deadline of ctx if sooner.
+func deadline(ctx context.Context, timeout time.Duration) time.Time {
+	d := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(d) {
+		return ctxDeadline
+	}
+	return d
+}
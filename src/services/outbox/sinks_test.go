@@ -0,0 +1,173 @@
+package outbox
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	outboxrepo "github.com/cryptonlx/crypto/src/repositories/outbox"
+)
+
+func message(id int64) outboxrepo.Message {
+	return outboxrepo.Message{
+		Id:        id,
+		Type:      outboxrepo.EventFundsDeposited,
+		Key:       outboxrepo.WalletKey(4),
+		CreatedAt: time.Date(2025, 6, 9, 2, 2, 31, 0, time.UTC),
+		Data:      json.RawMessage(`{"wallet_id":4,"amount":"1.5"}`),
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	sink, err := NewFileSink(path)
+	if err != nil {
+		t.Fatalf("NewFileSink err %v", err)
+	}
+	for id := int64(1); id <= 2; id++ {
+		if err := sink.Send(context.Background(), message(id)); err != nil {
+			t.Fatalf("Send err %v", err)
+		}
+	}
+	sink.Close()
+
+	b, _ := os.ReadFile(path)
+	want := `{"id":1,"type":"funds.deposited","key":"wallet:4","created_at":"2025-06-09T02:02:31Z","data":{"wallet_id":4,"amount":"1.5"}}` + "\n" +
+		`{"id":2,"type":"funds.deposited","key":"wallet:4","created_at":"2025-06-09T02:02:31Z","data":{"wallet_id":4,"amount":"1.5"}}` + "\n"
+	if string(b) != want {
+		t.Fatalf("want %q. got %q", want, b)
+	}
+}
+
+// natsServer
+// Accepts one connection at a time, records PUBs and answers -ERR to PUBs of subject reject.
+func natsServer(t *testing.T, reject string) (addr string, pubs chan string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen err %v", err)
+	}
+	t.Cleanup(func() { l.Close() })
+	pubs = make(chan string, 10)
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			r := bufio.NewReader(conn)
+			io.WriteString(conn, `INFO {"server_id":"test","tls_required":false}`+"\r\n")
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					break
+				}
+				line = strings.TrimRight(line, "\r\n")
+				switch {
+				case strings.HasPrefix(line, "CONNECT "):
+				case line == "PING":
+					io.WriteString(conn, "PONG\r\n")
+				case strings.HasPrefix(line, "PUB "):
+					var subject string
+					var n int
+					fmt.Sscanf(line, "PUB %s %d", &subject, &n)
+					payload := make([]byte, n+2)
+					io.ReadFull(r, payload)
+					if subject == reject {
+						io.WriteString(conn, "-ERR 'Permissions Violation'\r\n")
+						continue
+					}
+					pubs <- subject + " " + string(payload[:n])
+				}
+			}
+			conn.Close()
+		}
+	}()
+	return l.Addr().String(), pubs
+}
+
+func TestNatsSink(t *testing.T) {
+	addr, pubs := natsServer(t, "crypto.events.transaction.failed")
+	sink, err := NewNatsSink("nats://"+addr, "crypto.events", time.Second)
+	if err != nil {
+		t.Fatalf("NewNatsSink err %v", err)
+	}
+	defer sink.Close()
+
+	if err := sink.Send(context.Background(), message(1)); err != nil {
+		t.Fatalf("Send err %v", err)
+	}
+	if got := <-pubs; !strings.HasPrefix(got, `crypto.events.funds.deposited {"id":1,`) {
+		t.Fatalf("want PUB crypto.events.funds.deposited of message 1. got %q", got)
+	}
+
+	rejected := message(2)
+	rejected.Type = outboxrepo.EventTransactionFailed
+	if err := sink.Send(context.Background(), rejected); err == nil || !strings.Contains(err.Error(), "Permissions Violation") {
+		t.Fatalf("Send rejected want -ERR. got %v", err)
+	}
+
+	// reconnects after the failure
+	if err := sink.Send(context.Background(), message(3)); err != nil {
+		t.Fatalf("Send after failure err %v", err)
+	}
+	if got := <-pubs; !strings.HasPrefix(got, `crypto.events.funds.deposited {"id":3,`) {
+		t.Fatalf("want PUB of message 3. got %q", got)
+	}
+
+	if _, err := NewNatsSink("http://"+addr, "crypto.events", time.Second); err == nil {
+		t.Fatalf("NewNatsSink with http url want error")
+	}
+}
+
+func TestKafkaSink(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		response string
+		wantErr  bool
+	}{
+		{name: "produced", status: http.StatusOK, response: `{"offsets":[{"partition":1,"offset":7,"error_code":null,"error":null}]}`},
+		{name: "partition error", status: http.StatusOK, response: `{"offsets":[{"error_code":50002,"error":"leader not available"}]}`, wantErr: true},
+		{name: "http error", status: http.StatusServiceUnavailable, response: `{}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got struct {
+				Records []struct {
+					Key   string             `json:"key"`
+					Value outboxrepo.Message `json:"value"`
+				} `json:"records"`
+			}
+			var path, contentType string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path, contentType = r.URL.Path, r.Header.Get("Content-Type")
+				json.NewDecoder(r.Body).Decode(&got)
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.response)
+			}))
+			defer server.Close()
+
+			sink := NewKafkaSink(server.URL+"/", "crypto.events", time.Second)
+			err := sink.Send(context.Background(), message(1))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("want err %v. got %v", tt.wantErr, err)
+			}
+			if path != "/topics/crypto.events" || contentType != "application/vnd.kafka.json.v2+json" {
+				t.Fatalf("want POST /topics/crypto.events as kafka json. got %s %s", path, contentType)
+			}
+			if len(got.Records) != 1 || got.Records[0].Key != "wallet:4" || got.Records[0].Value.Id != 1 {
+				t.Fatalf("want 1 record keyed wallet:4 of message 1. got %+v", got)
+			}
+		})
+	}
+}
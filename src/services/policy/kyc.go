@@ -0,0 +1,65 @@
+package policy
+
+import (
+	"slices"
+
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+
+	"github.com/shopspring/decimal"
+)
+
+// AccountStatus
+// Stored per user in user_accounts.status.
+type AccountStatus string
+
+const (
+	// AccountStatusPending is the status of new users, yet to pass KYC.
+	AccountStatusPending  AccountStatus = "pending"
+	AccountStatusVerified AccountStatus = "verified"
+	// AccountStatusSuspended users cannot move money.
+	AccountStatusSuspended AccountStatus = "suspended"
+)
+
+var AccountStatuses = []AccountStatus{AccountStatusPending, AccountStatusVerified, AccountStatusSuspended}
+
+func (s AccountStatus) Valid() bool {
+	return slices.Contains(AccountStatuses, s)
+}
+
+// Tier
+// KYC tier stored per user in user_accounts.kyc_tier. Tiers above 0 are not granted to pending users.
+type Tier int
+
+// TierRule
+// MaxAmount is the limit per operation.
+type TierRule struct {
+	Actions   []Action
+	MaxAmount decimal.Decimal
+}
+
+var TierRules = map[Tier]TierRule{
+	0: {Actions: []Action{ActionDeposit, ActionWithdraw, ActionTransfer}, MaxAmount: decimal.NewFromInt(1_000)},
+	1: {Actions: []Action{ActionDeposit, ActionWithdraw, ActionTransfer}, MaxAmount: decimal.NewFromInt(10_000)},
+	2: {Actions: []Action{ActionDeposit, ActionWithdraw, ActionTransfer}, MaxAmount: decimal.NewFromInt(1_000_000)},
+}
+
+func (t Tier) Valid() bool {
+	_, ok := TierRules[t]
+	return ok
+}
+
+// CheckKyc
+// Checks that a user of status and tier may move amount by action. Called before money moves.
+func CheckKyc(status AccountStatus, tier Tier, action Action, amount decimal.Decimal) error {
+	if status == AccountStatusSuspended {
+		return utils.AccountSuspendedError
+	}
+	rule, ok := TierRules[tier]
+	if !ok || !slices.Contains(rule.Actions, action) {
+		return utils.KycTierRequiredError
+	}
+	if amount.GreaterThan(rule.MaxAmount) {
+		return utils.KycLimitExceededErrorF(rule.MaxAmount)
+	}
+	return nil
+}
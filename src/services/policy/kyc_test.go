@@ -0,0 +1,41 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestCheckKyc(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  AccountStatus
+		tier    Tier
+		action  Action
+		amount  string
+		wantErr error
+	}{
+		{"pending deposits within tier 0", AccountStatusPending, 0, ActionDeposit, "1000", nil},
+		{"pending deposits over tier 0", AccountStatusPending, 0, ActionDeposit, "1000.000001", utils.KycLimitExceededErrorF(decimal.Zero)},
+		{"verified transfers within tier 1", AccountStatusVerified, 1, ActionTransfer, "10000", nil},
+		{"verified withdraws over tier 1", AccountStatusVerified, 1, ActionWithdraw, "10001", utils.KycLimitExceededErrorF(decimal.Zero)},
+		{"verified tier 2", AccountStatusVerified, 2, ActionWithdraw, "500000", nil},
+		{"suspended deposits", AccountStatusSuspended, 2, ActionDeposit, "1", utils.AccountSuspendedError},
+		{"unknown tier", AccountStatusVerified, 9, ActionDeposit, "1", utils.KycTierRequiredError},
+		{"action outside tier", AccountStatusVerified, 2, ActionAdjust, "1", utils.KycTierRequiredError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckKyc(tt.status, tt.tier, tt.action, decimal.RequireFromString(tt.amount))
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("CheckKyc() want nil err. got %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CheckKyc() want err %v. got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package policy
+
+import (
+	"slices"
+
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+)
+
+// MemberRole
+// Role of the subject on a wallet, stored per member in wallet_memberships.role. Empty for non-members.
+type MemberRole string
+
+const (
+	MemberRoleOwner   MemberRole = "owner"
+	MemberRoleSpender MemberRole = "spender"
+	MemberRoleViewer  MemberRole = "viewer"
+)
+
+var MemberRoles = []MemberRole{MemberRoleOwner, MemberRoleSpender, MemberRoleViewer}
+
+func (r MemberRole) Valid() bool {
+	return slices.Contains(MemberRoles, r)
+}
+
+// memberActions
+// Allowed to members of the wallet. Spend limits of spenders are checked by the repo, under the wallet lock.
+var memberActions = map[MemberRole][]Action{
+	MemberRoleOwner:   {ActionDeposit, ActionWithdraw, ActionTransfer, ActionReadWallet, ActionManageMembers},
+	MemberRoleSpender: {ActionDeposit, ActionWithdraw, ActionTransfer, ActionReadWallet},
+	MemberRoleViewer:  {ActionReadWallet},
+}
+
+// AuthorizeWallet
+// Same as Authorize for a wallet, member is the role of the subject on the wallet. Privileged actions are not allowed
+// on wallets the subject is a member of.
+func AuthorizeWallet(subject Subject, action Action, member MemberRole) error {
+	if subject.Frozen && !slices.Contains(readActions, action) {
+		return utils.AccountFrozenError
+	}
+	if member != "" && slices.Contains(privilegedActions, action) {
+		return utils.ForbiddenErrorF("not allowed on own wallet")
+	}
+	if slices.Contains(memberActions[member], action) {
+		return nil
+	}
+	if slices.Contains(roleActions[subject.Role], action) {
+		return nil
+	}
+	if slices.Contains(walletOwnerActions, action) {
+		return utils.ForbiddenErrorF("requestor is not an owner nor spender of the wallet")
+	}
+	return utils.ForbiddenError
+}
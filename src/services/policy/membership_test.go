@@ -0,0 +1,68 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+)
+
+func TestAuthorizeWallet(t *testing.T) {
+	customer := Subject{Username: "alice", Role: RoleCustomer}
+	support := Subject{Username: "sam", Role: RoleSupportReadonly}
+	admin := Subject{Username: "ada", Role: RoleAdmin}
+	frozenCustomer := Subject{Username: "alice", Role: RoleCustomer, Frozen: true}
+
+	tests := []struct {
+		name    string
+		subject Subject
+		action  Action
+		member  MemberRole
+		wantErr error
+	}{
+		{"owner withdraws", customer, ActionWithdraw, MemberRoleOwner, nil},
+		{"owner manages members", customer, ActionManageMembers, MemberRoleOwner, nil},
+		{"spender transfers", customer, ActionTransfer, MemberRoleSpender, nil},
+		{"spender reads wallet", customer, ActionReadWallet, MemberRoleSpender, nil},
+		{"spender manages members", customer, ActionManageMembers, MemberRoleSpender, utils.ForbiddenError},
+		{"viewer reads wallet", customer, ActionReadWallet, MemberRoleViewer, nil},
+		{"viewer deposits", customer, ActionDeposit, MemberRoleViewer, utils.ForbiddenError},
+		{"viewer withdraws", customer, ActionWithdraw, MemberRoleViewer, utils.ForbiddenError},
+		{"non-member withdraws", customer, ActionWithdraw, "", utils.ForbiddenError},
+		{"non-member reads wallet", customer, ActionReadWallet, "", utils.ForbiddenError},
+
+		{"support reads wallet", support, ActionReadWallet, "", nil},
+		{"support manages members", support, ActionManageMembers, "", utils.ForbiddenError},
+		{"admin withdraws from other wallet", admin, ActionWithdraw, "", utils.ForbiddenError},
+		{"admin sets approval policy", admin, ActionSetApprovalPolicy, "", nil},
+		{"admin sets approval policy of owned wallet", admin, ActionSetApprovalPolicy, MemberRoleOwner, utils.ForbiddenError},
+		{"admin adjusts viewed wallet", admin, ActionAdjust, MemberRoleViewer, utils.ForbiddenError},
+
+		{"frozen owner withdraws", frozenCustomer, ActionWithdraw, MemberRoleOwner, utils.AccountFrozenError},
+		{"frozen owner reads wallet", frozenCustomer, ActionReadWallet, MemberRoleOwner, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := AuthorizeWallet(tt.subject, tt.action, tt.member)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("AuthorizeWallet() want nil err. got %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AuthorizeWallet() want err %v. got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMemberRole_Valid(t *testing.T) {
+	for _, role := range MemberRoles {
+		if !role.Valid() {
+			t.Errorf("Valid() want true for %s", role)
+		}
+	}
+	for _, role := range []MemberRole{"", "Owner", "admin"} {
+		if role.Valid() {
+			t.Errorf("Valid() want false for %q", role)
+		}
+	}
+}
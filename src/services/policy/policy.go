@@ -0,0 +1,121 @@
+package policy
+
+import (
+	"slices"
+
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+)
+
+// Role
+// Stored per user in user_accounts.role.
+type Role string
+
+const (
+	RoleCustomer        Role = "customer"
+	RoleSupportReadonly Role = "support_readonly"
+	RoleOperator        Role = "operator"
+	RoleAdmin           Role = "admin"
+)
+
+var Roles = []Role{RoleCustomer, RoleSupportReadonly, RoleOperator, RoleAdmin}
+
+func (r Role) Valid() bool {
+	return slices.Contains(Roles, r)
+}
+
+type Action string
+
+const (
+	// ActionDeposit, ActionWithdraw and ActionTransfer are allowed to owners and spenders of the wallet only,
+	// regardless of role, see AuthorizeWallet.
+	ActionDeposit  Action = "wallet:deposit"
+	ActionWithdraw Action = "wallet:withdraw"
+	ActionTransfer Action = "wallet:transfer"
+	// ActionAdjust creates a manual credit or debit of any wallet pending approval, or rejects one.
+	ActionAdjust Action = "wallet:adjust"
+	// ActionApproveAdjustment posts an adjustment created by another principal.
+	ActionApproveAdjustment Action = "wallet:approve_adjustment"
+	// ActionSetWalletStatus freezes, unfreezes or closes a wallet.
+	ActionSetWalletStatus Action = "wallet:set_status"
+	// ActionSetApprovalPolicy sets or deletes the approval policy of transfers from a wallet. Reading it is
+	// ActionReadUser.
+	ActionSetApprovalPolicy Action = "wallet:set_approval_policy"
+	// ActionReadWallet reads a wallet, its history, members and approval policy.
+	ActionReadWallet Action = "wallet:read"
+	// ActionManageMembers adds, changes and removes members of a wallet.
+	ActionManageMembers Action = "wallet:manage_members"
+	// ActionCreateWallet creates a wallet of the owner, its owner member.
+	ActionCreateWallet Action = "wallet:create"
+
+	// ActionReadUser reads the account, wallets and transactions of a user.
+	ActionReadUser   Action = "user:read"
+	ActionFreezeUser Action = "user:freeze"
+	ActionSetRole    Action = "user:set_role"
+	// ActionUpdateProfile updates display name and email.
+	ActionUpdateProfile Action = "user:update_profile"
+	// ActionVerifyUser sets account status and KYC tier.
+	ActionVerifyUser Action = "user:verify"
+	// ActionManageWebhooks creates, deletes and replays webhooks of a user. Reading them is ActionReadUser.
+	ActionManageWebhooks Action = "user:manage_webhooks"
+
+	ActionReadAudit Action = "audit:read"
+	// ActionReadLedger reads pending transactions and reconciles ledgers across all wallets.
+	ActionReadLedger Action = "ledger:read"
+)
+
+// ownerActions
+// Allowed to the owner of the resource.
+var ownerActions = []Action{ActionDeposit, ActionWithdraw, ActionTransfer, ActionReadUser, ActionUpdateProfile, ActionManageWebhooks,
+	ActionCreateWallet}
+
+// walletOwnerActions
+// Denied to non-owners with an owner mismatch detail.
+var walletOwnerActions = []Action{ActionDeposit, ActionWithdraw, ActionTransfer}
+
+// roleActions
+// Allowed on resources of any owner.
+var roleActions = map[Role][]Action{
+	RoleCustomer:        {},
+	RoleSupportReadonly: {ActionReadUser, ActionReadWallet, ActionReadAudit, ActionReadLedger},
+	RoleOperator:        {ActionReadUser, ActionReadWallet, ActionReadAudit, ActionReadLedger, ActionFreezeUser, ActionSetWalletStatus, ActionVerifyUser, ActionAdjust, ActionApproveAdjustment},
+	RoleAdmin:           {ActionReadUser, ActionReadWallet, ActionReadAudit, ActionReadLedger, ActionFreezeUser, ActionSetWalletStatus, ActionVerifyUser, ActionSetRole, ActionAdjust, ActionApproveAdjustment, ActionSetApprovalPolicy},
+}
+
+// privilegedActions
+// Not allowed on the subject's own account or wallets, i.e. an admin cannot credit own wallet.
+var privilegedActions = []Action{ActionAdjust, ActionApproveAdjustment, ActionSetApprovalPolicy, ActionFreezeUser, ActionSetWalletStatus, ActionVerifyUser, ActionSetRole}
+
+// readActions
+// Allowed to frozen subjects.
+var readActions = []Action{ActionReadUser, ActionReadWallet, ActionReadAudit, ActionReadLedger}
+
+// Subject
+// Principal performing the action.
+type Subject struct {
+	Username string
+	Role     Role
+	Frozen   bool
+}
+
+// Authorize
+// owner is the username owning the resource acted on, empty if the resource has no owner. Usernames are compared by
+// canonical form.
+func Authorize(subject Subject, action Action, owner string) error {
+	if subject.Frozen && !slices.Contains(readActions, action) {
+		return utils.AccountFrozenError
+	}
+	owned := owner != "" && CanonicalUsername(owner) == CanonicalUsername(subject.Username)
+	if owned && slices.Contains(privilegedActions, action) {
+		return utils.ForbiddenErrorF("not allowed on own account")
+	}
+	if owned && slices.Contains(ownerActions, action) {
+		return nil
+	}
+	if slices.Contains(roleActions[subject.Role], action) {
+		return nil
+	}
+	if slices.Contains(walletOwnerActions, action) {
+		return utils.ForbiddenErrorF("requestor and wallet owner mismatch")
+	}
+	return utils.ForbiddenError
+}
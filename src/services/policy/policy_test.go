@@ -0,0 +1,123 @@
+package policy
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+)
+
+func TestAuthorize(t *testing.T) {
+	customer := Subject{Username: "alice", Role: RoleCustomer}
+	support := Subject{Username: "sam", Role: RoleSupportReadonly}
+	operator := Subject{Username: "olga", Role: RoleOperator}
+	admin := Subject{Username: "ada", Role: RoleAdmin}
+	frozenCustomer := Subject{Username: "alice", Role: RoleCustomer, Frozen: true}
+	frozenAdmin := Subject{Username: "ada", Role: RoleAdmin, Frozen: true}
+
+	tests := []struct {
+		name    string
+		subject Subject
+		action  Action
+		owner   string
+		wantErr error
+	}{
+		{"customer deposits to own wallet", customer, ActionDeposit, "alice", nil},
+		{"customer withdraws from own wallet", customer, ActionWithdraw, "alice", nil},
+		{"customer transfers from own wallet", customer, ActionTransfer, "alice", nil},
+		{"customer withdraws from other wallet", customer, ActionWithdraw, "bob", utils.ForbiddenError},
+		{"customer reads self", customer, ActionReadUser, "alice", nil},
+		{"customer reads self by case variant", customer, ActionReadUser, "Alice", nil},
+		{"customer reads other user", customer, ActionReadUser, "bob", utils.ForbiddenError},
+		{"customer reads audit", customer, ActionReadAudit, "", utils.ForbiddenError},
+		{"customer reads ledger", customer, ActionReadLedger, "", utils.ForbiddenError},
+		{"customer freezes", customer, ActionFreezeUser, "bob", utils.ForbiddenError},
+		{"customer freezes self", customer, ActionFreezeUser, "alice", utils.ForbiddenError},
+		{"customer adjusts own wallet", customer, ActionAdjust, "alice", utils.ForbiddenError},
+		{"customer sets own role", customer, ActionSetRole, "alice", utils.ForbiddenError},
+
+		{"support reads other user", support, ActionReadUser, "bob", nil},
+		{"support reads audit", support, ActionReadAudit, "", nil},
+		{"support reads ledger", support, ActionReadLedger, "", nil},
+		{"support withdraws from other wallet", support, ActionWithdraw, "bob", utils.ForbiddenError},
+		{"support freezes", support, ActionFreezeUser, "bob", utils.ForbiddenError},
+		{"support adjusts", support, ActionAdjust, "bob", utils.ForbiddenError},
+		{"support approves adjustment", support, ActionApproveAdjustment, "bob", utils.ForbiddenError},
+
+		{"operator freezes", operator, ActionFreezeUser, "bob", nil},
+		{"operator sets wallet status", operator, ActionSetWalletStatus, "bob", nil},
+		{"operator verifies", operator, ActionVerifyUser, "bob", nil},
+		{"operator verifies self", operator, ActionVerifyUser, "olga", utils.ForbiddenError},
+		{"operator updates profile of other user", operator, ActionUpdateProfile, "bob", utils.ForbiddenError},
+		{"customer updates own profile", customer, ActionUpdateProfile, "alice", nil},
+		{"customer manages own webhooks", customer, ActionManageWebhooks, "alice", nil},
+		{"customer creates own wallet", customer, ActionCreateWallet, "alice", nil},
+		{"customer creates wallet of other user", customer, ActionCreateWallet, "bob", utils.ForbiddenError},
+		{"admin creates wallet of other user", admin, ActionCreateWallet, "bob", utils.ForbiddenError},
+		{"customer manages webhooks of other user", customer, ActionManageWebhooks, "bob", utils.ForbiddenError},
+		{"support manages webhooks", support, ActionManageWebhooks, "bob", utils.ForbiddenError},
+		{"customer verifies self", customer, ActionVerifyUser, "alice", utils.ForbiddenError},
+		{"support verifies", support, ActionVerifyUser, "bob", utils.ForbiddenError},
+		{"operator sets own wallet status", operator, ActionSetWalletStatus, "olga", utils.ForbiddenError},
+		{"support sets wallet status", support, ActionSetWalletStatus, "bob", utils.ForbiddenError},
+		{"customer closes own wallet", customer, ActionSetWalletStatus, "alice", utils.ForbiddenError},
+		{"operator reads audit", operator, ActionReadAudit, "", nil},
+		{"operator transfers from other wallet", operator, ActionTransfer, "bob", utils.ForbiddenError},
+		{"operator adjusts", operator, ActionAdjust, "bob", nil},
+		{"operator approves adjustment", operator, ActionApproveAdjustment, "bob", nil},
+		{"operator approves adjustment of own wallet", operator, ActionApproveAdjustment, "olga", utils.ForbiddenError},
+		{"operator sets role", operator, ActionSetRole, "bob", utils.ForbiddenError},
+		{"operator sets approval policy", operator, ActionSetApprovalPolicy, "bob", utils.ForbiddenError},
+		{"customer sets approval policy of own wallet", customer, ActionSetApprovalPolicy, "alice", utils.ForbiddenError},
+
+		{"admin adjusts", admin, ActionAdjust, "bob", nil},
+		{"admin sets role", admin, ActionSetRole, "bob", nil},
+		{"admin sets approval policy", admin, ActionSetApprovalPolicy, "bob", nil},
+		{"admin sets approval policy of own wallet", admin, ActionSetApprovalPolicy, "ada", utils.ForbiddenError},
+		{"admin freezes", admin, ActionFreezeUser, "bob", nil},
+		{"admin reads other user", admin, ActionReadUser, "bob", nil},
+		{"admin deposits to own wallet", admin, ActionDeposit, "ada", nil},
+		{"admin adjusts own wallet", admin, ActionAdjust, "ada", utils.ForbiddenError},
+		{"admin adjusts own wallet by case variant", admin, ActionAdjust, "ADA", utils.ForbiddenError},
+		{"admin sets own role", admin, ActionSetRole, "ada", utils.ForbiddenError},
+		{"operator freezes self", operator, ActionFreezeUser, "olga", utils.ForbiddenError},
+		{"admin withdraws from other wallet", admin, ActionWithdraw, "bob", utils.ForbiddenError},
+
+		{"frozen customer deposits", frozenCustomer, ActionDeposit, "alice", utils.AccountFrozenError},
+		{"frozen customer transfers", frozenCustomer, ActionTransfer, "alice", utils.AccountFrozenError},
+		{"frozen customer reads self", frozenCustomer, ActionReadUser, "alice", nil},
+		{"frozen customer manages own webhooks", frozenCustomer, ActionManageWebhooks, "alice", utils.AccountFrozenError},
+		{"frozen admin adjusts", frozenAdmin, ActionAdjust, "bob", utils.AccountFrozenError},
+		{"frozen admin approves adjustment", frozenAdmin, ActionApproveAdjustment, "bob", utils.AccountFrozenError},
+		{"frozen admin unfreezes self", frozenAdmin, ActionFreezeUser, "ada", utils.AccountFrozenError},
+		{"frozen admin reads audit", frozenAdmin, ActionReadAudit, "", nil},
+		{"frozen admin reads ledger", frozenAdmin, ActionReadLedger, "", nil},
+
+		{"unknown role", Subject{Username: "eve", Role: "root"}, ActionReadAudit, "", utils.ForbiddenError},
+		{"empty owner is not owned by empty username", Subject{Role: RoleCustomer}, ActionReadUser, "", utils.ForbiddenError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Authorize(tt.subject, tt.action, tt.owner)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("Authorize() want nil err. got %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authorize() want err %v. got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRole_Valid(t *testing.T) {
+	for _, role := range Roles {
+		if !role.Valid() {
+			t.Errorf("Valid() want true for %s", role)
+		}
+	}
+	for _, role := range []Role{"", "Admin", "support-readonly", "root"} {
+		if role.Valid() {
+			t.Errorf("Valid() want false for %q", role)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package policy
+
+import (
+	"regexp"
+	"slices"
+
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	UsernameMinLength = 3
+	UsernameMaxLength = 64
+)
+
+// usernamePattern
+// ASCII letters and digits, separated by single '.', '_' or '-'. Excludes ':' which delimits Basic auth credentials.
+var usernamePattern = regexp.MustCompile(`^[A-Za-z0-9]+([._-][A-Za-z0-9]+)*$`)
+
+// ReservedUsernames
+// Canonical usernames that cannot be registered, to avoid impersonating the service or its staff.
+var ReservedUsernames = []string{
+	"admin", "administrator", "anonymous", "api", "audit", "health", "help", "null", "operator", "root", "security",
+	"support", "system", "undefined",
+}
+
+// NormalizeUsername
+// NFKC form, stored as the display username. i.e. fullwidth "ａｌｉｃｅ" is "alice".
+func NormalizeUsername(username string) string {
+	return norm.NFKC.String(username)
+}
+
+// CanonicalUsername
+// NFKC case folded form, identifying a user regardless of the case or compatibility characters used.
+// i.e. "Alice", "ALICE" and fullwidth "Ａｌｉｃｅ" are "alice".
+func CanonicalUsername(username string) string {
+	return norm.NFKC.String(cases.Fold().String(norm.NFKC.String(username)))
+}
+
+// ValidateUsername
+// Checks length, charset and reserved names of a username to be registered.
+func ValidateUsername(username string) error {
+	normalized := NormalizeUsername(username)
+	if len(normalized) < UsernameMinLength || len(normalized) > UsernameMaxLength {
+		return utils.InvalidArgumentErrorF("username must be %d to %d characters", UsernameMinLength, UsernameMaxLength)
+	}
+	if !usernamePattern.MatchString(normalized) {
+		return utils.InvalidArgumentErrorF("username must be letters and digits, separated by single '.', '_' or '-'")
+	}
+	if slices.Contains(ReservedUsernames, CanonicalUsername(normalized)) {
+		return utils.InvalidArgumentErrorF("username is reserved")
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCanonicalUsername(t *testing.T) {
+	tests := []struct {
+		username string
+		want     string
+	}{
+		{"alice", "alice"},
+		{"Alice", "alice"},
+		{"ALICE", "alice"},
+		{"Ａｌｉｃｅ", "alice"},
+		{"\u212Aelvin", "kelvin"},
+		{"Straße", "strasse"},
+		{"ΟΔΟΣ", "οδοσ"},
+		{"οδος", "οδοσ"},
+		{"T_0014_abcDEF_1700000000000", "t_0014_abcdef_1700000000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			if got := CanonicalUsername(tt.username); got != tt.want {
+				t.Fatalf("CanonicalUsername() want %q. got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateUsername(t *testing.T) {
+	tests := []struct {
+		name     string
+		username string
+		wantErr  string
+	}{
+		{"letters and digits", "alice01", ""},
+		{"separators", "t00001_ARFVCOHvfkwp_1700000000000", ""},
+		{"fullwidth", "ａｌｉｃｅ01", ""},
+		{"max length", strings.Repeat("a", UsernameMaxLength), ""},
+		{"empty", "", "characters"},
+		{"too short", "ab", "characters"},
+		{"too long", strings.Repeat("a", UsernameMaxLength+1), "characters"},
+		{"space", "alice smith", "letters and digits"},
+		{"colon", "alice:secret", "letters and digits"},
+		{"leading separator", "_alice", "letters and digits"},
+		{"double separator", "alice..smith", "letters and digits"},
+		{"cyrillic confusable", "\u0430lice", "letters and digits"},
+		{"zero width", "ali\u200bce", "letters and digits"},
+		{"reserved", "admin", "reserved"},
+		{"reserved case variant", "System", "reserved"},
+		{"reserved fullwidth", "ｒｏｏｔ", "reserved"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUsername(tt.username)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("ValidateUsername() want nil err. got %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("ValidateUsername() want err containing %q. got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
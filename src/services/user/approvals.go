@@ -0,0 +1,145 @@
+package user
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+	"github.com/cryptonlx/crypto/src/services/policy"
+)
+
+const (
+	MinApprovalTimeout = time.Minute
+	MaxApprovalTimeout = 30 * 24 * time.Hour
+	// ApprovalExpiryInterval between expiries of transfers pending approval past their timeout.
+	ApprovalExpiryInterval = time.Minute
+)
+
+// ApprovalPolicy
+// Approval policy of transfers from the wallet, for members of the wallet or roles reading wallets.
+func (s Service) ApprovalPolicy(ctx context.Context, requestor string, walletId int64) (userrepo.ApprovalPolicy, error) {
+	if err := s.authorizeWalletRead(ctx, requestor, walletId); err != nil {
+		return userrepo.ApprovalPolicy{}, err
+	}
+
+	p, err := s.repo.ApprovalPolicy(ctx, walletId)
+	if err != nil {
+		return userrepo.ApprovalPolicy{}, err
+	}
+	if p == nil {
+		return userrepo.ApprovalPolicy{}, utils.NotFoundErrorF("approval policy")
+	}
+	return *p, nil
+}
+
+// SetApprovalPolicy
+// Transfers from the wallet over the threshold then require approvals of RequiredApprovals of the approvers. Role
+// admin only, not on own wallets.
+func (s Service) SetApprovalPolicy(ctx context.Context, requestor string, approvalPolicy userrepo.ApprovalPolicy) (userrepo.ApprovalPolicy, error) {
+	if approvalPolicy.Threshold.IsNegative() {
+		return userrepo.ApprovalPolicy{}, utils.InvalidArgumentErrorF("threshold cannot be negative")
+	}
+	if approvalPolicy.Timeout < MinApprovalTimeout || approvalPolicy.Timeout > MaxApprovalTimeout {
+		return userrepo.ApprovalPolicy{}, utils.InvalidArgumentErrorF("timeout must be between %s and %s", MinApprovalTimeout, MaxApprovalTimeout)
+	}
+	approvers := make([]string, 0, len(approvalPolicy.Approvers))
+	for _, approver := range approvalPolicy.Approvers {
+		approvers = append(approvers, policy.CanonicalUsername(approver))
+	}
+	slices.Sort(approvers)
+	approvalPolicy.Approvers = slices.Compact(approvers)
+	if approvalPolicy.RequiredApprovals < 1 || approvalPolicy.RequiredApprovals > len(approvalPolicy.Approvers) {
+		return userrepo.ApprovalPolicy{}, utils.InvalidArgumentErrorF("required_approvals must be between 1 and the number of approvers")
+	}
+	if _, err := s.authorizeWallet(ctx, requestor, policy.ActionSetApprovalPolicy, approvalPolicy.WalletId); err != nil {
+		return userrepo.ApprovalPolicy{}, err
+	}
+
+	return s.repo.SetApprovalPolicy(ctx, policy.CanonicalUsername(requestor), approvalPolicy)
+}
+
+// DeleteApprovalPolicy
+// Transfers from the wallet then need no approval. Role admin only, not on own wallets.
+func (s Service) DeleteApprovalPolicy(ctx context.Context, requestor string, walletId int64) error {
+	if _, err := s.authorizeWallet(ctx, requestor, policy.ActionSetApprovalPolicy, walletId); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteApprovalPolicy(ctx, walletId)
+}
+
+// ApproveTransfer
+// Approval of a transfer pending approval by one of its approvers. The transfer executes once approved by the
+// required approvals.
+func (s Service) ApproveTransfer(ctx context.Context, requestor string, id int64) (userrepo.Transaction, []userrepo.Approval, []userrepo.Ledger, error) {
+	if err := s.authorizeApprover(ctx, requestor); err != nil {
+		return userrepo.Transaction{}, nil, nil, err
+	}
+
+	return s.repo.ApproveTransfer(policy.CanonicalUsername(requestor), ctx, id)
+}
+
+// RejectTransfer
+// Rejection of a transfer pending approval by one of its approvers, or cancellation by its requestor.
+func (s Service) RejectTransfer(ctx context.Context, requestor string, id int64) (userrepo.Transaction, []userrepo.Approval, error) {
+	if err := s.authorizeApprover(ctx, requestor); err != nil {
+		return userrepo.Transaction{}, nil, err
+	}
+
+	return s.repo.RejectTransfer(policy.CanonicalUsername(requestor), ctx, id)
+}
+
+// authorizeApprover
+// Approvers are designated per transfer, checked by the repo. Unknown principals are unauthorized, frozen ones cannot
+// decide.
+func (s Service) authorizeApprover(ctx context.Context, principal string) error {
+	user, err := s.repo.User(ctx, policy.CanonicalUsername(principal))
+	if utils.ErrorCodeOf(err) == utils.ErrorCodeNotFound {
+		return utils.UnauthorizedError
+	}
+	if err != nil {
+		return err
+	}
+	if user.Frozen {
+		return utils.AccountFrozenError
+	}
+	return nil
+}
+
+// PendingApprovals
+// Transfers awaiting the decision of username, for the user or privileged roles.
+func (s Service) PendingApprovals(ctx context.Context, requestor string, username string) ([]userrepo.Transaction, error) {
+	if username == "" {
+		return []userrepo.Transaction{}, utils.InvalidArgumentErrorF("user name cannot be empty")
+	}
+	if err := s.authorizeRead(ctx, requestor, username); err != nil {
+		return []userrepo.Transaction{}, err
+	}
+
+	return s.repo.PendingApprovals(ctx, policy.CanonicalUsername(username))
+}
+
+// RunApprovalExpiry
+// Expires transfers pending approval past their timeout every interval until ctx is done. Approving or rejecting an
+// expired transfer also expires it, so the interval only bounds how long it is listed as pending_approval.
+func (s Service) RunApprovalExpiry(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		n, err := s.repo.ExpireTransfers(ctx)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("approval expiry err %v\n", err)
+		}
+		if n > 0 {
+			log.Printf("approval expiry expired %d transfers\n", n)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package user
+
+import (
+	"context"
+
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+	"github.com/cryptonlx/crypto/src/services/policy"
+
+	"github.com/shopspring/decimal"
+)
+
+// Members
+// Members of the wallet, for members of the wallet or roles reading wallets.
+func (s Service) Members(ctx context.Context, requestor string, walletId int64) ([]userrepo.Membership, error) {
+	if err := s.authorizeWalletRead(ctx, requestor, walletId); err != nil {
+		return []userrepo.Membership{}, err
+	}
+
+	return s.repo.Memberships(ctx, walletId)
+}
+
+// SetMember
+// Adds username to the members of the wallet or changes its role, by owners of the wallet. Spenders debit up to
+// spendLimit in total per UTC day, other roles have no spend limit.
+func (s Service) SetMember(ctx context.Context, requestor string, walletId int64, username string, role policy.MemberRole, spendLimit *decimal.Decimal) (userrepo.Membership, error) {
+	if username == "" {
+		return userrepo.Membership{}, utils.InvalidArgumentErrorF("user name cannot be empty")
+	}
+	if !role.Valid() {
+		return userrepo.Membership{}, utils.InvalidArgumentErrorF("role must be one of %v", policy.MemberRoles)
+	}
+	if role == policy.MemberRoleSpender && (spendLimit == nil || !spendLimit.IsPositive()) {
+		return userrepo.Membership{}, utils.InvalidArgumentErrorF("spend_limit must be positive for spenders")
+	}
+	if role != policy.MemberRoleSpender && spendLimit != nil {
+		return userrepo.Membership{}, utils.InvalidArgumentErrorF("spend_limit is for spenders only")
+	}
+	if _, err := s.authorizeWallet(ctx, requestor, policy.ActionManageMembers, walletId); err != nil {
+		return userrepo.Membership{}, err
+	}
+
+	return s.repo.SetMembership(ctx, policy.CanonicalUsername(requestor), userrepo.Membership{
+		WalletId:   walletId,
+		Username:   policy.CanonicalUsername(username),
+		Role:       userrepo.MemberRole(role),
+		SpendLimit: spendLimit,
+	})
+}
+
+// RemoveMember
+// Removes username from the members of the wallet, by owners of the wallet or the member leaving it. The last owner
+// cannot be removed.
+func (s Service) RemoveMember(ctx context.Context, requestor string, walletId int64, username string) error {
+	if username == "" {
+		return utils.InvalidArgumentErrorF("user name cannot be empty")
+	}
+	action := policy.ActionManageMembers
+	if policy.CanonicalUsername(requestor) == policy.CanonicalUsername(username) {
+		action = policy.ActionReadWallet
+	}
+	if _, err := s.authorizeWallet(ctx, requestor, action, walletId); err != nil {
+		return err
+	}
+
+	return s.repo.DeleteMembership(ctx, walletId, policy.CanonicalUsername(username))
+}
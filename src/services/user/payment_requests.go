@@ -0,0 +1,131 @@
+package user
+
+import (
+	"context"
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+	"github.com/cryptonlx/crypto/src/services/policy"
+)
+
+const (
+	// DefaultPaymentRequestExpiry applies to payment requests created without expires_at.
+	DefaultPaymentRequestExpiry = 7 * 24 * time.Hour
+	MaxPaymentRequestExpiry     = 90 * 24 * time.Hour
+	MaxMemoLength               = 140
+)
+
+var paymentRequestStatuses = []string{userrepo.PaymentRequestStatusPending, userrepo.PaymentRequestStatusPaid,
+	userrepo.PaymentRequestStatusCancelled, userrepo.PaymentRequestStatusExpired}
+
+// CreatePaymentRequest
+// Requests request.Payer to pay request.Amount into request.WalletId, by owners and spenders of the wallet. The request
+// expires after DefaultPaymentRequestExpiry if request.ExpiresAt is zero.
+func (s Service) CreatePaymentRequest(ctx context.Context, requestor string, request userrepo.PaymentRequest) (userrepo.PaymentRequest, error) {
+	if !request.Amount.IsPositive() {
+		return userrepo.PaymentRequest{}, utils.InvalidAmountError
+	}
+	if request.Currency == "" {
+		return userrepo.PaymentRequest{}, utils.InvalidArgumentErrorF("currency cannot be empty")
+	}
+	if request.Payer == "" {
+		return userrepo.PaymentRequest{}, utils.InvalidArgumentErrorF("payer cannot be empty")
+	}
+	if policy.CanonicalUsername(request.Payer) == policy.CanonicalUsername(requestor) {
+		return userrepo.PaymentRequest{}, utils.InvalidArgumentErrorF("payer cannot be the requestor")
+	}
+	if utf8.RuneCountInString(request.Memo) > MaxMemoLength {
+		return userrepo.PaymentRequest{}, utils.InvalidArgumentErrorF("memo must be at most %d characters", MaxMemoLength)
+	}
+	now := time.Now()
+	if request.ExpiresAt.IsZero() {
+		request.ExpiresAt = now.Add(DefaultPaymentRequestExpiry)
+	}
+	if !request.ExpiresAt.After(now) || request.ExpiresAt.After(now.Add(MaxPaymentRequestExpiry)) {
+		return userrepo.PaymentRequest{}, utils.InvalidArgumentErrorF("expires_at must be in the future and within %s",
+			MaxPaymentRequestExpiry)
+	}
+	if _, err := s.authorizeWallet(ctx, requestor, policy.ActionDeposit, request.WalletId); err != nil {
+		return userrepo.PaymentRequest{}, err
+	}
+
+	request.Payer = policy.CanonicalUsername(request.Payer)
+	return s.repo.CreatePaymentRequest(ctx, policy.CanonicalUsername(requestor), request)
+}
+
+// PaymentRequests
+// Payment requests to be paid by username if direction is incoming, the default, or requested by username if
+// outgoing. All statuses if status is empty.
+func (s Service) PaymentRequests(ctx context.Context, requestor string, username string, direction string, status string) ([]userrepo.PaymentRequest, error) {
+	if direction == "" {
+		direction = userrepo.PaymentRequestsIncoming
+	}
+	if direction != userrepo.PaymentRequestsIncoming && direction != userrepo.PaymentRequestsOutgoing {
+		return []userrepo.PaymentRequest{}, utils.InvalidArgumentErrorF("direction must be %s or %s",
+			userrepo.PaymentRequestsIncoming, userrepo.PaymentRequestsOutgoing)
+	}
+	if status != "" && !slices.Contains(paymentRequestStatuses, status) {
+		return []userrepo.PaymentRequest{}, utils.InvalidArgumentErrorF("status must be one of %v", paymentRequestStatuses)
+	}
+	if err := s.authorizeRead(ctx, requestor, username); err != nil {
+		return []userrepo.PaymentRequest{}, err
+	}
+
+	return s.repo.PaymentRequests(ctx, policy.CanonicalUsername(username), direction, status)
+}
+
+// PayPaymentRequest
+// Pays the pending request by the payer from sourceWalletId, as a transfer to the wallet of the request. Amounts over
+// the threshold of the approval policy of the source wallet cannot be paid by request.
+func (s Service) PayPaymentRequest(ctx context.Context, requestor string, nonce int64, id int64, sourceWalletId int64) (userrepo.Transaction, []userrepo.Ledger, error) {
+	if nonce == 0 {
+		return userrepo.Transaction{}, []userrepo.Ledger{}, utils.InvalidNonceError
+	}
+	request, err := s.paymentRequest(ctx, requestor, id)
+	if err != nil {
+		return userrepo.Transaction{}, []userrepo.Ledger{}, err
+	}
+	approvalPolicy, err := s.authorizeTransfer(ctx, requestor, sourceWalletId, request.WalletId, request.Amount)
+	if err != nil {
+		return userrepo.Transaction{}, []userrepo.Ledger{}, err
+	}
+	if approvalPolicy != nil && request.Amount.GreaterThan(approvalPolicy.Threshold) {
+		return userrepo.Transaction{}, []userrepo.Ledger{}, utils.InvalidArgumentErrorF(
+			"amount is over the approval threshold %s of the wallet", approvalPolicy.Threshold)
+	}
+
+	return s.repo.PayPaymentRequest(policy.CanonicalUsername(requestor), ctx, nonce, id, sourceWalletId)
+}
+
+// CancelPaymentRequest
+// Cancels the pending request, by its requester. Frozen, suspended or lower-tier requesters may cancel, no money moves.
+func (s Service) CancelPaymentRequest(ctx context.Context, requestor string, id int64) (userrepo.PaymentRequest, error) {
+	if _, err := s.paymentRequest(ctx, requestor, id); err != nil {
+		return userrepo.PaymentRequest{}, err
+	}
+
+	return s.repo.CancelPaymentRequest(policy.CanonicalUsername(requestor), ctx, id)
+}
+
+// paymentRequest
+// Payment request by id, not found unless principal is its requester or payer. Unknown principals are unauthorized.
+func (s Service) paymentRequest(ctx context.Context, principal string, id int64) (userrepo.PaymentRequest, error) {
+	user, err := s.repo.User(ctx, policy.CanonicalUsername(principal))
+	if utils.ErrorCodeOf(err) == utils.ErrorCodeNotFound {
+		return userrepo.PaymentRequest{}, utils.UnauthorizedError
+	}
+	if err != nil {
+		return userrepo.PaymentRequest{}, err
+	}
+	request, err := s.repo.PaymentRequest(ctx, id)
+	if err != nil {
+		return userrepo.PaymentRequest{}, err
+	}
+	if user.Id != request.RequesterId && user.Id != request.PayerId {
+		return userrepo.PaymentRequest{}, utils.NotFoundErrorF("payment request")
+	}
+	return request, nil
+}
@@ -2,9 +2,15 @@ package user
 
 import (
 	"context"
-	"errors"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+	"github.com/cryptonlx/crypto/src/services/policy"
 
 	"github.com/shopspring/decimal"
 )
@@ -17,49 +23,215 @@ func New(repo *userrepo.Repo) *Service {
 	return &Service{repo: repo}
 }
 
-func (s Service) GetUserWalletBalanceByUserName(ctx context.Context, username string) (userrepo.UserWallets, error) {
+// Authorize
+// Checks policy for principal acting on a resource owned by owner. Unknown principals are unauthorized.
+func (s Service) Authorize(ctx context.Context, principal string, action policy.Action, owner string) error {
+	user, err := s.repo.User(ctx, policy.CanonicalUsername(principal))
+	if utils.ErrorCodeOf(err) == utils.ErrorCodeNotFound {
+		return utils.UnauthorizedError
+	}
+	if err != nil {
+		return err
+	}
+	return policy.Authorize(policy.Subject{
+		Username: user.Username,
+		Role:     policy.Role(user.Role),
+		Frozen:   user.Frozen,
+	}, action, owner)
+}
+
+// authorizeWallet
+// Checks policy for principal acting on the wallet, by the membership of principal in the wallet. Returns the
+// principal. Unknown principals are unauthorized.
+func (s Service) authorizeWallet(ctx context.Context, principal string, action policy.Action, walletId int64) (*userrepo.User, error) {
+	user, err := s.repo.User(ctx, policy.CanonicalUsername(principal))
+	if utils.ErrorCodeOf(err) == utils.ErrorCodeNotFound {
+		return nil, utils.UnauthorizedError
+	}
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.repo.Membership(ctx, walletId, user.Id)
+	if err != nil {
+		return nil, err
+	}
+	var role policy.MemberRole
+	if member != nil {
+		role = policy.MemberRole(member.Role)
+	}
+	err = policy.AuthorizeWallet(policy.Subject{
+		Username: user.Username,
+		Role:     policy.Role(user.Role),
+		Frozen:   user.Frozen,
+	}, action, role)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// checkKyc
+// Checks account status and KYC tier of the user moving money, before it moves.
+func checkKyc(user *userrepo.User, action policy.Action, amount decimal.Decimal) error {
+	return policy.CheckKyc(policy.AccountStatus(user.Status), policy.Tier(user.KycTier), action, amount)
+}
+
+// authorizeRead
+// Checks policy for principal reading data of username. Denials are reported as not found so that usernames cannot be
+// enumerated.
+func (s Service) authorizeRead(ctx context.Context, principal string, username string) error {
+	err := s.Authorize(ctx, principal, policy.ActionReadUser, username)
+	if utils.ErrorCodeOf(err) == utils.ErrorCodeForbidden {
+		return utils.NotFoundErrorF("user")
+	}
+	return err
+}
+
+// authorizeWalletRead
+// Same as authorizeRead for data of the wallet, read by its members and roles reading wallets. Denials are reported
+// as wallet not found.
+func (s Service) authorizeWalletRead(ctx context.Context, principal string, walletId int64) error {
+	_, err := s.authorizeWallet(ctx, principal, policy.ActionReadWallet, walletId)
+	if utils.ErrorCodeOf(err) == utils.ErrorCodeForbidden {
+		return utils.NotFoundErrorF("wallet")
+	}
+	return err
+}
+
+func (s Service) GetUserWalletBalanceByUserName(ctx context.Context, requestor string, username string) (userrepo.UserWallets, error) {
 	if username == "" {
-		return userrepo.UserWallets{}, errors.New("user id cannot be empty")
+		return userrepo.UserWallets{}, utils.InvalidArgumentErrorF("user id cannot be empty")
+	}
+	if err := s.authorizeRead(ctx, requestor, username); err != nil {
+		return userrepo.UserWallets{}, err
 	}
 
-	walletBalances, err := s.repo.UserWallets(ctx, username)
+	walletBalances, err := s.repo.UserWallets(ctx, policy.CanonicalUsername(username))
 	if err != nil {
 		return userrepo.UserWallets{}, err
 	}
 	return walletBalances, nil
 }
 
-func (s Service) GetUserTransactionsByUserName(ctx context.Context, username string) ([]userrepo.TransactionLedgers, error) {
+func (s Service) GetUserTransactionsByUserName(ctx context.Context, requestor string, username string) ([]userrepo.TransactionLedgers, error) {
 	if username == "" {
-		return []userrepo.TransactionLedgers{}, errors.New("user id cannot be empty")
+		return []userrepo.TransactionLedgers{}, utils.InvalidArgumentErrorF("user id cannot be empty")
+	}
+	if err := s.authorizeRead(ctx, requestor, username); err != nil {
+		return []userrepo.TransactionLedgers{}, err
 	}
 
-	transactions, err := s.repo.Transactions(ctx, username)
+	transactions, err := s.repo.Transactions(ctx, policy.CanonicalUsername(username))
 	if err != nil {
 		return []userrepo.TransactionLedgers{}, err
 	}
 	return transactions, nil
 }
 
+// UserWalletsAt
+// Same as GetUserWalletBalanceByUserName with balances as of at, which cannot be in the future.
+func (s Service) UserWalletsAt(ctx context.Context, requestor string, username string, at time.Time) (userrepo.UserWallets, error) {
+	if username == "" {
+		return userrepo.UserWallets{}, utils.InvalidArgumentErrorF("user id cannot be empty")
+	}
+	if at.After(time.Now()) {
+		return userrepo.UserWallets{}, utils.InvalidArgumentErrorF("invalid_at")
+	}
+	if err := s.authorizeRead(ctx, requestor, username); err != nil {
+		return userrepo.UserWallets{}, err
+	}
+
+	return s.repo.UserWalletsAt(ctx, policy.CanonicalUsername(username), at)
+}
+
+// WalletAt
+// Wallet with the balance as of at, which cannot be in the future. Zero at is now.
+func (s Service) WalletAt(ctx context.Context, requestor string, walletId int64, at time.Time) (userrepo.Wallet, error) {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	if at.After(time.Now()) {
+		return userrepo.Wallet{}, utils.InvalidArgumentErrorF("invalid_at")
+	}
+	if err := s.authorizeWalletRead(ctx, requestor, walletId); err != nil {
+		return userrepo.Wallet{}, err
+	}
+
+	return s.repo.WalletAt(ctx, walletId, at)
+}
+
+// StreamTransactions
+// Same as GetUserTransactionsByUserName, rows are passed to fn as they are read. Authorization fails before fn is called.
+func (s Service) StreamTransactions(ctx context.Context, requestor string, username string, fn func(userrepo.TransactionLedgerRow) error) error {
+	if username == "" {
+		return utils.InvalidArgumentErrorF("user id cannot be empty")
+	}
+	if err := s.authorizeRead(ctx, requestor, username); err != nil {
+		return err
+	}
+
+	return s.repo.StreamTransactions(ctx, policy.CanonicalUsername(username), fn)
+}
+
+// Statement
+// Statement of the wallet for [from, to), for members of the wallet or roles reading wallets, otherwise the wallet is
+// not found. Zero from is the first ledger, zero to is now.
+func (s Service) Statement(ctx context.Context, requestor string, walletId int64, from, to time.Time) (userrepo.Statement, error) {
+	if to.IsZero() {
+		to = time.Now()
+	}
+	if !from.Before(to) {
+		return userrepo.Statement{}, utils.InvalidArgumentErrorF("invalid_time_range")
+	}
+	if err := s.authorizeWalletRead(ctx, requestor, walletId); err != nil {
+		return userrepo.Statement{}, err
+	}
+
+	return s.repo.Statement(ctx, walletId, from, to)
+}
+
+// CreateUser
+// The username is registered in NFKC form and must be unique by canonical form, i.e. "Alice" once "alice" exists.
 func (s Service) CreateUser(ctx context.Context, username string) (userrepo.User, error) {
 	if username == "" {
-		return userrepo.User{}, errors.New("user name cannot be empty")
+		return userrepo.User{}, utils.InvalidArgumentErrorF("user name cannot be empty")
+	}
+	if err := policy.ValidateUsername(username); err != nil {
+		return userrepo.User{}, err
 	}
 
-	user, err := s.repo.CreateUser(ctx, username)
+	user, err := s.repo.CreateUser(ctx, policy.NormalizeUsername(username), policy.CanonicalUsername(username))
 	if err != nil {
 		return userrepo.User{}, err
 	}
 	return user, nil
 }
 
-func (s Service) CreateWallet(ctx context.Context, username string, _currency string) (userrepo.Wallet, error) {
+const (
+	DefaultWalletName   = "main"
+	MaxWalletNameLength = 64
+)
+
+// CreateWallet
+// Creates a wallet of username, its owner member, by username only. Users have any number of wallets per currency,
+// each with a distinct name. Empty name is DefaultWalletName.
+func (s Service) CreateWallet(ctx context.Context, principal string, username string, _currency string, name string) (userrepo.Wallet, error) {
 	if username == "" {
-		return userrepo.Wallet{}, errors.New("user name cannot be empty")
+		return userrepo.Wallet{}, utils.InvalidArgumentErrorF("user name cannot be empty")
 	}
 	currency := userrepo.CurrencyType(_currency)
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = DefaultWalletName
+	}
+	if utf8.RuneCountInString(name) > MaxWalletNameLength {
+		return userrepo.Wallet{}, utils.InvalidArgumentErrorF("name must be at most %d characters", MaxWalletNameLength)
+	}
+	if err := s.Authorize(ctx, principal, policy.ActionCreateWallet, username); err != nil {
+		return userrepo.Wallet{}, err
+	}
 
-	wallet, err := s.repo.CreateWallet(ctx, username, currency)
+	wallet, err := s.repo.CreateWallet(ctx, policy.CanonicalUsername(username), currency, name)
 	if err != nil {
 		return userrepo.Wallet{}, err
 	}
@@ -68,33 +240,349 @@ func (s Service) CreateWallet(ctx context.Context, username string, _currency st
 
 func (s Service) Deposit(ctx context.Context, requestor string, nonce int64, walletId int64, amount decimal.Decimal) (userrepo.Transaction, userrepo.Ledger, error) {
 	if !amount.IsPositive() {
-		return userrepo.Transaction{}, userrepo.Ledger{}, errors.New("invalid_amount")
+		return userrepo.Transaction{}, userrepo.Ledger{}, utils.InvalidAmountError
 	}
 	if nonce == 0 {
-		return userrepo.Transaction{}, userrepo.Ledger{}, errors.New("invalid_nonce")
+		return userrepo.Transaction{}, userrepo.Ledger{}, utils.InvalidNonceError
+	}
+	user, err := s.authorizeWallet(ctx, requestor, policy.ActionDeposit, walletId)
+	if err != nil {
+		return userrepo.Transaction{}, userrepo.Ledger{}, err
+	}
+	if err := checkKyc(user, policy.ActionDeposit, amount); err != nil {
+		return userrepo.Transaction{}, userrepo.Ledger{}, err
 	}
 
-	return s.repo.Deposit(requestor, ctx, nonce, walletId, amount)
+	return s.repo.Deposit(policy.CanonicalUsername(requestor), ctx, nonce, walletId, amount)
 }
 
 func (s Service) Withdraw(ctx context.Context, requestor string, nonce int64, walletId int64, amount decimal.Decimal) (userrepo.Transaction, userrepo.Ledger, error) {
 	if !amount.IsPositive() {
-		return userrepo.Transaction{}, userrepo.Ledger{}, errors.New("invalid_amount")
+		return userrepo.Transaction{}, userrepo.Ledger{}, utils.InvalidAmountError
 	}
 	if nonce == 0 {
-		return userrepo.Transaction{}, userrepo.Ledger{}, errors.New("invalid_nonce")
+		return userrepo.Transaction{}, userrepo.Ledger{}, utils.InvalidNonceError
+	}
+	user, err := s.authorizeWallet(ctx, requestor, policy.ActionWithdraw, walletId)
+	if err != nil {
+		return userrepo.Transaction{}, userrepo.Ledger{}, err
+	}
+	if err := checkKyc(user, policy.ActionWithdraw, amount); err != nil {
+		return userrepo.Transaction{}, userrepo.Ledger{}, err
 	}
 
-	return s.repo.Withdraw(requestor, ctx, nonce, walletId, amount)
+	return s.repo.Withdraw(policy.CanonicalUsername(requestor), ctx, nonce, walletId, amount)
 }
 
+// Transfer
+// Transfers over the threshold of the approval policy of the source wallet are pending approval without ledgers, see
+// ApproveTransfer.
 func (s Service) Transfer(ctx context.Context, requestor string, nonce int64, sourceWalletId, destinationWalletId int64, amount decimal.Decimal) (userrepo.Transaction, []userrepo.Ledger, error) {
 	if !amount.IsPositive() {
-		return userrepo.Transaction{}, []userrepo.Ledger{}, errors.New("invalid_amount")
+		return userrepo.Transaction{}, []userrepo.Ledger{}, utils.InvalidAmountError
+	}
+	if nonce == 0 {
+		return userrepo.Transaction{}, []userrepo.Ledger{}, utils.InvalidNonceError
+	}
+	approvalPolicy, err := s.authorizeTransfer(ctx, requestor, sourceWalletId, destinationWalletId, amount)
+	if err != nil {
+		return userrepo.Transaction{}, []userrepo.Ledger{}, err
+	}
+	if approvalPolicy != nil && amount.GreaterThan(approvalPolicy.Threshold) {
+		transaction, err := s.repo.CreatePendingTransfer(policy.CanonicalUsername(requestor), ctx, nonce, sourceWalletId,
+			destinationWalletId, amount, *approvalPolicy)
+		return transaction, []userrepo.Ledger{}, err
+	}
+
+	return s.repo.Transfer(policy.CanonicalUsername(requestor), ctx, nonce, sourceWalletId, destinationWalletId, amount)
+}
+
+// authorizeTransfer
+// Checks policy and KYC for principal transferring amount from the source wallet, and the account of the destination
+// wallet. Returns the approval policy of the source wallet, nil if none.
+func (s Service) authorizeTransfer(ctx context.Context, principal string, sourceWalletId, destinationWalletId int64, amount decimal.Decimal) (*userrepo.ApprovalPolicy, error) {
+	user, err := s.authorizeWallet(ctx, principal, policy.ActionTransfer, sourceWalletId)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkKyc(user, policy.ActionTransfer, amount); err != nil {
+		return nil, err
+	}
+	destinationOwner, err := s.repo.WalletOwner(ctx, destinationWalletId)
+	if err != nil {
+		return nil, err
+	}
+	if policy.AccountStatus(destinationOwner.Status) == policy.AccountStatusSuspended {
+		return nil, utils.AccountSuspendedError
+	}
+	return s.repo.ApprovalPolicy(ctx, sourceWalletId)
+}
+
+// UserAccount
+// Account and wallets of username, for the user or privileged roles.
+func (s Service) UserAccount(ctx context.Context, requestor string, username string) (userrepo.UserWallets, error) {
+	if username == "" {
+		return userrepo.UserWallets{}, utils.InvalidArgumentErrorF("user name cannot be empty")
+	}
+	if err := s.Authorize(ctx, requestor, policy.ActionReadUser, username); err != nil {
+		return userrepo.UserWallets{}, err
+	}
+
+	return s.repo.UserWallets(ctx, policy.CanonicalUsername(username))
+}
+
+func (s Service) SetFrozen(ctx context.Context, requestor string, username string, frozen bool) (userrepo.User, error) {
+	if username == "" {
+		return userrepo.User{}, utils.InvalidArgumentErrorF("user name cannot be empty")
+	}
+	if err := s.Authorize(ctx, requestor, policy.ActionFreezeUser, username); err != nil {
+		return userrepo.User{}, err
+	}
+
+	return s.repo.SetFrozen(ctx, policy.CanonicalUsername(username), frozen)
+}
+
+func (s Service) SetRole(ctx context.Context, requestor string, username string, role policy.Role) (userrepo.User, error) {
+	if username == "" {
+		return userrepo.User{}, utils.InvalidArgumentErrorF("user name cannot be empty")
+	}
+	if !role.Valid() {
+		return userrepo.User{}, utils.InvalidArgumentErrorF("role must be one of %v", policy.Roles)
+	}
+	if err := s.Authorize(ctx, requestor, policy.ActionSetRole, username); err != nil {
+		return userrepo.User{}, err
+	}
+
+	return s.repo.SetRole(ctx, policy.CanonicalUsername(username), string(role))
+}
+
+// CreateAdjustment
+// Manual credit or debit of a wallet, i.e. to correct a ledger or settle a chargeback. Pending approval by another
+// operator, see ApproveAdjustment. Roles operator and admin only, not on own wallets.
+func (s Service) CreateAdjustment(ctx context.Context, requestor string, nonce int64, walletId int64, entryType string, amount decimal.Decimal, reasonCode userrepo.AdjustmentReasonCode, reason string) (userrepo.Transaction, error) {
+	if !amount.IsPositive() {
+		return userrepo.Transaction{}, utils.InvalidAmountError
 	}
 	if nonce == 0 {
-		return userrepo.Transaction{}, []userrepo.Ledger{}, errors.New("invalid_nonce")
+		return userrepo.Transaction{}, utils.InvalidNonceError
+	}
+	if entryType != "credit" && entryType != "debit" {
+		return userrepo.Transaction{}, utils.InvalidArgumentErrorF("entry_type must be credit or debit")
+	}
+	if !slices.Contains(userrepo.AdjustmentReasonCodes, reasonCode) {
+		return userrepo.Transaction{}, utils.InvalidArgumentErrorF("reason_code must be one of %v", userrepo.AdjustmentReasonCodes)
+	}
+	if reason == "" {
+		return userrepo.Transaction{}, utils.InvalidArgumentErrorF("reason is required")
+	}
+	if _, err := s.authorizeWallet(ctx, requestor, policy.ActionAdjust, walletId); err != nil {
+		return userrepo.Transaction{}, err
+	}
+
+	return s.repo.CreateAdjustment(policy.CanonicalUsername(requestor), ctx, nonce, walletId, entryType, amount, reasonCode, reason)
+}
+
+// ApproveAdjustment
+// Posts the ledger of an adjustment pending approval. Roles operator and admin only, not by the maker nor on own
+// wallets.
+func (s Service) ApproveAdjustment(ctx context.Context, requestor string, id int64) (userrepo.Transaction, userrepo.Ledger, error) {
+	if _, err := s.authorizeAdjustment(ctx, requestor, policy.ActionApproveAdjustment, id); err != nil {
+		return userrepo.Transaction{}, userrepo.Ledger{}, err
+	}
+
+	return s.repo.ApproveAdjustment(policy.CanonicalUsername(requestor), ctx, id)
+}
+
+// RejectAdjustment
+// Rejects an adjustment pending approval, by its maker or another operator. Roles operator and admin only, not on own
+// wallets.
+func (s Service) RejectAdjustment(ctx context.Context, requestor string, id int64) (userrepo.Transaction, error) {
+	if _, err := s.authorizeAdjustment(ctx, requestor, policy.ActionAdjust, id); err != nil {
+		return userrepo.Transaction{}, err
+	}
+
+	return s.repo.RejectAdjustment(policy.CanonicalUsername(requestor), ctx, id)
+}
+
+// authorizeAdjustment
+// Checks policy for principal acting on the wallet of the adjustment. Returns the adjustment.
+func (s Service) authorizeAdjustment(ctx context.Context, principal string, action policy.Action, id int64) (userrepo.Transaction, error) {
+	adjustment, err := s.repo.Adjustment(ctx, id)
+	if err != nil {
+		return userrepo.Transaction{}, err
+	}
+	if adjustment.MetaData.SourceWalletId == nil {
+		return userrepo.Transaction{}, utils.NotFoundErrorF("adjustment")
+	}
+	if _, err := s.authorizeWallet(ctx, principal, action, *adjustment.MetaData.SourceWalletId); err != nil {
+		return userrepo.Transaction{}, err
+	}
+	return adjustment, nil
+}
+
+const (
+	DefaultPendingAdjustmentsLimit = 100
+	MaxPendingAdjustmentsLimit     = 1000
+)
+
+// PendingAdjustments
+// Adjustments pending approval sorted by oldest. Roles support_readonly, operator and admin only.
+func (s Service) PendingAdjustments(ctx context.Context, requestor string, limit int) ([]userrepo.Transaction, error) {
+	if limit < 0 || limit > MaxPendingAdjustmentsLimit {
+		return []userrepo.Transaction{}, utils.InvalidArgumentErrorF("invalid_limit")
+	}
+	if limit == 0 {
+		limit = DefaultPendingAdjustmentsLimit
+	}
+	if err := s.Authorize(ctx, requestor, policy.ActionReadLedger, ""); err != nil {
+		return []userrepo.Transaction{}, err
+	}
+
+	return s.repo.PendingAdjustments(ctx, limit)
+}
+
+// SetWalletStatus
+// Freezes, unfreezes or closes a wallet, the reason is recorded in the wallet status history.
+func (s Service) SetWalletStatus(ctx context.Context, requestor string, walletId int64, status userrepo.WalletStatus, reason string) (userrepo.Wallet, error) {
+	if !slices.Contains(userrepo.WalletStatuses, status) {
+		return userrepo.Wallet{}, utils.InvalidArgumentErrorF("status must be one of %v", userrepo.WalletStatuses)
+	}
+	if reason == "" {
+		return userrepo.Wallet{}, utils.InvalidArgumentErrorF("reason is required")
+	}
+	if _, err := s.authorizeWallet(ctx, requestor, policy.ActionSetWalletStatus, walletId); err != nil {
+		return userrepo.Wallet{}, err
 	}
 
-	return s.repo.Transfer(requestor, ctx, nonce, sourceWalletId, destinationWalletId, amount)
+	return s.repo.SetWalletStatus(ctx, policy.CanonicalUsername(requestor), walletId, status, reason)
+}
+
+func (s Service) WalletStatusChanges(ctx context.Context, requestor string, walletId int64) ([]userrepo.WalletStatusChange, error) {
+	if _, err := s.authorizeWallet(ctx, requestor, policy.ActionReadWallet, walletId); err != nil {
+		return []userrepo.WalletStatusChange{}, err
+	}
+
+	return s.repo.WalletStatusChanges(ctx, walletId)
+}
+
+const (
+	DefaultPendingTransactionsLimit = 100
+	MaxPendingTransactionsLimit     = 1000
+)
+
+// PendingTransactions
+// Transactions pending for longer than age, left by a crashed process, sorted by oldest. Roles support_readonly,
+// operator and admin only.
+func (s Service) PendingTransactions(ctx context.Context, requestor string, age time.Duration, limit int) ([]userrepo.Transaction, error) {
+	if age < 0 {
+		return []userrepo.Transaction{}, utils.InvalidArgumentErrorF("older_than cannot be negative")
+	}
+	if limit < 0 || limit > MaxPendingTransactionsLimit {
+		return []userrepo.Transaction{}, utils.InvalidArgumentErrorF("invalid_limit")
+	}
+	if limit == 0 {
+		limit = DefaultPendingTransactionsLimit
+	}
+	if err := s.Authorize(ctx, requestor, policy.ActionReadLedger, ""); err != nil {
+		return []userrepo.Transaction{}, err
+	}
+
+	return s.repo.PendingTransactions(ctx, time.Now().Add(-age), limit)
+}
+
+// Reconcile
+// Checks ledgers against wallet balances and transactions. Roles support_readonly, operator and admin only.
+func (s Service) Reconcile(ctx context.Context, requestor string) (userrepo.Reconciliation, error) {
+	if err := s.Authorize(ctx, requestor, policy.ActionReadLedger, ""); err != nil {
+		return userrepo.Reconciliation{}, err
+	}
+
+	return s.repo.Reconcile(ctx)
+}
+
+// Profile
+// Profile of username, for the user or privileged roles.
+func (s Service) Profile(ctx context.Context, requestor string, username string) (userrepo.User, error) {
+	if username == "" {
+		return userrepo.User{}, utils.InvalidArgumentErrorF("user name cannot be empty")
+	}
+	if err := s.authorizeRead(ctx, requestor, username); err != nil {
+		return userrepo.User{}, err
+	}
+
+	user, err := s.repo.User(ctx, policy.CanonicalUsername(username))
+	if err != nil {
+		return userrepo.User{}, err
+	}
+	return *user, nil
+}
+
+const MaxDisplayNameLength = 100
+
+// UpdateProfile
+// Display name and email are updated by the user, status and KYC tier by operators and admins.
+func (s Service) UpdateProfile(ctx context.Context, requestor string, username string, update userrepo.ProfileUpdate) (userrepo.User, error) {
+	if username == "" {
+		return userrepo.User{}, utils.InvalidArgumentErrorF("user name cannot be empty")
+	}
+	if update == (userrepo.ProfileUpdate{}) {
+		return userrepo.User{}, utils.InvalidArgumentErrorF("nothing to update")
+	}
+	if err := s.authorizeRead(ctx, requestor, username); err != nil {
+		return userrepo.User{}, err
+	}
+	if update.DisplayName != nil || update.Email != nil {
+		if err := s.Authorize(ctx, requestor, policy.ActionUpdateProfile, username); err != nil {
+			return userrepo.User{}, err
+		}
+	}
+	if update.Status != nil || update.KycTier != nil {
+		if err := s.Authorize(ctx, requestor, policy.ActionVerifyUser, username); err != nil {
+			return userrepo.User{}, err
+		}
+	}
+
+	if err := validateProfileUpdate(update); err != nil {
+		return userrepo.User{}, err
+	}
+	if update.Status != nil || update.KycTier != nil {
+		current, err := s.repo.User(ctx, policy.CanonicalUsername(username))
+		if err != nil {
+			return userrepo.User{}, err
+		}
+		status, tier := current.Status, current.KycTier
+		if update.Status != nil {
+			status = *update.Status
+		}
+		if update.KycTier != nil {
+			tier = *update.KycTier
+		}
+		// suspended users keep their tier for when they are reinstated
+		if tier > 0 && policy.AccountStatus(status) == policy.AccountStatusPending {
+			return userrepo.User{}, utils.InvalidArgumentErrorF("kyc_tier above 0 requires a verified account")
+		}
+	}
+
+	return s.repo.UpdateProfile(ctx, policy.CanonicalUsername(username), update)
+}
+
+// validateProfileUpdate
+// Checks the fields of update regardless of the current profile. Lengths are in characters.
+func validateProfileUpdate(update userrepo.ProfileUpdate) error {
+	if update.DisplayName != nil && utf8.RuneCountInString(*update.DisplayName) > MaxDisplayNameLength {
+		return utils.InvalidArgumentErrorF("display_name must be at most %d characters", MaxDisplayNameLength)
+	}
+	if update.Email != nil {
+		address, err := mail.ParseAddress(*update.Email)
+		if err != nil || address.Address != *update.Email {
+			return utils.InvalidArgumentErrorF("email must be an address i.e. user@example.com")
+		}
+	}
+	if update.Status != nil && !policy.AccountStatus(*update.Status).Valid() {
+		return utils.InvalidArgumentErrorF("status must be one of %v", policy.AccountStatuses)
+	}
+	if update.KycTier != nil && !policy.Tier(*update.KycTier).Valid() {
+		return utils.InvalidArgumentErrorF("kyc_tier must be between 0 and %d", len(policy.TierRules)-1)
+	}
+	return nil
 }
@@ -0,0 +1,44 @@
+package user
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	userrepo "github.com/cryptonlx/crypto/src/repositories/user"
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+)
+
+func TestValidateProfileUpdate(t *testing.T) {
+	pointer := func(s string) *string { return &s }
+	tier := func(i int) *int { return &i }
+	invalid := utils.NewError(utils.ErrorCodeInvalidArgument, "")
+
+	tests := []struct {
+		name    string
+		update  userrepo.ProfileUpdate
+		wantErr error
+	}{
+		{"ascii display name at limit", userrepo.ProfileUpdate{DisplayName: pointer(strings.Repeat("a", 100))}, nil},
+		{"ascii display name over limit", userrepo.ProfileUpdate{DisplayName: pointer(strings.Repeat("a", 101))}, invalid},
+		{"multibyte display name at limit", userrepo.ProfileUpdate{DisplayName: pointer(strings.Repeat("é", 100))}, nil},
+		{"cjk display name at limit", userrepo.ProfileUpdate{DisplayName: pointer(strings.Repeat("名", 100))}, nil},
+		{"multibyte display name over limit", userrepo.ProfileUpdate{DisplayName: pointer(strings.Repeat("é", 101))}, invalid},
+		{"email", userrepo.ProfileUpdate{Email: pointer("ada@example.com")}, nil},
+		{"email with name", userrepo.ProfileUpdate{Email: pointer("Ada <ada@example.com>")}, invalid},
+		{"status", userrepo.ProfileUpdate{Status: pointer("verified")}, nil},
+		{"unknown status", userrepo.ProfileUpdate{Status: pointer("closed")}, invalid},
+		{"unknown tier", userrepo.ProfileUpdate{KycTier: tier(3)}, invalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateProfileUpdate(tt.update)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("validateProfileUpdate() want nil err. got %v", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("validateProfileUpdate() want err %v. got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
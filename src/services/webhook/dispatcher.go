@@ -0,0 +1,250 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	outboxrepo "github.com/cryptonlx/crypto/src/repositories/outbox"
+	webhookrepo "github.com/cryptonlx/crypto/src/repositories/webhook"
+)
+
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	EventHeader     = "X-Webhook-Event"
+
+	// maxErrorLength
+	// Of last_error recorded per failed attempt.
+	maxErrorLength = 512
+)
+
+type DispatcherParams struct {
+	// Interval between polls of outbox events and due deliveries.
+	Interval time.Duration
+	// BatchSize is the outbox events enqueued and the deliveries sent at once per poll.
+	BatchSize int
+	// Timeout of one attempt.
+	Timeout time.Duration
+	// MaxAttempts before a delivery is dead.
+	MaxAttempts int
+	// RetryBackoff is the delay before the second attempt, doubled per attempt up to MaxBackoff.
+	RetryBackoff time.Duration
+	MaxBackoff   time.Duration
+	// AllowPrivateUrls dials private addresses, for receivers in development and e2e tests. See dialControl.
+	AllowPrivateUrls bool
+}
+
+// Dispatcher
+// Consumes the outbox into deliveries, see Notifications, and sends due deliveries. Deliveries are sent at least once,
+// receivers deduplicate by X-Webhook-Delivery.
+type Dispatcher struct {
+	repo   *webhookrepo.Repo
+	outbox *outboxrepo.Repo
+	client *http.Client
+	params DispatcherParams
+}
+
+func NewDispatcher(repo *webhookrepo.Repo, outbox *outboxrepo.Repo, params DispatcherParams) *Dispatcher {
+	dialer := &net.Dialer{Timeout: params.Timeout}
+	if !params.AllowPrivateUrls {
+		dialer.Control = dialControl
+	}
+	return &Dispatcher{
+		repo:   repo,
+		outbox: outbox,
+		client: &http.Client{
+			Timeout: params.Timeout,
+			// no proxy, the receiver is the address dialed
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				ForceAttemptHTTP2:   true,
+				MaxIdleConns:        100,
+				IdleConnTimeout:     90 * time.Second,
+				TLSHandshakeTimeout: 10 * time.Second,
+			},
+			// redirects are failed attempts, receivers are configured with their final url
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		params: params,
+	}
+}
+
+// Run
+// Enqueues outbox events and dispatches due deliveries every Interval until ctx is done. Attempts in flight are
+// cancelled and retried later.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.params.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for {
+			n, err := d.Enqueue(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("webhook enqueue err %v\n", err)
+			}
+			if err != nil || n < d.params.BatchSize {
+				break
+			}
+		}
+		for {
+			n, err := d.DispatchDue(ctx)
+			if err != nil && ctx.Err() == nil {
+				log.Printf("webhook dispatch err %v\n", err)
+			}
+			// a full batch suggests a backlog, poll again without waiting
+			if err != nil || n < d.params.BatchSize {
+				break
+			}
+		}
+	}
+}
+
+// Enqueue
+// Writes the deliveries of one batch of outbox events. Returns the number of events enqueued.
+func (d *Dispatcher) Enqueue(ctx context.Context) (int, error) {
+	return d.outbox.EnqueueWebhooks(ctx, d.params.BatchSize, enqueue)
+}
+
+// DispatchDue
+// Sends one batch of due deliveries concurrently. Returns the number of deliveries attempted.
+func (d *Dispatcher) DispatchDue(ctx context.Context) (int, error) {
+	// claimed deliveries are due again if this dispatcher stops before recording the outcome
+	claimed, err := d.repo.Claim(ctx, d.params.BatchSize, 2*d.params.Timeout)
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(claimed))
+	for i, c := range claimed {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = d.attempt(ctx, c)
+		}()
+	}
+	wg.Wait()
+	return len(claimed), errors.Join(errs...)
+}
+
+// attempt
+// Sends the delivery and records the outcome. The outcome is recorded even if ctx is cancelled mid-attempt.
+func (d *Dispatcher) attempt(ctx context.Context, c webhookrepo.ClaimedDelivery) error {
+	sendErr := Send(ctx, d.client, c.Url, c.Secret, c.Delivery, time.Now())
+
+	ctx = context.WithoutCancel(ctx)
+	if sendErr == nil {
+		return d.repo.MarkDelivered(ctx, c.Delivery.Id)
+	}
+	lastError := sendErr.Error()
+	if len(lastError) > maxErrorLength {
+		lastError = lastError[:maxErrorLength]
+	}
+	var retryAt *time.Time
+	if c.Delivery.Attempts < d.params.MaxAttempts {
+		t := time.Now().Add(Backoff(c.Delivery.Attempts, d.params.RetryBackoff, d.params.MaxBackoff))
+		retryAt = &t
+	}
+	return d.repo.MarkFailed(ctx, c.Delivery.Id, lastError, retryAt)
+}
+
+// Send
+// POSTs the payload of the delivery to url, signed with secret at now. Responses other than 2xx are errors.
+func Send(ctx context.Context, client *http.Client, url string, secret string, delivery webhookrepo.Delivery, now time.Time) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "crypto-webhooks/1")
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.Id, 10))
+	req.Header.Set(EventHeader, string(delivery.EventType))
+	req.Header.Set(SignatureHeader, Sign(secret, now, delivery.Payload))
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	// drained for the connection to be reused
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("http status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// Sign
+// Value of the X-Webhook-Signature header, t=<unix seconds>,v1=<hex hmac-sha256 of "<unix seconds>.<body>" keyed by
+// secret>. The timestamp is signed so that captured requests cannot be replayed later.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	t := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + t + ",v1=" + hex.EncodeToString(mac(secret, t, body))
+}
+
+// Verify
+// Checks a X-Webhook-Signature header for receivers. Signatures older or newer than tolerance around now are rejected.
+func Verify(secret string, signature string, body []byte, now time.Time, tolerance time.Duration) error {
+	var t, v1 string
+	for _, part := range strings.Split(signature, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			t = value
+		case "v1":
+			v1 = value
+		}
+	}
+	unix, err := strconv.ParseInt(t, 10, 64)
+	if err != nil {
+		return errors.New("signature without timestamp")
+	}
+	if age := now.Sub(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return errors.New("signature timestamp out of tolerance")
+	}
+	got, err := hex.DecodeString(v1)
+	if err != nil || !hmac.Equal(got, mac(secret, t, body)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+func mac(secret string, timestamp string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
+
+// Backoff
+// Delay after the attempt-th failed attempt, base doubled per attempt and capped at max.
+func Backoff(attempt int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempt; i++ {
+		delay *= 2
+		if delay >= max {
+			return max
+		}
+	}
+	return min(delay, max)
+}
@@ -0,0 +1,128 @@
+package webhook
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	webhookrepo "github.com/cryptonlx/crypto/src/repositories/webhook"
+)
+
+func TestSend(t *testing.T) {
+	const secret = "whsec_test"
+	now := time.Unix(1749460653, 0)
+	delivery := webhookrepo.Delivery{
+		Id:        7,
+		EventType: webhookrepo.EventBalanceChanged,
+		Payload:   []byte(`{"type":"balance.changed","data":{"wallet_id":1,"balance":"10"}}`),
+	}
+
+	tests := []struct {
+		name    string
+		status  int
+		wantErr string
+	}{
+		{"ok", http.StatusOK, ""},
+		{"no content", http.StatusNoContent, ""},
+		{"server error", http.StatusInternalServerError, "http status 500"},
+		{"redirect", http.StatusFound, "http status 302"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != string(delivery.Payload) {
+					t.Errorf("body want %s. got %s", delivery.Payload, body)
+				}
+				if got := r.Header.Get(DeliveryHeader); got != "7" {
+					t.Errorf("%s want 7. got %q", DeliveryHeader, got)
+				}
+				if got := r.Header.Get(EventHeader); got != "balance.changed" {
+					t.Errorf("%s want balance.changed. got %q", EventHeader, got)
+				}
+				if err := Verify(secret, r.Header.Get(SignatureHeader), body, now, 5*time.Minute); err != nil {
+					t.Errorf("Verify() want nil err. got %v", err)
+				}
+				if tt.status == http.StatusFound {
+					w.Header().Set("Location", "/elsewhere")
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer receiver.Close()
+
+			err := Send(context.Background(), NewDispatcher(nil, nil, DispatcherParams{Timeout: time.Second, AllowPrivateUrls: true}).client, receiver.URL, secret, delivery, now)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Send() want nil err. got %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Fatalf("Send() want err containing %q. got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSendPrivateAddress(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("want loopback receiver not dialed")
+	}))
+	defer receiver.Close()
+
+	err := Send(context.Background(), NewDispatcher(nil, nil, DispatcherParams{Timeout: time.Second}).client, receiver.URL, "whsec_test", webhookrepo.Delivery{Id: 7}, time.Now())
+	if err == nil || !strings.Contains(err.Error(), "address is not public") {
+		t.Fatalf("Send() want err address is not public. got %v", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1749460653, 0)
+	body := []byte(`{"type":"transaction.succeeded"}`)
+	signature := Sign("whsec_test", now, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		signature string
+		body      []byte
+		now       time.Time
+		wantErr   bool
+	}{
+		{"valid", "whsec_test", signature, body, now, false},
+		{"within tolerance", "whsec_test", signature, body, now.Add(4 * time.Minute), false},
+		{"wrong secret", "whsec_other", signature, body, now, true},
+		{"tampered body", "whsec_test", signature, []byte(`{"type":"transaction.failed"}`), now, true},
+		{"replayed later", "whsec_test", signature, body, now.Add(6 * time.Minute), true},
+		{"timestamp changed", "whsec_test", strings.Replace(signature, "t=1749460653", "t=1749460654", 1), body, now, true},
+		{"malformed", "whsec_test", "v1=00", body, now, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.signature, tt.body, tt.now, 5*time.Minute)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() want err %v. got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{1, 10 * time.Second},
+		{2, 20 * time.Second},
+		{3, 40 * time.Second},
+		{7, 640 * time.Second},
+		{8, 15 * time.Minute},
+		{100, 15 * time.Minute},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.attempt, 10*time.Second, 15*time.Minute); got != tt.want {
+			t.Fatalf("Backoff(%d) want %s. got %s", tt.attempt, tt.want, got)
+		}
+	}
+}
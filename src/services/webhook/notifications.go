@@ -0,0 +1,133 @@
+package webhook
+
+import (
+	"context"
+
+	outboxrepo "github.com/cryptonlx/crypto/src/repositories/outbox"
+	webhookrepo "github.com/cryptonlx/crypto/src/repositories/webhook"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Notification
+// Webhook event for the user of UserAccountId, or for the members of WalletId if UserAccountId is 0.
+type Notification struct {
+	UserAccountId int64
+	WalletId      int64
+	Event         webhookrepo.Event
+}
+
+// Notifications
+// Webhook events of a domain event: transaction.succeeded or transaction.failed to the requestor of the transaction and
+// balance.changed per ledger to the members of its wallet. Users and wallets created have none.
+func Notifications(m outboxrepo.Message) ([]Notification, error) {
+	event, err := m.Event()
+	if err != nil {
+		return []Notification{}, err
+	}
+
+	switch e := event.(type) {
+	case outboxrepo.FundsDeposited:
+		return funds(m, e.Funds, "credit"), nil
+	case outboxrepo.FundsWithdrawn:
+		return funds(m, e.Funds, "debit"), nil
+	case outboxrepo.TransferCompleted:
+		return []Notification{
+			transaction(m, webhookrepo.EventTransactionSucceeded, webhookrepo.TransactionData{
+				TransactionId: e.TransactionId,
+				RequestorId:   e.RequestorId,
+				Nonce:         e.Nonce,
+				Operation:     "transfer",
+				Status:        "success",
+				MetaData:      e.MetaData,
+			}),
+			balance(m, webhookrepo.BalanceData{
+				WalletId:      e.SourceWalletId,
+				Currency:      e.Currency,
+				TransactionId: e.TransactionId,
+				LedgerId:      e.SourceLedgerId,
+				EntryType:     "debit",
+				Amount:        e.Amount,
+				Balance:       e.SourceBalance,
+			}),
+			balance(m, webhookrepo.BalanceData{
+				WalletId:      e.DestinationWalletId,
+				Currency:      e.Currency,
+				TransactionId: e.TransactionId,
+				LedgerId:      e.DestinationLedgerId,
+				EntryType:     "credit",
+				Amount:        e.Amount,
+				Balance:       e.DestinationBalance,
+			}),
+		}, nil
+	case outboxrepo.TransactionFailed:
+		return []Notification{
+			transaction(m, webhookrepo.EventTransactionFailed, webhookrepo.TransactionData{
+				TransactionId: e.TransactionId,
+				RequestorId:   e.RequestorId,
+				Nonce:         e.Nonce,
+				Operation:     e.Operation,
+				Status:        e.Status,
+				MetaData:      e.MetaData,
+			}),
+		}, nil
+	}
+	return []Notification{}, nil
+}
+
+func funds(m outboxrepo.Message, f outboxrepo.Funds, entryType string) []Notification {
+	return []Notification{
+		transaction(m, webhookrepo.EventTransactionSucceeded, webhookrepo.TransactionData{
+			TransactionId: f.TransactionId,
+			RequestorId:   f.RequestorId,
+			Nonce:         f.Nonce,
+			Operation:     f.Operation,
+			Status:        "success",
+			MetaData:      f.MetaData,
+		}),
+		balance(m, webhookrepo.BalanceData{
+			WalletId:      f.WalletId,
+			Currency:      f.Currency,
+			TransactionId: f.TransactionId,
+			LedgerId:      f.LedgerId,
+			EntryType:     entryType,
+			Amount:        f.Amount,
+			Balance:       f.Balance,
+		}),
+	}
+}
+
+func transaction(m outboxrepo.Message, eventType webhookrepo.EventType, data webhookrepo.TransactionData) Notification {
+	return Notification{
+		UserAccountId: data.RequestorId,
+		Event:         webhookrepo.Event{Type: eventType, CreatedAt: m.CreatedAt, Data: data},
+	}
+}
+
+func balance(m outboxrepo.Message, data webhookrepo.BalanceData) Notification {
+	return Notification{
+		WalletId: data.WalletId,
+		Event:    webhookrepo.Event{Type: webhookrepo.EventBalanceChanged, CreatedAt: m.CreatedAt, Data: data},
+	}
+}
+
+// enqueue
+// Writes the deliveries of the notifications of the message in tx. Members of wallets are the ones at the time of
+// enqueueing.
+func enqueue(ctx context.Context, tx pgx.Tx, m outboxrepo.Message) error {
+	notifications, err := Notifications(m)
+	if err != nil {
+		return err
+	}
+	for _, n := range notifications {
+		if n.UserAccountId != 0 {
+			err = webhookrepo.Enqueue(ctx, tx, n.UserAccountId, n.Event)
+		} else {
+			err = webhookrepo.EnqueueMembers(ctx, tx, n.WalletId, n.Event)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package webhook
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	outboxrepo "github.com/cryptonlx/crypto/src/repositories/outbox"
+	webhookrepo "github.com/cryptonlx/crypto/src/repositories/webhook"
+
+	"github.com/shopspring/decimal"
+)
+
+func outboxMessage(t *testing.T, event outboxrepo.Event) outboxrepo.Message {
+	data, err := json.Marshal(event)
+	if err != nil {
+		t.Fatalf("Marshal err %v", err)
+	}
+	return outboxrepo.Message{Id: 1, Type: event.Type(), Key: event.Key(), CreatedAt: time.Unix(1749460653, 0), Data: data}
+}
+
+func TestNotifications(t *testing.T) {
+	m := outboxMessage(t, outboxrepo.TransferCompleted{
+		TransactionId:       9,
+		RequestorId:         2,
+		Nonce:               7,
+		SourceWalletId:      4,
+		DestinationWalletId: 5,
+		Currency:            "SGD",
+		Amount:              decimal.NewFromInt(3),
+		SourceLedgerId:      11,
+		SourceBalance:       decimal.NewFromInt(7),
+		DestinationLedgerId: 12,
+		DestinationBalance:  decimal.NewFromInt(3),
+		MetaData:            json.RawMessage(`{"source_wallet_id":4}`),
+	})
+	notifications, err := Notifications(m)
+	if err != nil {
+		t.Fatalf("Notifications err %v", err)
+	}
+	if len(notifications) != 3 {
+		t.Fatalf("want 3 notifications. got %+v", notifications)
+	}
+
+	succeeded := notifications[0]
+	data, _ := succeeded.Event.Data.(webhookrepo.TransactionData)
+	if succeeded.UserAccountId != 2 || succeeded.Event.Type != webhookrepo.EventTransactionSucceeded ||
+		data.TransactionId != 9 || data.Nonce != 7 || data.Status != "success" || !succeeded.Event.CreatedAt.Equal(m.CreatedAt) {
+		t.Fatalf("want transaction.succeeded to requestor 2. got %+v", succeeded)
+	}
+	payload, _ := json.Marshal(succeeded.Event.Data)
+	if want := `{"transaction_id":9,"requestor_id":2,"nonce":7,"operation":"transfer","status":"success","metadata":{"source_wallet_id":4}}`; string(payload) != want {
+		t.Fatalf("payload want %s. got %s", want, payload)
+	}
+
+	for i, want := range []struct {
+		walletId  int64
+		entryType string
+		balance   string
+	}{{4, "debit", "7"}, {5, "credit", "3"}} {
+		n := notifications[i+1]
+		data, _ := n.Event.Data.(webhookrepo.BalanceData)
+		if n.UserAccountId != 0 || n.WalletId != want.walletId || n.Event.Type != webhookrepo.EventBalanceChanged ||
+			data.EntryType != want.entryType || data.Balance.String() != want.balance {
+			t.Fatalf("want balance.changed to members of wallet %d. got %+v", want.walletId, n)
+		}
+	}
+}
+
+func TestNotificationsFailed(t *testing.T) {
+	notifications, err := Notifications(outboxMessage(t, outboxrepo.TransactionFailed{TransactionId: 9, RequestorId: 2, Operation: "transfer", Status: "rejected"}))
+	if err != nil {
+		t.Fatalf("Notifications err %v", err)
+	}
+	if len(notifications) != 1 || notifications[0].UserAccountId != 2 || notifications[0].Event.Type != webhookrepo.EventTransactionFailed {
+		t.Fatalf("want transaction.failed to requestor 2. got %+v", notifications)
+	}
+
+	notifications, err = Notifications(outboxMessage(t, outboxrepo.WalletCreated{WalletId: 4, UserAccountId: 2, Currency: "SGD"}))
+	if err != nil || len(notifications) != 0 {
+		t.Fatalf("want no notifications of wallet.created. got %+v %v", notifications, err)
+	}
+
+	if _, err := Notifications(outboxrepo.Message{Id: 1, Type: "wallet.renamed", Data: json.RawMessage(`{}`)}); err == nil {
+		t.Fatalf("want err of unknown event type")
+	}
+}
@@ -0,0 +1,82 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"syscall"
+
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+)
+
+// blockedPrefixes
+// Not routable on the internet, besides loopback, private, link-local and multicast addresses checked by publicAddr.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"), // NAT64 of any IPv4 address
+	netip.MustParsePrefix("2001:db8::/32"),
+}
+
+// publicAddr
+// Whether addr is routable on the internet. Receivers in the network of the server, i.e. cloud metadata at
+// 169.254.169.254, are not, so that webhooks cannot be used to reach them.
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsValid() || addr.IsUnspecified() || addr.IsLoopback() || addr.IsPrivate() || addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() || addr.IsInterfaceLocalMulticast() || addr.IsMulticast() {
+		return false
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// validateUrl
+// Checks a subscription url: https without credentials, resolving to public addresses only. allowPrivate also admits
+// http and private addresses, for receivers in development and e2e tests.
+func validateUrl(ctx context.Context, rawUrl string, allowPrivate bool) error {
+	u, err := url.Parse(rawUrl)
+	if err != nil || (u.Scheme != "https" && !(allowPrivate && u.Scheme == "http")) || u.Hostname() == "" || u.User != nil || len(rawUrl) > MaxUrlLength {
+		return utils.InvalidArgumentErrorF("url must be an absolute https url without credentials, at most %d characters", MaxUrlLength)
+	}
+	if allowPrivate {
+		return nil
+	}
+
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return utils.InvalidArgumentErrorF("url host %s cannot be resolved", u.Hostname())
+	}
+	for _, addr := range addrs {
+		if !publicAddr(addr) {
+			return utils.InvalidArgumentErrorF("url host %s must resolve to public addresses only", u.Hostname())
+		}
+	}
+	return nil
+}
+
+// dialControl
+// net.Dialer.Control of the dispatcher. Checks the address actually dialed, so that a host resolving to a public address
+// when subscribed and to a private one when delivered (DNS rebinding) is refused.
+func dialControl(network string, address string, c syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !publicAddr(addrPort.Addr()) {
+		return fmt.Errorf("dial %s: address is not public", address)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"slices"
+
+	"github.com/cryptonlx/crypto/src/repositories/utils"
+	webhookrepo "github.com/cryptonlx/crypto/src/repositories/webhook"
+	"github.com/cryptonlx/crypto/src/services/policy"
+)
+
+const (
+	MaxUrlLength           = 2048
+	DefaultDeliveriesLimit = 100
+	MaxDeliveriesLimit     = 1000
+)
+
+// Authorizer
+// Implemented by userservice.Service.
+type Authorizer interface {
+	Authorize(ctx context.Context, principal string, action policy.Action, owner string) error
+}
+
+type Service struct {
+	repo       *webhookrepo.Repo
+	authorizer Authorizer
+	// allowPrivateUrls admits http urls and private addresses, see validateUrl.
+	allowPrivateUrls bool
+}
+
+func New(repo *webhookrepo.Repo, authorizer Authorizer, allowPrivateUrls bool) *Service {
+	return &Service{repo: repo, authorizer: authorizer, allowPrivateUrls: allowPrivateUrls}
+}
+
+// authorize
+// Checks policy for principal acting on webhooks of username. Principals that cannot read the user get user not found,
+// so that usernames cannot be enumerated.
+func (s Service) authorize(ctx context.Context, principal string, action policy.Action, username string) error {
+	if username == "" {
+		return utils.InvalidArgumentErrorF("user name cannot be empty")
+	}
+	err := s.authorizer.Authorize(ctx, principal, policy.ActionReadUser, username)
+	if utils.ErrorCodeOf(err) == utils.ErrorCodeForbidden {
+		return utils.NotFoundErrorF("user")
+	}
+	if err != nil || action == policy.ActionReadUser {
+		return err
+	}
+	return s.authorizer.Authorize(ctx, principal, action, username)
+}
+
+// CreateSubscription
+// Subscribes url to eventTypes of username. The returned secret signs every delivery and is not returned again.
+// url must be https and resolve to public addresses, checked again on every delivery, see dialControl.
+func (s Service) CreateSubscription(ctx context.Context, requestor string, username string, rawUrl string, eventTypes []string) (webhookrepo.Subscription, error) {
+	if err := s.authorize(ctx, requestor, policy.ActionManageWebhooks, username); err != nil {
+		return webhookrepo.Subscription{}, err
+	}
+
+	if err := validateUrl(ctx, rawUrl, s.allowPrivateUrls); err != nil {
+		return webhookrepo.Subscription{}, err
+	}
+	if len(eventTypes) == 0 {
+		return webhookrepo.Subscription{}, utils.InvalidArgumentErrorF("event_types cannot be empty")
+	}
+	var types []webhookrepo.EventType
+	for _, eventType := range eventTypes {
+		t := webhookrepo.EventType(eventType)
+		if !slices.Contains(webhookrepo.EventTypes, t) {
+			return webhookrepo.Subscription{}, utils.InvalidArgumentErrorF("event_types must be of %v", webhookrepo.EventTypes)
+		}
+		if !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+
+	secret, err := newSecret()
+	if err != nil {
+		return webhookrepo.Subscription{}, err
+	}
+	return s.repo.CreateSubscription(ctx, policy.CanonicalUsername(username), rawUrl, secret, types)
+}
+
+func (s Service) Subscriptions(ctx context.Context, requestor string, username string) ([]webhookrepo.Subscription, error) {
+	if err := s.authorize(ctx, requestor, policy.ActionReadUser, username); err != nil {
+		return []webhookrepo.Subscription{}, err
+	}
+	return s.repo.Subscriptions(ctx, policy.CanonicalUsername(username))
+}
+
+func (s Service) DeleteSubscription(ctx context.Context, requestor string, username string, id int64) error {
+	if err := s.authorize(ctx, requestor, policy.ActionManageWebhooks, username); err != nil {
+		return err
+	}
+	return s.repo.DeleteSubscription(ctx, policy.CanonicalUsername(username), id)
+}
+
+// Deliveries
+// Deliveries of a subscription sorted by newest, limit defaults to DefaultDeliveriesLimit.
+func (s Service) Deliveries(ctx context.Context, requestor string, username string, subscriptionId int64, limit int) ([]webhookrepo.Delivery, error) {
+	if err := s.authorize(ctx, requestor, policy.ActionReadUser, username); err != nil {
+		return []webhookrepo.Delivery{}, err
+	}
+	if limit < 0 || limit > MaxDeliveriesLimit {
+		return []webhookrepo.Delivery{}, utils.InvalidArgumentErrorF("invalid_limit")
+	}
+	if limit == 0 {
+		limit = DefaultDeliveriesLimit
+	}
+	return s.repo.Deliveries(ctx, policy.CanonicalUsername(username), subscriptionId, limit)
+}
+
+// Replay
+// Sends a delivery again, typically a dead one after the receiver is fixed.
+func (s Service) Replay(ctx context.Context, requestor string, username string, deliveryId int64) (webhookrepo.Delivery, error) {
+	if err := s.authorize(ctx, requestor, policy.ActionManageWebhooks, username); err != nil {
+		return webhookrepo.Delivery{}, err
+	}
+	return s.repo.Replay(ctx, policy.CanonicalUsername(username), deliveryId)
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}